@@ -0,0 +1,292 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"neon/core/helpers"
+
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+// POSConfig holds the per-installation point of sale settings
+type POSConfig struct {
+	// VoidApprovalAmount is the fare above which voiding a ticket requires an admin. Zero disables the check.
+	VoidApprovalAmount int `yaml:"void_approval_amount"`
+	// PrinterEmulator replaces the Ethernet printer with a built-in ESC/POS emulator
+	PrinterEmulator PrinterEmulatorConfig `yaml:"printer_emulator"`
+	// Company is the operator printed on the receipts
+	Company CompanyConfig `yaml:"company"`
+	// TicketSigningKey is the installation's secret used to sign the code printed on every ticket
+	TicketSigningKey string `yaml:"ticket_signing_key"`
+	// TicketCode selects the code printed on tickets: "qr", "barcode" (CODE128 short code) or "none"
+	TicketCode string `yaml:"ticket_code"`
+	// AdvanceSaleDays is how many days ahead tickets can be sold
+	AdvanceSaleDays int `yaml:"advance_sale_days"`
+	// Holidays are the dates (YYYY-MM-DD) that run on the holiday timetable
+	Holidays []string `yaml:"holidays"`
+	// HolidayWeekdays are the weekdays (e.g. "sunday") that run on the holiday timetable
+	HolidayWeekdays []string `yaml:"holiday_weekdays"`
+	// Gold configures the checks on gold (senior citizen) tickets
+	Gold GoldConfig `yaml:"gold"`
+	// PaymentTerminal charges card payments on a card terminal
+	PaymentTerminal PaymentTerminalConfig `yaml:"payment_terminal"`
+	// EInvoice issues Hacienda electronic invoices for the sales
+	EInvoice EInvoiceConfig `yaml:"einvoice"`
+	// Cash configures the cash drawer checks of the reports
+	Cash CashConfig `yaml:"cash"`
+}
+
+// CashConfig configures the cash drawer checks of the reports
+type CashConfig struct {
+	// FloatApproval requires an admin to confirm the opening float of a report
+	FloatApproval bool `yaml:"float_approval"`
+	// BlindClose hides the cash expected at the closes from the cashier until the drawer count is submitted
+	BlindClose bool `yaml:"blind_close"`
+	// RecountThreshold is the variance, in colones either way, above which a blind close waits for an
+	// admin recount. Zero requires a recount for any variance.
+	RecountThreshold int `yaml:"recount_threshold"`
+}
+
+// IsHoliday reports whether a date runs on the holiday timetable
+func (c *POSConfig) IsHoliday(date time.Time) bool {
+	day := date.Format("2006-01-02")
+	for _, holiday := range c.Holidays {
+		if holiday == day {
+			return true
+		}
+	}
+	for _, weekday := range c.HolidayWeekdays {
+		if strings.EqualFold(weekday, date.Weekday().String()) {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	// TicketCodeQR prints the signed payload as a QR code
+	TicketCodeQR = "qr"
+	// TicketCodeBarcode prints the signed short code as a CODE128 barcode
+	TicketCodeBarcode = "barcode"
+	// TicketCodeNone prints no code
+	TicketCodeNone = "none"
+)
+
+// GoldConfig limits the gold tickets issued per passenger
+type GoldConfig struct {
+	// RequireRegistry rejects gold tickets for IDs missing from the synced gold passenger registry
+	RequireRegistry bool `yaml:"require_registry"`
+	// MaxPerDay is the number of gold tickets an ID can travel on per day. Zero disables the limit.
+	MaxPerDay int `yaml:"max_per_day"`
+	// MaxPerDeparture is the number of gold tickets an ID can hold on one departure. Zero disables the limit.
+	MaxPerDeparture int `yaml:"max_per_departure"`
+}
+
+// CompanyConfig identifies the bus operator on printed receipts
+type CompanyConfig struct {
+	// Name is printed one entry per line
+	Name   []string `yaml:"name"`
+	Phone  string   `yaml:"phone"`
+	Footer string   `yaml:"footer"`
+}
+
+// PrinterEmulatorConfig configures the built-in ESC/POS printer emulator used for development
+type PrinterEmulatorConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Address string `yaml:"address"`
+}
+
+const (
+	// PaymentTerminalTCP drives a countertop terminal over the TCP/JSON protocol
+	PaymentTerminalTCP = "tcp"
+	// PaymentTerminalSimulator uses the built-in terminal simulator
+	PaymentTerminalSimulator = "simulator"
+)
+
+// PaymentTerminalConfig selects the card terminal. Without a driver, card payments are keyed in with their
+// voucher reference.
+type PaymentTerminalConfig struct {
+	// Driver is "tcp", "simulator" or empty for no terminal
+	Driver string `yaml:"driver"`
+	// Address is the terminal's host:port for the tcp driver
+	Address string `yaml:"address"`
+	// TimeoutSeconds is how long an operation waits for the terminal. Zero uses 90 seconds.
+	TimeoutSeconds int `yaml:"timeout_seconds"`
+	// Script are the simulator's first outcomes ("approve", "decline", "timeout")
+	Script []string `yaml:"script"`
+}
+
+const (
+	// EInvoiceAPIHacienda submits documents to Hacienda's recepción API
+	EInvoiceAPIHacienda = "hacienda"
+	// EInvoiceAPIMock submits documents to the built-in mock of the recepción API
+	EInvoiceAPIMock = "mock"
+)
+
+// EInvoiceConfig sets up the electronic invoices. Every sale gets a Tiquete Electrónico, or a Factura
+// Electrónica when the customer gives their identification.
+type EInvoiceConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Emitter is the company registered with Hacienda
+	Emitter EInvoiceEmitterConfig `yaml:"emitter"`
+	// ActivityCode is the economic activity registered with Hacienda
+	ActivityCode string `yaml:"activity_code"`
+	// Provider is the identification of the system's provider; empty uses the emitter's
+	Provider string `yaml:"provider"`
+	// Branch and Terminal number the documents' consecutive numbers
+	Branch   int `yaml:"branch"`
+	Terminal int `yaml:"terminal"`
+	// CABYS is the catalog code of the passenger transport service
+	CABYS string `yaml:"cabys"`
+	// TaxRateCode is the IVA rate code of the tickets; "10" is exempt
+	TaxRateCode string `yaml:"tax_rate_code"`
+	// Certificate is the .p12 signing key issued by Hacienda, relative to the config dir
+	Certificate    string `yaml:"certificate"`
+	CertificatePIN string `yaml:"certificate_pin"`
+	// API is "hacienda" or "mock"
+	API string `yaml:"api"`
+	// Environment is "staging" or "production" for the hacienda API
+	Environment string `yaml:"environment"`
+	// Username and Password are the emitter's API user
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+}
+
+// EInvoiceEmitterConfig is the emitter printed on the electronic invoices
+type EInvoiceEmitterConfig struct {
+	Name string `yaml:"name"`
+	// IDType is "01" (cédula física), "02" (cédula jurídica), "03" (DIMEX) or "04" (NITE)
+	IDType         string `yaml:"id_type"`
+	IDNumber       string `yaml:"id_number"`
+	CommercialName string `yaml:"commercial_name"`
+	Province       string `yaml:"province"`
+	Canton         string `yaml:"canton"`
+	District       string `yaml:"district"`
+	Address        string `yaml:"address"`
+	Phone          string `yaml:"phone"`
+	Email          string `yaml:"email"`
+}
+
+// CertificatePath returns the path of the signing key; relative paths are in the config dir
+func (c EInvoiceConfig) CertificatePath() (string, error) {
+	if filepath.IsAbs(c.Certificate) {
+		return c.Certificate, nil
+	}
+	appDir, err := helpers.GetAppDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(appDir, c.Certificate), nil
+}
+
+// getPOSConfigPath returns the path to pos.yaml (app config dir)
+func getPOSConfigPath() (string, error) {
+	appDir, err := helpers.GetAppDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(appDir, "pos.yaml"), nil
+}
+
+// LoadPOSConfig loads the point of sale settings from pos.yaml plus env overrides.
+// A missing or unreadable file falls back to the defaults so the POS keeps working.
+func LoadPOSConfig() *POSConfig {
+	cfg := defaultPOSConfig()
+
+	path, err := getPOSConfigPath()
+	if err != nil {
+		zap.L().Warn("failed to get pos config path, using defaults", zap.Error(err))
+		return cfg
+	}
+
+	if data, err := os.ReadFile(path); err == nil {
+		if err := yaml.Unmarshal(data, cfg); err != nil {
+			zap.L().Warn("failed to parse pos.yaml, using defaults", zap.Error(err))
+			cfg = defaultPOSConfig()
+		}
+	}
+
+	applyPOSEnvOverrides(cfg)
+	return cfg
+}
+
+// defaultPOSConfig returns the settings used when pos.yaml does not set them
+func defaultPOSConfig() *POSConfig {
+	return &POSConfig{
+		TicketCode:      TicketCodeQR,
+		AdvanceSaleDays: 30,
+		Gold: GoldConfig{
+			MaxPerDay:       2,
+			MaxPerDeparture: 1,
+		},
+		Company: CompanyConfig{
+			Name:   []string{"TRANSPORTES", "EL PUMA PARDO S.A"},
+			Phone:  "2765-1349",
+			Footer: "BUEN VIAJE",
+		},
+		EInvoice: EInvoiceConfig{
+			Branch:      1,
+			Terminal:    1,
+			TaxRateCode: "10",
+			Certificate: "einvoice.p12",
+			API:         EInvoiceAPIMock,
+			Environment: "staging",
+		},
+	}
+}
+
+func applyPOSEnvOverrides(cfg *POSConfig) {
+	if v := os.Getenv("POS_VOID_APPROVAL_AMOUNT"); v != "" {
+		if amount, err := strconv.Atoi(v); err == nil {
+			cfg.VoidApprovalAmount = amount
+		}
+	}
+	if v := os.Getenv("POS_TICKET_SIGNING_KEY"); v != "" {
+		cfg.TicketSigningKey = v
+	}
+	if v := os.Getenv("POS_GOLD_REQUIRE_REGISTRY"); v != "" {
+		if enabled, err := strconv.ParseBool(v); err == nil {
+			cfg.Gold.RequireRegistry = enabled
+		}
+	}
+	if v := os.Getenv("POS_CASH_FLOAT_APPROVAL"); v != "" {
+		if enabled, err := strconv.ParseBool(v); err == nil {
+			cfg.Cash.FloatApproval = enabled
+		}
+	}
+	if v := os.Getenv("POS_CASH_BLIND_CLOSE"); v != "" {
+		if enabled, err := strconv.ParseBool(v); err == nil {
+			cfg.Cash.BlindClose = enabled
+		}
+	}
+	if v := os.Getenv("POS_CASH_RECOUNT_THRESHOLD"); v != "" {
+		if amount, err := strconv.Atoi(v); err == nil {
+			cfg.Cash.RecountThreshold = amount
+		}
+	}
+	if v := os.Getenv("POS_PRINTER_EMULATOR"); v != "" {
+		if enabled, err := strconv.ParseBool(v); err == nil {
+			cfg.PrinterEmulator.Enabled = enabled
+		}
+	}
+	if v := os.Getenv("POS_PRINTER_EMULATOR_ADDRESS"); v != "" {
+		cfg.PrinterEmulator.Address = v
+	}
+	if v := os.Getenv("POS_PAYMENT_TERMINAL"); v != "" {
+		cfg.PaymentTerminal.Driver = v
+	}
+	if v := os.Getenv("POS_PAYMENT_TERMINAL_ADDRESS"); v != "" {
+		cfg.PaymentTerminal.Address = v
+	}
+	if v := os.Getenv("POS_EINVOICE_CERTIFICATE_PIN"); v != "" {
+		cfg.EInvoice.CertificatePIN = v
+	}
+	if v := os.Getenv("POS_EINVOICE_PASSWORD"); v != "" {
+		cfg.EInvoice.Password = v
+	}
+}
@@ -17,6 +17,12 @@ const (
 	// RouteCollection is the name of the collection for the route model
 	RouteCollection = "routes"
 
+	// GoldPassengerCollection is the name of the collection for the gold passenger registry
+	GoldPassengerCollection = "gold_passengers"
+
+	// FareVersionCollection is the name of the collection for the scheduled route tariffs
+	FareVersionCollection = "fare_versions"
+
 	// RemoteReportsMySQLTable is the MySQL table for synced POS report snapshots (remote Aiven / MySQL).
 	RemoteReportsMySQLTable = "reports"
 
@@ -26,9 +32,79 @@ const (
 	// TicketsTable is the name of the table for the ticket model
 	TicketsTable = "tickets"
 
+	// TicketVoidsTable is the name of the table for the ticket void audit records
+	TicketVoidsTable = "ticket_voids"
+
+	// TicketPrintsTable is the name of the table for reprints and void slips
+	TicketPrintsTable = "ticket_prints"
+
+	// TicketBoardingsTable is the name of the table for tickets validated on board
+	TicketBoardingsTable = "ticket_boardings"
+
+	// SeatInventoryTable is the name of the table for the seats sold per departure
+	SeatInventoryTable = "seat_inventory"
+
+	// SeatReservationsTable is the name of the table for the numbered seats taken per departure
+	SeatReservationsTable = "seat_reservations"
+
+	// ReportFareTotalsTable is the name of the table for the report totals per fare category
+	ReportFareTotalsTable = "report_fare_totals"
+
+	// SalesTable is the name of the table for the sales grouping tickets
+	SalesTable = "sales"
+
+	// SalePaymentsTable is the name of the table for the payments of the sales
+	SalePaymentsTable = "sale_payments"
+
+	// ReportPaymentTotalsTable is the name of the table for the report totals per payment method
+	ReportPaymentTotalsTable = "report_payment_totals"
+
+	// ElectronicDocumentsTable is the name of the table for the Hacienda electronic invoices of the sales
+	ElectronicDocumentsTable = "electronic_documents"
+
+	// EInvoiceSequencesTable is the name of the table for the last consecutive number per branch, terminal
+	// and document type
+	EInvoiceSequencesTable = "einvoice_sequences"
+
+	// ReportCashCountsTable is the name of the table for the bills and coins counted at each close
+	ReportCashCountsTable = "report_cash_counts"
+
+	// ReportRecountsTable is the name of the table for the admin recounts of the drawer at a close
+	ReportRecountsTable = "report_recounts"
+
+	// ReportHandoversTable is the name of the table for the shift handovers of the reports
+	ReportHandoversTable = "report_handovers"
+
+	// ReportHandoverCountsTable is the name of the table for the bills and coins counted at each handover
+	ReportHandoverCountsTable = "report_handover_counts"
+
+	// ReportDropsTable is the name of the table for the interim cash drops (retiros) of the reports
+	ReportDropsTable = "report_drops"
+
+	// ReportDropCountsTable is the name of the table for the bills and coins counted at each drop
+	ReportDropCountsTable = "report_drop_counts"
+
+	// ZReportsTable is the name of the table for the sequential numbers of the printed daily summaries
+	ZReportsTable = "z_reports"
+
+	// ZReportPrintsTable is the name of the table for the prints of each daily summary
+	ZReportPrintsTable = "z_report_prints"
+
+	// OutboxMessagesTable is the name of the table for the documents owed to third parties
+	OutboxMessagesTable = "outbox_messages"
+
+	// SchemaMigrationsTable is the name of the table for the one-time data migrations already applied
+	SchemaMigrationsTable = "schema_migrations"
+
 	// DataDir is the name of the directory for the data
 	DataDir = "data"
 
 	// DateLayout is the layout for the date
 	DateLayout = "2006-01-02"
+
+	// DefaultPageSize is the page size of a search that doesn't give one
+	DefaultPageSize = 50
+
+	// MaxPageSize is the largest page a search returns
+	MaxPageSize = 200
 )
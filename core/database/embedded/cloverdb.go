@@ -82,6 +82,8 @@ func (d *CloverDB) initCollections() {
 		constants.RouteCollection,
 		constants.UserCollection,
 		constants.CountCollection,
+		constants.GoldPassengerCollection,
+		constants.FareVersionCollection,
 	}
 
 	for _, collection := range collections {
@@ -42,6 +42,9 @@ func (s *SQLite) Connect(ctx context.Context) error {
 	if s.config.InMemory {
 		dsn = ":memory:"
 	}
+	// Writers wait for each other instead of failing with SQLITE_BUSY while another connection's
+	// transaction is open
+	dsn += "?_pragma=busy_timeout(5000)"
 
 	// Create database directory if it doesn't exist
 	if !s.config.InMemory {
@@ -185,12 +188,130 @@ func (s *SQLite) QueryRow(query string, args ...interface{}) *sql.Row {
 	return s.db.QueryRow(query, args...)
 }
 
+// createSchemaMigrationsTable creates the table of the one-time data migrations already applied if it
+// doesn't exist
+func (s *SQLite) createSchemaMigrationsTable() error {
+	query := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			name TEXT PRIMARY KEY,
+			applied_at TEXT NOT NULL
+		)
+	`, constants.SchemaMigrationsTable)
+
+	if _, err := s.db.Exec(query); err != nil {
+		return fmt.Errorf("failed to create schema migrations table: %w", err)
+	}
+
+	return nil
+}
+
+// migrateOnce runs a data migration and records it under name in one transaction, unless it was applied
+// already, so a backfill never runs twice
+func (s *SQLite) migrateOnce(name string, migrate func(tx *sql.Tx) error) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin migration %s: %w", name, err)
+	}
+	defer tx.Rollback()
+
+	var applied int
+	query := fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE name = ?`, constants.SchemaMigrationsTable)
+	if err := tx.QueryRow(query, name).Scan(&applied); err != nil {
+		return fmt.Errorf("failed to check migration %s: %w", name, err)
+	}
+	if applied > 0 {
+		return nil
+	}
+
+	if err := migrate(tx); err != nil {
+		return err
+	}
+
+	insert := fmt.Sprintf(`INSERT INTO %s (name, applied_at) VALUES (?, strftime('%%Y-%%m-%%dT%%H:%%M:%%SZ', 'now'))`,
+		constants.SchemaMigrationsTable)
+	if _, err := tx.Exec(insert, name); err != nil {
+		return fmt.Errorf("failed to record migration %s: %w", name, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migration %s: %w", name, err)
+	}
+
+	return nil
+}
+
 // initTables creates the necessary tables if they don't exist
 func (s *SQLite) initTables() error {
+	if err := s.createSchemaMigrationsTable(); err != nil {
+		return err
+	}
 	if err := s.createReportsTable(); err != nil {
 		return err
 	}
-	return s.createTicketsTable()
+	if err := s.createTicketsTable(); err != nil {
+		return err
+	}
+	if err := s.createTicketVoidsTable(); err != nil {
+		return err
+	}
+	if err := s.createTicketPrintsTable(); err != nil {
+		return err
+	}
+	if err := s.createTicketBoardingsTable(); err != nil {
+		return err
+	}
+	if err := s.createSeatInventoryTable(); err != nil {
+		return err
+	}
+	if err := s.createSeatReservationsTable(); err != nil {
+		return err
+	}
+	if err := s.createReportFareTotalsTable(); err != nil {
+		return err
+	}
+	if err := s.createSalesTable(); err != nil {
+		return err
+	}
+	if err := s.createSalePaymentsTable(); err != nil {
+		return err
+	}
+	if err := s.createReportPaymentTotalsTable(); err != nil {
+		return err
+	}
+	if err := s.createElectronicDocumentsTable(); err != nil {
+		return err
+	}
+	if err := s.createEInvoiceSequencesTable(); err != nil {
+		return err
+	}
+	if err := s.createOutboxMessagesTable(); err != nil {
+		return err
+	}
+	if err := s.createReportCashCountsTable(); err != nil {
+		return err
+	}
+	if err := s.createReportRecountsTable(); err != nil {
+		return err
+	}
+	if err := s.createReportHandoversTable(); err != nil {
+		return err
+	}
+	if err := s.createReportHandoverCountsTable(); err != nil {
+		return err
+	}
+	if err := s.createReportDropsTable(); err != nil {
+		return err
+	}
+	if err := s.createReportDropCountsTable(); err != nil {
+		return err
+	}
+	if err := s.migrateReportDrops(); err != nil {
+		return err
+	}
+	if err := s.createZReportsTable(); err != nil {
+		return err
+	}
+	return s.createZReportPrintsTable()
 }
 
 // initTriggers creates the necessary triggers if they don't exist
@@ -201,6 +322,15 @@ func (s *SQLite) initTriggers() error {
 	if err := s.createTriggerUpdateReportAfterTicketIsUpdatedToNull(); err != nil {
 		return err
 	}
+	if err := s.createTriggerUpdateReportAfterSaleInsert(); err != nil {
+		return err
+	}
+	if err := s.createTriggerUpdateReportAfterSaleIsUpdatedToNull(); err != nil {
+		return err
+	}
+	if err := s.createTriggerUpdateReportAfterSalePaymentInsert(); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -229,7 +359,10 @@ func (s *SQLite) createReportsTable() error {
 			created_at TEXT,
 			partial_closed_by TEXT,
 			closed_by TEXT,
-			remote_synced INTEGER NOT NULL DEFAULT 0
+			remote_synced INTEGER NOT NULL DEFAULT 0,
+			advance_tickets INTEGER NOT NULL DEFAULT 0,
+			advance_cash INTEGER NOT NULL DEFAULT 0,
+			total_sales INTEGER NOT NULL DEFAULT 0
 		)
 	`, constants.ReportsTable)
 
@@ -237,7 +370,23 @@ func (s *SQLite) createReportsTable() error {
 	if err != nil {
 		return fmt.Errorf("failed to create reports table: %w", err)
 	}
-	return nil
+
+	if err := s.addColumnIfMissing(constants.ReportsTable, "advance_tickets", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+	if err := s.addColumnIfMissing(constants.ReportsTable, "advance_cash", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+	if err := s.addColumnIfMissing(constants.ReportsTable, "total_sales", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+	if err := s.addColumnIfMissing(constants.ReportsTable, "opening_float", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+	if err := s.addColumnIfMissing(constants.ReportsTable, "float_confirmed_by", "TEXT"); err != nil {
+		return err
+	}
+	return s.addColumnIfMissing(constants.ReportsTable, "pending_recount", "TEXT NOT NULL DEFAULT ''")
 }
 
 // createTicketsTable creates the tickets table if it doesn't exist
@@ -257,6 +406,15 @@ func (s *SQLite) createTicketsTable() error {
 			report_id INTEGER NOT NULL,
 			created_at TEXT,
 			updated_at TEXT,
+			travel_date TEXT NOT NULL DEFAULT '',
+			is_advance BOOLEAN NOT NULL DEFAULT 0,
+			seat_number INTEGER NOT NULL DEFAULT 0,
+			fare_category TEXT NOT NULL DEFAULT '',
+			fare_rule TEXT NOT NULL DEFAULT '',
+			fare_version INTEGER NOT NULL DEFAULT 0,
+			sale_id INTEGER NOT NULL DEFAULT 0,
+			approval_code TEXT NOT NULL DEFAULT '',
+			drop_sequence INTEGER NOT NULL DEFAULT 0,
 			FOREIGN KEY (report_id) REFERENCES %s(id) ON DELETE CASCADE
 		)
 	`, constants.TicketsTable, constants.ReportsTable)
@@ -266,12 +424,745 @@ func (s *SQLite) createTicketsTable() error {
 		return fmt.Errorf("failed to create tickets table: %w", err)
 	}
 
+	// Tickets sold before advance sales have no travel date; they travel on their sale date.
+	if err := s.addColumnIfMissing(constants.TicketsTable, "travel_date", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+	if err := s.addColumnIfMissing(constants.TicketsTable, "is_advance", "BOOLEAN NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+	if err := s.addColumnIfMissing(constants.TicketsTable, "seat_number", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+	// Tickets sold before fare categories have none; they count as gold or regular by is_gold.
+	if err := s.addColumnIfMissing(constants.TicketsTable, "fare_category", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+	if err := s.addColumnIfMissing(constants.TicketsTable, "fare_rule", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+	if err := s.addColumnIfMissing(constants.TicketsTable, "fare_version", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+	// Tickets sold before sales have no sale (0)
+	if err := s.addColumnIfMissing(constants.TicketsTable, "sale_id", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+	if err := s.addColumnIfMissing(constants.TicketsTable, "approval_code", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+	// Tickets sold since the last drop of their report have no drop (0)
+	return s.addColumnIfMissing(constants.TicketsTable, "drop_sequence", "INTEGER NOT NULL DEFAULT 0")
+}
+
+// addColumnIfMissing adds a column to a table created by an older version of the app
+func (s *SQLite) addColumnIfMissing(table string, column string, definition string) error {
+	rows, err := s.db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return fmt.Errorf("failed to get %s columns: %w", table, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			cid        int
+			name       string
+			columnType string
+			notNull    bool
+			defaultVal sql.NullString
+			primaryKey int
+		)
+		if err := rows.Scan(&cid, &name, &columnType, &notNull, &defaultVal, &primaryKey); err != nil {
+			return fmt.Errorf("failed to scan %s column: %w", table, err)
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate %s columns: %w", table, err)
+	}
+	rows.Close()
+
+	if _, err := s.db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition)); err != nil {
+		return fmt.Errorf("failed to add column %s.%s: %w", table, column, err)
+	}
+
 	return nil
 }
 
+// createTicketVoidsTable creates the ticket voids audit table if it doesn't exist
+func (s *SQLite) createTicketVoidsTable() error {
+	query := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			ticket_id INTEGER NOT NULL UNIQUE,
+			report_id INTEGER NOT NULL,
+			reason TEXT NOT NULL,
+			note TEXT NOT NULL DEFAULT '',
+			fare INTEGER NOT NULL DEFAULT 0,
+			bucket TEXT NOT NULL,
+			voided_by TEXT NOT NULL,
+			approved_by TEXT,
+			created_at TEXT NOT NULL,
+			FOREIGN KEY (ticket_id) REFERENCES %s(id) ON DELETE CASCADE,
+			FOREIGN KEY (report_id) REFERENCES %s(id) ON DELETE CASCADE
+		)
+	`, constants.TicketVoidsTable, constants.TicketsTable, constants.ReportsTable)
+
+	_, err := s.db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to create ticket voids table: %w", err)
+	}
+
+	return nil
+}
+
+// createTicketPrintsTable creates the table recording reprints and void slips if it doesn't exist
+func (s *SQLite) createTicketPrintsTable() error {
+	query := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			ticket_id INTEGER NOT NULL,
+			report_id INTEGER NOT NULL,
+			kind TEXT NOT NULL,
+			copy_number INTEGER NOT NULL DEFAULT 0,
+			printed_by TEXT NOT NULL,
+			status TEXT NOT NULL DEFAULT 'printed',
+			created_at TEXT NOT NULL,
+			FOREIGN KEY (ticket_id) REFERENCES %s(id) ON DELETE CASCADE,
+			FOREIGN KEY (report_id) REFERENCES %s(id) ON DELETE CASCADE
+		)
+	`, constants.TicketPrintsTable, constants.TicketsTable, constants.ReportsTable)
+
+	_, err := s.db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to create ticket prints table: %w", err)
+	}
+
+	// Prints recorded before the status column were only stored once printed
+	if err := s.addColumnIfMissing(constants.TicketPrintsTable, "status", "TEXT NOT NULL DEFAULT 'printed'"); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// createTicketBoardingsTable creates the table recording validated tickets if it doesn't exist.
+// ticket_id is unique so a ticket can only board once.
+func (s *SQLite) createTicketBoardingsTable() error {
+	query := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			ticket_id INTEGER NOT NULL UNIQUE,
+			device TEXT NOT NULL DEFAULT '',
+			boarded_at TEXT NOT NULL,
+			FOREIGN KEY (ticket_id) REFERENCES %s(id) ON DELETE CASCADE
+		)
+	`, constants.TicketBoardingsTable, constants.TicketsTable)
+
+	_, err := s.db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to create ticket boardings table: %w", err)
+	}
+
+	return nil
+}
+
+// createSeatInventoryTable creates the table of seats sold per route, travel date and departure time
+// if it doesn't exist
+func (s *SQLite) createSeatInventoryTable() error {
+	query := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			departure TEXT NOT NULL,
+			destination TEXT NOT NULL,
+			travel_date TEXT NOT NULL,
+			time TEXT NOT NULL,
+			capacity INTEGER NOT NULL DEFAULT 0,
+			sold INTEGER NOT NULL DEFAULT 0,
+			UNIQUE (departure, destination, travel_date, time)
+		)
+	`, constants.SeatInventoryTable)
+
+	_, err := s.db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to create seat inventory table: %w", err)
+	}
+
+	return nil
+}
+
+// createSeatReservationsTable creates the table of numbered seats taken per departure if it doesn't
+// exist. The unique key rejects double-booking; voids delete the ticket's reservation.
+func (s *SQLite) createSeatReservationsTable() error {
+	query := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			ticket_id INTEGER NOT NULL UNIQUE,
+			departure TEXT NOT NULL,
+			destination TEXT NOT NULL,
+			travel_date TEXT NOT NULL,
+			time TEXT NOT NULL,
+			seat_number INTEGER NOT NULL,
+			UNIQUE (departure, destination, travel_date, time, seat_number),
+			FOREIGN KEY (ticket_id) REFERENCES %s(id) ON DELETE CASCADE
+		)
+	`, constants.SeatReservationsTable, constants.TicketsTable)
+
+	_, err := s.db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to create seat reservations table: %w", err)
+	}
+
+	return nil
+}
+
+// createReportFareTotalsTable creates the table of tickets and cash per fare category of each report if it
+// doesn't exist. The ticket triggers keep it up to date; reports from before fare categories are filled
+// in from their tickets the first time.
+func (s *SQLite) createReportFareTotalsTable() error {
+	query := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			report_id INTEGER NOT NULL,
+			category TEXT NOT NULL,
+			tickets INTEGER NOT NULL DEFAULT 0,
+			cash INTEGER NOT NULL DEFAULT 0,
+			UNIQUE (report_id, category),
+			FOREIGN KEY (report_id) REFERENCES %s(id) ON DELETE CASCADE
+		)
+	`, constants.ReportFareTotalsTable, constants.ReportsTable)
+
+	_, err := s.db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to create report fare totals table: %w", err)
+	}
+
+	backfill := fmt.Sprintf(`
+		INSERT INTO %s (report_id, category, tickets, cash)
+		SELECT report_id, %s, COUNT(*), SUM(fare)
+		FROM %s
+		WHERE is_null = 0 AND NOT EXISTS (SELECT 1 FROM %s)
+		GROUP BY report_id, 2
+	`, constants.ReportFareTotalsTable, ticketCategorySQL("tickets"), constants.TicketsTable, constants.ReportFareTotalsTable)
+
+	return s.migrateOnce("report_fare_totals_backfill", func(tx *sql.Tx) error {
+		if _, err := tx.Exec(backfill); err != nil {
+			return fmt.Errorf("failed to fill report fare totals: %w", err)
+		}
+		return nil
+	})
+}
+
+// createSalesTable creates the table of sales grouping the tickets sold together if it doesn't exist
+func (s *SQLite) createSalesTable() error {
+	query := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			report_id INTEGER NOT NULL,
+			username TEXT NOT NULL,
+			total INTEGER NOT NULL DEFAULT 0,
+			ticket_count INTEGER NOT NULL DEFAULT 0,
+			is_null BOOLEAN NOT NULL DEFAULT 0,
+			created_at TEXT NOT NULL,
+			voided_at TEXT,
+			voided_by TEXT,
+			FOREIGN KEY (report_id) REFERENCES %s(id) ON DELETE CASCADE
+		)
+	`, constants.SalesTable, constants.ReportsTable)
+
+	_, err := s.db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to create sales table: %w", err)
+	}
+
+	return nil
+}
+
+// createSalePaymentsTable creates the table of payments taken for the sales if it doesn't exist
+func (s *SQLite) createSalePaymentsTable() error {
+	query := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			sale_id INTEGER NOT NULL,
+			method TEXT NOT NULL DEFAULT 'cash',
+			amount INTEGER NOT NULL DEFAULT 0,
+			tendered INTEGER NOT NULL DEFAULT 0,
+			change_given INTEGER NOT NULL DEFAULT 0,
+			reference TEXT NOT NULL DEFAULT '',
+			approval_code TEXT NOT NULL DEFAULT '',
+			capture_status TEXT NOT NULL DEFAULT '',
+			refund_status TEXT NOT NULL DEFAULT '',
+			created_at TEXT NOT NULL,
+			FOREIGN KEY (sale_id) REFERENCES %s(id) ON DELETE CASCADE
+		)
+	`, constants.SalePaymentsTable, constants.SalesTable)
+
+	_, err := s.db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to create sale payments table: %w", err)
+	}
+
+	if err := s.addColumnIfMissing(constants.SalePaymentsTable, "tendered", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+	if err := s.addColumnIfMissing(constants.SalePaymentsTable, "change_given", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+	if err := s.addColumnIfMissing(constants.SalePaymentsTable, "reference", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+	if err := s.addColumnIfMissing(constants.SalePaymentsTable, "approval_code", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+	if err := s.addColumnIfMissing(constants.SalePaymentsTable, "capture_status", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+	return s.addColumnIfMissing(constants.SalePaymentsTable, "refund_status", "TEXT NOT NULL DEFAULT ''")
+}
+
+// createReportPaymentTotalsTable creates the table of amounts per payment method of each report if it
+// doesn't exist. The payment triggers keep it up to date; reports from before payment methods were all
+// paid in cash and are filled in from their cash totals the first time.
+func (s *SQLite) createReportPaymentTotalsTable() error {
+	query := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			report_id INTEGER NOT NULL,
+			method TEXT NOT NULL,
+			payments INTEGER NOT NULL DEFAULT 0,
+			partial_amount INTEGER NOT NULL DEFAULT 0,
+			final_amount INTEGER NOT NULL DEFAULT 0,
+			UNIQUE (report_id, method),
+			FOREIGN KEY (report_id) REFERENCES %s(id) ON DELETE CASCADE
+		)
+	`, constants.ReportPaymentTotalsTable, constants.ReportsTable)
+
+	_, err := s.db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to create report payment totals table: %w", err)
+	}
+
+	backfill := fmt.Sprintf(`
+		INSERT INTO %s (report_id, method, payments, partial_amount, final_amount)
+		SELECT id, 'cash', total_sales, partial_cash, final_cash
+		FROM %s
+		WHERE NOT EXISTS (SELECT 1 FROM %s)
+	`, constants.ReportPaymentTotalsTable, constants.ReportsTable, constants.ReportPaymentTotalsTable)
+
+	return s.migrateOnce("report_payment_totals_backfill", func(tx *sql.Tx) error {
+		if _, err := tx.Exec(backfill); err != nil {
+			return fmt.Errorf("failed to fill report payment totals: %w", err)
+		}
+		return nil
+	})
+}
+
+// createElectronicDocumentsTable creates the table of the sales' electronic invoices if it doesn't exist
+func (s *SQLite) createElectronicDocumentsTable() error {
+	query := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			sale_id INTEGER NOT NULL,
+			report_id INTEGER NOT NULL,
+			document_type TEXT NOT NULL,
+			clave TEXT NOT NULL UNIQUE,
+			consecutive TEXT NOT NULL UNIQUE,
+			receiver_name TEXT NOT NULL DEFAULT '',
+			receiver_id_type TEXT NOT NULL DEFAULT '',
+			receiver_id_number TEXT NOT NULL DEFAULT '',
+			receiver_email TEXT NOT NULL DEFAULT '',
+			reference_clave TEXT NOT NULL DEFAULT '',
+			total INTEGER NOT NULL DEFAULT 0,
+			xml TEXT NOT NULL,
+			status TEXT NOT NULL DEFAULT 'pending',
+			issued_at TEXT NOT NULL,
+			submitted_at TEXT,
+			FOREIGN KEY (sale_id) REFERENCES %s(id) ON DELETE CASCADE
+		)
+	`, constants.ElectronicDocumentsTable, constants.SalesTable)
+
+	_, err := s.db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to create electronic documents table: %w", err)
+	}
+
+	return s.addColumnIfMissing(constants.ElectronicDocumentsTable, "reference_clave", "TEXT NOT NULL DEFAULT ''")
+}
+
+// createEInvoiceSequencesTable creates the table of the last consecutive number issued per branch, terminal
+// and document type if it doesn't exist
+func (s *SQLite) createEInvoiceSequencesTable() error {
+	query := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			branch INTEGER NOT NULL,
+			terminal INTEGER NOT NULL,
+			document_type TEXT NOT NULL,
+			last_sequence INTEGER NOT NULL DEFAULT 0,
+			UNIQUE (branch, terminal, document_type)
+		)
+	`, constants.EInvoiceSequencesTable)
+
+	_, err := s.db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to create e-invoice sequences table: %w", err)
+	}
+
+	return nil
+}
+
+// createOutboxMessagesTable creates the table of the documents owed to third parties if it doesn't exist.
+// Electronic documents queued before the outbox get their message the first time, as sent when they had
+// already been submitted.
+func (s *SQLite) createOutboxMessagesTable() error {
+	query := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			kind TEXT NOT NULL,
+			record_id INTEGER NOT NULL,
+			reference TEXT NOT NULL,
+			state TEXT NOT NULL DEFAULT 'pending',
+			attempts INTEGER NOT NULL DEFAULT 0,
+			next_attempt_at TEXT NOT NULL,
+			last_error TEXT NOT NULL DEFAULT '',
+			response TEXT NOT NULL DEFAULT '',
+			reason TEXT NOT NULL DEFAULT '',
+			claimed_until TEXT NOT NULL DEFAULT '',
+			created_at TEXT NOT NULL,
+			updated_at TEXT NOT NULL,
+			UNIQUE (kind, record_id)
+		)
+	`, constants.OutboxMessagesTable)
+
+	_, err := s.db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to create outbox messages table: %w", err)
+	}
+
+	if err := s.addColumnIfMissing(constants.OutboxMessagesTable, "claimed_until", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+
+	index := fmt.Sprintf(`CREATE INDEX IF NOT EXISTS idx_%s_due ON %s (state, next_attempt_at)`,
+		constants.OutboxMessagesTable, constants.OutboxMessagesTable)
+	if _, err := s.db.Exec(index); err != nil {
+		return fmt.Errorf("failed to create outbox messages index: %w", err)
+	}
+
+	backfill := fmt.Sprintf(`
+		INSERT INTO %s (kind, record_id, reference, state, next_attempt_at, created_at, updated_at)
+		SELECT 'einvoice', d.id, d.clave,
+			CASE d.status WHEN 'submitted' THEN 'sent' ELSE 'pending' END,
+			strftime('%%Y-%%m-%%dT%%H:%%M:%%SZ', 'now'),
+			strftime('%%Y-%%m-%%dT%%H:%%M:%%SZ', 'now'),
+			strftime('%%Y-%%m-%%dT%%H:%%M:%%SZ', 'now')
+		FROM %s d
+		WHERE NOT EXISTS (SELECT 1 FROM %s m WHERE m.kind = 'einvoice' AND m.record_id = d.id)
+	`, constants.OutboxMessagesTable, constants.ElectronicDocumentsTable, constants.OutboxMessagesTable)
+
+	if _, err := s.db.Exec(backfill); err != nil {
+		return fmt.Errorf("failed to fill outbox messages: %w", err)
+	}
+
+	update := fmt.Sprintf(`UPDATE %s SET status = 'sent' WHERE status = 'submitted'`, constants.ElectronicDocumentsTable)
+	if _, err := s.db.Exec(update); err != nil {
+		return fmt.Errorf("failed to update electronic documents status: %w", err)
+	}
+
+	return nil
+}
+
+// createReportCashCountsTable creates the table of the bills and coins counted in the drawer at each close
+// of a report if it doesn't exist
+func (s *SQLite) createReportCashCountsTable() error {
+	query := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			report_id INTEGER NOT NULL,
+			stage TEXT NOT NULL,
+			denomination INTEGER NOT NULL,
+			quantity INTEGER NOT NULL DEFAULT 0,
+			amount INTEGER NOT NULL DEFAULT 0,
+			counted_by TEXT NOT NULL DEFAULT '',
+			counted_at TEXT NOT NULL,
+			UNIQUE (report_id, stage, denomination),
+			FOREIGN KEY (report_id) REFERENCES %s(id) ON DELETE CASCADE
+		)
+	`, constants.ReportCashCountsTable, constants.ReportsTable)
+
+	_, err := s.db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to create report cash counts table: %w", err)
+	}
+
+	return nil
+}
+
+// createReportRecountsTable creates the table of the admin recounts of the drawer at a report's closes if it
+// doesn't exist
+func (s *SQLite) createReportRecountsTable() error {
+	query := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			report_id INTEGER NOT NULL,
+			stage TEXT NOT NULL,
+			expected INTEGER NOT NULL DEFAULT 0,
+			previous_received INTEGER NOT NULL DEFAULT 0,
+			received INTEGER NOT NULL DEFAULT 0,
+			variance INTEGER NOT NULL DEFAULT 0,
+			reason TEXT NOT NULL,
+			recounted_by TEXT NOT NULL,
+			recounted_at TEXT NOT NULL,
+			FOREIGN KEY (report_id) REFERENCES %s(id) ON DELETE CASCADE
+		)
+	`, constants.ReportRecountsTable, constants.ReportsTable)
+
+	_, err := s.db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to create report recounts table: %w", err)
+	}
+
+	return nil
+}
+
+// createReportHandoversTable creates the table of the shift handovers of the reports if it doesn't exist
+func (s *SQLite) createReportHandoversTable() error {
+	query := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			report_id INTEGER NOT NULL,
+			sequence INTEGER NOT NULL,
+			from_username TEXT NOT NULL,
+			to_username TEXT NOT NULL DEFAULT '',
+			tickets INTEGER NOT NULL DEFAULT 0,
+			cash_taken INTEGER NOT NULL DEFAULT 0,
+			expected INTEGER NOT NULL DEFAULT 0,
+			cash INTEGER NOT NULL DEFAULT 0,
+			counted_at TEXT NOT NULL,
+			accepted_at TEXT,
+			UNIQUE (report_id, sequence),
+			FOREIGN KEY (report_id) REFERENCES %s(id) ON DELETE CASCADE
+		)
+	`, constants.ReportHandoversTable, constants.ReportsTable)
+
+	_, err := s.db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to create report handovers table: %w", err)
+	}
+
+	return nil
+}
+
+// createReportHandoverCountsTable creates the table of the bills and coins counted at each handover if it
+// doesn't exist
+func (s *SQLite) createReportHandoverCountsTable() error {
+	query := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			handover_id INTEGER NOT NULL,
+			report_id INTEGER NOT NULL,
+			stage TEXT NOT NULL,
+			denomination INTEGER NOT NULL,
+			quantity INTEGER NOT NULL DEFAULT 0,
+			amount INTEGER NOT NULL DEFAULT 0,
+			counted_by TEXT NOT NULL DEFAULT '',
+			counted_at TEXT NOT NULL,
+			UNIQUE (handover_id, denomination),
+			FOREIGN KEY (handover_id) REFERENCES %s(id) ON DELETE CASCADE
+		)
+	`, constants.ReportHandoverCountsTable, constants.ReportHandoversTable)
+
+	_, err := s.db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to create report handover counts table: %w", err)
+	}
+
+	return nil
+}
+
+// createReportDropsTable creates the table of the interim cash drops (retiros) of the reports if it doesn't
+// exist. Each drop keeps the tickets, cash and cash payments accrued since the previous one.
+func (s *SQLite) createReportDropsTable() error {
+	query := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			report_id INTEGER NOT NULL,
+			sequence INTEGER NOT NULL,
+			dropped_at TEXT NOT NULL,
+			dropped_by TEXT NOT NULL DEFAULT '',
+			cash_received INTEGER NOT NULL DEFAULT 0,
+			tickets INTEGER NOT NULL DEFAULT 0,
+			cash INTEGER NOT NULL DEFAULT 0,
+			drawer INTEGER NOT NULL DEFAULT 0,
+			UNIQUE (report_id, sequence),
+			FOREIGN KEY (report_id) REFERENCES %s(id) ON DELETE CASCADE
+		)
+	`, constants.ReportDropsTable, constants.ReportsTable)
+
+	_, err := s.db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to create report drops table: %w", err)
+	}
+
+	return nil
+}
+
+// createReportDropCountsTable creates the table of the bills and coins counted at each drop if it doesn't
+// exist
+func (s *SQLite) createReportDropCountsTable() error {
+	query := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			drop_id INTEGER NOT NULL,
+			report_id INTEGER NOT NULL,
+			stage TEXT NOT NULL,
+			denomination INTEGER NOT NULL,
+			quantity INTEGER NOT NULL DEFAULT 0,
+			amount INTEGER NOT NULL DEFAULT 0,
+			counted_by TEXT NOT NULL DEFAULT '',
+			counted_at TEXT NOT NULL,
+			UNIQUE (drop_id, denomination),
+			FOREIGN KEY (drop_id) REFERENCES %s(id) ON DELETE CASCADE
+		)
+	`, constants.ReportDropCountsTable, constants.ReportDropsTable)
+
+	_, err := s.db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to create report drop counts table: %w", err)
+	}
+
+	return nil
+}
+
+// migrateReportDrops moves the reports of older versions to drops. A partial close becomes the report's
+// first drop, with the tickets sold up to it (compared as instants: the tickets' times are UTC) and its
+// count. The totals of a report without one were kept in the partial columns while it was open; they
+// move to the final ones, where the open period is kept now. It runs once per database.
+func (s *SQLite) migrateReportDrops() error {
+	steps := []string{
+		fmt.Sprintf(`
+			UPDATE %[1]s
+			SET drop_sequence = 1
+			WHERE drop_sequence = 0 AND EXISTS (
+				SELECT 1 FROM %[2]s
+				WHERE %[2]s.id = %[1]s.report_id
+					AND %[2]s.partial_closed_at IS NOT NULL
+					AND julianday(%[1]s.created_at) <= julianday(%[2]s.partial_closed_at)
+					AND NOT EXISTS (SELECT 1 FROM %[3]s WHERE %[3]s.report_id = %[2]s.id)
+			)
+		`, constants.TicketsTable, constants.ReportsTable, constants.ReportDropsTable),
+		fmt.Sprintf(`
+			INSERT INTO %[1]s (report_id, sequence, dropped_at, dropped_by, cash_received, tickets, cash, drawer)
+			SELECT
+				id,
+				1,
+				partial_closed_at,
+				COALESCE(partial_closed_by, ''),
+				partial_cash_received,
+				partial_tickets,
+				partial_cash,
+				COALESCE((SELECT partial_amount FROM %[3]s WHERE %[3]s.report_id = %[2]s.id AND method = 'cash'), 0)
+			FROM %[2]s
+			WHERE partial_closed_at IS NOT NULL
+				AND NOT EXISTS (SELECT 1 FROM %[1]s WHERE %[1]s.report_id = %[2]s.id)
+		`, constants.ReportDropsTable, constants.ReportsTable, constants.ReportPaymentTotalsTable),
+		fmt.Sprintf(`
+			INSERT INTO %[1]s (drop_id, report_id, stage, denomination, quantity, amount, counted_by, counted_at)
+			SELECT %[2]s.id, %[3]s.report_id, %[3]s.stage, %[3]s.denomination, %[3]s.quantity, %[3]s.amount,
+				%[3]s.counted_by, %[3]s.counted_at
+			FROM %[3]s
+			JOIN %[2]s ON %[2]s.report_id = %[3]s.report_id AND %[2]s.sequence = 1
+			WHERE %[3]s.stage = 'partial'
+		`, constants.ReportDropCountsTable, constants.ReportDropsTable, constants.ReportCashCountsTable),
+		fmt.Sprintf(`DELETE FROM %s WHERE stage = 'partial'`, constants.ReportCashCountsTable),
+		fmt.Sprintf(`
+			UPDATE %[1]s
+			SET final_amount = final_amount + partial_amount, partial_amount = 0
+			WHERE partial_amount != 0
+				AND report_id IN (SELECT id FROM %[2]s WHERE partial_closed_at IS NULL)
+		`, constants.ReportPaymentTotalsTable, constants.ReportsTable),
+		fmt.Sprintf(`
+			UPDATE %s
+			SET
+				final_tickets = final_tickets + partial_tickets,
+				final_cash = final_cash + partial_cash,
+				partial_tickets = 0,
+				partial_cash = 0
+			WHERE partial_closed_at IS NULL AND (partial_tickets != 0 OR partial_cash != 0)
+		`, constants.ReportsTable),
+	}
+
+	return s.migrateOnce("report_drops", func(tx *sql.Tx) error {
+		for _, step := range steps {
+			if _, err := tx.Exec(step); err != nil {
+				return fmt.Errorf("failed to migrate report drops: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// createZReportsTable creates the table of the sequential numbers of the daily summaries (Z-reports) if it
+// doesn't exist. A business day gets its number when its summary is first printed.
+func (s *SQLite) createZReportsTable() error {
+	query := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			number INTEGER NOT NULL UNIQUE,
+			business_date TEXT NOT NULL UNIQUE,
+			version INTEGER NOT NULL DEFAULT 1,
+			fingerprint TEXT NOT NULL,
+			printed_by TEXT NOT NULL DEFAULT '',
+			printed_at TEXT NOT NULL
+		)
+	`, constants.ZReportsTable)
+
+	_, err := s.db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to create z reports table: %w", err)
+	}
+
+	return nil
+}
+
+// createZReportPrintsTable creates the table of the prints of each Z-report if it doesn't exist
+func (s *SQLite) createZReportPrintsTable() error {
+	query := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			z_report_id INTEGER NOT NULL,
+			version INTEGER NOT NULL,
+			copy_number INTEGER NOT NULL,
+			printed_by TEXT NOT NULL DEFAULT '',
+			printed_at TEXT NOT NULL,
+			UNIQUE (z_report_id, copy_number),
+			FOREIGN KEY (z_report_id) REFERENCES %s(id) ON DELETE CASCADE
+		)
+	`, constants.ZReportPrintsTable, constants.ZReportsTable)
+
+	_, err := s.db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to create z report prints table: %w", err)
+	}
+
+	return nil
+}
+
+// ticketCategorySQL is the SQL expression of a ticket row's fare category; tickets sold before fare
+// categories are gold or regular by is_gold
+func ticketCategorySQL(row string) string {
+	return fmt.Sprintf(
+		"COALESCE(NULLIF(%[1]s.fare_category, ''), CASE WHEN %[1]s.is_gold = 1 THEN 'gold' ELSE 'regular' END)",
+		row,
+	)
+}
+
 // createTriggerUpdateReportAfterTicketInsert creates the trigger to update the report after
-// a ticket is inserted. Drops first so existing DBs get the updated definition (partial_tickets
-// and partial_cash are set only at partial close, not updated by the trigger).
+// a ticket is inserted. The ticket counts toward the period open since the last drop (final_tickets
+// and final_cash); a drop moves the period into partial_tickets and partial_cash. Drops first so
+// existing DBs get the updated definition.
 func (s *SQLite) createTriggerUpdateReportAfterTicketInsert() error {
 	_, _ = s.db.Exec("DROP TRIGGER IF EXISTS update_report_after_ticket_insert")
 	query := `
@@ -285,11 +1176,18 @@ func (s *SQLite) createTriggerUpdateReportAfterTicketInsert() error {
 				total_gold_cash = total_gold_cash + CASE WHEN NEW.is_gold = 1 THEN NEW.fare ELSE 0 END,
 				total_regular = total_regular + CASE WHEN NEW.is_gold = 0 AND NEW.is_null = 0 THEN 1 ELSE 0 END,
 				total_regular_cash = total_regular_cash + CASE WHEN NEW.is_gold = 0 AND NEW.is_null = 0 THEN NEW.fare ELSE 0 END,
-				partial_tickets = partial_tickets + CASE WHEN partial_closed_at IS NULL THEN 1 ELSE 0 END,
-				partial_cash = partial_cash + CASE WHEN partial_closed_at IS NULL THEN NEW.fare ELSE 0 END,
-				final_tickets = final_tickets + CASE WHEN partial_closed_at IS NOT NULL THEN 1 ELSE 0 END,
-				final_cash = final_cash + CASE WHEN partial_closed_at IS NOT NULL THEN NEW.fare ELSE 0 END
+				final_tickets = final_tickets + 1,
+				final_cash = final_cash + NEW.fare,
+				advance_tickets = advance_tickets + CASE WHEN NEW.is_advance = 1 AND NEW.is_null = 0 THEN 1 ELSE 0 END,
+				advance_cash = advance_cash + CASE WHEN NEW.is_advance = 1 AND NEW.is_null = 0 THEN NEW.fare ELSE 0 END
 			WHERE id = NEW.report_id;
+
+			INSERT INTO report_fare_totals (report_id, category, tickets, cash)
+			SELECT NEW.report_id, ` + ticketCategorySQL("NEW") + `, 1, NEW.fare
+			WHERE NEW.is_null = 0
+			ON CONFLICT (report_id, category) DO UPDATE SET
+				tickets = tickets + 1,
+				cash = cash + excluded.cash;
 		END
 	`
 
@@ -302,7 +1200,9 @@ func (s *SQLite) createTriggerUpdateReportAfterTicketInsert() error {
 }
 
 // createTriggerUpdateReportAfterTicketIsUpdatedToNull creates the trigger to update the report after
-// a ticket is updated to null. Drops first so existing DBs get the updated definition.
+// a ticket is updated to null. The voided fare is taken from the period open since the last drop,
+// even for a ticket sold before it, since that is the drawer the refund is paid from. Drops first so
+// existing DBs get the updated definition.
 func (s *SQLite) createTriggerUpdateReportAfterTicketIsUpdatedToNull() error {
 	_, _ = s.db.Exec("DROP TRIGGER IF EXISTS update_report_after_ticket_null")
 	query := `
@@ -317,13 +1217,24 @@ func (s *SQLite) createTriggerUpdateReportAfterTicketIsUpdatedToNull() error {
 				total_null_cash = total_null_cash + NEW.fare,
 				total_gold = total_gold - CASE WHEN NEW.is_gold = 1 THEN 1 ELSE 0 END,
 				total_gold_cash = total_gold_cash - CASE WHEN NEW.is_gold = 1 THEN NEW.fare ELSE 0 END,
-				total_regular = total_regular - CASE WHEN NEW.is_gold = 0 AND NEW.is_null = 0 THEN 1 ELSE 0 END,
-				total_regular_cash = total_regular_cash - CASE WHEN NEW.is_gold = 0 AND NEW.is_null = 0 THEN NEW.fare ELSE 0 END,
-				partial_tickets = partial_tickets - CASE WHEN partial_closed_at IS NULL THEN 1 ELSE 0 END,
-				partial_cash = partial_cash - CASE WHEN partial_closed_at IS NULL THEN NEW.fare ELSE 0 END,
-				final_tickets = final_tickets - CASE WHEN partial_closed_at IS NOT NULL THEN 1 ELSE 0 END,
-				final_cash = final_cash - CASE WHEN partial_closed_at IS NOT NULL THEN NEW.fare ELSE 0 END
+				total_regular = total_regular - CASE WHEN NEW.is_gold = 0 THEN 1 ELSE 0 END,
+				total_regular_cash = total_regular_cash - CASE WHEN NEW.is_gold = 0 THEN NEW.fare ELSE 0 END,
+				final_tickets = final_tickets - 1,
+				final_cash = final_cash - NEW.fare,
+				advance_tickets = advance_tickets - CASE WHEN NEW.is_advance = 1 THEN 1 ELSE 0 END,
+				advance_cash = advance_cash - CASE WHEN NEW.is_advance = 1 THEN NEW.fare ELSE 0 END
 			WHERE id = NEW.report_id;
+
+			UPDATE report_fare_totals
+			SET
+				tickets = tickets - 1,
+				cash = cash - NEW.fare
+			WHERE report_id = NEW.report_id AND category = ` + ticketCategorySQL("NEW") + `;
+
+			-- Tickets sold with a sale are refunded by a payment; older tickets were paid in cash
+			UPDATE report_payment_totals
+			SET final_amount = final_amount - NEW.fare
+			WHERE report_id = NEW.report_id AND method = 'cash' AND NEW.sale_id = 0;
 		END
 	`
 
@@ -334,3 +1245,85 @@ func (s *SQLite) createTriggerUpdateReportAfterTicketIsUpdatedToNull() error {
 
 	return nil
 }
+
+// createTriggerUpdateReportAfterSaleInsert creates the trigger counting the sales of a report. Drops
+// first so existing DBs get the updated definition.
+func (s *SQLite) createTriggerUpdateReportAfterSaleInsert() error {
+	_, _ = s.db.Exec("DROP TRIGGER IF EXISTS update_report_after_sale_insert")
+	query := `
+		CREATE TRIGGER update_report_after_sale_insert
+		AFTER INSERT ON sales
+		FOR EACH ROW
+		WHEN NEW.is_null = 0
+		BEGIN
+			UPDATE reports
+			SET total_sales = total_sales + 1
+			WHERE id = NEW.report_id;
+		END
+	`
+
+	_, err := s.db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to create trigger update_report_after_sale_insert: %w", err)
+	}
+
+	return nil
+}
+
+// createTriggerUpdateReportAfterSaleIsUpdatedToNull creates the trigger taking a voided sale out of the
+// report's sale count; its tickets are taken out by the ticket trigger. Drops first so existing DBs get
+// the updated definition.
+func (s *SQLite) createTriggerUpdateReportAfterSaleIsUpdatedToNull() error {
+	_, _ = s.db.Exec("DROP TRIGGER IF EXISTS update_report_after_sale_null")
+	query := `
+		CREATE TRIGGER update_report_after_sale_null
+		AFTER UPDATE OF is_null ON sales
+		FOR EACH ROW
+		WHEN NEW.is_null = 1 AND OLD.is_null = 0
+		BEGIN
+			UPDATE reports
+			SET total_sales = total_sales - 1
+			WHERE id = NEW.report_id;
+		END
+	`
+
+	_, err := s.db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to create trigger update_report_after_sale_null: %w", err)
+	}
+
+	return nil
+}
+
+// createTriggerUpdateReportAfterSalePaymentInsert creates the trigger adding a payment to its report's
+// totals per method, in the period open since the last drop. Refunds are payments with a negative
+// amount and don't count as payments. Drops first so existing DBs get the updated definition.
+func (s *SQLite) createTriggerUpdateReportAfterSalePaymentInsert() error {
+	_, _ = s.db.Exec("DROP TRIGGER IF EXISTS update_report_after_sale_payment_insert")
+	query := `
+		CREATE TRIGGER update_report_after_sale_payment_insert
+		AFTER INSERT ON sale_payments
+		FOR EACH ROW
+		BEGIN
+			INSERT INTO report_payment_totals (report_id, method, payments, partial_amount, final_amount)
+			SELECT
+				sales.report_id,
+				NEW.method,
+				CASE WHEN NEW.amount > 0 THEN 1 ELSE 0 END,
+				0,
+				NEW.amount
+			FROM sales
+			WHERE sales.id = NEW.sale_id
+			ON CONFLICT (report_id, method) DO UPDATE SET
+				payments = payments + excluded.payments,
+				final_amount = final_amount + excluded.final_amount;
+		END
+	`
+
+	_, err := s.db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to create trigger update_report_after_sale_payment_insert: %w", err)
+	}
+
+	return nil
+}
@@ -93,6 +93,8 @@ CREATE TABLE IF NOT EXISTS %s (
   created_at VARCHAR(64) NULL,
   partial_closed_by VARCHAR(255) NULL,
   closed_by VARCHAR(255) NULL,
+  opening_float INT NOT NULL DEFAULT 0,
+  float_confirmed_by VARCHAR(255) NULL,
   remote_saved_at VARCHAR(64) NOT NULL
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci
 `, constants.RemoteReportsMySQLTable)
@@ -101,6 +103,33 @@ CREATE TABLE IF NOT EXISTS %s (
 	if err != nil {
 		return fmt.Errorf("mysql report sync: create table: %w", err)
 	}
+
+	// Tables created by earlier versions miss the later columns
+	if err := ensureReportSyncColumn(ctx, db, "opening_float", "INT NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+	return ensureReportSyncColumn(ctx, db, "float_confirmed_by", "VARCHAR(255) NULL")
+}
+
+// ensureReportSyncColumn adds a column to the remote reports table if it doesn't have it. MySQL has no ADD
+// COLUMN IF NOT EXISTS, so the column is looked up in information_schema first.
+func ensureReportSyncColumn(ctx context.Context, db *sql.DB, column string, definition string) error {
+	var count int
+	err := db.QueryRowContext(ctx, `
+SELECT COUNT(*) FROM information_schema.COLUMNS
+WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND COLUMN_NAME = ?
+`, constants.RemoteReportsMySQLTable, column).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("mysql report sync: check column %s: %w", column, err)
+	}
+	if count > 0 {
+		return nil
+	}
+
+	q := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", constants.RemoteReportsMySQLTable, column, definition)
+	if _, err := db.ExecContext(ctx, q); err != nil {
+		return fmt.Errorf("mysql report sync: add column %s: %w", column, err)
+	}
 	return nil
 }
 
@@ -0,0 +1,63 @@
+package einvoice
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+const (
+	// ClaveLength is the length of a document's clave (numeric key)
+	ClaveLength = 50
+	// ConsecutiveLength is the length of a document's consecutive number
+	ConsecutiveLength = 20
+	// countryCode is Costa Rica's calling code, the first digits of every clave
+	countryCode = "506"
+	// maxSequence is the largest sequence of a consecutive number (10 digits)
+	maxSequence = 9999999999
+)
+
+// Situations of a document when it was issued
+const (
+	// SituationNormal is a document issued and submitted normally
+	SituationNormal = "1"
+	// SituationContingency is a document issued on a pre-printed contingency receipt
+	SituationContingency = "2"
+	// SituationNoInternet is a document issued without internet access, submitted later
+	SituationNoInternet = "3"
+)
+
+// Consecutive builds the 20-digit consecutive number of a document: branch (3 digits), terminal (5),
+// document type (2) and sequence (10). Sequences are numbered per branch, terminal and document type.
+func Consecutive(branch int, terminal int, docType DocumentType, sequence int64) (string, error) {
+	if branch < 1 || branch > 999 || terminal < 1 || terminal > 99999 || !docType.IsValid() ||
+		sequence < 1 || sequence > maxSequence {
+		return "", ErrInvalidDocument
+	}
+	return fmt.Sprintf("%03d%05d%s%010d", branch, terminal, docType, sequence), nil
+}
+
+// NewClave builds the 50-digit clave of a document: country code (3), issue date as DDMMYY (6), the
+// emitter's identification padded to 12 digits, the consecutive number (20), the situation (1) and a
+// random security code (8).
+func NewClave(issuedAt time.Time, emitterID string, consecutive string, situation string) (string, error) {
+	if len(emitterID) == 0 || len(emitterID) > 12 || len(consecutive) != ConsecutiveLength || len(situation) != 1 {
+		return "", ErrInvalidDocument
+	}
+
+	code, err := rand.Int(rand.Reader, big.NewInt(100000000))
+	if err != nil {
+		return "", fmt.Errorf("failed to generate security code: %w", err)
+	}
+
+	return fmt.Sprintf("%s%s%s%s%s%08d",
+		countryCode,
+		issuedAt.In(costaRica).Format("020106"),
+		strings.Repeat("0", 12-len(emitterID))+emitterID,
+		consecutive,
+		situation,
+		code.Int64(),
+	), nil
+}
@@ -0,0 +1,139 @@
+package einvoice
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestConsecutive(t *testing.T) {
+	tests := []struct {
+		name     string
+		branch   int
+		terminal int
+		docType  DocumentType
+		sequence int64
+		want     string
+		wantErr  error
+	}{
+		{"tiquete", 1, 1, Tiquete, 1, "00100001040000000001", nil},
+		{"factura", 1, 1, Factura, 42, "00100001010000000042", nil},
+		{"largest", 999, 99999, Tiquete, maxSequence, "99999999049999999999", nil},
+		{"branch zero", 0, 1, Tiquete, 1, "", ErrInvalidDocument},
+		{"branch too long", 1000, 1, Tiquete, 1, "", ErrInvalidDocument},
+		{"terminal zero", 1, 0, Tiquete, 1, "", ErrInvalidDocument},
+		{"terminal too long", 1, 100000, Tiquete, 1, "", ErrInvalidDocument},
+		{"unknown type", 1, 1, DocumentType("02"), 1, "", ErrInvalidDocument},
+		{"sequence zero", 1, 1, Tiquete, 0, "", ErrInvalidDocument},
+		{"sequence too long", 1, 1, Tiquete, maxSequence + 1, "", ErrInvalidDocument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Consecutive(tt.branch, tt.terminal, tt.docType, tt.sequence)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Consecutive() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Consecutive() = %q, want %q", got, tt.want)
+			}
+			if tt.wantErr == nil && len(got) != ConsecutiveLength {
+				t.Errorf("Consecutive() length = %d, want %d", len(got), ConsecutiveLength)
+			}
+		})
+	}
+}
+
+func TestNewClave(t *testing.T) {
+	consecutive := "00100001040000000001"
+
+	tests := []struct {
+		name        string
+		issuedAt    time.Time
+		emitterID   string
+		consecutive string
+		situation   string
+		// wantPrefix is the clave up to the security code, which is random
+		wantPrefix string
+		wantErr    error
+	}{
+		{
+			name:        "cédula física padded",
+			issuedAt:    time.Date(2026, 3, 9, 12, 0, 0, 0, costaRica),
+			emitterID:   "112345678",
+			consecutive: consecutive,
+			situation:   SituationNormal,
+			wantPrefix:  "506" + "090326" + "000112345678" + consecutive + "1",
+		},
+		{
+			name:        "cédula jurídica without internet",
+			issuedAt:    time.Date(2026, 12, 31, 8, 30, 0, 0, costaRica),
+			emitterID:   "3101123456",
+			consecutive: consecutive,
+			situation:   SituationNoInternet,
+			wantPrefix:  "506" + "311226" + "003101123456" + consecutive + "3",
+		},
+		{
+			// 02:00 UTC on March 10 is still March 9 in Costa Rica
+			name:        "date in Costa Rica",
+			issuedAt:    time.Date(2026, 3, 10, 2, 0, 0, 0, time.UTC),
+			emitterID:   "112345678",
+			consecutive: consecutive,
+			situation:   SituationContingency,
+			wantPrefix:  "506" + "090326" + "000112345678" + consecutive + "2",
+		},
+		{
+			name:        "missing emitter",
+			issuedAt:    time.Now(),
+			consecutive: consecutive,
+			situation:   SituationNormal,
+			wantErr:     ErrInvalidDocument,
+		},
+		{
+			name:        "emitter too long",
+			issuedAt:    time.Now(),
+			emitterID:   "1234567890123",
+			consecutive: consecutive,
+			situation:   SituationNormal,
+			wantErr:     ErrInvalidDocument,
+		},
+		{
+			name:        "short consecutive",
+			issuedAt:    time.Now(),
+			emitterID:   "112345678",
+			consecutive: consecutive[1:],
+			situation:   SituationNormal,
+			wantErr:     ErrInvalidDocument,
+		},
+		{
+			name:        "missing situation",
+			issuedAt:    time.Now(),
+			emitterID:   "112345678",
+			consecutive: consecutive,
+			wantErr:     ErrInvalidDocument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewClave(tt.issuedAt, tt.emitterID, tt.consecutive, tt.situation)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewClave() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if len(got) != ClaveLength {
+				t.Fatalf("NewClave() length = %d, want %d", len(got), ClaveLength)
+			}
+			if got[:len(tt.wantPrefix)] != tt.wantPrefix {
+				t.Errorf("NewClave() = %q, want prefix %q", got, tt.wantPrefix)
+			}
+			for _, r := range got[len(tt.wantPrefix):] {
+				if r < '0' || r > '9' {
+					t.Errorf("NewClave() security code of %q isn't numeric", got)
+				}
+			}
+		})
+	}
+}
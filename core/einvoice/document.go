@@ -0,0 +1,161 @@
+package einvoice
+
+import (
+	"strconv"
+	"strings"
+)
+
+const (
+	// dateLayout is the layout of FechaEmision, with the time zone offset
+	dateLayout = "2006-01-02T15:04:05-07:00"
+	// serviceUnit is the unit of measure of services (UnidadMedida)
+	serviceUnit = "Sp"
+	// taxIVA is the tax code of the IVA
+	taxIVA = "01"
+	// saleCash is the condition of a sale paid on the spot (CondicionVenta contado)
+	saleCash = "01"
+	// currencyColon is the currency of the documents
+	currencyColon = "CRC"
+)
+
+// build builds the unsigned document. Public transport is sold as exempt services: every line carries
+// the IVA with the configured rate code and a zero amount.
+func build(doc Document) (*element, error) {
+	if err := doc.validate(); err != nil {
+		return nil, err
+	}
+
+	root := newElement(doc.Type.rootName(), attr{"xmlns", doc.Type.namespace()})
+	root.
+		add("Clave", doc.Clave).
+		add("ProveedorSistemas", doc.Provider).
+		add("CodigoActividadEmisor", doc.ActivityCode).
+		add("NumeroConsecutivo", doc.Consecutive).
+		add("FechaEmision", FormatDate(doc.IssuedAt))
+
+	emitter := root.child("Emisor")
+	emitter.add("Nombre", doc.Emitter.Name)
+	emitter.child("Identificacion").
+		add("Tipo", doc.Emitter.IDType).
+		add("Numero", doc.Emitter.IDNumber)
+	if doc.Emitter.CommercialName != "" {
+		emitter.add("NombreComercial", doc.Emitter.CommercialName)
+	}
+	if doc.Emitter.Province != "" {
+		emitter.child("Ubicacion").
+			add("Provincia", doc.Emitter.Province).
+			add("Canton", doc.Emitter.Canton).
+			add("Distrito", doc.Emitter.District).
+			add("OtrasSenas", doc.Emitter.Address)
+	}
+	if phone := digits(doc.Emitter.Phone); phone != "" {
+		emitter.child("Telefono").
+			add("CodigoPais", countryCode).
+			add("NumTelefono", phone)
+	}
+	if doc.Emitter.Email != "" {
+		emitter.add("CorreoElectronico", doc.Emitter.Email)
+	}
+
+	if doc.Type != Tiquete && doc.Receiver != nil {
+		receiver := root.child("Receptor")
+		receiver.add("Nombre", doc.Receiver.Name)
+		receiver.child("Identificacion").
+			add("Tipo", doc.Receiver.IDType).
+			add("Numero", doc.Receiver.IDNumber)
+		if doc.Receiver.Email != "" {
+			receiver.add("CorreoElectronico", doc.Receiver.Email)
+		}
+	}
+
+	root.add("CondicionVenta", saleCash)
+
+	detail := root.child("DetalleServicio")
+	for i, line := range doc.Lines {
+		subtotal := amount(line.Quantity * line.UnitPrice)
+		item := detail.child("LineaDetalle").
+			add("NumeroLinea", strconv.Itoa(i+1)).
+			add("CodigoCABYS", doc.CABYS).
+			add("Cantidad", strconv.Itoa(line.Quantity)+".000").
+			add("UnidadMedida", serviceUnit).
+			add("Detalle", truncate(line.Description, 200)).
+			add("PrecioUnitario", amount(line.UnitPrice)).
+			add("MontoTotal", subtotal).
+			add("SubTotal", subtotal).
+			add("BaseImponible", subtotal)
+		item.child("Impuesto").
+			add("Codigo", taxIVA).
+			add("CodigoTarifaIVA", doc.TaxRateCode).
+			add("Tarifa", "0.00").
+			add("Monto", amount(0))
+		item.
+			add("ImpuestoAsumidoEmisorFabrica", amount(0)).
+			add("ImpuestoNeto", amount(0)).
+			add("MontoTotalLinea", subtotal)
+	}
+
+	total := amount(doc.Total())
+	summary := root.child("ResumenFactura")
+	summary.child("CodigoTipoMoneda").
+		add("CodigoMoneda", currencyColon).
+		add("TipoCambio", "1.00000")
+	summary.
+		add("TotalServExentos", total).
+		add("TotalExento", total).
+		add("TotalVenta", total).
+		add("TotalVentaNeta", total)
+	summary.child("TotalDesgloseImpuesto").
+		add("Codigo", taxIVA).
+		add("CodigoTarifaIVA", doc.TaxRateCode).
+		add("TotalMontoImpuesto", amount(0))
+	summary.add("TotalImpuesto", amount(0))
+	for _, payment := range doc.Payments {
+		summary.child("MedioPago").
+			add("TipoMedioPago", payment.Method).
+			add("TotalMedioPago", amount(payment.Amount))
+	}
+	summary.add("TotalComprobante", total)
+
+	for _, reference := range doc.References {
+		root.child("InformacionReferencia").
+			add("TipoDocIR", string(reference.Type)).
+			add("Numero", reference.Number).
+			add("FechaEmisionIR", FormatDate(reference.IssuedAt)).
+			add("Codigo", reference.Code).
+			add("Razon", truncate(reference.Reason, 180))
+	}
+
+	return root, nil
+}
+
+// Build returns the unsigned XML of a document, e.g. to preview it
+func Build(doc Document) ([]byte, error) {
+	root, err := build(doc)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xmlHeader), root.canonical()...), nil
+}
+
+// amount formats colones with the five decimals of the schema's amounts
+func amount(colones int) string {
+	return strconv.Itoa(colones) + ".00000"
+}
+
+func digits(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func truncate(s string, length int) string {
+	runes := []rune(s)
+	if len(runes) <= length {
+		return s
+	}
+	return string(runes[:length])
+}
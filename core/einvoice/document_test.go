@@ -0,0 +1,117 @@
+package einvoice
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildReferences(t *testing.T) {
+	invoiceClave := testDocument(Tiquete).Clave
+	issuedAt := time.Date(2026, 3, 9, 12, 0, 0, 0, costaRica)
+
+	credit := func(docType DocumentType, code string) Document {
+		doc := testDocument(NotaCredito)
+		doc.References = []Reference{{
+			Type:     docType,
+			Number:   invoiceClave,
+			IssuedAt: issuedAt,
+			Code:     code,
+			Reason:   "Anulación de la venta 7",
+		}}
+		return doc
+	}
+
+	creditFactura := credit(Factura, ReferenceVoid)
+	creditFactura.Receiver = &Party{Name: "Empresa S.A.", IDType: IDJuridica, IDNumber: "3101123456"}
+
+	contingency := testDocument(Tiquete)
+	contingency.References = []Reference{{
+		Type:     Contingencia,
+		Number:   "A-0042",
+		IssuedAt: issuedAt,
+		Code:     ReferenceContingency,
+		Reason:   "Sustituye comprobante provisional por contingencia",
+	}}
+
+	withoutReason := credit(Tiquete, ReferenceVoid)
+	withoutReason.References[0].Reason = " "
+
+	tests := []struct {
+		name         string
+		doc          Document
+		root         string
+		want         string
+		wantReceiver bool
+		wantErr      error
+	}{
+		{
+			name: "credit note annulling a tiquete",
+			doc:  credit(Tiquete, ReferenceVoid),
+			root: "NotaCreditoElectronica",
+			want: "<InformacionReferencia><TipoDocIR>04</TipoDocIR><Numero>" + invoiceClave + "</Numero>" +
+				"<FechaEmisionIR>2026-03-09T12:00:00-06:00</FechaEmisionIR><Codigo>01</Codigo>" +
+				"<Razon>Anulación de la venta 7</Razon></InformacionReferencia>",
+		},
+		{
+			name: "credit note taking back part of a tiquete",
+			doc:  credit(Tiquete, ReferenceOther),
+			root: "NotaCreditoElectronica",
+			want: "<TipoDocIR>04</TipoDocIR><Numero>" + invoiceClave + "</Numero>" +
+				"<FechaEmisionIR>2026-03-09T12:00:00-06:00</FechaEmisionIR><Codigo>99</Codigo>",
+		},
+		{
+			name:         "credit note to a factura",
+			doc:          creditFactura,
+			root:         "NotaCreditoElectronica",
+			want:         "<TipoDocIR>01</TipoDocIR><Numero>" + invoiceClave + "</Numero>",
+			wantReceiver: true,
+		},
+		{
+			name: "tiquete replacing a contingency receipt",
+			doc:  contingency,
+			root: "TiqueteElectronico",
+			want: "<InformacionReferencia><TipoDocIR>08</TipoDocIR><Numero>A-0042</Numero>" +
+				"<FechaEmisionIR>2026-03-09T12:00:00-06:00</FechaEmisionIR><Codigo>05</Codigo>",
+		},
+		{
+			name:    "credit note without reference",
+			doc:     testDocument(NotaCredito),
+			wantErr: ErrInvalidDocument,
+		},
+		{
+			name:    "reference without reason",
+			doc:     withoutReason,
+			wantErr: ErrInvalidDocument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := Build(tt.doc)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Build() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			built := string(out)
+			if !strings.HasPrefix(built, xmlHeader+"<"+tt.root+` xmlns="`+tt.doc.Type.namespace()+`">`) {
+				t.Fatalf("Build() root = %.120q, want %s", built, tt.root)
+			}
+			if !strings.Contains(built, tt.want) {
+				t.Errorf("Build() = %s, want it to contain %s", built, tt.want)
+			}
+			if got := strings.Contains(built, "<Receptor>"); got != tt.wantReceiver {
+				t.Errorf("Build() has a receiver = %v, want %v", got, tt.wantReceiver)
+			}
+
+			// The references follow the summary, as the schema orders them
+			if strings.Index(built, "<InformacionReferencia>") < strings.Index(built, "</ResumenFactura>") {
+				t.Errorf("Build() has the references before the summary")
+			}
+		})
+	}
+}
@@ -0,0 +1,231 @@
+// Package einvoice builds, signs and submits the electronic invoices (comprobantes electrónicos v4.4)
+// that Costa Rica's Hacienda requires for every sale
+package einvoice
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidDocument is returned when a document misses a field the schema requires
+	ErrInvalidDocument = errors.New("invalid electronic document")
+	// ErrInvalidIdentification is returned when an identification number doesn't match its type
+	ErrInvalidIdentification = errors.New("invalid identification")
+)
+
+// costaRica is the time zone of the documents' dates. Costa Rica has no daylight saving time, so a fixed
+// zone avoids depending on the tz database, which is missing on some Windows installs.
+var costaRica = time.FixedZone("America/Costa_Rica", -6*60*60)
+
+// FormatDate formats a time as the documents' dates, with Costa Rica's offset
+func FormatDate(t time.Time) string {
+	return t.In(costaRica).Format(dateLayout)
+}
+
+// ParseDate parses a date formatted by FormatDate
+func ParseDate(s string) (time.Time, error) {
+	return time.Parse(dateLayout, s)
+}
+
+// DocumentType is the type of an electronic document, as coded in its consecutive number
+type DocumentType string
+
+const (
+	// Factura is a Factura Electrónica, issued to an identified receiver such as a company
+	Factura DocumentType = "01"
+	// NotaCredito is a Nota de Crédito Electrónica, which takes back all or part of an issued document
+	NotaCredito DocumentType = "03"
+	// Tiquete is a Tiquete Electrónico, issued to a final consumer
+	Tiquete DocumentType = "04"
+	// Contingencia is a receipt written by hand while the system was down. It is never issued, only
+	// referenced by the document that replaces it.
+	Contingencia DocumentType = "08"
+)
+
+// IsValid reports whether the document type is supported
+func (d DocumentType) IsValid() bool {
+	return d == Factura || d == NotaCredito || d == Tiquete
+}
+
+// rootName is the name of the document's root element
+func (d DocumentType) rootName() string {
+	switch d {
+	case Factura:
+		return "FacturaElectronica"
+	case NotaCredito:
+		return "NotaCreditoElectronica"
+	}
+	return "TiqueteElectronico"
+}
+
+// namespace is the v4.4 namespace of the document's schema
+func (d DocumentType) namespace() string {
+	switch d {
+	case Factura:
+		return "https://cdn.comprobanteselectronicos.go.cr/xml-schemas/v4.4/facturaElectronica"
+	case NotaCredito:
+		return "https://cdn.comprobanteselectronicos.go.cr/xml-schemas/v4.4/notaCreditoElectronica"
+	}
+	return "https://cdn.comprobanteselectronicos.go.cr/xml-schemas/v4.4/tiqueteElectronico"
+}
+
+// Identification types
+const (
+	// IDFisica is a national ID card (cédula física), 9 digits
+	IDFisica = "01"
+	// IDJuridica is a company ID (cédula jurídica), 10 digits
+	IDJuridica = "02"
+	// IDDimex is a foreign resident ID (DIMEX), 11 or 12 digits
+	IDDimex = "03"
+	// IDNite is a tax ID for people without a cédula (NITE), 10 digits
+	IDNite = "04"
+)
+
+// ValidateIdentification checks an identification number against its type
+func ValidateIdentification(idType string, number string) error {
+	for _, r := range number {
+		if r < '0' || r > '9' {
+			return ErrInvalidIdentification
+		}
+	}
+
+	switch idType {
+	case IDFisica:
+		if len(number) == 9 {
+			return nil
+		}
+	case IDJuridica, IDNite:
+		if len(number) == 10 {
+			return nil
+		}
+	case IDDimex:
+		if len(number) == 11 || len(number) == 12 {
+			return nil
+		}
+	}
+	return ErrInvalidIdentification
+}
+
+// Means of payment (MedioPago)
+const (
+	// PaymentCash is cash (efectivo)
+	PaymentCash = "01"
+	// PaymentCard is a debit or credit card (tarjeta)
+	PaymentCard = "02"
+	// PaymentSinpe is a SINPE Móvil transfer
+	PaymentSinpe = "06"
+)
+
+// Reference codes (CodigoReferencia), why a document references another
+const (
+	// ReferenceVoid annuls the referenced document as a whole
+	ReferenceVoid = "01"
+	// ReferenceContingency replaces a receipt written by hand in contingency
+	ReferenceContingency = "05"
+	// ReferenceOther is any other reason, e.g. taking back part of a document
+	ReferenceOther = "99"
+)
+
+// Reference is a document referenced by the one issued (InformacionReferencia), e.g. the invoice a credit
+// note takes back
+type Reference struct {
+	Type DocumentType
+	// Number is the clave of an electronic document or the number of a contingency receipt
+	Number   string
+	IssuedAt time.Time
+	Code     string
+	Reason   string
+}
+
+// Party is the emitter or the receiver of a document
+type Party struct {
+	Name     string
+	IDType   string
+	IDNumber string
+	// CommercialName, the location and the phone are only printed for the emitter
+	CommercialName string
+	Province       string
+	Canton         string
+	District       string
+	Address        string
+	Phone          string
+	Email          string
+}
+
+// Line is a line of the document's detail. Amounts are in colones.
+type Line struct {
+	Description string
+	Quantity    int
+	UnitPrice   int
+}
+
+// Payment is a means of payment of the document and the amount paid with it
+type Payment struct {
+	Method string
+	Amount int
+}
+
+// Document is an electronic document to build and sign
+type Document struct {
+	Type        DocumentType
+	Clave       string
+	Consecutive string
+	IssuedAt    time.Time
+	// Provider is the identification of the system's provider (ProveedorSistemas)
+	Provider string
+	// ActivityCode is the emitter's economic activity registered with Hacienda
+	ActivityCode string
+	Emitter      Party
+	// Receiver is required for a Factura, optional for a Nota de Crédito and ignored for a Tiquete
+	Receiver *Party
+	// CABYS is the goods and services catalog code of the lines
+	CABYS string
+	// TaxRateCode is the IVA rate code of the lines (CodigoTarifaIVA); public transport is exempt
+	TaxRateCode string
+	Lines       []Line
+	Payments    []Payment
+	// References are required for a Nota de Crédito, to the document it takes back
+	References []Reference
+}
+
+// Total is the sum of the document's lines
+func (d Document) Total() int {
+	total := 0
+	for _, line := range d.Lines {
+		total += line.Quantity * line.UnitPrice
+	}
+	return total
+}
+
+// validate checks the fields the schema requires
+func (d Document) validate() error {
+	if !d.Type.IsValid() || len(d.Clave) != ClaveLength || len(d.Consecutive) != ConsecutiveLength ||
+		len(d.Lines) == 0 || d.CABYS == "" || d.ActivityCode == "" || strings.TrimSpace(d.Emitter.Name) == "" {
+		return ErrInvalidDocument
+	}
+	if err := ValidateIdentification(d.Emitter.IDType, d.Emitter.IDNumber); err != nil {
+		return err
+	}
+	if d.Type == Factura && d.Receiver == nil {
+		return ErrInvalidDocument
+	}
+	if d.Type != Tiquete && d.Receiver != nil {
+		if strings.TrimSpace(d.Receiver.Name) == "" {
+			return ErrInvalidDocument
+		}
+		if err := ValidateIdentification(d.Receiver.IDType, d.Receiver.IDNumber); err != nil {
+			return err
+		}
+	}
+	if d.Type == NotaCredito && len(d.References) == 0 {
+		return ErrInvalidDocument
+	}
+	for _, reference := range d.References {
+		if reference.Number == "" || reference.Code == "" || strings.TrimSpace(reference.Reason) == "" {
+			return ErrInvalidDocument
+		}
+	}
+	return nil
+}
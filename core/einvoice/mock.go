@@ -0,0 +1,140 @@
+package einvoice
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// MockDocument is a document submitted to the mock recepción API
+type MockDocument struct {
+	Clave       string `json:"clave"`
+	State       string `json:"state"`
+	SubmittedAt string `json:"submitted_at"`
+	Size        int    `json:"size"`
+}
+
+// MockRecepcion is a local stand-in for Hacienda's recepción API, for development and training. Submitted
+// documents are received, reported as being processed on the first status query and then take the next
+// scripted state ("aceptado" or "rechazado"), accepted once the script runs out. Duplicate claves are
+// refused as the real API does.
+type MockRecepcion struct {
+	mu        sync.Mutex
+	script    []string
+	documents []*mockDocument
+}
+
+type mockDocument struct {
+	MockDocument
+	final   string
+	queries int
+}
+
+// NewMockRecepcion creates a mock that gives script's states to the next documents
+func NewMockRecepcion(script ...string) *MockRecepcion {
+	return &MockRecepcion{script: script}
+}
+
+// Script replaces the final states of the next documents
+func (m *MockRecepcion) Script(states ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.script = append([]string(nil), states...)
+}
+
+// Documents returns the documents submitted to the mock, oldest first
+func (m *MockRecepcion) Documents() []MockDocument {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	documents := make([]MockDocument, 0, len(m.documents))
+	for _, document := range m.documents {
+		documents = append(documents, document.MockDocument)
+	}
+	return documents
+}
+
+// Submit receives a document
+func (m *MockRecepcion) Submit(ctx context.Context, submission Submission) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(submission.Clave) != ClaveLength || len(submission.XML) == 0 {
+		return &SubmissionError{StatusCode: http.StatusBadRequest, Cause: "comprobante incompleto"}
+	}
+	if m.find(submission.Clave) != nil {
+		return &SubmissionError{
+			StatusCode: http.StatusBadRequest,
+			Cause:      fmt.Sprintf("El comprobante [%s] ya fue recibido anteriormente.", submission.Clave),
+		}
+	}
+
+	final := StateAccepted
+	if len(m.script) > 0 {
+		final = m.script[0]
+		m.script = m.script[1:]
+	}
+
+	m.documents = append(m.documents, &mockDocument{
+		MockDocument: MockDocument{
+			Clave:       submission.Clave,
+			State:       StateReceived,
+			SubmittedAt: time.Now().Format(time.RFC3339),
+			Size:        len(submission.XML),
+		},
+		final: final,
+	})
+	return nil
+}
+
+// Status reports a document as being processed the first time, then with its final state and response
+func (m *MockRecepcion) Status(ctx context.Context, clave string) (*Status, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	document := m.find(clave)
+	if document == nil {
+		return nil, ErrUnknownDocument
+	}
+
+	document.queries++
+	status := &Status{Clave: clave, Date: FormatDate(time.Now())}
+	if document.queries == 1 {
+		document.State = StateProcessing
+		status.State = StateProcessing
+		return status, nil
+	}
+
+	document.State = document.final
+	status.State = document.final
+	status.ResponseXML = mockResponse(clave, document.final)
+	return status, nil
+}
+
+func (m *MockRecepcion) find(clave string) *mockDocument {
+	for _, document := range m.documents {
+		if document.Clave == clave {
+			return document
+		}
+	}
+	return nil
+}
+
+// mockResponse is the MensajeHacienda answering a document
+func mockResponse(clave string, state string) []byte {
+	message, detail := "1", "Este comprobante fue aceptado en el ambiente de pruebas."
+	if state != StateAccepted {
+		message, detail = "3", "Este comprobante fue rechazado en el ambiente de pruebas."
+	}
+
+	root := newElement("MensajeHacienda",
+		attr{"xmlns", "https://cdn.comprobanteselectronicos.go.cr/xml-schemas/v4.4/mensajeHacienda"},
+	)
+	root.
+		add("Clave", clave).
+		add("Mensaje", message).
+		add("DetalleMensaje", detail)
+	return append([]byte(xmlHeader), root.canonical()...)
+}
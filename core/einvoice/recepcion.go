@@ -0,0 +1,258 @@
+package einvoice
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// States of a document reported by the recepción API
+const (
+	// StateReceived is a document received and waiting to be processed
+	StateReceived = "recibido"
+	// StateProcessing is a document being validated
+	StateProcessing = "procesando"
+	// StateAccepted is a document accepted by Hacienda
+	StateAccepted = "aceptado"
+	// StateRejected is a document rejected by Hacienda; the response XML gives the reason
+	StateRejected = "rechazado"
+	// StateError is a document Hacienda failed to process
+	StateError = "error"
+)
+
+// Environments of the recepción API
+const (
+	// EnvironmentStaging is Hacienda's sandbox, for testing with staging credentials
+	EnvironmentStaging = "staging"
+	// EnvironmentProduction is Hacienda's production API
+	EnvironmentProduction = "production"
+)
+
+// ErrUnknownDocument is returned when the recepción API doesn't know a clave
+var ErrUnknownDocument = errors.New("unknown electronic document")
+
+// Submitter sends signed documents to Hacienda's recepción API and queries their state
+type Submitter interface {
+	Submit(ctx context.Context, submission Submission) error
+	Status(ctx context.Context, clave string) (*Status, error)
+}
+
+// Identification identifies the emitter or the receiver of a submission
+type Identification struct {
+	Type   string `json:"tipoIdentificacion"`
+	Number string `json:"numeroIdentificacion"`
+}
+
+// Submission is a signed document sent to the recepción API
+type Submission struct {
+	Clave    string          `json:"clave"`
+	Date     string          `json:"fecha"`
+	Emitter  Identification  `json:"emisor"`
+	Receiver *Identification `json:"receptor,omitempty"`
+	// XML is the signed document, sent base64 encoded
+	XML []byte `json:"comprobanteXml"`
+}
+
+// Status is the state of a submitted document
+type Status struct {
+	Clave string `json:"clave"`
+	Date  string `json:"fecha"`
+	State string `json:"ind-estado"`
+	// ResponseXML is Hacienda's MensajeHacienda once the document is accepted or rejected
+	ResponseXML []byte `json:"respuesta-xml"`
+}
+
+// Detail returns the explanation (DetalleMensaje) of Hacienda's response, e.g. why a document was rejected
+func (s *Status) Detail() string {
+	var message struct {
+		Detail string `xml:"DetalleMensaje"`
+	}
+	if err := xml.Unmarshal(s.ResponseXML, &message); err != nil {
+		return ""
+	}
+	return strings.TrimSpace(message.Detail)
+}
+
+// SubmissionError is a submission refused by the recepción API, e.g. a malformed or duplicate document
+type SubmissionError struct {
+	StatusCode int
+	Cause      string
+}
+
+func (e *SubmissionError) Error() string {
+	return fmt.Sprintf("submission refused (%d): %s", e.StatusCode, e.Cause)
+}
+
+// Duplicate reports whether the document was refused because it had already been received
+func (e *SubmissionError) Duplicate() bool {
+	return e.StatusCode == http.StatusBadRequest && strings.Contains(e.Cause, "ya fue recibido")
+}
+
+// Permanent reports whether sending the same document again can't succeed. Authorization and server
+// errors may clear up; a malformed document won't.
+func (e *SubmissionError) Permanent() bool {
+	return e.StatusCode == http.StatusBadRequest && !e.Duplicate()
+}
+
+// Recepcion is the client of Hacienda's recepción API. Requests are authorized with a token from
+// Hacienda's identity provider, requested with the emitter's API user.
+type Recepcion struct {
+	baseURL  string
+	tokenURL string
+	clientID string
+	username string
+	password string
+	client   *http.Client
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// NewRecepcion creates a client of the recepción API of an environment ("staging" or "production")
+func NewRecepcion(environment string, username string, password string) (*Recepcion, error) {
+	switch environment {
+	case EnvironmentStaging:
+		return NewRecepcionAt(
+			"https://api-sandbox.comprobanteselectronicos.go.cr/recepcion/v1",
+			"https://idp.comprobanteselectronicos.go.cr/auth/realms/rut-stag/protocol/openid-connect/token",
+			"api-stag", username, password,
+		), nil
+	case EnvironmentProduction:
+		return NewRecepcionAt(
+			"https://api.comprobanteselectronicos.go.cr/recepcion/v1",
+			"https://idp.comprobanteselectronicos.go.cr/auth/realms/rut/protocol/openid-connect/token",
+			"api-prod", username, password,
+		), nil
+	default:
+		return nil, fmt.Errorf("unknown e-invoice environment %q", environment)
+	}
+}
+
+// NewRecepcionAt creates a client of a recepción API at baseURL, e.g. a local mock
+func NewRecepcionAt(baseURL string, tokenURL string, clientID string, username string, password string) *Recepcion {
+	return &Recepcion{
+		baseURL:  strings.TrimSuffix(baseURL, "/"),
+		tokenURL: tokenURL,
+		clientID: clientID,
+		username: username,
+		password: password,
+		client:   &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Submit sends a document. The API answers 202 once the document is received; it is validated later.
+func (r *Recepcion) Submit(ctx context.Context, submission Submission) error {
+	body, err := json.Marshal(submission)
+	if err != nil {
+		return fmt.Errorf("failed to encode submission: %w", err)
+	}
+
+	response, err := r.do(ctx, http.MethodPost, r.baseURL+"/recepcion", body)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusAccepted && response.StatusCode != http.StatusOK {
+		return &SubmissionError{StatusCode: response.StatusCode, Cause: response.Header.Get("X-Error-Cause")}
+	}
+	return nil
+}
+
+// Status queries the state of a submitted document
+func (r *Recepcion) Status(ctx context.Context, clave string) (*Status, error) {
+	response, err := r.do(ctx, http.MethodGet, r.baseURL+"/recepcion/"+url.PathEscape(clave), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, ErrUnknownDocument
+	default:
+		return nil, &SubmissionError{StatusCode: response.StatusCode, Cause: response.Header.Get("X-Error-Cause")}
+	}
+
+	var status Status
+	if err := json.NewDecoder(response.Body).Decode(&status); err != nil {
+		return nil, fmt.Errorf("failed to decode status: %w", err)
+	}
+	return &status, nil
+}
+
+func (r *Recepcion) do(ctx context.Context, method string, target string, body []byte) (*http.Response, error) {
+	token, err := r.accessToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	request.Header.Set("Authorization", "Bearer "+token)
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+
+	response, err := r.client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach recepción: %w", err)
+	}
+	return response, nil
+}
+
+// accessToken returns the cached token, requesting a new one a minute before it expires
+func (r *Recepcion) accessToken(ctx context.Context) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.token != "" && time.Now().Before(r.expires) {
+		return r.token, nil
+	}
+
+	form := url.Values{
+		"grant_type": {"password"},
+		"client_id":  {r.clientID},
+		"username":   {r.username},
+		"password":   {r.password},
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, r.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to create token request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	response, err := r.client.Do(request)
+	if err != nil {
+		return "", fmt.Errorf("failed to reach identity provider: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("identity provider refused the API user (%d)", response.StatusCode)
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("failed to decode token: %w", err)
+	}
+
+	r.token = token.AccessToken
+	r.expires = time.Now().Add(time.Duration(token.ExpiresIn)*time.Second - time.Minute)
+	return r.token, nil
+}
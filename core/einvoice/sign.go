@@ -0,0 +1,198 @@
+package einvoice
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"golang.org/x/crypto/pkcs12"
+)
+
+const (
+	namespaceDS    = "http://www.w3.org/2000/09/xmldsig#"
+	namespaceXAdES = "http://uri.etsi.org/01903/v1.3.2#"
+
+	algorithmC14N      = "http://www.w3.org/TR/2001/REC-xml-c14n-20010315"
+	algorithmRSASHA256 = "http://www.w3.org/2001/04/xmldsig-more#rsa-sha256"
+	algorithmSHA256    = "http://www.w3.org/2001/04/xmlenc#sha256"
+	algorithmEnveloped = "http://www.w3.org/2000/09/xmldsig#enveloped-signature"
+	typeSignedProps    = "http://uri.etsi.org/01903#SignedProperties"
+)
+
+// ErrInvalidCertificate is returned when the .p12 has no RSA key with its certificate
+var ErrInvalidCertificate = errors.New("invalid signing certificate")
+
+// Policy is the signature policy referenced by the XAdES-EPES signature
+type Policy struct {
+	// Identifier is the URL of the policy document
+	Identifier string
+	// Hash is the base64 SHA-256 digest of the policy document
+	Hash string
+}
+
+// DefaultPolicy is the policy published by Hacienda for the v4.4 documents
+var DefaultPolicy = Policy{
+	Identifier: "https://cdn.comprobanteselectronicos.go.cr/xml-schemas/Resoluci%C3%B3n_General_sobre_disposiciones_t%C3%A9cnicas_comprobantes_electr%C3%B3nicos_para_efectos_tributarios.pdf",
+	Hash:       "DWxin1xWOeI8OuWQXazh4VjLWAaCLAA954em7DMh0h8=",
+}
+
+// Signer signs documents with XAdES-EPES using the emitter's cryptographic key issued by Hacienda
+type Signer struct {
+	key         *rsa.PrivateKey
+	certificate *x509.Certificate
+	Policy      Policy
+}
+
+// NewSigner creates a signer from a key and its certificate
+func NewSigner(key *rsa.PrivateKey, certificate *x509.Certificate) *Signer {
+	return &Signer{key: key, certificate: certificate, Policy: DefaultPolicy}
+}
+
+// LoadSigner reads the key and certificate of a .p12 file protected by pin. Hacienda's .p12 files carry
+// the CA chain too; the certificate kept is the one matching the key.
+func LoadSigner(path string, pin string) (*Signer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read certificate: %w", err)
+	}
+
+	blocks, err := pkcs12.ToPEM(data, pin)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode certificate: %w", err)
+	}
+
+	var key *rsa.PrivateKey
+	var certificates []*x509.Certificate
+	for _, block := range blocks {
+		switch block.Type {
+		case "PRIVATE KEY":
+			if key, err = x509.ParsePKCS1PrivateKey(block.Bytes); err != nil {
+				return nil, ErrInvalidCertificate
+			}
+		case "CERTIFICATE":
+			certificate, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse certificate: %w", err)
+			}
+			certificates = append(certificates, certificate)
+		}
+	}
+	if key == nil {
+		return nil, ErrInvalidCertificate
+	}
+
+	for _, certificate := range certificates {
+		if public, ok := certificate.PublicKey.(*rsa.PublicKey); ok && public.Equal(&key.PublicKey) {
+			return NewSigner(key, certificate), nil
+		}
+	}
+	return nil, ErrInvalidCertificate
+}
+
+// Certificate returns the signing certificate
+func (s *Signer) Certificate() *x509.Certificate {
+	return s.certificate
+}
+
+// Sign builds a document and returns its XML with an enveloped XAdES-EPES signature, signed at
+// signingTime. The document is referenced whole (URI "") and the signed properties by their ID; both
+// digests and the signature use SHA-256 over Canonical XML 1.0.
+func (s *Signer) Sign(doc Document, signingTime time.Time) ([]byte, error) {
+	root, err := build(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := randomID()
+	if err != nil {
+		return nil, err
+	}
+	signatureID := "Signature-" + id
+	referenceID := "Reference-" + id
+	signedPropertiesID := "SignedProperties-" + id
+
+	// The enveloped-signature transform leaves out the signature, which isn't in the tree yet
+	documentDigest := digest(root.canonical())
+
+	signature := root.child("ds:Signature", attr{"xmlns:ds", namespaceDS}, attr{"Id", signatureID})
+	signedInfo := signature.child("ds:SignedInfo")
+	signedInfo.child("ds:CanonicalizationMethod", attr{"Algorithm", algorithmC14N})
+	signedInfo.child("ds:SignatureMethod", attr{"Algorithm", algorithmRSASHA256})
+
+	documentReference := signedInfo.child("ds:Reference", attr{"Id", referenceID}, attr{"URI", ""})
+	documentReference.child("ds:Transforms").child("ds:Transform", attr{"Algorithm", algorithmEnveloped})
+	documentReference.child("ds:DigestMethod", attr{"Algorithm", algorithmSHA256})
+	documentReference.add("ds:DigestValue", documentDigest)
+
+	propertiesReference := signedInfo.child("ds:Reference",
+		attr{"Type", typeSignedProps},
+		attr{"URI", "#" + signedPropertiesID},
+	)
+	propertiesReference.child("ds:DigestMethod", attr{"Algorithm", algorithmSHA256})
+	propertiesDigest := propertiesReference.child("ds:DigestValue")
+
+	signatureValue := signature.child("ds:SignatureValue", attr{"Id", "SignatureValue-" + id})
+	signature.child("ds:KeyInfo", attr{"Id", "KeyInfo-" + id}).
+		child("ds:X509Data").
+		add("ds:X509Certificate", base64.StdEncoding.EncodeToString(s.certificate.Raw))
+
+	qualifying := signature.child("ds:Object").child("xades:QualifyingProperties",
+		attr{"xmlns:xades", namespaceXAdES},
+		attr{"Target", "#" + signatureID},
+	)
+	signedProperties := qualifying.child("xades:SignedProperties", attr{"Id", signedPropertiesID})
+	signatureProperties := signedProperties.child("xades:SignedSignatureProperties")
+	signatureProperties.add("xades:SigningTime", FormatDate(signingTime))
+
+	certificate := signatureProperties.child("xades:SigningCertificate").child("xades:Cert")
+	certificateDigest := certificate.child("xades:CertDigest")
+	certificateDigest.child("ds:DigestMethod", attr{"Algorithm", algorithmSHA256})
+	certificateDigest.add("ds:DigestValue", digest(s.certificate.Raw))
+	certificate.child("xades:IssuerSerial").
+		add("ds:X509IssuerName", s.certificate.Issuer.String()).
+		add("ds:X509SerialNumber", s.certificate.SerialNumber.String())
+
+	policy := signatureProperties.child("xades:SignaturePolicyIdentifier").child("xades:SignaturePolicyId")
+	policy.child("xades:SigPolicyId").add("xades:Identifier", s.Policy.Identifier)
+	policyHash := policy.child("xades:SigPolicyHash")
+	policyHash.child("ds:DigestMethod", attr{"Algorithm", algorithmSHA256})
+	policyHash.add("ds:DigestValue", s.Policy.Hash)
+
+	signedProperties.child("xades:SignedDataObjectProperties").
+		child("xades:DataObjectFormat", attr{"ObjectReference", "#" + referenceID}).
+		add("xades:MimeType", "text/xml").
+		add("xades:Encoding", "UTF-8")
+
+	propertiesDigest.text = digest(signedProperties.canonical(root, signature, qualifying))
+
+	hashed := sha256.Sum256(signedInfo.canonical(root, signature))
+	value, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, hashed[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign document: %w", err)
+	}
+	signatureValue.text = base64.StdEncoding.EncodeToString(value)
+
+	return append([]byte(xmlHeader), root.canonical()...), nil
+}
+
+// digest returns the base64 SHA-256 digest of data
+func digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+func randomID() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", fmt.Errorf("failed to generate signature ID: %w", err)
+	}
+	return hex.EncodeToString(id), nil
+}
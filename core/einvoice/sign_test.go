@@ -0,0 +1,172 @@
+package einvoice
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testSigner signs with a throwaway key and a self-signed certificate
+func testSigner(t *testing.T) *Signer {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1234),
+		Subject:      pkix.Name{CommonName: "Neon Test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %v", err)
+	}
+	return NewSigner(key, certificate)
+}
+
+func testDocument(docType DocumentType) Document {
+	doc := Document{
+		Type:         docType,
+		Clave:        "50609032600011234567800100001" + string(docType) + "0000000001" + "1" + "12345678",
+		Consecutive:  "00100001" + string(docType) + "0000000001",
+		IssuedAt:     time.Date(2026, 3, 9, 12, 0, 0, 0, costaRica),
+		Provider:     "112345678",
+		ActivityCode: "492101",
+		Emitter: Party{
+			Name:     "Transportes Neón & Hijos",
+			IDType:   IDFisica,
+			IDNumber: "112345678",
+		},
+		CABYS:       "6421100000000",
+		TaxRateCode: "01",
+		Lines:       []Line{{Description: "San José -> Cartago", Quantity: 2, UnitPrice: 1150}},
+		Payments:    []Payment{{Method: PaymentCash, Amount: 2300}},
+	}
+	if docType == Factura {
+		doc.Receiver = &Party{Name: "Empresa S.A.", IDType: IDJuridica, IDNumber: "3101123456"}
+	}
+	return doc
+}
+
+// between returns the text of signed from the start of open up to the end of end, inclusive
+func between(t *testing.T, signed string, open string, end string) string {
+	t.Helper()
+	start := strings.Index(signed, open)
+	if start < 0 {
+		t.Fatalf("%s not found", open)
+	}
+	length := strings.Index(signed[start:], end)
+	if length < 0 {
+		t.Fatalf("%s not found", end)
+	}
+	return signed[start : start+length+len(end)]
+}
+
+// inScope renders the namespaces in scope on the first element of a canonical subset, as Canonical XML
+// does when the subset is digested on its own
+func inScope(subset string, name string, namespaces string) string {
+	return strings.Replace(subset, "<"+name, "<"+name+" "+namespaces, 1)
+}
+
+func TestSign(t *testing.T) {
+	signer := testSigner(t)
+	signingTime := time.Date(2026, 3, 9, 12, 0, 5, 0, costaRica)
+	digests := regexp.MustCompile(`<ds:DigestValue>([^<]*)</ds:DigestValue>`)
+
+	invalid := testDocument(Factura)
+	invalid.Receiver = nil
+
+	tests := []struct {
+		name    string
+		doc     Document
+		root    string
+		wantErr error
+	}{
+		{"tiquete", testDocument(Tiquete), "TiqueteElectronico", nil},
+		{"factura", testDocument(Factura), "FacturaElectronica", nil},
+		{"factura without receiver", invalid, "", ErrInvalidDocument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := signer.Sign(tt.doc, signingTime)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Sign() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			signed, ok := strings.CutPrefix(string(out), xmlHeader)
+			if !ok {
+				t.Fatalf("Sign() doesn't start with the XML header")
+			}
+			if !strings.HasPrefix(signed, "<"+tt.root+` xmlns="`+tt.doc.Type.namespace()+`">`) {
+				t.Fatalf("Sign() root = %.80q, want %s", signed, tt.root)
+			}
+			if !strings.Contains(signed, "<Nombre>Transportes Neón &amp; Hijos</Nombre>") {
+				t.Errorf("Sign() doesn't escape the emitter's name")
+			}
+			if !strings.Contains(signed, "<xades:SigningTime>2026-03-09T12:00:05-06:00</xades:SigningTime>") {
+				t.Errorf("Sign() doesn't carry the signing time")
+			}
+
+			found := digests.FindAllStringSubmatch(signed, -1)
+			if len(found) != 4 {
+				t.Fatalf("Sign() has %d digests, want 4", len(found))
+			}
+
+			// The document is digested without its enveloped signature
+			signature := between(t, signed, "<ds:Signature ", "</ds:Signature>")
+			if got, want := found[0][1], digest([]byte(strings.Replace(signed, signature, "", 1))); got != want {
+				t.Errorf("document digest = %s, want %s", got, want)
+			}
+
+			// The signed properties are digested with the namespaces of their ancestors
+			properties := between(t, signed, "<xades:SignedProperties ", "</xades:SignedProperties>")
+			properties = inScope(properties, "xades:SignedProperties",
+				`xmlns="`+tt.doc.Type.namespace()+`" xmlns:ds="`+namespaceDS+`" xmlns:xades="`+namespaceXAdES+`"`)
+			if got, want := found[1][1], digest([]byte(properties)); got != want {
+				t.Errorf("signed properties digest = %s, want %s", got, want)
+			}
+
+			if got, want := found[2][1], digest(signer.Certificate().Raw); got != want {
+				t.Errorf("certificate digest = %s, want %s", got, want)
+			}
+			if got, want := found[3][1], signer.Policy.Hash; got != want {
+				t.Errorf("policy digest = %s, want %s", got, want)
+			}
+
+			signedInfo := between(t, signed, "<ds:SignedInfo>", "</ds:SignedInfo>")
+			signedInfo = inScope(signedInfo, "ds:SignedInfo",
+				`xmlns="`+tt.doc.Type.namespace()+`" xmlns:ds="`+namespaceDS+`"`)
+			value := between(t, signed, "<ds:SignatureValue ", "</ds:SignatureValue>")
+			value = value[strings.Index(value, ">")+1 : strings.LastIndex(value, "<")]
+			decoded, err := base64.StdEncoding.DecodeString(value)
+			if err != nil {
+				t.Fatalf("signature value isn't base64: %v", err)
+			}
+			hashed := sha256.Sum256([]byte(signedInfo))
+			public := signer.Certificate().PublicKey.(*rsa.PublicKey)
+			if err := rsa.VerifyPKCS1v15(public, crypto.SHA256, hashed[:], decoded); err != nil {
+				t.Errorf("signature doesn't verify: %v", err)
+			}
+		})
+	}
+}
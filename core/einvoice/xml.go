@@ -0,0 +1,139 @@
+package einvoice
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+)
+
+// xmlHeader starts every serialized document
+const xmlHeader = `<?xml version="1.0" encoding="utf-8"?>` + "\n"
+
+// element is a node of a document being built. Documents are serialized in canonical form (Canonical XML
+// 1.0), so the bytes that are signed are exactly the bytes that are sent.
+type element struct {
+	name     string
+	attrs    []attr
+	text     string
+	children []*element
+}
+
+type attr struct {
+	name  string
+	value string
+}
+
+func newElement(name string, attrs ...attr) *element {
+	return &element{name: name, attrs: attrs}
+}
+
+// child appends an empty child element and returns it
+func (e *element) child(name string, attrs ...attr) *element {
+	c := newElement(name, attrs...)
+	e.children = append(e.children, c)
+	return c
+}
+
+// add appends a child element holding text and returns e, to chain simple fields
+func (e *element) add(name string, text string) *element {
+	e.child(name).text = text
+	return e
+}
+
+// namespaces returns the namespace declarations of the element by prefix ("" is the default namespace)
+func (e *element) namespaces() map[string]string {
+	declared := map[string]string{}
+	for _, a := range e.attrs {
+		if a.name == "xmlns" {
+			declared[""] = a.value
+		} else if prefix, ok := strings.CutPrefix(a.name, "xmlns:"); ok {
+			declared[prefix] = a.value
+		}
+	}
+	return declared
+}
+
+// canonical serializes the element as a document subset whose ancestors are the given elements, outermost
+// first. The namespaces they declare are in scope and, as Canonical XML requires, rendered on the element.
+func (e *element) canonical(ancestors ...*element) []byte {
+	inScope := map[string]string{}
+	for _, ancestor := range ancestors {
+		for prefix, uri := range ancestor.namespaces() {
+			inScope[prefix] = uri
+		}
+	}
+
+	var b bytes.Buffer
+	e.writeCanonical(&b, map[string]string{}, inScope)
+	return b.Bytes()
+}
+
+// writeCanonical writes the element with the namespace declarations not already rendered by its parent.
+// inherited are namespaces in scope from ancestors left out of the output.
+func (e *element) writeCanonical(b *bytes.Buffer, rendered map[string]string, inherited map[string]string) {
+	scope := map[string]string{}
+	for prefix, uri := range rendered {
+		scope[prefix] = uri
+	}
+
+	declared := inherited
+	if own := e.namespaces(); len(own) > 0 {
+		declared = map[string]string{}
+		for prefix, uri := range inherited {
+			declared[prefix] = uri
+		}
+		for prefix, uri := range own {
+			declared[prefix] = uri
+		}
+	}
+
+	var prefixes []string
+	for prefix, uri := range declared {
+		if current, ok := rendered[prefix]; ok && current == uri {
+			continue
+		}
+		prefixes = append(prefixes, prefix)
+		scope[prefix] = uri
+	}
+	// The default namespace sorts first as its prefix is empty
+	sort.Strings(prefixes)
+
+	var attrs []attr
+	for _, a := range e.attrs {
+		if a.name != "xmlns" && !strings.HasPrefix(a.name, "xmlns:") {
+			attrs = append(attrs, a)
+		}
+	}
+	sort.Slice(attrs, func(i, j int) bool { return attrs[i].name < attrs[j].name })
+
+	b.WriteString("<" + e.name)
+	for _, prefix := range prefixes {
+		name := "xmlns"
+		if prefix != "" {
+			name += ":" + prefix
+		}
+		b.WriteString(" " + name + `="` + escapeAttr(declared[prefix]) + `"`)
+	}
+	for _, a := range attrs {
+		b.WriteString(" " + a.name + `="` + escapeAttr(a.value) + `"`)
+	}
+	b.WriteString(">")
+	b.WriteString(escapeText(e.text))
+	for _, c := range e.children {
+		c.writeCanonical(b, scope, map[string]string{})
+	}
+	b.WriteString("</" + e.name + ">")
+}
+
+var (
+	textEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\r", "&#xD;")
+	attrEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", `"`, "&quot;", "\t", "&#x9;", "\n", "&#xA;", "\r", "&#xD;")
+)
+
+func escapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+func escapeAttr(s string) string {
+	return attrEscaper.Replace(s)
+}
@@ -0,0 +1,282 @@
+package emulator
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	nul byte = 0x00
+	ht  byte = 0x09
+	lf  byte = 0x0a
+	cr  byte = 0x0d
+	dle byte = 0x10
+	eot byte = 0x04
+	esc byte = 0x1b
+	gs  byte = 0x1d
+
+	// receiptColumns is the number of font A characters per line on 58mm paper
+	receiptColumns = 32
+)
+
+// receipt renders decoded print data as plain text, one line per LF, honouring justification
+type receipt struct {
+	lines   []string
+	line    strings.Builder
+	justify byte
+	width   int
+	qr      string
+}
+
+func newReceipt() *receipt {
+	return &receipt{width: 1}
+}
+
+func (r *receipt) write(b byte) {
+	r.line.WriteByte(b)
+}
+
+// writeLine adds an annotation line such as a barcode or QR code placeholder
+func (r *receipt) writeLine(text string) {
+	if r.line.Len() > 0 {
+		r.newLine()
+	}
+	r.line.WriteString(text)
+	r.newLine()
+}
+
+func (r *receipt) newLine() {
+	text := r.line.String()
+	r.line.Reset()
+
+	columns := receiptColumns / r.width
+	pad := columns - len(text)
+	switch {
+	case pad <= 0:
+	case r.justify == 1:
+		text = strings.Repeat(" ", pad/2) + text
+	case r.justify == 2:
+		text = strings.Repeat(" ", pad) + text
+	}
+	r.lines = append(r.lines, strings.TrimRight(text, " "))
+}
+
+func (r *receipt) reset() {
+	r.justify = 0
+	r.width = 1
+}
+
+// String returns the receipt transcript, without trailing blank lines
+func (r *receipt) String() string {
+	lines := r.lines
+	if r.line.Len() > 0 {
+		lines = append(append([]string(nil), lines...), r.line.String())
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// realtime answers DLE EOT n status requests; other DLE commands are skipped
+func (p *Printer) realtime(r *bufio.Reader, w io.Writer) error {
+	cmd, err := r.ReadByte()
+	if err != nil {
+		return err
+	}
+	n, err := r.ReadByte()
+	if err != nil {
+		return err
+	}
+	if cmd != eot {
+		// DLE ENQ n and DLE DC4 fn ... are acknowledged by ignoring them
+		return nil
+	}
+
+	_, err = w.Write([]byte{p.Status().realtimeStatus(n)})
+	return err
+}
+
+// escCommand applies an ESC command, skipping the parameters of commands that don't affect the text
+func (p *Printer) escCommand(r *bufio.Reader) error {
+	cmd, err := r.ReadByte()
+	if err != nil {
+		return err
+	}
+
+	switch cmd {
+	case '@':
+		p.withReceipt(func(rc *receipt) { rc.reset() })
+		return nil
+	case 'a':
+		n, err := r.ReadByte()
+		if err != nil {
+			return err
+		}
+		p.withReceipt(func(rc *receipt) { rc.justify = n % 48 })
+		return nil
+	case '!':
+		n, err := r.ReadByte()
+		if err != nil {
+			return err
+		}
+		width := 1
+		if n&0x20 != 0 {
+			width = 2
+		}
+		p.withReceipt(func(rc *receipt) { rc.width = width })
+		return nil
+	case 'd':
+		n, err := r.ReadByte()
+		if err != nil {
+			return err
+		}
+		p.withReceipt(func(rc *receipt) {
+			for i := 0; i < int(n); i++ {
+				rc.newLine()
+			}
+		})
+		return nil
+	case 'p':
+		return skip(r, 3)
+	case '$', '\\':
+		return skip(r, 2)
+	case '2', '<':
+		return nil
+	case 'i', 'm':
+		p.cut()
+		return nil
+	default:
+		// ESC E, ESC -, ESC M, ESC t, ESC 3, ESC J, ESC G, ESC {, ESC V, ESC R, ESC SP, ...
+		return skip(r, 1)
+	}
+}
+
+// gsCommand applies a GS command, decoding cuts, character size, barcodes and QR codes
+func (p *Printer) gsCommand(r *bufio.Reader, w io.Writer) error {
+	cmd, err := r.ReadByte()
+	if err != nil {
+		return err
+	}
+
+	switch cmd {
+	case '!':
+		n, err := r.ReadByte()
+		if err != nil {
+			return err
+		}
+		width := int(n>>4) + 1
+		p.withReceipt(func(rc *receipt) { rc.width = width })
+		return nil
+	case 'V':
+		m, err := r.ReadByte()
+		if err != nil {
+			return err
+		}
+		if m == 65 || m == 66 {
+			if err := skip(r, 1); err != nil {
+				return err
+			}
+		}
+		p.cut()
+		return nil
+	case 'k':
+		return p.barcode(r)
+	case '(':
+		return p.function(r)
+	case 'r':
+		if err := skip(r, 1); err != nil {
+			return err
+		}
+		_, err := w.Write([]byte{0x00})
+		return err
+	case 'v':
+		// GS v 0 m xL xH yL yH d1...dk raster image
+		header := make([]byte, 6)
+		if _, err := io.ReadFull(r, header); err != nil {
+			return err
+		}
+		width := int(header[2]) + int(header[3])<<8
+		height := int(header[4]) + int(header[5])<<8
+		p.withReceipt(func(rc *receipt) { rc.writeLine("[IMAGE]") })
+		return skip(r, width*height)
+	case 'L', 'W', 'P', '$', '\\':
+		return skip(r, 2)
+	default:
+		// GS B, GS H, GS h, GS w, GS f, GS a, ...
+		return skip(r, 1)
+	}
+}
+
+// barcode decodes GS k in both the NUL-terminated and the length-prefixed forms
+func (p *Printer) barcode(r *bufio.Reader) error {
+	m, err := r.ReadByte()
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+	if m <= 6 {
+		data, err = r.ReadBytes(nul)
+		if err != nil {
+			return err
+		}
+		data = data[:len(data)-1]
+	} else {
+		n, err := r.ReadByte()
+		if err != nil {
+			return err
+		}
+		data = make([]byte, n)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return err
+		}
+	}
+
+	// CODE128 data starts with a code set selector such as "{B"
+	text := string(data)
+	if m == 73 && len(text) >= 2 && text[0] == '{' {
+		text = text[2:]
+	}
+	p.withReceipt(func(rc *receipt) { rc.writeLine(fmt.Sprintf("[BARCODE %s]", text)) })
+	return nil
+}
+
+// function decodes GS ( k for QR codes and skips the other GS ( functions
+func (p *Printer) function(r *bufio.Reader) error {
+	fn, err := r.ReadByte()
+	if err != nil {
+		return err
+	}
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return err
+	}
+	payload := make([]byte, int(header[0])+int(header[1])<<8)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return err
+	}
+
+	// GS ( k cn=49 fn=80 m=48 stores the QR data; fn=81 prints it
+	if fn != 'k' || len(payload) < 3 || payload[0] != 49 {
+		return nil
+	}
+	switch payload[1] {
+	case 80:
+		data := string(payload[3:])
+		p.withReceipt(func(rc *receipt) { rc.qr = data })
+	case 81:
+		p.withReceipt(func(rc *receipt) { rc.writeLine(fmt.Sprintf("[QR %s]", rc.qr)) })
+	}
+	return nil
+}
+
+func skip(r *bufio.Reader, n int) error {
+	_, err := r.Discard(n)
+	return err
+}
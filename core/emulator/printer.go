@@ -0,0 +1,231 @@
+package emulator
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// DefaultAddress is the local endpoint the emulator listens on when none is configured
+const DefaultAddress = "127.0.0.1:9101"
+
+// Printer is an emulated ESC/POS network printer. It answers the DLE EOT status queries with the
+// scripted Status and decodes everything it receives into plain-text receipts.
+type Printer struct {
+	mu       sync.Mutex
+	listener net.Listener
+	status   Status
+	receipts []string
+	current  *receipt
+	conns    map[net.Conn]struct{}
+	wg       sync.WaitGroup
+}
+
+// New creates an emulated printer with the given initial status
+func New(status Status) *Printer {
+	return &Printer{
+		status:  status,
+		current: newReceipt(),
+		conns:   map[net.Conn]struct{}{},
+	}
+}
+
+// Start listens on address (DefaultAddress when empty) and serves connections in the background
+func (p *Printer) Start(address string) error {
+	if address == "" {
+		address = DefaultAddress
+	}
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return fmt.Errorf("printer emulator: listen on %q: %w", address, err)
+	}
+
+	p.mu.Lock()
+	p.listener = listener
+	p.mu.Unlock()
+
+	p.wg.Add(1)
+	go p.serve(listener)
+
+	return nil
+}
+
+// Addr returns the address the emulator is listening on
+func (p *Printer) Addr() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.listener == nil {
+		return ""
+	}
+	return p.listener.Addr().String()
+}
+
+// Close stops the emulator, drops the open connections and waits for their handlers to return
+func (p *Printer) Close() error {
+	p.mu.Lock()
+	listener := p.listener
+	p.listener = nil
+	p.mu.Unlock()
+
+	if listener == nil {
+		return nil
+	}
+
+	err := listener.Close()
+
+	// A client that keeps its connection open would otherwise block the handler, and Close, forever
+	p.mu.Lock()
+	for conn := range p.conns {
+		conn.Close()
+	}
+	p.mu.Unlock()
+
+	p.wg.Wait()
+	return err
+}
+
+// SetStatus replaces the scripted printer status
+func (p *Printer) SetStatus(status Status) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.status = status
+}
+
+// Status returns the current printer status
+func (p *Printer) Status() Status {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.status
+}
+
+// Receipts returns the transcripts of every receipt cut so far, plus any uncut trailing output
+func (p *Printer) Receipts() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	receipts := append([]string(nil), p.receipts...)
+	if pending := p.current.String(); pending != "" {
+		receipts = append(receipts, pending)
+	}
+	return receipts
+}
+
+// Reset clears the recorded receipts
+func (p *Printer) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.receipts = nil
+	p.current = newReceipt()
+}
+
+func (p *Printer) serve(listener net.Listener) {
+	defer p.wg.Done()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				zap.L().Warn("printer emulator accept failed", zap.Error(err))
+			}
+			return
+		}
+
+		if !p.track(conn) {
+			conn.Close()
+			return
+		}
+
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			defer p.untrack(conn)
+			if err := p.handle(conn); err != nil && !errors.Is(err, io.EOF) {
+				zap.L().Debug("printer emulator connection closed", zap.Error(err))
+			}
+		}()
+	}
+}
+
+// track records an accepted connection for Close to drop. It reports false once the emulator is closing.
+func (p *Printer) track(conn net.Conn) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.listener == nil {
+		return false
+	}
+	p.conns[conn] = struct{}{}
+	return true
+}
+
+// untrack closes a connection and forgets it
+func (p *Printer) untrack(conn net.Conn) {
+	p.mu.Lock()
+	delete(p.conns, conn)
+	p.mu.Unlock()
+	conn.Close()
+}
+
+// handle decodes one connection's byte stream until the client disconnects or the paper runs out
+func (p *Printer) handle(conn net.Conn) error {
+	r := bufio.NewReader(conn)
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return err
+		}
+
+		switch b {
+		case dle:
+			if err := p.realtime(r, conn); err != nil {
+				return err
+			}
+		case esc:
+			if err := p.escCommand(r); err != nil {
+				return err
+			}
+		case gs:
+			if err := p.gsCommand(r, conn); err != nil {
+				return err
+			}
+		case lf:
+			p.withReceipt(func(rc *receipt) { rc.newLine() })
+		case cr, ht, nul:
+		default:
+			if p.Status().PaperEnd {
+				return errPaperEnd
+			}
+			p.withReceipt(func(rc *receipt) { rc.write(b) })
+		}
+	}
+}
+
+// errPaperEnd drops the connection the way a printer without paper stops accepting data
+var errPaperEnd = errors.New("paper end")
+
+func (p *Printer) withReceipt(fn func(rc *receipt)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	fn(p.current)
+}
+
+// cut closes the current receipt and applies the scripted paper countdown
+func (p *Printer) cut() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.receipts = append(p.receipts, p.current.String())
+	p.current = newReceipt()
+
+	if p.status.PaperEndAfterCuts > 0 {
+		p.status.PaperEndAfterCuts--
+		if p.status.PaperEndAfterCuts == 0 {
+			p.status.PaperEnd = true
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package emulator
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+// send writes data to a running emulator and waits for it to hang up, so everything was decoded
+func send(t *testing.T, p *Printer, data []byte) {
+	t.Helper()
+
+	conn, err := net.Dial("tcp", p.Addr())
+	if err != nil {
+		t.Fatalf("dial emulator: %v", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write(data); err != nil {
+		t.Fatalf("write to emulator: %v", err)
+	}
+	if err := conn.(*net.TCPConn).CloseWrite(); err != nil {
+		t.Fatalf("close write: %v", err)
+	}
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	if _, err := io.Copy(io.Discard, conn); err != nil {
+		t.Fatalf("wait for emulator: %v", err)
+	}
+}
+
+func startPrinter(t *testing.T, status Status) *Printer {
+	t.Helper()
+
+	p := New(status)
+	if err := p.Start("127.0.0.1:0"); err != nil {
+		t.Fatalf("start emulator: %v", err)
+	}
+	t.Cleanup(func() { p.Close() })
+	return p
+}
+
+func TestRender(t *testing.T) {
+	cut := []byte{gs, 'V', 0}
+	qr := func(data string) []byte {
+		n := len(data) + 3
+		store := append([]byte{gs, '(', 'k', byte(n), byte(n >> 8), 49, 80, 48}, data...)
+		return append(store, gs, '(', 'k', 3, 0, 49, 81, 48)
+	}
+
+	tests := []struct {
+		name string
+		data [][]byte
+		want []string
+	}{
+		{
+			name: "plain lines",
+			data: [][]byte{[]byte("HELLO\nWORLD\n"), cut},
+			want: []string{"HELLO\nWORLD\n"},
+		},
+		{
+			name: "centered and right justified",
+			data: [][]byte{{esc, 'a', 1}, []byte("ABCD\n"), {esc, 'a', 2}, []byte("XY\n"), cut},
+			want: []string{"              ABCD\n                              XY\n"},
+		},
+		{
+			name: "double width halves the columns",
+			data: [][]byte{{gs, '!', 0x10}, {esc, 'a', 1}, []byte("TOTAL\n"), cut},
+			want: []string{"     TOTAL\n"},
+		},
+		{
+			name: "reset clears justification",
+			data: [][]byte{{esc, 'a', 2}, {esc, '@'}, []byte("LEFT\n"), cut},
+			want: []string{"LEFT\n"},
+		},
+		{
+			name: "barcode and qr placeholders",
+			data: [][]byte{
+				{gs, 'k', 73, 8, '{', 'B', '1', '2', '-', 'A', 'B', 'C'},
+				qr("NT2.body.sig"),
+				cut,
+			},
+			want: []string{"[BARCODE 12-ABC]\n[QR NT2.body.sig]\n"},
+		},
+		{
+			name: "feed lines and partial cut",
+			data: [][]byte{[]byte("A"), {esc, 'd', 2}, []byte("B\n"), {gs, 'V', 66, 3}},
+			want: []string{"A\n\nB\n"},
+		},
+		{
+			name: "two receipts",
+			data: [][]byte{[]byte("ONE\n"), cut, []byte("TWO\n"), cut},
+			want: []string{"ONE\n", "TWO\n"},
+		},
+		{
+			name: "uncut trailing output",
+			data: [][]byte{[]byte("ONE\n"), cut, []byte("PENDING")},
+			want: []string{"ONE\n", "PENDING\n"},
+		},
+		{
+			name: "status queries print nothing",
+			data: [][]byte{{dle, eot, 1}, {dle, eot, 4}, []byte("OK\n"), cut},
+			want: []string{"OK\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := startPrinter(t, Status{})
+			send(t, p, bytes.Join(tt.data, nil))
+
+			got := p.Receipts()
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d receipts %q, want %d %q", len(got), got, len(tt.want), tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("receipt %d = %q, want %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRealtimeAnswers(t *testing.T) {
+	p := startPrinter(t, Status{PaperEnd: true})
+
+	conn, err := net.Dial("tcp", p.Addr())
+	if err != nil {
+		t.Fatalf("dial emulator: %v", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(2 * time.Second))
+
+	if _, err := conn.Write([]byte{dle, eot, realtimeStatusPaperEnd, dle, eot, realtimeStatusPrinter}); err != nil {
+		t.Fatalf("write status queries: %v", err)
+	}
+	answer := make([]byte, 2)
+	if _, err := io.ReadFull(conn, answer); err != nil {
+		t.Fatalf("read status answers: %v", err)
+	}
+	if want := []byte{0x72, 0x1a}; !bytes.Equal(answer, want) {
+		t.Errorf("answers = %#v, want %#v", answer, want)
+	}
+}
+
+func TestPaperEndAfterCuts(t *testing.T) {
+	p := startPrinter(t, Status{PaperEndAfterCuts: 1})
+	send(t, p, []byte("ONE\n\x1dV\x00TWO\n"))
+
+	if got := p.Receipts(); len(got) != 1 || got[0] != "ONE\n" {
+		t.Errorf("receipts = %q, want only the first", got)
+	}
+	if !p.Status().PaperEnd {
+		t.Error("paper end not reported after the last cut")
+	}
+}
+
+func TestCloseDropsOpenConnections(t *testing.T) {
+	p := New(Status{})
+	if err := p.Start("127.0.0.1:0"); err != nil {
+		t.Fatalf("start emulator: %v", err)
+	}
+
+	conn, err := net.Dial("tcp", p.Addr())
+	if err != nil {
+		t.Fatalf("dial emulator: %v", err)
+	}
+	defer conn.Close()
+	// The connection is idle and never closed by the client
+	if _, err := conn.Write([]byte("IDLE")); err != nil {
+		t.Fatalf("write to emulator: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	done := make(chan error, 1)
+	go func() { done <- p.Close() }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Close() = %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Close blocked on an open connection")
+	}
+}
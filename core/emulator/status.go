@@ -0,0 +1,85 @@
+// Package emulator provides an ESC/POS network printer emulator for offline development
+package emulator
+
+// Status is the scriptable state reported by the emulated printer
+type Status struct {
+	// Offline makes the printer report itself as offline
+	Offline bool `json:"offline"`
+	// CoverOpen makes the printer report an open cover
+	CoverOpen bool `json:"cover_open"`
+	// PaperNearEnd makes the printer report the paper roll near its end
+	PaperNearEnd bool `json:"paper_near_end"`
+	// PaperEnd makes the printer report the paper roll end and refuse print data
+	PaperEnd bool `json:"paper_end"`
+	// CutterError makes the printer report an auto-cutter error
+	CutterError bool `json:"cutter_error"`
+	// Unrecoverable makes the printer report an unrecoverable error
+	Unrecoverable bool `json:"unrecoverable"`
+	// AutoRecoverable makes the printer report an auto-recoverable error
+	AutoRecoverable bool `json:"auto_recoverable"`
+	// PaperEndAfterCuts runs out of paper after this many more cuts. Zero disables it.
+	PaperEndAfterCuts int `json:"paper_end_after_cuts"`
+}
+
+const (
+	statusFixedBits byte = 0x12
+
+	statusOffline byte = 0x08
+
+	offlineCoverOpen       byte = 0x04
+	offlinePaperStop       byte = 0x20
+	offlineErrorOccurred   byte = 0x40
+	errorAutoCutter        byte = 0x08
+	errorUnrecoverable     byte = 0x20
+	errorAutoRecoverable   byte = 0x40
+	paperSensorNearEnd     byte = 0x0c
+	paperSensorRollEnd     byte = 0x60
+	realtimeStatusPrinter  byte = 1
+	realtimeStatusOffline  byte = 2
+	realtimeStatusError    byte = 3
+	realtimeStatusPaperEnd byte = 4
+)
+
+// hasError reports whether any error condition is set
+func (s Status) hasError() bool {
+	return s.CutterError || s.Unrecoverable || s.AutoRecoverable
+}
+
+// realtimeStatus encodes the DLE EOT n response byte for the status
+func (s Status) realtimeStatus(n byte) byte {
+	b := statusFixedBits
+	switch n {
+	case realtimeStatusPrinter:
+		if s.Offline || s.CoverOpen || s.PaperEnd || s.hasError() {
+			b |= statusOffline
+		}
+	case realtimeStatusOffline:
+		if s.CoverOpen {
+			b |= offlineCoverOpen
+		}
+		if s.PaperEnd {
+			b |= offlinePaperStop
+		}
+		if s.hasError() {
+			b |= offlineErrorOccurred
+		}
+	case realtimeStatusError:
+		if s.CutterError {
+			b |= errorAutoCutter
+		}
+		if s.Unrecoverable {
+			b |= errorUnrecoverable
+		}
+		if s.AutoRecoverable {
+			b |= errorAutoRecoverable
+		}
+	case realtimeStatusPaperEnd:
+		if s.PaperNearEnd {
+			b |= paperSensorNearEnd
+		}
+		if s.PaperEnd {
+			b |= paperSensorRollEnd
+		}
+	}
+	return b
+}
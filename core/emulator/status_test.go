@@ -0,0 +1,41 @@
+package emulator
+
+import "testing"
+
+func TestRealtimeStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		status Status
+		n      byte
+		want   byte
+	}{
+		{"printer ready", Status{}, realtimeStatusPrinter, 0x12},
+		{"printer offline", Status{Offline: true}, realtimeStatusPrinter, 0x1a},
+		{"printer cover open", Status{CoverOpen: true}, realtimeStatusPrinter, 0x1a},
+		{"printer paper end", Status{PaperEnd: true}, realtimeStatusPrinter, 0x1a},
+		{"printer cutter error", Status{CutterError: true}, realtimeStatusPrinter, 0x1a},
+		{"printer near end is still online", Status{PaperNearEnd: true}, realtimeStatusPrinter, 0x12},
+		{"offline ready", Status{}, realtimeStatusOffline, 0x12},
+		{"offline cover open", Status{CoverOpen: true}, realtimeStatusOffline, 0x16},
+		{"offline paper stop", Status{PaperEnd: true}, realtimeStatusOffline, 0x32},
+		{"offline error", Status{Unrecoverable: true}, realtimeStatusOffline, 0x52},
+		{"offline everything", Status{CoverOpen: true, PaperEnd: true, AutoRecoverable: true}, realtimeStatusOffline, 0x76},
+		{"error none", Status{}, realtimeStatusError, 0x12},
+		{"error auto-cutter", Status{CutterError: true}, realtimeStatusError, 0x1a},
+		{"error unrecoverable", Status{Unrecoverable: true}, realtimeStatusError, 0x32},
+		{"error auto-recoverable", Status{AutoRecoverable: true}, realtimeStatusError, 0x52},
+		{"paper present", Status{}, realtimeStatusPaperEnd, 0x12},
+		{"paper near end", Status{PaperNearEnd: true}, realtimeStatusPaperEnd, 0x1e},
+		{"paper roll end", Status{PaperEnd: true}, realtimeStatusPaperEnd, 0x72},
+		{"paper near and roll end", Status{PaperNearEnd: true, PaperEnd: true}, realtimeStatusPaperEnd, 0x7e},
+		{"unknown status request", Status{Offline: true, PaperEnd: true}, 5, 0x12},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.status.realtimeStatus(tt.n); got != tt.want {
+				t.Errorf("realtimeStatus(%d) = %#02x, want %#02x", tt.n, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package helpers
+
+import (
+	"strings"
+	"unicode"
+
+	"neon/core/helpers/enums"
+)
+
+// NormalizeIDNumber removes the separators people type in identification numbers (1-0234-0567,
+// 1 0234 0567) and upper-cases passport letters
+func NormalizeIDNumber(id string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(id) {
+		if r == '-' || r == '.' || unicode.IsSpace(r) {
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// ValidateIDNumber checks an identification number against the format of its document type and returns
+// it normalized. Physical cédulas have 9 digits and start with the province (1 to 9); DIMEX have 11 or
+// 12 digits and don't start with 0; passports have 6 to 20 letters and digits.
+func ValidateIDNumber(idType enums.IDType, id string) (string, error) {
+	normalized := NormalizeIDNumber(id)
+
+	switch idType {
+	case enums.IDPhysical:
+		if len(normalized) != 9 || !isDigits(normalized) || normalized[0] == '0' {
+			return "", ErrInvalidIDNumber
+		}
+	case enums.IDDimex:
+		if (len(normalized) != 11 && len(normalized) != 12) || !isDigits(normalized) || normalized[0] == '0' {
+			return "", ErrInvalidIDNumber
+		}
+	case enums.IDPassport:
+		if len(normalized) < 6 || len(normalized) > 20 || !isAlphanumeric(normalized) {
+			return "", ErrInvalidIDNumber
+		}
+	default:
+		return "", ErrInvalidIDNumber
+	}
+
+	return normalized, nil
+}
+
+// DetectIDType guesses the document type of an identification number from its format: 9 digits is a
+// physical cédula, 11 or 12 digits a DIMEX, anything else is validated as a passport
+func DetectIDType(id string) enums.IDType {
+	normalized := NormalizeIDNumber(id)
+	if isDigits(normalized) {
+		switch len(normalized) {
+		case 9:
+			return enums.IDPhysical
+		case 11, 12:
+			return enums.IDDimex
+		}
+	}
+	return enums.IDPassport
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func isAlphanumeric(s string) bool {
+	for _, r := range s {
+		if (r < '0' || r > '9') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,97 @@
+package enums
+
+// Denomination is the value in colones of a bill or coin counted in the cash drawer
+type Denomination int
+
+const (
+	// Bill20000 is the ₡20,000 bill
+	Bill20000 Denomination = 20000
+	// Bill10000 is the ₡10,000 bill
+	Bill10000 Denomination = 10000
+	// Bill5000 is the ₡5,000 bill
+	Bill5000 Denomination = 5000
+	// Bill2000 is the ₡2,000 bill
+	Bill2000 Denomination = 2000
+	// Bill1000 is the ₡1,000 bill
+	Bill1000 Denomination = 1000
+	// Coin500 is the ₡500 coin
+	Coin500 Denomination = 500
+	// Coin100 is the ₡100 coin
+	Coin100 Denomination = 100
+	// Coin50 is the ₡50 coin
+	Coin50 Denomination = 50
+	// Coin25 is the ₡25 coin
+	Coin25 Denomination = 25
+	// Coin10 is the ₡10 coin
+	Coin10 Denomination = 10
+	// Coin5 is the ₡5 coin
+	Coin5 Denomination = 5
+)
+
+// AllDenominations is a list of all the denominations, from the largest bill to the smallest coin
+var AllDenominations = []struct {
+	Value  Denomination
+	TSName string
+}{
+	{Bill20000, "BILL_20000"},
+	{Bill10000, "BILL_10000"},
+	{Bill5000, "BILL_5000"},
+	{Bill2000, "BILL_2000"},
+	{Bill1000, "BILL_1000"},
+	{Coin500, "COIN_500"},
+	{Coin100, "COIN_100"},
+	{Coin50, "COIN_50"},
+	{Coin25, "COIN_25"},
+	{Coin10, "COIN_10"},
+	{Coin5, "COIN_5"},
+}
+
+// IsValid reports whether the denomination is a colón bill or coin in circulation
+func (d Denomination) IsValid() bool {
+	for _, denomination := range AllDenominations {
+		if denomination.Value == d {
+			return true
+		}
+	}
+	return false
+}
+
+// IsCoin reports whether the denomination is a coin rather than a bill
+func (d Denomination) IsCoin() bool {
+	return d < Bill1000
+}
+
+// CashCountStage is the moment of a report at which the drawer was counted
+type CashCountStage string
+
+const (
+	// CountOpening is the opening float counted when the report starts
+	CountOpening CashCountStage = "opening"
+	// CountPartial is the count delivered at a drop (the partial close)
+	CountPartial CashCountStage = "partial"
+	// CountFinal is the count delivered at the total close
+	CountFinal CashCountStage = "final"
+	// CountHandover is the count the outgoing cashier hands over at a shift handover
+	CountHandover CashCountStage = "handover"
+)
+
+// AllCashCountStages is a list of all the cash count stages
+var AllCashCountStages = []struct {
+	Value  CashCountStage
+	TSName string
+}{
+	{CountOpening, "OPENING"},
+	{CountPartial, "PARTIAL"},
+	{CountFinal, "FINAL"},
+	{CountHandover, "HANDOVER"},
+}
+
+// IsValid reports whether the stage is one of the known cash count stages
+func (s CashCountStage) IsValid() bool {
+	for _, stage := range AllCashCountStages {
+		if stage.Value == s {
+			return true
+		}
+	}
+	return false
+}
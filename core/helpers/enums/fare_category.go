@@ -0,0 +1,42 @@
+package enums
+
+// FareCategory is the passenger category a fare is charged for
+type FareCategory string
+
+const (
+	// FareRegular is the full fare
+	FareRegular FareCategory = "regular"
+	// FareGold is the senior citizen (ciudadano de oro) fare
+	FareGold FareCategory = "gold"
+	// FareChild is the fare for children
+	FareChild FareCategory = "child"
+	// FareStudent is the fare for students
+	FareStudent FareCategory = "student"
+	// FareDisability is the fare for passengers with a disability
+	FareDisability FareCategory = "disability"
+	// FareStaff is the fare for the operator's staff
+	FareStaff FareCategory = "staff"
+)
+
+// AllFareCategories is a list of all the fare categories
+var AllFareCategories = []struct {
+	Value  FareCategory
+	TSName string
+}{
+	{FareRegular, "REGULAR"},
+	{FareGold, "GOLD"},
+	{FareChild, "CHILD"},
+	{FareStudent, "STUDENT"},
+	{FareDisability, "DISABILITY"},
+	{FareStaff, "STAFF"},
+}
+
+// IsValid reports whether the category is one of the known fare categories
+func (f FareCategory) IsValid() bool {
+	for _, category := range AllFareCategories {
+		if category.Value == f {
+			return true
+		}
+	}
+	return false
+}
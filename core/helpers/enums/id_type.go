@@ -0,0 +1,23 @@
+package enums
+
+// IDType is the kind of identification document of a passenger
+type IDType string
+
+const (
+	// IDPhysical is the Costa Rican cédula de identidad (9 digits, e.g. 1-0234-0567)
+	IDPhysical IDType = "physical"
+	// IDDimex is the foreign resident document, DIMEX (11 or 12 digits)
+	IDDimex IDType = "dimex"
+	// IDPassport is a passport (6 to 20 letters and digits)
+	IDPassport IDType = "passport"
+)
+
+// AllIDTypes is a list of all the identification document types
+var AllIDTypes = []struct {
+	Value  IDType
+	TSName string
+}{
+	{IDPhysical, "PHYSICAL"},
+	{IDDimex, "DIMEX"},
+	{IDPassport, "PASSPORT"},
+}
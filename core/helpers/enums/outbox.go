@@ -0,0 +1,85 @@
+package enums
+
+// OutboxKind is the kind of document an outbox message delivers to a third party
+type OutboxKind string
+
+const (
+	// OutboxEInvoice is an electronic invoice submitted to Hacienda
+	OutboxEInvoice OutboxKind = "einvoice"
+	// OutboxCardCapture is the capture of a card payment authorized on the payment terminal
+	OutboxCardCapture OutboxKind = "card_capture"
+	// OutboxCardRefund is a refund owed to a card on the payment terminal that charged it
+	OutboxCardRefund OutboxKind = "card_refund"
+	// OutboxCardVoid is the release of a card payment authorized on the payment terminal and voided before
+	// it was captured
+	OutboxCardVoid OutboxKind = "card_void"
+)
+
+// AllOutboxKinds is a list of all the outbox message kinds
+var AllOutboxKinds = []struct {
+	Value  OutboxKind
+	TSName string
+}{
+	{OutboxEInvoice, "EINVOICE"},
+	{OutboxCardCapture, "CARD_CAPTURE"},
+	{OutboxCardRefund, "CARD_REFUND"},
+	{OutboxCardVoid, "CARD_VOID"},
+}
+
+// IsValid reports whether the kind is one of the known outbox message kinds
+func (k OutboxKind) IsValid() bool {
+	for _, kind := range AllOutboxKinds {
+		if kind.Value == k {
+			return true
+		}
+	}
+	return false
+}
+
+// OutboxState is where an outbox message is in its delivery
+type OutboxState string
+
+const (
+	// OutboxPending is waiting to be sent, or to be retried after a failed attempt
+	OutboxPending OutboxState = "pending"
+	// OutboxSent was received by the third party, which hasn't answered yet
+	OutboxSent OutboxState = "sent"
+	// OutboxAccepted was accepted by the third party
+	OutboxAccepted OutboxState = "accepted"
+	// OutboxRejected was rejected by the third party, with a reason
+	OutboxRejected OutboxState = "rejected"
+	// OutboxError was refused as invalid or couldn't be processed by the third party; it waits for an
+	// admin to retry it
+	OutboxError OutboxState = "error"
+	// OutboxCancelled was withdrawn before it was sent, e.g. the capture of a card payment whose sale was
+	// voided first
+	OutboxCancelled OutboxState = "cancelled"
+)
+
+// AllOutboxStates is a list of all the outbox message states
+var AllOutboxStates = []struct {
+	Value  OutboxState
+	TSName string
+}{
+	{OutboxPending, "PENDING"},
+	{OutboxSent, "SENT"},
+	{OutboxAccepted, "ACCEPTED"},
+	{OutboxRejected, "REJECTED"},
+	{OutboxError, "ERROR"},
+	{OutboxCancelled, "CANCELLED"},
+}
+
+// IsValid reports whether the state is one of the known outbox message states
+func (s OutboxState) IsValid() bool {
+	for _, state := range AllOutboxStates {
+		if state.Value == s {
+			return true
+		}
+	}
+	return false
+}
+
+// IsFinal reports whether the message is settled and the worker leaves it alone
+func (s OutboxState) IsFinal() bool {
+	return s == OutboxAccepted || s == OutboxRejected || s == OutboxError || s == OutboxCancelled
+}
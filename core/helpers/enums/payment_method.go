@@ -0,0 +1,38 @@
+package enums
+
+// PaymentMethod is how a sale was paid
+type PaymentMethod string
+
+const (
+	// PaymentCash is paid in cash into the drawer
+	PaymentCash PaymentMethod = "cash"
+	// PaymentCard is paid by debit or credit card
+	PaymentCard PaymentMethod = "card"
+	// PaymentSinpe is paid by a SINPE Móvil transfer
+	PaymentSinpe PaymentMethod = "sinpe"
+)
+
+// AllPaymentMethods is a list of all the payment methods
+var AllPaymentMethods = []struct {
+	Value  PaymentMethod
+	TSName string
+}{
+	{PaymentCash, "CASH"},
+	{PaymentCard, "CARD"},
+	{PaymentSinpe, "SINPE"},
+}
+
+// IsValid reports whether the method is one of the known payment methods
+func (p PaymentMethod) IsValid() bool {
+	for _, method := range AllPaymentMethods {
+		if method.Value == p {
+			return true
+		}
+	}
+	return false
+}
+
+// NeedsReference reports whether payments with the method carry the card voucher or transfer number
+func (p PaymentMethod) NeedsReference() bool {
+	return p == PaymentCard || p == PaymentSinpe
+}
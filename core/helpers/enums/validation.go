@@ -0,0 +1,32 @@
+package enums
+
+// ValidationStatus is the result of validating a scanned ticket
+type ValidationStatus string
+
+const (
+	// ValidationValid is a genuine ticket presented for the first time
+	ValidationValid ValidationStatus = "valid"
+	// ValidationUsed is a genuine ticket that was already validated
+	ValidationUsed ValidationStatus = "used"
+	// ValidationVoided is a genuine ticket that was voided
+	ValidationVoided ValidationStatus = "voided"
+	// ValidationWrongDeparture is a genuine ticket for another date or departure time
+	ValidationWrongDeparture ValidationStatus = "wrong_departure"
+	// ValidationInvalid is a code that was not signed by this installation or doesn't match the sale
+	ValidationInvalid ValidationStatus = "invalid"
+	// ValidationNotFound is a correctly signed code whose ticket is not in the database
+	ValidationNotFound ValidationStatus = "not_found"
+)
+
+// AllValidationStatuses is a list of all the validation statuses
+var AllValidationStatuses = []struct {
+	Value  ValidationStatus
+	TSName string
+}{
+	{ValidationValid, "VALID"},
+	{ValidationUsed, "USED"},
+	{ValidationVoided, "VOIDED"},
+	{ValidationWrongDeparture, "WRONG_DEPARTURE"},
+	{ValidationInvalid, "INVALID"},
+	{ValidationNotFound, "NOT_FOUND"},
+}
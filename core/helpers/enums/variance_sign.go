@@ -0,0 +1,33 @@
+package enums
+
+// VarianceSign is the sign of a closed report's cash variance, the cash received less the cash expected
+type VarianceSign string
+
+const (
+	// VarianceShort is less cash received than expected
+	VarianceShort VarianceSign = "short"
+	// VarianceOver is more cash received than expected
+	VarianceOver VarianceSign = "over"
+	// VarianceEven is the cash received matching the cash expected
+	VarianceEven VarianceSign = "even"
+)
+
+// AllVarianceSigns is a list of all the variance signs
+var AllVarianceSigns = []struct {
+	Value  VarianceSign
+	TSName string
+}{
+	{VarianceShort, "SHORT"},
+	{VarianceOver, "OVER"},
+	{VarianceEven, "EVEN"},
+}
+
+// IsValid reports whether the sign is one of the known variance signs
+func (v VarianceSign) IsValid() bool {
+	for _, sign := range AllVarianceSigns {
+		if sign.Value == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package enums
+
+// VoidReason is a type that represents the reason a ticket was voided
+type VoidReason string
+
+const (
+	// VoidCustomerRequest is used when the passenger asks for a refund
+	VoidCustomerRequest VoidReason = "customer_request"
+	// VoidWrongDestination is used when the ticket was sold for the wrong stop
+	VoidWrongDestination VoidReason = "wrong_destination"
+	// VoidWrongDeparture is used when the ticket was sold for the wrong departure time
+	VoidWrongDeparture VoidReason = "wrong_departure"
+	// VoidPrintError is used when the receipt could not be printed correctly
+	VoidPrintError VoidReason = "print_error"
+	// VoidDuplicate is used when the same ticket was sold twice
+	VoidDuplicate VoidReason = "duplicate"
+	// VoidCancelledTrip is used when the departure was cancelled
+	VoidCancelledTrip VoidReason = "cancelled_trip"
+	// VoidOther is used for any other reason, described in the void note
+	VoidOther VoidReason = "other"
+)
+
+// AllVoidReasons is a list of all the void reasons
+var AllVoidReasons = []struct {
+	Value  VoidReason
+	TSName string
+}{
+	{VoidCustomerRequest, "CUSTOMER_REQUEST"},
+	{VoidWrongDestination, "WRONG_DESTINATION"},
+	{VoidWrongDeparture, "WRONG_DEPARTURE"},
+	{VoidPrintError, "PRINT_ERROR"},
+	{VoidDuplicate, "DUPLICATE"},
+	{VoidCancelledTrip, "CANCELLED_TRIP"},
+	{VoidOther, "OTHER"},
+}
+
+// IsValid reports whether the reason is one of the known void reasons
+func (v VoidReason) IsValid() bool {
+	for _, reason := range AllVoidReasons {
+		if reason.Value == v {
+			return true
+		}
+	}
+	return false
+}
@@ -34,3 +34,186 @@ var ErrTicketNotBelongToReport = errors.New("TICKET_NOT_BELONG_TO_REPORT")
 
 // ErrRouteIsEmpty is the error returned when a route is empty
 var ErrRouteIsEmpty = errors.New("ROUTE_IS_EMPTY")
+
+// ErrInvalidVoidReason is the error returned when a ticket void has an unknown reason code
+var ErrInvalidVoidReason = errors.New("INVALID_VOID_REASON")
+
+// ErrVoidApprovalRequired is the error returned when a ticket void needs an admin's approval
+var ErrVoidApprovalRequired = errors.New("VOID_APPROVAL_REQUIRED")
+
+// ErrVoidApproverNotAdmin is the error returned when the user approving a void is not an admin
+var ErrVoidApproverNotAdmin = errors.New("VOID_APPROVER_NOT_ADMIN")
+
+// ErrTicketNotNullified is the error returned when a void slip is requested for a ticket that is not nullified
+var ErrTicketNotNullified = errors.New("TICKET_NOT_NULLIFIED")
+
+// ErrTicketSigningKeyMissing is the error returned when ticket codes are validated without a signing key configured
+var ErrTicketSigningKeyMissing = errors.New("TICKET_SIGNING_KEY_MISSING")
+
+// ErrInvalidTicketCode is the error returned when a ticket code is malformed or was not signed by this installation
+var ErrInvalidTicketCode = errors.New("INVALID_TICKET_CODE")
+
+// ErrTicketWrongDeparture is the error returned when a ticket is presented for another date or departure time
+var ErrTicketWrongDeparture = errors.New("TICKET_WRONG_DEPARTURE")
+
+// ErrTicketWrongRoute is the error returned when a ticket is presented on another route
+var ErrTicketWrongRoute = errors.New("TICKET_WRONG_ROUTE")
+
+// ErrTicketAlreadyBoarded is the error returned when a ticket that already boarded is presented again
+var ErrTicketAlreadyBoarded = errors.New("TICKET_ALREADY_BOARDED")
+
+// ErrDepartureFull is the error returned when a sale needs more seats than a departure has left
+var ErrDepartureFull = errors.New("DEPARTURE_FULL")
+
+// ErrInvalidTravelDate is the error returned when a ticket's travel date is in the past or beyond the advance sale window
+var ErrInvalidTravelDate = errors.New("INVALID_TRAVEL_DATE")
+
+// ErrDepartureNotInTimetable is the error returned when a ticket's departure time is not in the route's timetable for its travel date
+var ErrDepartureNotInTimetable = errors.New("DEPARTURE_NOT_IN_TIMETABLE")
+
+// ErrInvalidSeat is the error returned when a seat number is not in the route's bus layout or is blocked
+var ErrInvalidSeat = errors.New("INVALID_SEAT")
+
+// ErrSeatTaken is the error returned when a seat is already booked on the departure
+var ErrSeatTaken = errors.New("SEAT_TAKEN")
+
+// ErrInvalidIDNumber is the error returned when an identification number doesn't match its document format
+var ErrInvalidIDNumber = errors.New("INVALID_ID_NUMBER")
+
+// ErrGoldIDRequired is the error returned when a gold ticket is sold without the passenger's identification number
+var ErrGoldIDRequired = errors.New("GOLD_ID_REQUIRED")
+
+// ErrGoldPassengerNotRegistered is the error returned when a gold ticket is sold to an ID missing from the gold registry
+var ErrGoldPassengerNotRegistered = errors.New("GOLD_PASSENGER_NOT_REGISTERED")
+
+// ErrGoldPassengerInactive is the error returned when a gold ticket is sold to an inactive or expired registration
+var ErrGoldPassengerInactive = errors.New("GOLD_PASSENGER_INACTIVE")
+
+// ErrGoldDailyLimit is the error returned when an ID already has the maximum gold tickets for the travel date
+var ErrGoldDailyLimit = errors.New("GOLD_DAILY_LIMIT")
+
+// ErrGoldDepartureLimit is the error returned when an ID already has the maximum gold tickets for the departure
+var ErrGoldDepartureLimit = errors.New("GOLD_DEPARTURE_LIMIT")
+
+// ErrInvalidFareCategory is the error returned when a ticket or quote names an unknown fare category
+var ErrInvalidFareCategory = errors.New("INVALID_FARE_CATEGORY")
+
+// ErrStopNotFound is the error returned when a stop is not part of the route
+var ErrStopNotFound = errors.New("STOP_NOT_FOUND")
+
+// ErrFareNotAvailable is the error returned when a stop has no fare for the passenger category
+var ErrFareNotAvailable = errors.New("FARE_NOT_AVAILABLE")
+
+// ErrInvalidEffectiveDate is the error returned when a tariff change doesn't take effect on a future date
+var ErrInvalidEffectiveDate = errors.New("INVALID_EFFECTIVE_DATE")
+
+// ErrSaleAlreadyNullified is the error returned when a sale is already voided
+var ErrSaleAlreadyNullified = errors.New("SALE_ALREADY_NULLIFIED")
+
+// ErrInvalidPaymentMethod is the error returned when a payment's method is not a known payment method
+var ErrInvalidPaymentMethod = errors.New("INVALID_PAYMENT_METHOD")
+
+// ErrInvalidPaymentAmount is the error returned when a payment's amount is not positive
+var ErrInvalidPaymentAmount = errors.New("INVALID_PAYMENT_AMOUNT")
+
+// ErrPaymentTotalMismatch is the error returned when a sale's payments don't add up to its total
+var ErrPaymentTotalMismatch = errors.New("PAYMENT_TOTAL_MISMATCH")
+
+// ErrInsufficientTendered is the error returned when the cash tendered is less than the cash payment
+var ErrInsufficientTendered = errors.New("INSUFFICIENT_TENDERED")
+
+// ErrPaymentReferenceRequired is the error returned when a card or SINPE Móvil payment has no reference
+var ErrPaymentReferenceRequired = errors.New("PAYMENT_REFERENCE_REQUIRED")
+
+// ErrPaymentDeclined is the error returned when the card terminal declines a card payment
+var ErrPaymentDeclined = errors.New("PAYMENT_DECLINED")
+
+// ErrPaymentTimeout is the error returned when the card terminal doesn't answer in time
+var ErrPaymentTimeout = errors.New("PAYMENT_TIMEOUT")
+
+// ErrPaymentSimulatorDisabled is the error returned when the payment simulator is used but not enabled
+var ErrPaymentSimulatorDisabled = errors.New("PAYMENT_SIMULATOR_DISABLED")
+
+// ErrEInvoiceDisabled is the error returned when a Factura Electrónica is requested but e-invoicing is off
+var ErrEInvoiceDisabled = errors.New("EINVOICE_DISABLED")
+
+// ErrEInvoiceUnavailable is the error returned when e-invoicing is on but its signing key or settings failed
+// to load, so no sale can get its electronic invoice
+var ErrEInvoiceUnavailable = errors.New("EINVOICE_UNAVAILABLE")
+
+// ErrInvalidInvoiceReceiver is the error returned when the receiver of a Factura Electrónica has no name or
+// an identification that doesn't match its type
+var ErrInvalidInvoiceReceiver = errors.New("INVALID_INVOICE_RECEIVER")
+
+// ErrEInvoiceMockDisabled is the error returned when the recepción mock is used but not enabled
+var ErrEInvoiceMockDisabled = errors.New("EINVOICE_MOCK_DISABLED")
+
+// ErrInvalidCashCount is the error returned when a cash count has an unknown or repeated denomination or a
+// negative quantity
+var ErrInvalidCashCount = errors.New("INVALID_CASH_COUNT")
+
+// ErrInvalidOpeningFloat is the error returned when a report's opening float is negative
+var ErrInvalidOpeningFloat = errors.New("INVALID_OPENING_FLOAT")
+
+// ErrFloatApprovalRequired is the error returned when an opening float needs an admin's confirmation and
+// no credentials were given
+var ErrFloatApprovalRequired = errors.New("FLOAT_APPROVAL_REQUIRED")
+
+// ErrFloatApproverNotAdmin is the error returned when the user confirming an opening float is not an admin
+var ErrFloatApproverNotAdmin = errors.New("FLOAT_APPROVER_NOT_ADMIN")
+
+// ErrBlindClose is the error returned when the full report is asked for while the installation closes
+// blind; the cashier gets the open report without the expected cash from GetCashierReport
+var ErrBlindClose = errors.New("BLIND_CLOSE")
+
+// ErrRecountRequired is the error returned when a blind close's count can't be changed or the report can't
+// go on because a close waits for an admin recount
+var ErrRecountRequired = errors.New("RECOUNT_REQUIRED")
+
+// ErrInvalidRecount is the error returned when the recounted close hasn't been recorded or the recounted
+// cash is negative
+var ErrInvalidRecount = errors.New("INVALID_RECOUNT")
+
+// ErrRecountReasonRequired is the error returned when a recount is submitted without a reason
+var ErrRecountReasonRequired = errors.New("RECOUNT_REASON_REQUIRED")
+
+// ErrRecountApprovalRequired is the error returned when a recount is submitted without an admin's credentials
+var ErrRecountApprovalRequired = errors.New("RECOUNT_APPROVAL_REQUIRED")
+
+// ErrRecountApproverNotAdmin is the error returned when the user recounting a close is not an admin
+var ErrRecountApproverNotAdmin = errors.New("RECOUNT_APPROVER_NOT_ADMIN")
+
+// ErrHandoverPending is the error returned when a report can't sell, close or be handed over again because
+// its drawer waits for the incoming cashier to accept a handover
+var ErrHandoverPending = errors.New("HANDOVER_PENDING")
+
+// ErrNoHandoverPending is the error returned when a handover is accepted or cancelled but the report has
+// none waiting
+var ErrNoHandoverPending = errors.New("NO_HANDOVER_PENDING")
+
+// ErrNotReportCashier is the error returned when a report is handed over by someone other than its current
+// cashier
+var ErrNotReportCashier = errors.New("NOT_REPORT_CASHIER")
+
+// ErrHandoverSameCashier is the error returned when the outgoing cashier tries to accept their own handover
+var ErrHandoverSameCashier = errors.New("HANDOVER_SAME_CASHIER")
+
+// ErrInvalidHandover is the error returned when a handover's cash is negative or its report is closed
+var ErrInvalidHandover = errors.New("INVALID_HANDOVER")
+
+// ErrInvalidDrop is the error returned when a drop's cash is negative or its report is closed
+var ErrInvalidDrop = errors.New("INVALID_DROP")
+
+// ErrInvalidBusinessDate is the error returned when a daily summary's date isn't a YYYY-MM-DD date
+var ErrInvalidBusinessDate = errors.New("INVALID_BUSINESS_DATE")
+
+// ErrDayReportsOpen is the error returned when a day's Z-report is printed before all of its reports are
+// totally closed
+var ErrDayReportsOpen = errors.New("DAY_REPORTS_OPEN")
+
+// ErrOutboxMessageNotRetryable is the error returned when an outbox message is retried but wasn't refused
+// with an error
+var ErrOutboxMessageNotRetryable = errors.New("OUTBOX_MESSAGE_NOT_RETRYABLE")
+
+// ErrPrinterEmulatorDisabled is the error returned when the printer emulator is used but not enabled
+var ErrPrinterEmulatorDisabled = errors.New("PRINTER_EMULATOR_DISABLED")
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"neon/core/helpers/enums"
+)
+
+// TicketBoarding records the single time a ticket was used to board
+type TicketBoarding struct {
+	ID        int64  `json:"id" db:"id" goqu:"omitempty,skipinsert"`
+	TicketID  int64  `json:"ticket_id" db:"ticket_id"`
+	Device    string `json:"device" db:"device"`
+	BoardedAt string `json:"boarded_at" db:"boarded_at"`
+}
+
+// TicketValidation is the outcome of validating a scanned ticket code
+type TicketValidation struct {
+	Status   enums.ValidationStatus `json:"status"`
+	Ticket   *Ticket                `json:"ticket"`
+	Boarding *TicketBoarding        `json:"boarding"`
+}
+
+// BoardingRequest is an inspector's request to board a ticket on a departure
+type BoardingRequest struct {
+	// Code is the ticket ID typed by the inspector, or the scanned QR payload or barcode
+	Code        string `json:"code"`
+	Departure   string `json:"departure"`
+	Destination string `json:"destination"`
+	// Time is the departure being boarded, HH:MM
+	Time   string `json:"time"`
+	Device string `json:"device"`
+}
+
+// ManifestEntry is a ticket sold for a route with its boarding, if any
+type ManifestEntry struct {
+	TicketID   int64   `json:"ticket_id"`
+	Time       string  `json:"time"`
+	IsGold     bool    `json:"is_gold"`
+	CreatedAt  string  `json:"created_at"`
+	TravelDate string  `json:"travel_date"`
+	BoardedAt  *string `json:"boarded_at"`
+}
+
+// DepartureManifest compares the tickets sold for a departure time with the passengers carried
+type DepartureManifest struct {
+	Time       string `json:"time"`
+	Sold       int    `json:"sold"`
+	Gold       int    `json:"gold"`
+	Boarded    int    `json:"boarded"`
+	NotBoarded int    `json:"not_boarded"`
+}
+
+// Manifest is the per-departure manifest of a route on a date
+type Manifest struct {
+	Departure   string              `json:"departure"`
+	Destination string              `json:"destination"`
+	Date        string              `json:"date"`
+	Departures  []DepartureManifest `json:"departures"`
+	Sold        int                 `json:"sold"`
+	Boarded     int                 `json:"boarded"`
+}
@@ -0,0 +1,121 @@
+package models
+
+import (
+	"neon/core/helpers/enums"
+)
+
+// DailySummary is the consolidated Z-report of a business day: every report started that day, across all
+// cashiers, with their tickets. Tickets and Cash leave the voided tickets out; they are in Null and
+// NullCash.
+type DailySummary struct {
+	Date    string `json:"date"`
+	Reports int    `json:"reports"`
+	// OpenReports are the reports of the day not totally closed yet, whose figures can still change
+	OpenReports int `json:"open_reports"`
+	Sales       int `json:"sales"`
+	Tickets     int `json:"tickets"`
+	Cash        int `json:"cash"`
+	Gold        int `json:"gold"`
+	GoldCash    int `json:"gold_cash"`
+	Regular     int `json:"regular"`
+	RegularCash int `json:"regular_cash"`
+	Null        int `json:"null"`
+	NullCash    int `json:"null_cash"`
+	// OpeningFloat, Expected, Received and Difference reconcile the drawers of the closed reports: the
+	// cash expected from the cash payments, and the cash delivered at the drops and total closes net of
+	// the opening floats
+	OpeningFloat int                   `json:"opening_float"`
+	Expected     int                   `json:"expected"`
+	Received     int                   `json:"received"`
+	Difference   int                   `json:"difference"`
+	Routes       []DailyRouteTotal     `json:"routes"`
+	Stops        []DailyStopTotal      `json:"stops"`
+	Departures   []DailyDepartureTotal `json:"departures"`
+	Cashiers     []DailyCashierTotal   `json:"cashiers"`
+	Payments     []DailyPaymentTotal   `json:"payments"`
+	// ZReport is the day's Z-report, nil until it is first printed
+	ZReport *ZReport `json:"z_report"`
+}
+
+// DailyRouteTotal is the tickets sold and cash taken on a route in a day
+type DailyRouteTotal struct {
+	Departure   string `json:"departure"`
+	Destination string `json:"destination"`
+	Tickets     int    `json:"tickets"`
+	Cash        int    `json:"cash"`
+}
+
+// DailyStopTotal is the tickets sold and cash taken for a stop of a route in a day
+type DailyStopTotal struct {
+	Departure   string `json:"departure"`
+	Destination string `json:"destination"`
+	Stop        string `json:"stop"`
+	Tickets     int    `json:"tickets"`
+	Cash        int    `json:"cash"`
+}
+
+// DailyDepartureTotal is the tickets sold and cash taken for a departure time of a route in a day
+type DailyDepartureTotal struct {
+	Departure   string `json:"departure"`
+	Destination string `json:"destination"`
+	Time        string `json:"time"`
+	Tickets     int    `json:"tickets"`
+	Cash        int    `json:"cash"`
+}
+
+// DailyCashierTotal is a cashier's day: the reports they had a shift on, the tickets they sold and the
+// cash variance of their shifts. A shift's variance is how much the drawer's difference changed over it,
+// from the count it took over to the count it handed over or the total close; the last shift of an open
+// report has none yet.
+type DailyCashierTotal struct {
+	Username string `json:"username"`
+	Reports  int    `json:"reports"`
+	Tickets  int    `json:"tickets"`
+	Cash     int    `json:"cash"`
+	Variance int    `json:"variance"`
+}
+
+// DailyPaymentTotal is the payments and amount taken with a payment method in a day, net of refunds
+type DailyPaymentTotal struct {
+	Method   enums.PaymentMethod `json:"method"`
+	Payments int                 `json:"payments"`
+	Amount   int                 `json:"amount"`
+}
+
+// TicketTotal is the tickets of a group of reports sharing a route, stop, departure time, seller and kind,
+// with their fares
+type TicketTotal struct {
+	Departure   string
+	Destination string
+	Stop        string
+	Time        string
+	Username    string
+	IsGold      bool
+	IsNull      bool
+	Tickets     int
+	Cash        int
+}
+
+// ZReport is the sequential number given to a business day's summary when it is first printed. Version
+// goes up each time the day's figures have changed since the last print.
+type ZReport struct {
+	ID           int64  `json:"id" db:"id" goqu:"omitempty,skipinsert"`
+	Number       int    `json:"number" db:"number"`
+	BusinessDate string `json:"business_date" db:"business_date"`
+	Version      int    `json:"version" db:"version"`
+	// Fingerprint is a digest of the figures last printed, to tell when they change
+	Fingerprint string `json:"fingerprint" db:"fingerprint"`
+	PrintedBy   string `json:"printed_by" db:"printed_by"`
+	PrintedAt   string `json:"printed_at" db:"printed_at"`
+}
+
+// ZReportPrint records a print of a Z-report. Only the first print, with CopyNumber 0, is the original;
+// every later print is a copy, whatever its version.
+type ZReportPrint struct {
+	ID         int64  `json:"id" db:"id" goqu:"omitempty,skipinsert"`
+	ZReportID  int64  `json:"z_report_id" db:"z_report_id"`
+	Version    int    `json:"version" db:"version"`
+	CopyNumber int    `json:"copy_number" db:"copy_number"`
+	PrintedBy  string `json:"printed_by" db:"printed_by"`
+	PrintedAt  string `json:"printed_at" db:"printed_at"`
+}
@@ -0,0 +1,46 @@
+package models
+
+import "neon/core/helpers/enums"
+
+// ElectronicDocument is the Hacienda electronic invoice of a sale: a Tiquete Electrónico ("04") or, for
+// an identified receiver, a Factura Electrónica ("01"), or a Nota de Crédito ("03") taking back the tickets
+// of a sale voided after it was invoiced. It keeps the signed XML delivered through the outbox.
+type ElectronicDocument struct {
+	ID           int64  `json:"id" db:"id" goqu:"omitempty,skipinsert"`
+	SaleID       int64  `json:"sale_id" db:"sale_id"`
+	ReportID     int64  `json:"report_id" db:"report_id"`
+	DocumentType string `json:"document_type" db:"document_type"`
+	// Clave is the document's 50-digit numeric key
+	Clave string `json:"clave" db:"clave"`
+	// Consecutive is the 20-digit number of the document on its branch and terminal
+	Consecutive      string `json:"consecutive" db:"consecutive"`
+	ReceiverName     string `json:"receiver_name" db:"receiver_name"`
+	ReceiverIDType   string `json:"receiver_id_type" db:"receiver_id_type"`
+	ReceiverIDNumber string `json:"receiver_id_number" db:"receiver_id_number"`
+	ReceiverEmail    string `json:"receiver_email" db:"receiver_email"`
+	// ReferenceClave is the clave of the invoice a credit note takes back, empty for the invoices
+	ReferenceClave string `json:"reference_clave" db:"reference_clave"`
+	Total          int    `json:"total" db:"total"`
+	XML            string `json:"-" db:"xml"`
+	// Status mirrors the state of the document's outbox message
+	Status      enums.OutboxState `json:"status" db:"status"`
+	IssuedAt    string            `json:"issued_at" db:"issued_at"`
+	SubmittedAt *string           `json:"submitted_at" db:"submitted_at" goqu:"omitnil"`
+}
+
+// ContingencyReceipt is a receipt written by hand while the POS was down, which a sale keyed in later
+// replaces. Its electronic invoice is issued in contingency and references it.
+type ContingencyReceipt struct {
+	Number string `json:"number"`
+	// IssuedAt is when the receipt was written, as RFC3339
+	IssuedAt string `json:"issued_at"`
+}
+
+// InvoiceReceiver identifies the customer of a Factura Electrónica. IDType is "01" (cédula física), "02"
+// (cédula jurídica), "03" (DIMEX) or "04" (NITE).
+type InvoiceReceiver struct {
+	Name     string `json:"name"`
+	IDType   string `json:"id_type"`
+	IDNumber string `json:"id_number"`
+	Email    string `json:"email"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"neon/core/helpers/enums"
+)
+
+// CategoryFare is the fare of a stop for a passenger category
+type CategoryFare struct {
+	Category enums.FareCategory `json:"category" bson:"category" clover:"category"`
+	Fare     int                `json:"fare" bson:"fare" clover:"fare"`
+}
+
+// FarePromotion lowers the fare of a route's stops during a time window or a date range. Empty
+// categories or stops apply to all of them.
+type FarePromotion struct {
+	Name       string               `json:"name" bson:"name" clover:"name"`
+	Categories []enums.FareCategory `json:"categories" bson:"categories" clover:"categories"`
+	Stops      []string             `json:"stops" bson:"stops" clover:"stops"`
+	// DiscountPercent takes a percentage off the fare
+	DiscountPercent int `json:"discount_percent" bson:"discount_percent" clover:"discount_percent"`
+	// Fare replaces the fare with a fixed amount when it is lower, nil to use DiscountPercent only
+	Fare *int `json:"fare" bson:"fare,omitempty" clover:"fare"`
+	// StartTime and EndTime (HH:MM) limit the promotion to departures in the window, end excluded
+	StartTime string `json:"start_time" bson:"start_time" clover:"start_time"`
+	EndTime   string `json:"end_time" bson:"end_time" clover:"end_time"`
+	// StartDate and EndDate (YYYY-MM-DD) limit the promotion to travel dates in the range, both included
+	StartDate string `json:"start_date" bson:"start_date" clover:"start_date"`
+	EndDate   string `json:"end_date" bson:"end_date" clover:"end_date"`
+}
+
+// FareQuote is the fare of a stop for a passenger category at a moment, with the rule that set it
+type FareQuote struct {
+	Departure   string             `json:"departure"`
+	Destination string             `json:"destination"`
+	Stop        string             `json:"stop"`
+	Category    enums.FareCategory `json:"category"`
+	// BaseFare is the stop's fare for the category before promotions
+	BaseFare int `json:"base_fare"`
+	Fare     int `json:"fare"`
+	// Rule identifies the rule that set the fare: "stop:<category>" or "promo:<name>"
+	Rule string `json:"rule"`
+	// FareVersion is the route tariff the base fare comes from, 0 for the route's own stop fares
+	FareVersion int `json:"fare_version"`
+}
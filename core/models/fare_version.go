@@ -0,0 +1,62 @@
+package models
+
+import (
+	"neon/core/helpers/enums"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// FareVersion is a tariff of a route taking effect on a date. The fares of the stops it lists replace the
+// route's own stop fares from EffectiveFrom until the next version; stops it doesn't list keep them.
+type FareVersion struct {
+	ID          bson.ObjectID `json:"id" bson:"_id" clover:"id"`
+	Departure   string        `json:"departure" bson:"departure" clover:"departure"`
+	Destination string        `json:"destination" bson:"destination" clover:"destination"`
+	// Version numbers the route's tariffs from 1; 0 on a ticket means the route's own stop fares
+	Version int `json:"version" bson:"version" clover:"version"`
+	// EffectiveFrom is the local date (YYYY-MM-DD) the tariff starts applying to sales
+	EffectiveFrom string      `json:"effective_from" bson:"effective_from" clover:"effective_from"`
+	Stops         []StopFares `json:"stops" bson:"stops" clover:"stops"`
+	Note          string      `json:"note" bson:"note" clover:"note"`
+	CreatedBy     string      `json:"created_by" bson:"created_by" clover:"created_by"`
+	CreatedAt     string      `json:"created_at" bson:"created_at" clover:"created_at"`
+}
+
+// StopFares are the fares of a stop in a fare version
+type StopFares struct {
+	Stop     string         `json:"stop" bson:"stop" clover:"stop"`
+	Fare     int            `json:"fare" bson:"fare" clover:"fare"`
+	GoldFare int            `json:"gold_fare" bson:"gold_fare" clover:"gold_fare"`
+	Fares    []CategoryFare `json:"fares" bson:"fares,omitempty" clover:"fares"`
+}
+
+// FindStop finds the fares of a stop in the version
+func (v *FareVersion) FindStop(name string) (StopFares, bool) {
+	for _, stop := range v.Stops {
+		if stop.Stop == name {
+			return stop, true
+		}
+	}
+	return StopFares{}, false
+}
+
+// FareChangeRequest schedules a new tariff for a route
+type FareChangeRequest struct {
+	Departure     string      `json:"departure"`
+	Destination   string      `json:"destination"`
+	EffectiveFrom string      `json:"effective_from"`
+	Stops         []StopFares `json:"stops"`
+	Note          string      `json:"note"`
+	Username      string      `json:"username"`
+}
+
+// FareChangePreview is the effect of a scheduled tariff on a stop's fare for a category
+type FareChangePreview struct {
+	Stop        string             `json:"stop"`
+	Category    enums.FareCategory `json:"category"`
+	CurrentFare int                `json:"current_fare"`
+	NewFare     int                `json:"new_fare"`
+	Difference  int                `json:"difference"`
+	// CurrentVersion is the version the current fare comes from, 0 for the route's own stop fares
+	CurrentVersion int `json:"current_version"`
+}
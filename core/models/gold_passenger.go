@@ -0,0 +1,16 @@
+package models
+
+import (
+	"neon/core/helpers/enums"
+)
+
+// GoldPassenger is a senior citizen registered for gold tickets
+type GoldPassenger struct {
+	IDNumber string       `json:"id_number" bson:"id_number" clover:"id_number"`
+	IDType   enums.IDType `json:"id_type" bson:"id_type" clover:"id_type"`
+	Name     string       `json:"name" bson:"name" clover:"name"`
+	Active   bool         `json:"active" bson:"active" clover:"active"`
+	// ExpiresAt is the date (YYYY-MM-DD) the registration has to be renewed, nil when it doesn't expire
+	ExpiresAt *string `json:"expires_at" bson:"expires_at" clover:"expires_at"`
+	CreatedAt string  `json:"created_at" bson:"created_at" clover:"created_at"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import "neon/core/helpers/enums"
+
+// OutboxMessage is a document owed to a third party, e.g. an electronic invoice owed to Hacienda. It is
+// written in the same transaction as the document and delivered by the outbox worker, which retries it
+// with backoff until it is accepted, rejected or refused. Times are RFC 3339 in UTC.
+type OutboxMessage struct {
+	ID   int64            `json:"id" db:"id" goqu:"omitempty,skipinsert"`
+	Kind enums.OutboxKind `json:"kind" db:"kind"`
+	// RecordID is the ID of the document in the table of its kind
+	RecordID int64 `json:"record_id" db:"record_id"`
+	// Reference identifies the document with the third party, e.g. the clave of an electronic invoice
+	Reference     string            `json:"reference" db:"reference"`
+	State         enums.OutboxState `json:"state" db:"state"`
+	Attempts      int               `json:"attempts" db:"attempts"`
+	NextAttemptAt string            `json:"next_attempt_at" db:"next_attempt_at"`
+	LastError     string            `json:"last_error" db:"last_error"`
+	// Response is the third party's answer, e.g. Hacienda's MensajeHacienda XML
+	Response string `json:"response" db:"response"`
+	// Reason explains a rejection or an error, e.g. Hacienda's DetalleMensaje
+	Reason    string `json:"reason" db:"reason"`
+	CreatedAt string `json:"created_at" db:"created_at"`
+	UpdatedAt string `json:"updated_at" db:"updated_at"`
+}
+
+// RejectedDocument is an electronic document Hacienda rejected or refused, with its reason, for the admin
+// to correct and reissue
+type RejectedDocument struct {
+	MessageID    int64             `json:"message_id"`
+	DocumentID   int64             `json:"document_id"`
+	SaleID       int64             `json:"sale_id"`
+	ReportID     int64             `json:"report_id"`
+	DocumentType string            `json:"document_type"`
+	Clave        string            `json:"clave"`
+	Consecutive  string            `json:"consecutive"`
+	ReceiverName string            `json:"receiver_name"`
+	Total        int               `json:"total"`
+	IssuedAt     string            `json:"issued_at"`
+	State        enums.OutboxState `json:"state"`
+	Reason       string            `json:"reason"`
+	Response     string            `json:"response"`
+	Attempts     int               `json:"attempts"`
+	UpdatedAt    string            `json:"updated_at"`
+}
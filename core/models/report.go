@@ -28,4 +28,201 @@ type Report struct {
 	PartialClosedBy     *string         `json:"partial_closed_by" db:"partial_closed_by" goqu:"omitnil"`
 	ClosedBy            *string         `json:"closed_by" db:"closed_by" goqu:"omitnil"`
 	RemoteSynced        bool            `json:"remote_synced" db:"remote_synced" goqu:"omitempty"`
+	// AdvanceTickets and AdvanceCash are the tickets sold on this report for a later travel date
+	AdvanceTickets int `json:"advance_tickets" db:"advance_tickets" goqu:"omitempty"`
+	AdvanceCash    int `json:"advance_cash" db:"advance_cash" goqu:"omitempty"`
+	// TotalSales is the number of sales of the report, voided sales excluded
+	TotalSales int `json:"total_sales" db:"total_sales" goqu:"omitempty"`
+	// OpeningFloat is the change the cashier started with (fondo de caja), handed back at the total close
+	OpeningFloat int `json:"opening_float" db:"opening_float" goqu:"omitempty"`
+	// FloatConfirmedBy is the admin who confirmed the opening float, when confirmation is required
+	FloatConfirmedBy *string `json:"float_confirmed_by" db:"float_confirmed_by" goqu:"omitnil"`
+	// PendingRecount is the close whose variance, on a blind close, waits for an admin recount; empty when
+	// none does
+	PendingRecount enums.CashCountStage `json:"pending_recount" db:"pending_recount"`
+	// FareTotals are the tickets and cash per fare category, kept in their own table by the ticket triggers
+	FareTotals []ReportFareTotal `json:"fare_totals" db:"-"`
+	// PaymentTotals are the amounts taken per payment method, kept in their own table by the payment triggers
+	PaymentTotals []ReportPaymentTotal `json:"payment_totals" db:"-"`
+	// PartialDrawer and FinalDrawer are the cash expected in the drawer at the drops and at the total close:
+	// PartialCash and FinalCash without the card and SINPE Móvil payments. The opening float is on top of
+	// them and is subtracted from the cash received when reconciling.
+	PartialDrawer int `json:"partial_drawer" db:"-"`
+	FinalDrawer   int `json:"final_drawer" db:"-"`
+	// OpeningCount, PartialCount and FinalCount are the bills and coins counted at the start, at the drops
+	// (added up per denomination) and at the total close, empty when the cash was given as a single amount
+	OpeningCount []CashCount `json:"opening_count" db:"-"`
+	PartialCount []CashCount `json:"partial_count" db:"-"`
+	FinalCount   []CashCount `json:"final_count" db:"-"`
+	// PartialVariance and FinalVariance are the cash received less the cash expected at each close (the
+	// final one net of the opening float), nil until that close is recorded
+	PartialVariance *int `json:"partial_variance" db:"-"`
+	FinalVariance   *int `json:"final_variance" db:"-"`
+	// Recounts are the admin recounts of the drawer at the closes, oldest first
+	Recounts []ReportRecount `json:"recounts" db:"-"`
+	// Cashier is who sells on the report now: Username until a handover is accepted, then the incoming
+	// cashier of the last one
+	Cashier string `json:"cashier" db:"-"`
+	// Segments are the shifts of the report, one per cashier between handovers, oldest first
+	Segments []ReportSegment `json:"segments" db:"-"`
+	// Drops are the interim cash drops of the report, oldest first. The partial columns add them up:
+	// PartialTickets, PartialCash and PartialCashReceived are their totals, PartialClosedAt and
+	// PartialClosedBy the latest one's. FinalTickets and FinalCash are the period open since the last drop.
+	Drops []ReportDrop `json:"drops" db:"-"`
+}
+
+// ReportDrop is an interim cash drop (retiro) of a report: cash taken out of the drawer while the report
+// stays open. Tickets, Cash and Drawer are the tickets sold, cash taken and cash payments accrued since the
+// previous drop, so Drawer is the cash expected at it.
+type ReportDrop struct {
+	ID           int64  `json:"id" db:"id" goqu:"omitempty,skipinsert"`
+	ReportID     int64  `json:"report_id" db:"report_id"`
+	Sequence     int    `json:"sequence" db:"sequence"`
+	DroppedAt    string `json:"dropped_at" db:"dropped_at"`
+	DroppedBy    string `json:"dropped_by" db:"dropped_by"`
+	CashReceived int    `json:"cash_received" db:"cash_received"`
+	Tickets      int    `json:"tickets" db:"tickets"`
+	Cash         int    `json:"cash" db:"cash"`
+	Drawer       int    `json:"drawer" db:"drawer"`
+	// Count is the bills and coins counted, empty when the cash was given as a single amount
+	Count []CashCount `json:"count" db:"-"`
+}
+
+// ReportHandover hands a report's drawer from one cashier to the next without closing the report. The
+// outgoing cashier counts the drawer; the incoming cashier accepts it, and the sales after that are theirs.
+// Tickets and CashTaken are the tickets sold and cash taken in the shift it ends, Expected the cash expected
+// in the drawer at the count (opening float included, the cash delivered at the drops excluded).
+type ReportHandover struct {
+	ID           int64  `json:"id" db:"id" goqu:"omitempty,skipinsert"`
+	ReportID     int64  `json:"report_id" db:"report_id"`
+	Sequence     int    `json:"sequence" db:"sequence"`
+	FromUsername string `json:"from_username" db:"from_username"`
+	// ToUsername is the incoming cashier, empty until the handover is accepted
+	ToUsername string  `json:"to_username" db:"to_username"`
+	Tickets    int     `json:"tickets" db:"tickets"`
+	CashTaken  int     `json:"cash_taken" db:"cash_taken"`
+	Expected   int     `json:"expected" db:"expected"`
+	Cash       int     `json:"cash" db:"cash"`
+	CountedAt  string  `json:"counted_at" db:"counted_at"`
+	AcceptedAt *string `json:"accepted_at" db:"accepted_at"`
+	// Count is the bills and coins counted, empty when the cash was given as a single amount
+	Count []CashCount `json:"count" db:"-"`
+}
+
+// ReportSegment is a cashier's shift on a report: from the report's start or an accepted handover to the
+// next handover or the total close. The last shift's tickets and cash are the report's less the earlier
+// shifts'.
+type ReportSegment struct {
+	Sequence  int     `json:"sequence"`
+	Username  string  `json:"username"`
+	StartedAt *string `json:"started_at"`
+	EndedAt   *string `json:"ended_at"`
+	Tickets   int     `json:"tickets"`
+	CashTaken int     `json:"cash_taken"`
+	// Handover is the handover that ended the shift, nil for the current or last shift
+	Handover *ReportHandover `json:"handover"`
+}
+
+// ReportHandoverRequest is the outgoing cashier's count of the drawer at a handover, given as an amount or
+// as the bills and coins counted
+type ReportHandoverRequest struct {
+	ReportID int64       `json:"report_id"`
+	Username string      `json:"username"`
+	Cash     int         `json:"cash"`
+	Counts   []CashCount `json:"counts"`
+}
+
+// CashierReport is the open report as shown to the cashier when the installation closes blind: it leaves
+// out the cash and tickets expected at the closes, so the drawer is counted rather than matched, and
+// carries each close's variance once its count is submitted
+type CashierReport struct {
+	ID                  int64                `json:"id"`
+	Username            string               `json:"username"`
+	Timetable           enums.Timetable      `json:"timetable"`
+	Status              bool                 `json:"status"`
+	CreatedAt           *string              `json:"created_at"`
+	OpeningFloat        int                  `json:"opening_float"`
+	OpeningCount        []CashCount          `json:"opening_count"`
+	PartialClosedAt     *string              `json:"partial_closed_at"`
+	PartialClosedBy     *string              `json:"partial_closed_by"`
+	PartialCashReceived int                  `json:"partial_cash_received"`
+	PartialCount        []CashCount          `json:"partial_count"`
+	PartialVariance     *int                 `json:"partial_variance"`
+	ClosedAt            *string              `json:"closed_at"`
+	ClosedBy            *string              `json:"closed_by"`
+	FinalCashReceived   int                  `json:"final_cash_received"`
+	FinalCount          []CashCount          `json:"final_count"`
+	FinalVariance       *int                 `json:"final_variance"`
+	PendingRecount      enums.CashCountStage `json:"pending_recount"`
+}
+
+// ReportRecount is an admin's recount of the drawer at a report's close, which replaces the cash received
+// at that close; the partial stage is the report's latest drop. Expected is the cash expected then, net of
+// the opening float at the total close.
+type ReportRecount struct {
+	ID       int64                `json:"id" db:"id" goqu:"omitempty,skipinsert"`
+	ReportID int64                `json:"report_id" db:"report_id"`
+	Stage    enums.CashCountStage `json:"stage" db:"stage"`
+	Expected int                  `json:"expected" db:"expected"`
+	// PreviousReceived is the cash received at the close before the recount
+	PreviousReceived int    `json:"previous_received" db:"previous_received"`
+	Received         int    `json:"received" db:"received"`
+	Variance         int    `json:"variance" db:"variance"`
+	Reason           string `json:"reason" db:"reason"`
+	RecountedBy      string `json:"recounted_by" db:"recounted_by"`
+	RecountedAt      string `json:"recounted_at" db:"recounted_at"`
+}
+
+// ReportRecountRequest is the input of an admin recount of a close, given as an amount or as the bills and
+// coins counted, with the reason for it and the admin's credentials
+type ReportRecountRequest struct {
+	ReportID         int64                `json:"report_id"`
+	Stage            enums.CashCountStage `json:"stage"`
+	Cash             int                  `json:"cash"`
+	Counts           []CashCount          `json:"counts"`
+	Reason           string               `json:"reason"`
+	ApproverUsername string               `json:"approver_username"`
+	ApproverPassword string               `json:"approver_password"`
+}
+
+// ReportStartRequest is the input to start a report with an opening float, given as an amount or as the
+// bills and coins counted. The approver's credentials are required when the installation has the opening
+// float confirmed by an admin.
+type ReportStartRequest struct {
+	Username         string      `json:"username"`
+	Timetable        string      `json:"timetable"`
+	OpeningFloat     int         `json:"opening_float"`
+	OpeningCount     []CashCount `json:"opening_count"`
+	ApproverUsername string      `json:"approver_username"`
+	ApproverPassword string      `json:"approver_password"`
+}
+
+// ReportFareTotal is the tickets sold and cash taken for a fare category on a report, voids excluded
+type ReportFareTotal struct {
+	ReportID int64              `json:"report_id" db:"report_id"`
+	Category enums.FareCategory `json:"category" db:"category"`
+	Tickets  int                `json:"tickets" db:"tickets"`
+	Cash     int                `json:"cash" db:"cash"`
+}
+
+// CashCount is the number of bills or coins of a denomination counted in a report's drawer at a close.
+// Amount is Quantity times the denomination, computed when the count is stored.
+type CashCount struct {
+	ReportID     int64                `json:"report_id" db:"report_id"`
+	Stage        enums.CashCountStage `json:"stage" db:"stage"`
+	Denomination enums.Denomination   `json:"denomination" db:"denomination"`
+	Quantity     int                  `json:"quantity" db:"quantity"`
+	Amount       int                  `json:"amount" db:"amount"`
+	CountedBy    string               `json:"counted_by" db:"counted_by"`
+	CountedAt    string               `json:"counted_at" db:"counted_at"`
+}
+
+// ReportPaymentTotal is the amount taken with a payment method on a report at its drops (PartialAmount)
+// and since the last one (FinalAmount), net of the refunds of voided tickets
+type ReportPaymentTotal struct {
+	ReportID      int64               `json:"report_id" db:"report_id"`
+	Method        enums.PaymentMethod `json:"method" db:"method"`
+	Payments      int                 `json:"payments" db:"payments"`
+	PartialAmount int                 `json:"partial_amount" db:"partial_amount"`
+	FinalAmount   int                 `json:"final_amount" db:"final_amount"`
 }
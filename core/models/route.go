@@ -1,6 +1,8 @@
 package models
 
 import (
+	"neon/core/helpers/enums"
+
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
@@ -12,6 +14,10 @@ type Route struct {
 	Stops            []Stop        `json:"stops" bson:"stops" clover:"stops"`
 	Timetable        []Time        `json:"timetable" bson:"timetable" clover:"timetable"`
 	HolidayTimetable []Time        `json:"holiday_timetable" bson:"holiday_timetable" clover:"holiday"`
+	// Layout is the bus seat layout, nil when the route doesn't sell numbered seats
+	Layout *BusLayout `json:"layout" bson:"layout,omitempty" clover:"layout"`
+	// Promotions are the fare promotions of the route
+	Promotions []FarePromotion `json:"promotions" bson:"promotions,omitempty" clover:"promotions"`
 }
 
 // IsEmpty checks if the route is empty
@@ -22,3 +28,31 @@ func (r *Route) IsEmpty() bool {
 		len(r.Timetable) == 0 ||
 		len(r.HolidayTimetable) == 0
 }
+
+// Departures returns the departure times of a timetable
+func (r *Route) Departures(timetable enums.Timetable) []Time {
+	if timetable == enums.Holiday {
+		return r.HolidayTimetable
+	}
+	return r.Timetable
+}
+
+// FindDeparture finds a departure time (HH:MM) in a timetable
+func (r *Route) FindDeparture(timetable enums.Timetable, departureTime string) (Time, bool) {
+	for _, t := range r.Departures(timetable) {
+		if t.String() == departureTime {
+			return t, true
+		}
+	}
+	return Time{}, false
+}
+
+// FindStop finds a stop of the route by name
+func (r *Route) FindStop(name string) (Stop, bool) {
+	for _, stop := range r.Stops {
+		if stop.Name == name {
+			return stop, true
+		}
+	}
+	return Stop{}, false
+}
@@ -0,0 +1,81 @@
+package models
+
+import (
+	"neon/core/helpers/enums"
+)
+
+// Sale groups the tickets sold together to one customer (several passengers, outbound and return) with
+// their total and payment
+type Sale struct {
+	ID          int64   `json:"id" db:"id" goqu:"omitempty,skipinsert"`
+	ReportID    int64   `json:"report_id" db:"report_id" goqu:"omitempty"`
+	Username    string  `json:"username" db:"username" goqu:"omitempty"`
+	Total       int     `json:"total" db:"total"`
+	TicketCount int     `json:"ticket_count" db:"ticket_count" goqu:"omitempty"`
+	IsNull      bool    `json:"is_null" db:"is_null" goqu:"omitempty"`
+	CreatedAt   string  `json:"created_at" db:"created_at" goqu:"skipupdate"`
+	VoidedAt    *string `json:"voided_at" db:"voided_at" goqu:"omitnil"`
+	VoidedBy    *string `json:"voided_by" db:"voided_by" goqu:"omitnil"`
+	// Tickets and Payments are loaded from their own tables
+	Tickets  []Ticket      `json:"tickets" db:"-"`
+	Payments []SalePayment `json:"payments" db:"-"`
+	// Document is the sale's electronic invoice, nil when e-invoicing is off
+	Document *ElectronicDocument `json:"document" db:"-"`
+}
+
+// SalePayment is a payment taken for a sale. Cash payments record the amount tendered and the change
+// given back; card and SINPE Móvil payments carry the voucher or transfer reference. Refunds of voided
+// tickets are recorded as payments with a negative amount.
+type SalePayment struct {
+	ID        int64               `json:"id" db:"id" goqu:"omitempty,skipinsert"`
+	SaleID    int64               `json:"sale_id" db:"sale_id"`
+	Method    enums.PaymentMethod `json:"method" db:"method"`
+	Amount    int                 `json:"amount" db:"amount"`
+	Tendered  int                 `json:"tendered" db:"tendered"`
+	Change    int                 `json:"change" db:"change_given"`
+	Reference string              `json:"reference" db:"reference"`
+	// ApprovalCode is the card terminal's approval code; Reference then holds its transaction ID
+	ApprovalCode string `json:"approval_code" db:"approval_code"`
+	// CaptureStatus follows the capture of a card payment authorized on the terminal, done once the sale
+	// is stored: pending until the terminal captures it, accepted once it did, error when the terminal
+	// declined it. Empty on every other payment.
+	CaptureStatus enums.OutboxState `json:"capture_status" db:"capture_status"`
+	// RefundStatus follows the refund of a card payment on the terminal that charged it: pending until the
+	// terminal gives it back, accepted once it did, error when the terminal declined it. Empty on every
+	// other payment.
+	RefundStatus enums.OutboxState `json:"refund_status" db:"refund_status"`
+	CreatedAt    string            `json:"created_at" db:"created_at"`
+}
+
+// SaleRequest is the input to sell a group of tickets. The report defaults to the tickets', and the sale
+// belongs to the report's current cashier, who changes at each accepted shift handover. Payments must add
+// up to the sale's total; without payments the sale is paid in cash. With a Receiver, the sale's electronic
+// invoice is a Factura Electrónica instead of a Tiquete Electrónico.
+type SaleRequest struct {
+	ReportID int64 `json:"report_id"`
+	// Username is ignored: the report's current cashier makes the sale
+	Username string           `json:"username"`
+	Tickets  []Ticket         `json:"tickets"`
+	Payments []SalePayment    `json:"payments"`
+	Receiver *InvoiceReceiver `json:"receiver"`
+	// Contingency is the handwritten receipt the sale replaces, nil for a sale made at the counter
+	Contingency *ContingencyReceipt `json:"contingency"`
+}
+
+// SaleVoidRequest is the input to void every ticket of a sale. Approver credentials are required as for
+// TicketVoidRequest, applied to the sale's total.
+type SaleVoidRequest struct {
+	SaleID           int64            `json:"sale_id"`
+	ReportID         int64            `json:"report_id"`
+	Reason           enums.VoidReason `json:"reason"`
+	Note             string           `json:"note"`
+	VoidedBy         string           `json:"voided_by"`
+	ApproverUsername string           `json:"approver_username"`
+	ApproverPassword string           `json:"approver_password"`
+}
+
+// SaleVoid is the result of voiding a sale: the sale and the void record of each ticket
+type SaleVoid struct {
+	Sale  Sale         `json:"sale"`
+	Voids []TicketVoid `json:"voids"`
+}
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"neon/core/helpers/enums"
+)
+
+// ReportSearch filters the report history. Empty fields don't filter. From and To are the first and last
+// days (YYYY-MM-DD) the reports started on. Username matches who started a report or took it over at a
+// handover. Variance only matches closed reports. Page starts at 1; SortBy is one of id, created_at,
+// closed_at, username, tickets, cash or variance, and defaults to the newest first.
+type ReportSearch struct {
+	From         string             `json:"from"`
+	To           string             `json:"to"`
+	Username     string             `json:"username"`
+	Timetable    enums.Timetable    `json:"timetable"`
+	Status       *bool              `json:"status"`
+	RemoteSynced *bool              `json:"remote_synced"`
+	Variance     enums.VarianceSign `json:"variance"`
+	Page         int                `json:"page"`
+	PageSize     int                `json:"page_size"`
+	SortBy       string             `json:"sort_by"`
+	Descending   bool               `json:"descending"`
+}
+
+// ReportSearchTotals add up every report matching a search, not just the page returned. Expected, Received
+// and Difference reconcile the drawers of the closed reports among them.
+type ReportSearchTotals struct {
+	Reports    int `json:"reports"`
+	Tickets    int `json:"tickets"`
+	Cash       int `json:"cash"`
+	Sales      int `json:"sales"`
+	Gold       int `json:"gold"`
+	Null       int `json:"null"`
+	NullCash   int `json:"null_cash"`
+	Expected   int `json:"expected"`
+	Received   int `json:"received"`
+	Difference int `json:"difference"`
+}
+
+// ReportSearchResult is a page of the reports matching a search, with their totals loaded, and the totals
+// of all of them
+type ReportSearchResult struct {
+	Reports  []*Report          `json:"reports"`
+	Totals   ReportSearchTotals `json:"totals"`
+	Page     int                `json:"page"`
+	PageSize int                `json:"page_size"`
+	Pages    int                `json:"pages"`
+}
+
+// TicketSearch filters the tickets. Empty fields don't filter. SoldFrom and SoldTo are the first and last
+// days (YYYY-MM-DD) the tickets were sold on, TravelFrom and TravelTo their travel dates. Page starts at 1;
+// SortBy is one of id, created_at, travel_date, time, stop or fare, and defaults to the newest first.
+type TicketSearch struct {
+	SoldFrom    string `json:"sold_from"`
+	SoldTo      string `json:"sold_to"`
+	TravelFrom  string `json:"travel_from"`
+	TravelTo    string `json:"travel_to"`
+	Departure   string `json:"departure"`
+	Destination string `json:"destination"`
+	Stop        string `json:"stop"`
+	Time        string `json:"time"`
+	Username    string `json:"username"`
+	IsGold      *bool  `json:"is_gold"`
+	IsNull      *bool  `json:"is_null"`
+	IDNumber    string `json:"id_number"`
+	ReportID    int64  `json:"report_id"`
+	Page        int    `json:"page"`
+	PageSize    int    `json:"page_size"`
+	SortBy      string `json:"sort_by"`
+	Descending  bool   `json:"descending"`
+}
+
+// TicketSearchTotals add up every ticket matching a search, not just the page returned. Tickets and Cash
+// leave the voided tickets out; they are in Null and NullCash.
+type TicketSearchTotals struct {
+	Tickets  int `json:"tickets"`
+	Cash     int `json:"cash"`
+	Gold     int `json:"gold"`
+	Null     int `json:"null"`
+	NullCash int `json:"null_cash"`
+}
+
+// TicketSearchResult is a page of the tickets matching a search and the totals of all of them
+type TicketSearchResult struct {
+	Tickets  []Ticket           `json:"tickets"`
+	Totals   TicketSearchTotals `json:"totals"`
+	Page     int                `json:"page"`
+	PageSize int                `json:"page_size"`
+	Pages    int                `json:"pages"`
+}
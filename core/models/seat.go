@@ -0,0 +1,21 @@
+package models
+
+// SeatInventory is the seats sold for a departure of a route on a date
+type SeatInventory struct {
+	ID          int64  `json:"id" db:"id" goqu:"omitempty,skipinsert"`
+	Departure   string `json:"departure" db:"departure"`
+	Destination string `json:"destination" db:"destination"`
+	TravelDate  string `json:"travel_date" db:"travel_date"`
+	Time        string `json:"time" db:"time"`
+	Capacity    int    `json:"capacity" db:"capacity"`
+	Sold        int    `json:"sold" db:"sold"`
+}
+
+// DepartureSeats is the seat availability of a departure
+type DepartureSeats struct {
+	Time     string `json:"time"`
+	Capacity int    `json:"capacity"`
+	Sold     int    `json:"sold"`
+	// Remaining is the number of seats left, -1 when the departure has no capacity limit
+	Remaining int `json:"remaining"`
+}
@@ -0,0 +1,50 @@
+package models
+
+// BusLayout is the seat layout of the bus of a route. Seats are numbered from 1, row by row, left to right.
+type BusLayout struct {
+	Rows    int `json:"rows" bson:"rows" clover:"rows"`
+	Columns int `json:"columns" bson:"columns" clover:"columns"`
+	// AisleAfter is the column after which the aisle is drawn, 0 for none
+	AisleAfter int `json:"aisle_after" bson:"aisle_after" clover:"aisle_after"`
+	// Blocked are the seat numbers that can't be sold (driver, door, broken seats)
+	Blocked []int `json:"blocked" bson:"blocked" clover:"blocked"`
+}
+
+// IsEmpty checks if the layout has no seats
+func (l *BusLayout) IsEmpty() bool {
+	return l == nil || l.Rows <= 0 || l.Columns <= 0
+}
+
+// IsSellable reports whether a seat number exists in the layout and is not blocked
+func (l *BusLayout) IsSellable(seat int) bool {
+	if l.IsEmpty() || seat < 1 || seat > l.Rows*l.Columns {
+		return false
+	}
+	for _, blocked := range l.Blocked {
+		if blocked == seat {
+			return false
+		}
+	}
+	return true
+}
+
+// SeatReservation is a numbered seat taken by a ticket on a departure
+type SeatReservation struct {
+	ID          int64  `json:"id" db:"id" goqu:"omitempty,skipinsert"`
+	TicketID    int64  `json:"ticket_id" db:"ticket_id"`
+	Departure   string `json:"departure" db:"departure"`
+	Destination string `json:"destination" db:"destination"`
+	TravelDate  string `json:"travel_date" db:"travel_date"`
+	Time        string `json:"time" db:"time"`
+	SeatNumber  int    `json:"seat_number" db:"seat_number"`
+}
+
+// SeatMap is the occupancy of a departure, used to draw the seat map
+type SeatMap struct {
+	Departure   string            `json:"departure"`
+	Destination string            `json:"destination"`
+	TravelDate  string            `json:"travel_date"`
+	Time        string            `json:"time"`
+	Layout      *BusLayout        `json:"layout"`
+	Occupied    []SeatReservation `json:"occupied"`
+}
@@ -7,4 +7,6 @@ type Stop struct {
 	Fare     int    `json:"fare" bson:"fare" clover:"fare"`
 	GoldFare int    `json:"gold_fare" bson:"gold_fare" clover:"gold_fare"`
 	IsMain   bool   `json:"is_main" bson:"is_main" clover:"is_main"`
+	// Fares are the fares of the other passenger categories. Regular and gold fall back to Fare and GoldFare.
+	Fares []CategoryFare `json:"fares" bson:"fares,omitempty" clover:"fares"`
 }
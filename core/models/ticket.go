@@ -1,5 +1,9 @@
 package models
 
+import (
+	"neon/core/helpers/enums"
+)
+
 // Ticket represents a ticket in the database
 type Ticket struct {
 	ID          int64  `json:"id" db:"id" goqu:"omitempty,skipinsert"`
@@ -15,4 +19,47 @@ type Ticket struct {
 	ReportID    int64  `json:"report_id" db:"report_id" goqu:"omitempty"`
 	CreatedAt   string `json:"created_at" db:"created_at" goqu:"skipupdate"`
 	UpdatedAt   string `json:"updated_at" db:"updated_at" goqu:"omitnil"`
+	// TravelDate is the local date (YYYY-MM-DD) the ticket travels on. Empty on tickets sold before
+	// advance sales, which travel on their sale date.
+	TravelDate string `json:"travel_date" db:"travel_date" goqu:"omitempty"`
+	// IsAdvance is set when the ticket was sold for a later travel date
+	IsAdvance bool `json:"is_advance" db:"is_advance" goqu:"omitempty"`
+	// SeatNumber is the numbered seat of the ticket, 0 when the seat is not numbered
+	SeatNumber int `json:"seat_number" db:"seat_number" goqu:"omitempty"`
+	// FareCategory is the passenger category the fare was charged for. Empty on tickets sold before fare
+	// categories, which are gold or regular by IsGold.
+	FareCategory enums.FareCategory `json:"fare_category" db:"fare_category" goqu:"omitempty"`
+	// FareRule is the fare rule applied by the sale, see models.FareQuote
+	FareRule string `json:"fare_rule" db:"fare_rule" goqu:"omitempty"`
+	// FareVersion is the route tariff the fare comes from, 0 for the route's own stop fares
+	FareVersion int `json:"fare_version" db:"fare_version" goqu:"omitempty"`
+	// SaleID is the sale the ticket was sold in, 0 on tickets sold before sales
+	SaleID int64 `json:"sale_id" db:"sale_id" goqu:"omitempty"`
+	// ApprovalCode is the card terminal's approval code of the sale's card payment, empty otherwise
+	ApprovalCode string `json:"approval_code" db:"approval_code" goqu:"omitempty"`
+	// DropSequence is the drop of the report the ticket was sold before, 0 while it is in the period open
+	// since the last one
+	DropSequence int `json:"drop_sequence" db:"drop_sequence" goqu:"omitempty"`
+}
+
+// Category returns the ticket's fare category, deriving it from IsGold on older tickets
+func (t *Ticket) Category() enums.FareCategory {
+	if t.FareCategory != "" {
+		return t.FareCategory
+	}
+	if t.IsGold {
+		return enums.FareGold
+	}
+	return enums.FareRegular
+}
+
+// AdvanceDeparture is the advance sales of a departure
+type AdvanceDeparture struct {
+	Departure   string `json:"departure"`
+	Destination string `json:"destination"`
+	TravelDate  string `json:"travel_date"`
+	Time        string `json:"time"`
+	Tickets     int    `json:"tickets"`
+	Gold        int    `json:"gold"`
+	Cash        int    `json:"cash"`
 }
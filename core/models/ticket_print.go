@@ -0,0 +1,36 @@
+package models
+
+const (
+	// TicketPrintReprint marks a copy of a ticket receipt
+	TicketPrintReprint = "reprint"
+	// TicketPrintVoidSlip marks a printed void slip
+	TicketPrintVoidSlip = "void_slip"
+)
+
+const (
+	// TicketPrintPending marks a print recorded but not yet sent to the printer. A print left pending
+	// was interrupted and may or may not have reached paper
+	TicketPrintPending = "pending"
+	// TicketPrintPrinted marks a print that reached the printer
+	TicketPrintPrinted = "printed"
+	// TicketPrintFailed marks a print the printer refused
+	TicketPrintFailed = "failed"
+)
+
+// TicketPrint represents a receipt printed after the original sale (a reprint or a void slip)
+type TicketPrint struct {
+	ID         int64  `json:"id" db:"id" goqu:"omitempty,skipinsert"`
+	TicketID   int64  `json:"ticket_id" db:"ticket_id"`
+	ReportID   int64  `json:"report_id" db:"report_id"`
+	Kind       string `json:"kind" db:"kind"`
+	CopyNumber int    `json:"copy_number" db:"copy_number"`
+	PrintedBy  string `json:"printed_by" db:"printed_by"`
+	Status     string `json:"status" db:"status"`
+	CreatedAt  string `json:"created_at" db:"created_at"`
+}
+
+// TicketPrintCounts holds how many reprints and void slips were printed for a report
+type TicketPrintCounts struct {
+	Reprints  int `json:"reprints"`
+	VoidSlips int `json:"void_slips"`
+}
@@ -1,7 +1,16 @@
 package models
 
+import "fmt"
+
 // Time represents a time in the database
 type Time struct {
 	Hour   int `json:"hour" bson:"hour" clover:"hour"`
 	Minute int `json:"minute" bson:"minute" clover:"minute"`
+	// Capacity is the number of seats of the bus for this departure, 0 for no limit
+	Capacity int `json:"capacity" bson:"capacity" clover:"capacity"`
+}
+
+// String returns the time as HH:MM, the format stored on tickets
+func (t Time) String() string {
+	return fmt.Sprintf("%02d:%02d", t.Hour, t.Minute)
 }
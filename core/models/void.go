@@ -0,0 +1,37 @@
+package models
+
+import (
+	"neon/core/helpers/enums"
+)
+
+// VoidBucketFinal marks a void deducted from the totals of the period open since the last drop. It is the
+// only bucket: a ticket sold before a drop is still refunded from the drawer open now, and the drop's
+// totals were already counted and handed over, so voids never reach back into them.
+const VoidBucketFinal = "final"
+
+// TicketVoid represents the audit record of a voided ticket
+type TicketVoid struct {
+	ID         int64            `json:"id" db:"id" goqu:"omitempty,skipinsert"`
+	TicketID   int64            `json:"ticket_id" db:"ticket_id"`
+	ReportID   int64            `json:"report_id" db:"report_id"`
+	Reason     enums.VoidReason `json:"reason" db:"reason"`
+	Note       string           `json:"note" db:"note"`
+	Fare       int              `json:"fare" db:"fare"`
+	Bucket     string           `json:"bucket" db:"bucket"`
+	VoidedBy   string           `json:"voided_by" db:"voided_by"`
+	ApprovedBy *string          `json:"approved_by" db:"approved_by"`
+	CreatedAt  string           `json:"created_at" db:"created_at"`
+}
+
+// TicketVoidRequest is the input to void a ticket. Approver credentials are only required when
+// the void exceeds the configured amount or the ticket was sold before the report's latest drop.
+// VoidedBy defaults to the report's current cashier.
+type TicketVoidRequest struct {
+	TicketID         int64            `json:"ticket_id"`
+	ReportID         int64            `json:"report_id"`
+	Reason           enums.VoidReason `json:"reason"`
+	Note             string           `json:"note"`
+	VoidedBy         string           `json:"voided_by"`
+	ApproverUsername string           `json:"approver_username"`
+	ApproverPassword string           `json:"approver_password"`
+}
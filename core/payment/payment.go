@@ -0,0 +1,31 @@
+// Package payment drives the card terminals that charge the card payments of a sale
+package payment
+
+import "errors"
+
+var (
+	// ErrDeclined is returned when the card or the issuer declines the charge
+	ErrDeclined = errors.New("payment declined")
+	// ErrTimeout is returned when the terminal doesn't answer in time, e.g. the customer never presented
+	// the card
+	ErrTimeout = errors.New("payment terminal timed out")
+	// ErrUnknownTransaction is returned when a capture, void or refund names a transaction the terminal
+	// doesn't know
+	ErrUnknownTransaction = errors.New("unknown payment transaction")
+)
+
+// Charge is a card payment to authorize on the terminal
+type Charge struct {
+	// Reference identifies the sale on the terminal's journal
+	Reference string `json:"reference"`
+	Amount    int    `json:"amount"`
+}
+
+// Authorization is a charge approved by the terminal, to be captured once the sale is stored, or voided
+type Authorization struct {
+	TransactionID string `json:"transaction_id"`
+	ApprovalCode  string `json:"approval_code"`
+	Amount        int    `json:"amount"`
+	// Card is the masked card number, e.g. "VISA ****1234"
+	Card string `json:"card"`
+}
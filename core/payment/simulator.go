@@ -0,0 +1,145 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Outcome is how the simulator answers an authorization
+type Outcome string
+
+const (
+	// OutcomeApprove approves the charge
+	OutcomeApprove Outcome = "approve"
+	// OutcomeDecline declines the charge
+	OutcomeDecline Outcome = "decline"
+	// OutcomeTimeout answers as a terminal the customer never presented a card to
+	OutcomeTimeout Outcome = "timeout"
+)
+
+// IsValid reports whether the outcome is one the simulator can play
+func (o Outcome) IsValid() bool {
+	return o == OutcomeApprove || o == OutcomeDecline || o == OutcomeTimeout
+}
+
+// Transaction states kept by the simulator
+const (
+	StateAuthorized = "authorized"
+	StateCaptured   = "captured"
+	StateVoided     = "voided"
+	StateRefunded   = "refunded"
+)
+
+// SimulatedTransaction is a charge taken on the simulator
+type SimulatedTransaction struct {
+	Authorization
+	Reference string `json:"reference"`
+	State     string `json:"state"`
+	Refunded  int    `json:"refunded"`
+}
+
+// Simulator is a local card terminal for development and training. Authorizations play the scripted
+// outcomes in order and are approved once the script runs out; captures, voids and refunds of known
+// transactions always succeed.
+type Simulator struct {
+	mu           sync.Mutex
+	script       []Outcome
+	next         int
+	transactions []*SimulatedTransaction
+}
+
+// NewSimulator creates a simulator that plays script on the next authorizations
+func NewSimulator(script ...Outcome) *Simulator {
+	return &Simulator{script: script}
+}
+
+// Script replaces the outcomes of the next authorizations
+func (s *Simulator) Script(outcomes ...Outcome) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.script = append([]Outcome(nil), outcomes...)
+}
+
+// Transactions returns the charges taken on the simulator, oldest first
+func (s *Simulator) Transactions() []SimulatedTransaction {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	transactions := make([]SimulatedTransaction, 0, len(s.transactions))
+	for _, transaction := range s.transactions {
+		transactions = append(transactions, *transaction)
+	}
+	return transactions
+}
+
+// Authorize plays the next scripted outcome
+func (s *Simulator) Authorize(ctx context.Context, charge Charge) (*Authorization, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	outcome := OutcomeApprove
+	if len(s.script) > 0 {
+		outcome = s.script[0]
+		s.script = s.script[1:]
+	}
+
+	switch outcome {
+	case OutcomeDecline:
+		return nil, fmt.Errorf("%w: simulated decline", ErrDeclined)
+	case OutcomeTimeout:
+		return nil, ErrTimeout
+	}
+
+	s.next++
+	transaction := &SimulatedTransaction{
+		Authorization: Authorization{
+			TransactionID: fmt.Sprintf("SIM%06d", s.next),
+			ApprovalCode:  fmt.Sprintf("%06d", 100000+s.next),
+			Amount:        charge.Amount,
+			Card:          "SIM ****0000",
+		},
+		Reference: charge.Reference,
+		State:     StateAuthorized,
+	}
+	s.transactions = append(s.transactions, transaction)
+
+	return &transaction.Authorization, nil
+}
+
+// Capture marks an authorized transaction captured
+func (s *Simulator) Capture(ctx context.Context, authorization Authorization) error {
+	return s.update(authorization.TransactionID, func(transaction *SimulatedTransaction) {
+		transaction.State = StateCaptured
+	})
+}
+
+// Void marks a transaction voided
+func (s *Simulator) Void(ctx context.Context, authorization Authorization) error {
+	return s.update(authorization.TransactionID, func(transaction *SimulatedTransaction) {
+		transaction.State = StateVoided
+	})
+}
+
+// Refund adds amount to a transaction's refunds
+func (s *Simulator) Refund(ctx context.Context, authorization Authorization, amount int) error {
+	return s.update(authorization.TransactionID, func(transaction *SimulatedTransaction) {
+		transaction.Refunded += amount
+		if transaction.Refunded >= transaction.Amount {
+			transaction.State = StateRefunded
+		}
+	})
+}
+
+func (s *Simulator) update(transactionID string, apply func(*SimulatedTransaction)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, transaction := range s.transactions {
+		if transaction.TransactionID == transactionID {
+			apply(transaction)
+			return nil
+		}
+	}
+	return ErrUnknownTransaction
+}
@@ -0,0 +1,148 @@
+package payment
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"time"
+)
+
+// DefaultTimeout is how long the terminal is waited for when no timeout is configured. It covers the
+// customer presenting the card and typing the PIN.
+const DefaultTimeout = 90 * time.Second
+
+const (
+	resultApproved = "approved"
+	resultDeclined = "declined"
+)
+
+// Terminal drives a countertop card terminal over TCP. Each operation opens a connection, sends one JSON
+// request line and reads one JSON response line:
+//
+//	-> {"type":"authorize","reference":"R12-1700000000","amount":6900}
+//	<- {"result":"approved","transaction_id":"000123","approval_code":"A1B2C3","card":"VISA ****1234"}
+//
+// The result is "approved", "declined" (with a "message") or "error".
+type Terminal struct {
+	address string
+	timeout time.Duration
+}
+
+// NewTerminal creates a driver for the terminal listening on address. A zero timeout uses DefaultTimeout.
+func NewTerminal(address string, timeout time.Duration) *Terminal {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Terminal{address: address, timeout: timeout}
+}
+
+type terminalRequest struct {
+	Type          string `json:"type"`
+	Reference     string `json:"reference,omitempty"`
+	TransactionID string `json:"transaction_id,omitempty"`
+	Amount        int    `json:"amount,omitempty"`
+}
+
+type terminalResponse struct {
+	Result        string `json:"result"`
+	TransactionID string `json:"transaction_id"`
+	ApprovalCode  string `json:"approval_code"`
+	Card          string `json:"card"`
+	Message       string `json:"message"`
+}
+
+// Authorize asks the terminal to charge the card presented by the customer
+func (t *Terminal) Authorize(ctx context.Context, charge Charge) (*Authorization, error) {
+	response, err := t.call(ctx, terminalRequest{Type: "authorize", Reference: charge.Reference, Amount: charge.Amount})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Authorization{
+		TransactionID: response.TransactionID,
+		ApprovalCode:  response.ApprovalCode,
+		Amount:        charge.Amount,
+		Card:          response.Card,
+	}, nil
+}
+
+// Capture confirms an authorized charge once the sale is stored
+func (t *Terminal) Capture(ctx context.Context, authorization Authorization) error {
+	_, err := t.call(ctx, terminalRequest{
+		Type:          "capture",
+		TransactionID: authorization.TransactionID,
+		Amount:        authorization.Amount,
+	})
+	return err
+}
+
+// Void cancels a charge that was not settled yet
+func (t *Terminal) Void(ctx context.Context, authorization Authorization) error {
+	_, err := t.call(ctx, terminalRequest{Type: "void", TransactionID: authorization.TransactionID})
+	return err
+}
+
+// Refund gives back part or all of a captured charge
+func (t *Terminal) Refund(ctx context.Context, authorization Authorization, amount int) error {
+	_, err := t.call(ctx, terminalRequest{
+		Type:          "refund",
+		TransactionID: authorization.TransactionID,
+		Amount:        amount,
+	})
+	return err
+}
+
+// call sends a request to the terminal and waits for its response
+func (t *Terminal) call(ctx context.Context, request terminalRequest) (*terminalResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", t.address)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, ErrTimeout
+		}
+		return nil, fmt.Errorf("payment terminal: connect to %q: %w", t.address, err)
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	if err := json.NewEncoder(conn).Encode(request); err != nil {
+		return nil, terminalError(request.Type, err)
+	}
+
+	line, err := bufio.NewReader(conn).ReadBytes('\n')
+	if err != nil {
+		return nil, terminalError(request.Type, err)
+	}
+
+	var response terminalResponse
+	if err := json.Unmarshal(line, &response); err != nil {
+		return nil, fmt.Errorf("payment terminal: %s: malformed response: %w", request.Type, err)
+	}
+
+	switch response.Result {
+	case resultApproved:
+		return &response, nil
+	case resultDeclined:
+		return nil, fmt.Errorf("%w: %s", ErrDeclined, response.Message)
+	default:
+		return nil, fmt.Errorf("payment terminal: %s failed: %s", request.Type, response.Message)
+	}
+}
+
+// terminalError maps a connection error, turning deadline errors into ErrTimeout
+func terminalError(operation string, err error) error {
+	if errors.Is(err, os.ErrDeadlineExceeded) {
+		return ErrTimeout
+	}
+	return fmt.Errorf("payment terminal: %s: %w", operation, err)
+}
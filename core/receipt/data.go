@@ -0,0 +1,369 @@
+package receipt
+
+import (
+	"fmt"
+	"time"
+
+	"neon/core/config"
+	"neon/core/helpers/enums"
+	"neon/core/models"
+)
+
+// TicketData is the data available to the ticket and void slip templates
+type TicketData struct {
+	Company config.CompanyConfig
+	Ticket  models.Ticket
+	// Date is the travel date
+	Date time.Time
+	// SaleDate is when the ticket was sold
+	SaleDate   time.Time
+	CopyNumber int
+	PrintedAt  time.Time
+	Void       *models.TicketVoid
+	// QRCode is the signed payload printed as a QR code, empty when not printed
+	QRCode string
+	// Barcode is the signed short code printed as a CODE128 barcode, empty when not printed
+	Barcode string
+	// Document is the electronic invoice of the ticket's sale, nil when it has none
+	Document *models.ElectronicDocument
+}
+
+// ReportData is the data available to the report template
+type ReportData struct {
+	Company   config.CompanyConfig
+	Report    models.Report
+	Timetable string
+	Prints    models.TicketPrintCounts
+	Sold      int
+	Expected  int
+	// Received is the cash delivered at the closes without the opening float
+	Received   int
+	Difference int
+	// TodayCash is the cash taken for travel on the sale date, apart from Report.AdvanceCash
+	TodayCash int
+	// Categories are the report's fare category totals with their printed labels
+	Categories []CategoryTotal
+	// Payments are the report's payment method totals with their printed labels
+	Payments []PaymentTotal
+	// OpeningCount, PartialCount and FinalCount are the bills and coins counted at the start, the drops
+	// (added up) and the total close, empty when the cash was given as a single amount
+	OpeningCount []DenominationCount
+	PartialCount []DenominationCount
+	FinalCount   []DenominationCount
+	// Segments are the cashiers' shifts, printed when the report was handed over
+	Segments []SegmentData
+	// Drops are the interim cash drops (retiros) with what each was reconciled against
+	Drops []DropData
+}
+
+// DropData is an interim cash drop of the report with the cash expected in the drawer at it
+type DropData struct {
+	Sequence     int
+	DroppedAt    string
+	DroppedBy    string
+	Tickets      int
+	Cash         int
+	Expected     int
+	CashReceived int
+	Difference   int
+	Count        []DenominationCount
+}
+
+// SegmentData is a cashier's shift on the report with the count of the handover that ended it
+type SegmentData struct {
+	Sequence  int
+	Username  string
+	StartedAt *string
+	EndedAt   *string
+	Tickets   int
+	CashTaken int
+	// Handover is set when a handover ended the shift, with the cash counted and expected in the drawer
+	Handover   bool
+	Cash       int
+	Expected   int
+	Difference int
+	Count      []DenominationCount
+}
+
+// SaleData is the data available to the sale summary template
+type SaleData struct {
+	Company  config.CompanyConfig
+	Sale     models.Sale
+	Date     time.Time
+	Payments []PaymentLine
+}
+
+// PaymentLine is a payment of a sale with its printed method name
+type PaymentLine struct {
+	Label     string
+	Amount    int
+	Tendered  int
+	Change    int
+	Reference string
+	// ApprovalCode is set on card payments charged on the card terminal
+	ApprovalCode string
+	// IsCash is set on cash payments, which print the amount tendered and the change
+	IsCash bool
+}
+
+// paymentLabels are the printed names of the payment methods
+var paymentLabels = map[enums.PaymentMethod]string{
+	enums.PaymentCash:  "Efectivo",
+	enums.PaymentCard:  "Tarjeta",
+	enums.PaymentSinpe: "SINPE Movil",
+}
+
+// paymentLabel returns the printed name of a payment method
+func paymentLabel(method enums.PaymentMethod) string {
+	if label, ok := paymentLabels[method]; ok {
+		return label
+	}
+	return string(method)
+}
+
+// NewSaleData builds the sale summary template data
+func NewSaleData(company config.CompanyConfig, sale models.Sale) SaleData {
+	date := time.Now()
+	if createdAt, err := time.Parse(time.RFC3339, sale.CreatedAt); err == nil {
+		date = createdAt.Local()
+	}
+
+	payments := make([]PaymentLine, 0, len(sale.Payments))
+	for _, payment := range sale.Payments {
+		payments = append(payments, PaymentLine{
+			Label:        paymentLabel(payment.Method),
+			Amount:       payment.Amount,
+			Tendered:     payment.Tendered,
+			Change:       payment.Change,
+			Reference:    payment.Reference,
+			ApprovalCode: payment.ApprovalCode,
+			IsCash:       payment.Method == enums.PaymentCash,
+		})
+	}
+
+	return SaleData{
+		Company:  company,
+		Sale:     sale,
+		Date:     date,
+		Payments: payments,
+	}
+}
+
+// DailySummaryData is the data available to the daily summary (Z-report) template
+type DailySummaryData struct {
+	Company config.CompanyConfig
+	Summary models.DailySummary
+	// Date is the business day
+	Date time.Time
+	// Number and Version are the Z-report's sequential number and the version of its figures
+	Number  int
+	Version int
+	// CopyNumber is 0 on the original and counts the copies after it
+	CopyNumber int
+	PrintedAt  time.Time
+	// Payments are the day's payment method totals with their printed labels
+	Payments []DailyPaymentLine
+}
+
+// DailyPaymentLine is a payment method line of the daily summary
+type DailyPaymentLine struct {
+	Label    string
+	Payments int
+	Amount   int
+}
+
+// NewDailySummaryData builds the daily summary template data for a print of its Z-report
+func NewDailySummaryData(company config.CompanyConfig, summary models.DailySummary, zReport models.ZReport, copyNumber int) DailySummaryData {
+	date := time.Now()
+	if businessDate, err := time.ParseInLocation("2006-01-02", summary.Date, time.Local); err == nil {
+		date = businessDate
+	}
+
+	payments := make([]DailyPaymentLine, 0, len(summary.Payments))
+	for _, total := range summary.Payments {
+		payments = append(payments, DailyPaymentLine{
+			Label:    paymentLabel(total.Method),
+			Payments: total.Payments,
+			Amount:   total.Amount,
+		})
+	}
+
+	return DailySummaryData{
+		Company:    company,
+		Summary:    summary,
+		Date:       date,
+		Number:     zReport.Number,
+		Version:    zReport.Version,
+		CopyNumber: copyNumber,
+		PrintedAt:  time.Now(),
+		Payments:   payments,
+	}
+}
+
+// CategoryTotal is a fare category line of the report
+type CategoryTotal struct {
+	Label   string
+	Tickets int
+	Cash    int
+}
+
+// DenominationCount is a bill or coin line of a report's cash count
+type DenominationCount struct {
+	Label    string
+	Quantity int
+	Amount   int
+}
+
+// PaymentTotal is a payment method line of the report
+type PaymentTotal struct {
+	Label    string
+	Payments int
+	Partial  int
+	Final    int
+	Total    int
+}
+
+// categoryLabels are the printed names of the fare categories
+var categoryLabels = map[enums.FareCategory]string{
+	enums.FareRegular:    "Regulares",
+	enums.FareGold:       "Oro",
+	enums.FareChild:      "Ninos",
+	enums.FareStudent:    "Estudiantes",
+	enums.FareDisability: "Discapacidad",
+	enums.FareStaff:      "Personal",
+}
+
+// NewTicketData builds the ticket template data. The sale date is taken from the ticket, or now; the
+// travel date is the ticket's, or the sale date.
+func NewTicketData(company config.CompanyConfig, ticket models.Ticket, copyNumber int) TicketData {
+	now := time.Now()
+	saleDate := now
+	if createdAt, err := time.Parse(time.RFC3339, ticket.CreatedAt); err == nil {
+		saleDate = createdAt.Local()
+	}
+	date := saleDate
+	if travelDate, err := time.ParseInLocation("2006-01-02", ticket.TravelDate, time.Local); err == nil {
+		date = travelDate
+	}
+
+	return TicketData{
+		Company:    company,
+		Ticket:     ticket,
+		Date:       date,
+		SaleDate:   saleDate,
+		CopyNumber: copyNumber,
+		PrintedAt:  now,
+	}
+}
+
+// NewReportData builds the report template data with the reconciliation totals
+func NewReportData(company config.CompanyConfig, report models.Report, prints models.TicketPrintCounts) ReportData {
+	timetable := "Regular"
+	if report.Timetable == enums.Holiday {
+		timetable = "Feriado"
+	}
+
+	// Only cash is counted in the drawer; card and SINPE Móvil payments are listed apart. The opening float
+	// is handed back with the cash and isn't a sale.
+	expected := report.PartialDrawer + report.FinalDrawer
+	received := report.PartialCashReceived + report.FinalCashReceived - report.OpeningFloat
+
+	payments := make([]PaymentTotal, 0, len(report.PaymentTotals))
+	for _, total := range report.PaymentTotals {
+		payments = append(payments, PaymentTotal{
+			Label:    paymentLabel(total.Method),
+			Payments: total.Payments,
+			Partial:  total.PartialAmount,
+			Final:    total.FinalAmount,
+			Total:    total.PartialAmount + total.FinalAmount,
+		})
+	}
+
+	categories := make([]CategoryTotal, 0, len(report.FareTotals))
+	for _, total := range report.FareTotals {
+		label, ok := categoryLabels[total.Category]
+		if !ok {
+			label = string(total.Category)
+		}
+		categories = append(categories, CategoryTotal{Label: label, Tickets: total.Tickets, Cash: total.Cash})
+	}
+
+	return ReportData{
+		Company:      company,
+		Report:       report,
+		Timetable:    timetable,
+		Prints:       prints,
+		Sold:         report.PartialTickets + report.FinalTickets,
+		Expected:     expected,
+		Received:     received,
+		Difference:   received - expected,
+		TodayCash:    report.PartialCash + report.FinalCash - report.AdvanceCash,
+		Categories:   categories,
+		Payments:     payments,
+		OpeningCount: denominationCounts(report.OpeningCount),
+		PartialCount: denominationCounts(report.PartialCount),
+		FinalCount:   denominationCounts(report.FinalCount),
+		Segments:     segments(report.Segments),
+		Drops:        drops(report.Drops),
+	}
+}
+
+// drops prints the drops of a report
+func drops(reportDrops []models.ReportDrop) []DropData {
+	lines := make([]DropData, 0, len(reportDrops))
+	for _, drop := range reportDrops {
+		lines = append(lines, DropData{
+			Sequence:     drop.Sequence,
+			DroppedAt:    drop.DroppedAt,
+			DroppedBy:    drop.DroppedBy,
+			Tickets:      drop.Tickets,
+			Cash:         drop.Cash,
+			Expected:     drop.Drawer,
+			CashReceived: drop.CashReceived,
+			Difference:   drop.CashReceived - drop.Drawer,
+			Count:        denominationCounts(drop.Count),
+		})
+	}
+	return lines
+}
+
+// segments prints the shifts of a report
+func segments(reportSegments []models.ReportSegment) []SegmentData {
+	lines := make([]SegmentData, 0, len(reportSegments))
+	for _, segment := range reportSegments {
+		line := SegmentData{
+			Sequence:  segment.Sequence,
+			Username:  segment.Username,
+			StartedAt: segment.StartedAt,
+			EndedAt:   segment.EndedAt,
+			Tickets:   segment.Tickets,
+			CashTaken: segment.CashTaken,
+		}
+		if handover := segment.Handover; handover != nil {
+			line.Handover = true
+			line.Cash = handover.Cash
+			line.Expected = handover.Expected
+			line.Difference = handover.Cash - handover.Expected
+			line.Count = denominationCounts(handover.Count)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// denominationCounts labels the bills and coins of a cash count
+func denominationCounts(counts []models.CashCount) []DenominationCount {
+	lines := make([]DenominationCount, 0, len(counts))
+	for _, count := range counts {
+		kind := "Billete"
+		if count.Denomination.IsCoin() {
+			kind = "Moneda"
+		}
+		lines = append(lines, DenominationCount{
+			Label:    fmt.Sprintf("%s %d", kind, count.Denomination),
+			Quantity: count.Quantity,
+			Amount:   count.Amount,
+		})
+	}
+	return lines
+}
@@ -0,0 +1,67 @@
+package receipt
+
+import (
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// funcs are the helpers available to every receipt template
+var funcs = template.FuncMap{
+	"money":    money,
+	"date":     formatTime("02/01/2006"),
+	"datetime": formatTime("02/01/2006 15:04:05"),
+	"upper":    strings.ToUpper,
+	"add":      func(a, b int) int { return a + b },
+	"sub":      func(a, b int) int { return a - b },
+}
+
+// money formats an amount of colones with thousands separators
+func money(amount int) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	digits := strconv.Itoa(amount)
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(d)
+	}
+	return sign + b.String()
+}
+
+// formatTime formats a time.Time, or an RFC3339 string or *string, with layout. Nil or unparsable values render empty.
+func formatTime(layout string) func(value any) string {
+	return func(value any) string {
+		switch v := value.(type) {
+		case time.Time:
+			return v.Format(layout)
+		case *time.Time:
+			if v == nil {
+				return ""
+			}
+			return v.Format(layout)
+		case string:
+			return parseAndFormat(v, layout)
+		case *string:
+			if v == nil {
+				return ""
+			}
+			return parseAndFormat(*v, layout)
+		}
+		return ""
+	}
+}
+
+func parseAndFormat(value string, layout string) string {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return ""
+	}
+	return t.Local().Format(layout)
+}
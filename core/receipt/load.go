@@ -0,0 +1,86 @@
+package receipt
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"neon/core/helpers"
+)
+
+const (
+	// TicketTemplate is the name of the ticket receipt template
+	TicketTemplate = "ticket"
+	// VoidSlipTemplate is the name of the void slip template
+	VoidSlipTemplate = "void_slip"
+	// ReportTemplate is the name of the report summary template
+	ReportTemplate = "report"
+	// SaleTemplate is the name of the sale summary template
+	SaleTemplate = "sale"
+	// DailySummaryTemplate is the name of the daily summary (Z-report) template
+	DailySummaryTemplate = "daily_summary"
+
+	templatesDir = "templates"
+)
+
+// Names are the names of every receipt template. No other name is read from disk.
+var Names = []string{TicketTemplate, VoidSlipTemplate, ReportTemplate, SaleTemplate, DailySummaryTemplate}
+
+//go:embed defaults/*.yaml
+var defaults embed.FS
+
+// Default returns the built-in layout of a template
+func Default(name string) (*Template, error) {
+	if !isKnown(name) {
+		return nil, fmt.Errorf("unknown receipt template %q", name)
+	}
+	source, err := defaults.ReadFile("defaults/" + name + ".yaml")
+	if err != nil {
+		return nil, fmt.Errorf("unknown receipt template %q", name)
+	}
+	return Parse(name, source)
+}
+
+// Load returns the template installed in <app config dir>/templates/<name>.yaml, or the built-in
+// layout when no file is installed
+func Load(name string) (*Template, error) {
+	path, err := Path(name)
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return Default(name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read receipt template %q: %w", name, err)
+	}
+
+	return Parse(name, source)
+}
+
+// Path returns where the installed template file of a given name lives. Names other than the known
+// templates are rejected, so a name can't reach outside the templates directory.
+func Path(name string) (string, error) {
+	if !isKnown(name) {
+		return "", fmt.Errorf("unknown receipt template %q", name)
+	}
+	appDir, err := helpers.GetAppDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(appDir, templatesDir, name+".yaml"), nil
+}
+
+func isKnown(name string) bool {
+	for _, known := range Names {
+		if name == known {
+			return true
+		}
+	}
+	return false
+}
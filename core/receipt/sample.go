@@ -0,0 +1,257 @@
+package receipt
+
+import (
+	"fmt"
+	"time"
+
+	"neon/core/config"
+	"neon/core/helpers/enums"
+	"neon/core/models"
+)
+
+// SampleData returns representative data for a template, used by previews
+func SampleData(name string, company config.CompanyConfig) (any, error) {
+	now := time.Now()
+	createdAt := now.Format(time.RFC3339)
+	username := "cajero"
+
+	ticket := models.Ticket{
+		ID:          1024,
+		Departure:   "San Isidro",
+		Destination: "San Vito",
+		Username:    username,
+		Stop:        "Buenos Aires",
+		Time:        "14:30",
+		Fare:        3450,
+		IDNumber:    "",
+		ReportID:    12,
+		CreatedAt:   createdAt,
+		TravelDate:  now.Format("2006-01-02"),
+		SeatNumber:  14,
+	}
+	document := &models.ElectronicDocument{
+		SaleID:       310,
+		ReportID:     ticket.ReportID,
+		DocumentType: "04",
+		Clave:        "50601012600310123456700100001040000000310112345678",
+		Consecutive:  "00100001040000000310",
+		Status:       enums.OutboxPending,
+	}
+
+	switch name {
+	case TicketTemplate:
+		data := NewTicketData(company, ticket, 1)
+		data.QRCode = "NT1.MTAyNHwxMnxTYW4gVml0b3xCdWVub3MgQWlyZXN8MTQ6MzB8MzQ1MHwwfDIwMjYwMTAx.c2FtcGxlLXNpZ25hdHVyZQ"
+		data.Document = document
+		return data, nil
+	case SaleTemplate:
+		second := ticket
+		second.ID = 1025
+		second.SeatNumber = 15
+		return NewSaleData(company, models.Sale{
+			ID:          310,
+			ReportID:    ticket.ReportID,
+			Username:    username,
+			Total:       ticket.Fare * 2,
+			TicketCount: 2,
+			CreatedAt:   createdAt,
+			Tickets:     []models.Ticket{ticket, second},
+			Payments: []models.SalePayment{{
+				Method:   enums.PaymentCash,
+				Amount:   ticket.Fare * 2,
+				Tendered: 10000,
+				Change:   10000 - ticket.Fare*2,
+			}},
+			Document: document,
+		}), nil
+	case VoidSlipTemplate:
+		ticket.IsNull = true
+		data := NewTicketData(company, ticket, 0)
+		data.Void = &models.TicketVoid{
+			TicketID:  ticket.ID,
+			ReportID:  ticket.ReportID,
+			Reason:    enums.VoidCustomerRequest,
+			Fare:      ticket.Fare,
+			Bucket:    models.VoidBucketFinal,
+			VoidedBy:  username,
+			CreatedAt: createdAt,
+		}
+		return data, nil
+	case ReportTemplate:
+		report := models.Report{
+			ID:                  12,
+			Username:            username,
+			Timetable:           enums.Regular,
+			PartialTickets:      40,
+			PartialCash:         138000,
+			PartialCashReceived: 103500,
+			FinalTickets:        25,
+			FinalCash:           86250,
+			FinalCashReceived:   72000,
+			TotalGold:           5,
+			TotalGoldCash:       0,
+			TotalNull:           1,
+			TotalNullCash:       3450,
+			TotalRegular:        60,
+			TotalRegularCash:    224250,
+			TotalSales:          48,
+			CreatedAt:           &createdAt,
+			PartialClosedAt:     &createdAt,
+			ClosedAt:            &createdAt,
+			PartialClosedBy:     &username,
+			ClosedBy:            &username,
+			FareTotals: []models.ReportFareTotal{
+				{ReportID: 12, Category: enums.FareRegular, Tickets: 52, Cash: 179400},
+				{ReportID: 12, Category: enums.FareGold, Tickets: 5, Cash: 0},
+				{ReportID: 12, Category: enums.FareStudent, Tickets: 8, Cash: 20700},
+			},
+			PaymentTotals: []models.ReportPaymentTotal{
+				{ReportID: 12, Method: enums.PaymentCash, Payments: 33, PartialAmount: 103500, FinalAmount: 62100},
+				{ReportID: 12, Method: enums.PaymentCard, Payments: 6, FinalAmount: 24150},
+				{ReportID: 12, Method: enums.PaymentSinpe, Payments: 9, PartialAmount: 34500},
+			},
+			PartialDrawer: 103500,
+			FinalDrawer:   62100,
+			OpeningFloat:  10000,
+			OpeningCount: []models.CashCount{
+				{ReportID: 12, Stage: enums.CountOpening, Denomination: enums.Bill1000, Quantity: 5, Amount: 5000},
+				{ReportID: 12, Stage: enums.CountOpening, Denomination: enums.Coin500, Quantity: 6, Amount: 3000},
+				{ReportID: 12, Stage: enums.CountOpening, Denomination: enums.Coin100, Quantity: 20, Amount: 2000},
+			},
+			FinalCount: []models.CashCount{
+				{ReportID: 12, Stage: enums.CountFinal, Denomination: enums.Bill20000, Quantity: 1, Amount: 20000},
+				{ReportID: 12, Stage: enums.CountFinal, Denomination: enums.Bill10000, Quantity: 3, Amount: 30000},
+				{ReportID: 12, Stage: enums.CountFinal, Denomination: enums.Bill5000, Quantity: 3, Amount: 15000},
+				{ReportID: 12, Stage: enums.CountFinal, Denomination: enums.Bill2000, Quantity: 2, Amount: 4000},
+				{ReportID: 12, Stage: enums.CountFinal, Denomination: enums.Bill1000, Quantity: 2, Amount: 2000},
+				{ReportID: 12, Stage: enums.CountFinal, Denomination: enums.Coin500, Quantity: 1, Amount: 500},
+				{ReportID: 12, Stage: enums.CountFinal, Denomination: enums.Coin100, Quantity: 4, Amount: 400},
+				{ReportID: 12, Stage: enums.CountFinal, Denomination: enums.Coin50, Quantity: 2, Amount: 100},
+			},
+			Drops: []models.ReportDrop{
+				{
+					ReportID:     12,
+					Sequence:     1,
+					DroppedAt:    createdAt,
+					DroppedBy:    username,
+					CashReceived: 58650,
+					Tickets:      22,
+					Cash:         75900,
+					Drawer:       58650,
+					Count: []models.CashCount{
+						{ReportID: 12, Stage: enums.CountPartial, Denomination: enums.Bill20000, Quantity: 2, Amount: 40000},
+						{ReportID: 12, Stage: enums.CountPartial, Denomination: enums.Bill10000, Quantity: 1, Amount: 10000},
+						{ReportID: 12, Stage: enums.CountPartial, Denomination: enums.Bill5000, Quantity: 1, Amount: 5000},
+						{ReportID: 12, Stage: enums.CountPartial, Denomination: enums.Bill2000, Quantity: 1, Amount: 2000},
+						{ReportID: 12, Stage: enums.CountPartial, Denomination: enums.Bill1000, Quantity: 1, Amount: 1000},
+						{ReportID: 12, Stage: enums.CountPartial, Denomination: enums.Coin500, Quantity: 1, Amount: 500},
+						{ReportID: 12, Stage: enums.CountPartial, Denomination: enums.Coin50, Quantity: 3, Amount: 150},
+					},
+				},
+				{
+					ReportID:     12,
+					Sequence:     2,
+					DroppedAt:    createdAt,
+					DroppedBy:    "maria",
+					CashReceived: 44850,
+					Tickets:      18,
+					Cash:         62100,
+					Drawer:       44850,
+					Count:        []models.CashCount{},
+				},
+			},
+			Cashier: "maria",
+			Segments: []models.ReportSegment{
+				{
+					Sequence:  1,
+					Username:  username,
+					StartedAt: &createdAt,
+					EndedAt:   &createdAt,
+					Tickets:   45,
+					CashTaken: 120000,
+					Handover: &models.ReportHandover{
+						ID:           1,
+						ReportID:     12,
+						Sequence:     1,
+						FromUsername: username,
+						ToUsername:   "maria",
+						Tickets:      45,
+						CashTaken:    120000,
+						Expected:     26500,
+						Cash:         26500,
+						CountedAt:    createdAt,
+						AcceptedAt:   &createdAt,
+						Count: []models.CashCount{
+							{ReportID: 12, Stage: enums.CountHandover, Denomination: enums.Bill10000, Quantity: 2, Amount: 20000},
+							{ReportID: 12, Stage: enums.CountHandover, Denomination: enums.Bill5000, Quantity: 1, Amount: 5000},
+							{ReportID: 12, Stage: enums.CountHandover, Denomination: enums.Coin500, Quantity: 3, Amount: 1500},
+						},
+					},
+				},
+				{Sequence: 2, Username: "maria", StartedAt: &createdAt, EndedAt: &createdAt, Tickets: 20, CashTaken: 45600},
+			},
+			Recounts: []models.ReportRecount{
+				{
+					ID:               1,
+					ReportID:         12,
+					Stage:            enums.CountFinal,
+					Expected:         72100,
+					PreviousReceived: 62000,
+					Received:         72000,
+					Variance:         -100,
+					Reason:           "Billete de 10000 en la gaveta",
+					RecountedBy:      "admin",
+					RecountedAt:      createdAt,
+				},
+			},
+		}
+		return NewReportData(company, report, models.TicketPrintCounts{Reprints: 1, VoidSlips: 1}), nil
+	case DailySummaryTemplate:
+		date := now.Format("2006-01-02")
+		summary := models.DailySummary{
+			Date:         date,
+			Reports:      3,
+			OpenReports:  1,
+			Sales:        96,
+			Tickets:      128,
+			Cash:         441600,
+			Gold:         9,
+			GoldCash:     0,
+			Regular:      119,
+			RegularCash:  441600,
+			Null:         2,
+			NullCash:     6900,
+			OpeningFloat: 20000,
+			Expected:     300150,
+			Received:     300050,
+			Difference:   -100,
+			Routes: []models.DailyRouteTotal{
+				{Departure: "San Isidro", Destination: "San Vito", Tickets: 86, Cash: 296700},
+				{Departure: "San Vito", Destination: "San Isidro", Tickets: 42, Cash: 144900},
+			},
+			Stops: []models.DailyStopTotal{
+				{Departure: "San Isidro", Destination: "San Vito", Stop: "Buenos Aires", Tickets: 51, Cash: 175950},
+				{Departure: "San Isidro", Destination: "San Vito", Stop: "San Vito", Tickets: 35, Cash: 120750},
+				{Departure: "San Vito", Destination: "San Isidro", Stop: "San Isidro", Tickets: 42, Cash: 144900},
+			},
+			Departures: []models.DailyDepartureTotal{
+				{Departure: "San Isidro", Destination: "San Vito", Time: "05:30", Tickets: 40, Cash: 138000},
+				{Departure: "San Isidro", Destination: "San Vito", Time: "14:30", Tickets: 46, Cash: 158700},
+				{Departure: "San Vito", Destination: "San Isidro", Time: "10:00", Tickets: 42, Cash: 144900},
+			},
+			Cashiers: []models.DailyCashierTotal{
+				{Username: username, Reports: 2, Tickets: 85, Cash: 293250, Variance: -100},
+				{Username: "maria", Reports: 2, Tickets: 43, Cash: 148350, Variance: 0},
+			},
+			Payments: []models.DailyPaymentTotal{
+				{Method: enums.PaymentCash, Payments: 70, Amount: 300150},
+				{Method: enums.PaymentCard, Payments: 11, Amount: 58650},
+				{Method: enums.PaymentSinpe, Payments: 15, Amount: 82800},
+			},
+		}
+		zReport := models.ZReport{ID: 1, Number: 42, BusinessDate: date, Version: 2, PrintedBy: "admin", PrintedAt: createdAt}
+		return NewDailySummaryData(company, summary, zReport, 1), nil
+	}
+
+	return nil, fmt.Errorf("unknown receipt template %q", name)
+}
@@ -0,0 +1,128 @@
+package receipt
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Target receives the printer operations produced by rendering a template
+type Target interface {
+	Initialize() error
+	Justify(justification Justification)
+	Size(width int, height int)
+	Style(style Style)
+	Bold(bold bool)
+	Print(text string)
+	Println(text string)
+	Feed(lines int)
+	Barcode(code string) error
+	QR(code string) error
+	Cut() error
+}
+
+// TextTarget renders a template to plain text, used to preview layouts without a printer
+type TextTarget struct {
+	width   int
+	scale   int
+	justify Justification
+	line    strings.Builder
+	out     strings.Builder
+}
+
+// NewTextTarget creates a plain-text target with the given characters per line
+func NewTextTarget(width int) *TextTarget {
+	if width <= 0 {
+		width = DefaultWidth
+	}
+	return &TextTarget{width: width, scale: 1, justify: Left}
+}
+
+// String returns the rendered text
+func (t *TextTarget) String() string {
+	if t.line.Len() > 0 {
+		t.flush()
+	}
+	return t.out.String()
+}
+
+// Initialize resets the formatting like ESC @
+func (t *TextTarget) Initialize() error {
+	t.scale = 1
+	t.justify = Left
+	return nil
+}
+
+// Justify sets the alignment of the following lines
+func (t *TextTarget) Justify(justification Justification) { t.justify = justification }
+
+// Size sets the character width multiplier used to compute the line length
+func (t *TextTarget) Size(width int, _ int) {
+	if width < 1 {
+		width = 1
+	}
+	t.scale = width
+}
+
+// Style is ignored by the text target
+func (t *TextTarget) Style(Style) {}
+
+// Bold is ignored by the text target
+func (t *TextTarget) Bold(bool) {}
+
+// Print writes text without ending the line
+func (t *TextTarget) Print(text string) { t.line.WriteString(text) }
+
+// Println writes text and ends the line
+func (t *TextTarget) Println(text string) {
+	t.line.WriteString(text)
+	t.flush()
+}
+
+// Feed writes empty lines
+func (t *TextTarget) Feed(lines int) {
+	for i := 0; i < lines; i++ {
+		t.flush()
+	}
+}
+
+// Barcode writes a barcode placeholder line
+func (t *TextTarget) Barcode(code string) error {
+	t.placeholder(fmt.Sprintf("[BARCODE %s]", code))
+	return nil
+}
+
+// QR writes a QR code placeholder line
+func (t *TextTarget) QR(code string) error {
+	t.placeholder(fmt.Sprintf("[QR %s]", code))
+	return nil
+}
+
+// Cut writes a cut marker line
+func (t *TextTarget) Cut() error {
+	t.placeholder(strings.Repeat("~", t.width))
+	return nil
+}
+
+func (t *TextTarget) placeholder(text string) {
+	if t.line.Len() > 0 {
+		t.flush()
+	}
+	t.line.WriteString(text)
+	t.flush()
+}
+
+func (t *TextTarget) flush() {
+	text := t.line.String()
+	t.line.Reset()
+
+	pad := t.width/t.scale - len([]rune(text))
+	switch {
+	case pad <= 0:
+	case t.justify == Center:
+		text = strings.Repeat(" ", pad/2) + text
+	case t.justify == Right:
+		text = strings.Repeat(" ", pad) + text
+	}
+	t.out.WriteString(strings.TrimRight(text, " "))
+	t.out.WriteString("\n")
+}
@@ -0,0 +1,221 @@
+// Package receipt provides configurable receipt templates rendered to ESC/POS printers or plain text
+package receipt
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultWidth is the number of characters per line on 58mm paper
+const DefaultWidth = 32
+
+// Justification is the horizontal alignment of printed text
+type Justification string
+
+const (
+	// Left aligns text to the left margin
+	Left Justification = "left"
+	// Center centers text
+	Center Justification = "center"
+	// Right aligns text to the right margin
+	Right Justification = "right"
+)
+
+// Style is a printer font style
+type Style string
+
+const (
+	// StyleThin selects the thin (font B) style
+	StyleThin Style = "thin"
+	// StyleBold selects the emphasized style
+	StyleBold Style = "bold"
+	// StyleUnderline selects the underlined style
+	StyleUnderline Style = "underline"
+)
+
+// Directive is one step of a receipt template. Formatting fields (justify, size, style, bold) are
+// applied first and stay in effect for the following directives, like the printer's own state.
+// Then the content fields are printed in the order they are declared below. Every string field
+// except justify and style is a Go text/template evaluated against the receipt data.
+type Directive struct {
+	// If skips the directive when it renders to "", "false", "0" or "<nil>"
+	If        string        `yaml:"if"`
+	Justify   Justification `yaml:"justify"`
+	Size      []int         `yaml:"size"`
+	Style     Style         `yaml:"style"`
+	Bold      *bool         `yaml:"bold"`
+	Inline    string        `yaml:"inline"`
+	Text      string        `yaml:"text"`
+	Separator string        `yaml:"separator"`
+	Barcode   string        `yaml:"barcode"`
+	QR        string        `yaml:"qr"`
+	Feed      int           `yaml:"feed"`
+	Cut       bool          `yaml:"cut"`
+}
+
+// Template is a named receipt layout
+type Template struct {
+	Name  string      `yaml:"name"`
+	Width int         `yaml:"width"`
+	Lines []Directive `yaml:"lines"`
+}
+
+// Parse parses a YAML receipt template and checks that every text template compiles
+func Parse(name string, source []byte) (*Template, error) {
+	var tpl Template
+	if err := yaml.Unmarshal(source, &tpl); err != nil {
+		return nil, fmt.Errorf("parse receipt template %q: %w", name, err)
+	}
+	if tpl.Name == "" {
+		tpl.Name = name
+	}
+	if tpl.Width <= 0 {
+		tpl.Width = DefaultWidth
+	}
+
+	for i, line := range tpl.Lines {
+		for _, field := range []string{line.If, line.Inline, line.Text, line.Separator, line.Barcode, line.QR} {
+			if _, err := compile(field); err != nil {
+				return nil, fmt.Errorf("receipt template %q line %d: %w", tpl.Name, i+1, err)
+			}
+		}
+		switch line.Justify {
+		case "", Left, Center, Right:
+		default:
+			return nil, fmt.Errorf("receipt template %q line %d: unknown justify %q", tpl.Name, i+1, line.Justify)
+		}
+		switch line.Style {
+		case "", StyleThin, StyleBold, StyleUnderline:
+		default:
+			return nil, fmt.Errorf("receipt template %q line %d: unknown style %q", tpl.Name, i+1, line.Style)
+		}
+		if len(line.Size) != 0 && len(line.Size) != 2 {
+			return nil, fmt.Errorf("receipt template %q line %d: size must be [width, height]", tpl.Name, i+1)
+		}
+	}
+
+	return &tpl, nil
+}
+
+// Render runs the template against data, sending every printer operation to target
+func (t *Template) Render(data any, target Target) error {
+	if err := target.Initialize(); err != nil {
+		return err
+	}
+
+	for i, line := range t.Lines {
+		if err := t.renderLine(line, data, target); err != nil {
+			return fmt.Errorf("receipt template %q line %d: %w", t.Name, i+1, err)
+		}
+	}
+
+	return nil
+}
+
+func (t *Template) renderLine(line Directive, data any, target Target) error {
+	if line.If != "" {
+		cond, err := execute(line.If, data)
+		if err != nil {
+			return err
+		}
+		switch strings.TrimSpace(cond) {
+		case "", "false", "0", "<nil>":
+			return nil
+		}
+	}
+
+	if line.Justify != "" {
+		target.Justify(line.Justify)
+	}
+	if len(line.Size) == 2 {
+		target.Size(line.Size[0], line.Size[1])
+	}
+	if line.Style != "" {
+		target.Style(line.Style)
+	}
+	if line.Bold != nil {
+		target.Bold(*line.Bold)
+	}
+
+	if line.Inline != "" {
+		text, err := execute(line.Inline, data)
+		if err != nil {
+			return err
+		}
+		target.Print(text)
+	}
+
+	if line.Text != "" {
+		text, err := execute(line.Text, data)
+		if err != nil {
+			return err
+		}
+		for _, l := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+			target.Println(l)
+		}
+	}
+
+	if line.Separator != "" {
+		sep, err := execute(line.Separator, data)
+		if err != nil {
+			return err
+		}
+		if sep != "" {
+			target.Println(strings.Repeat(sep, t.Width/len(sep)))
+		}
+	}
+
+	if line.Barcode != "" {
+		code, err := execute(line.Barcode, data)
+		if err != nil {
+			return err
+		}
+		if code != "" {
+			if err := target.Barcode(code); err != nil {
+				return err
+			}
+		}
+	}
+
+	if line.QR != "" {
+		code, err := execute(line.QR, data)
+		if err != nil {
+			return err
+		}
+		if code != "" {
+			if err := target.QR(code); err != nil {
+				return err
+			}
+		}
+	}
+
+	if line.Feed > 0 {
+		target.Feed(line.Feed)
+	}
+
+	if line.Cut {
+		return target.Cut()
+	}
+
+	return nil
+}
+
+func compile(text string) (*template.Template, error) {
+	return template.New("line").Funcs(funcs).Option("missingkey=zero").Parse(text)
+}
+
+func execute(text string, data any) (string, error) {
+	tpl, err := compile(text)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,162 @@
+package local
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"neon/core/database/embedded"
+	"neon/core/models"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+// TicketBoardingRepository implements TicketBoardingRepository for SQLite using goqu
+type TicketBoardingRepository struct {
+	ctx context.Context
+	db  *embedded.SQLite
+}
+
+// NewTicketBoardingRepository creates a new ticket boarding repository
+func NewTicketBoardingRepository(ctx context.Context, db *embedded.SQLite) *TicketBoardingRepository {
+	return &TicketBoardingRepository{
+		ctx: ctx,
+		db:  db,
+	}
+}
+
+// AddTx records a ticket boarding inside the caller's transaction
+func (r *TicketBoardingRepository) AddTx(tx *sql.Tx, boarding models.TicketBoarding) (*models.TicketBoarding, error) {
+	query := dialect.Insert(TableTicketBoardings).Rows(boarding)
+
+	sql, args, err := query.Prepared(true).ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	result, err := tx.Exec(sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add ticket boarding: %w", err)
+	}
+
+	generatedID, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get generated ID: %w", err)
+	}
+
+	boarding.ID = generatedID
+
+	return &boarding, nil
+}
+
+// GetByTicketID gets the boarding of a ticket. Returns sql.ErrNoRows when the ticket hasn't boarded.
+func (r *TicketBoardingRepository) GetByTicketID(ticketID int64) (*models.TicketBoarding, error) {
+	sql, args, err := ticketBoardingQuery(ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanTicketBoarding(r.db.GetDB().QueryRow(sql, args...))
+}
+
+// GetByTicketIDTx gets the boarding of a ticket inside the caller's transaction. Returns
+// sql.ErrNoRows when the ticket hasn't boarded.
+func (r *TicketBoardingRepository) GetByTicketIDTx(tx *sql.Tx, ticketID int64) (*models.TicketBoarding, error) {
+	sql, args, err := ticketBoardingQuery(ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanTicketBoarding(tx.QueryRow(sql, args...))
+}
+
+func ticketBoardingQuery(ticketID int64) (string, []interface{}, error) {
+	query := dialect.Select("id", "ticket_id", "device", "boarded_at").
+		From(TableTicketBoardings).
+		Where(ColumnTicketID.Eq(ticketID))
+
+	sql, args, err := query.Prepared(true).ToSQL()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	return sql, args, nil
+}
+
+func scanTicketBoarding(row interface{ Scan(dest ...any) error }) (*models.TicketBoarding, error) {
+	var boarding models.TicketBoarding
+	err := row.Scan(
+		&boarding.ID,
+		&boarding.TicketID,
+		&boarding.Device,
+		&boarding.BoardedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &boarding, nil
+}
+
+// GetManifestEntries gets the tickets sold for a route travelling on travelDate, with their boarding
+// time. Tickets without a travel date are matched on the half-open range [from, to) of created_at.
+// Voided tickets are left out.
+func (r *TicketBoardingRepository) GetManifestEntries(departure string, destination string, travelDate string, from string, to string) ([]models.ManifestEntry, error) {
+	query := dialect.Select(
+		TableTickets.Col("id"),
+		TableTickets.Col("time"),
+		TableTickets.Col("is_gold"),
+		TableTickets.Col("created_at"),
+		TableTickets.Col("travel_date"),
+		TableTicketBoardings.Col("boarded_at"),
+	).From(TableTickets).
+		LeftJoin(TableTicketBoardings, goqu.On(
+			TableTicketBoardings.Col("ticket_id").Eq(TableTickets.Col("id")),
+		)).
+		Where(
+			TableTickets.Col("departure").Eq(departure),
+			TableTickets.Col("destination").Eq(destination),
+			TableTickets.Col("is_null").Eq(false),
+			goqu.Or(
+				TableTickets.Col("travel_date").Eq(travelDate),
+				goqu.And(
+					TableTickets.Col("travel_date").Eq(""),
+					TableTickets.Col("created_at").Gte(from),
+					TableTickets.Col("created_at").Lt(to),
+				),
+			),
+		).
+		Order(TableTickets.Col("id").Asc())
+
+	sql, args, err := query.Prepared(true).ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	rows, err := r.db.GetDB().Query(sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query manifest: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []models.ManifestEntry
+	for rows.Next() {
+		var entry models.ManifestEntry
+		if err := rows.Scan(
+			&entry.TicketID,
+			&entry.Time,
+			&entry.IsGold,
+			&entry.CreatedAt,
+			&entry.TravelDate,
+			&entry.BoardedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan manifest entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate manifest: %w", err)
+	}
+
+	return entries, nil
+}
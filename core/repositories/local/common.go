@@ -11,6 +11,46 @@ var (
 	TableTickets = goqu.T(constants.TicketsTable)
 	// TableReports is the table name for the reports table
 	TableReports = goqu.T(constants.ReportsTable)
+	// TableTicketVoids is the table name for the ticket voids table
+	TableTicketVoids = goqu.T(constants.TicketVoidsTable)
+	// TableTicketPrints is the table name for the ticket prints table
+	TableTicketPrints = goqu.T(constants.TicketPrintsTable)
+	// TableTicketBoardings is the table name for the ticket boardings table
+	TableTicketBoardings = goqu.T(constants.TicketBoardingsTable)
+	// TableSeatInventory is the table name for the seat inventory table
+	TableSeatInventory = goqu.T(constants.SeatInventoryTable)
+	// TableSeatReservations is the table name for the seat reservations table
+	TableSeatReservations = goqu.T(constants.SeatReservationsTable)
+	// TableReportFareTotals is the table name for the report fare totals table
+	TableReportFareTotals = goqu.T(constants.ReportFareTotalsTable)
+	// TableSales is the table name for the sales table
+	TableSales = goqu.T(constants.SalesTable)
+	// TableSalePayments is the table name for the sale payments table
+	TableSalePayments = goqu.T(constants.SalePaymentsTable)
+	// TableReportPaymentTotals is the table name for the report payment totals table
+	TableReportPaymentTotals = goqu.T(constants.ReportPaymentTotalsTable)
+	// TableElectronicDocuments is the table name for the electronic documents table
+	TableElectronicDocuments = goqu.T(constants.ElectronicDocumentsTable)
+	// TableEInvoiceSequences is the table name for the e-invoice sequences table
+	TableEInvoiceSequences = goqu.T(constants.EInvoiceSequencesTable)
+	// TableReportCashCounts is the table name for the report cash counts table
+	TableReportCashCounts = goqu.T(constants.ReportCashCountsTable)
+	// TableReportRecounts is the table name for the report recounts table
+	TableReportRecounts = goqu.T(constants.ReportRecountsTable)
+	// TableReportHandovers is the table name for the report handovers table
+	TableReportHandovers = goqu.T(constants.ReportHandoversTable)
+	// TableReportHandoverCounts is the table name for the report handover counts table
+	TableReportHandoverCounts = goqu.T(constants.ReportHandoverCountsTable)
+	// TableReportDrops is the table name for the report drops table
+	TableReportDrops = goqu.T(constants.ReportDropsTable)
+	// TableReportDropCounts is the table name for the report drop counts table
+	TableReportDropCounts = goqu.T(constants.ReportDropCountsTable)
+	// TableZReports is the table name for the z reports table
+	TableZReports = goqu.T(constants.ZReportsTable)
+	// TableZReportPrints is the table name for the z report prints table
+	TableZReportPrints = goqu.T(constants.ZReportPrintsTable)
+	// TableOutboxMessages is the table name for the outbox messages table
+	TableOutboxMessages = goqu.T(constants.OutboxMessagesTable)
 
 	// ColumnID is the column name for the id column
 	ColumnID = goqu.C("id")
@@ -18,6 +58,12 @@ var (
 	// ColumnReportID is the column name for the report_id column
 	ColumnReportID = goqu.C("report_id")
 
+	// ColumnTicketID is the column name for the ticket_id column
+	ColumnTicketID = goqu.C("ticket_id")
+
+	// ColumnSaleID is the column name for the sale_id column
+	ColumnSaleID = goqu.C("sale_id")
+
 	// ColumnStatus is the column name for the status column
 	ColumnStatus = goqu.C("status")
 
@@ -30,5 +76,8 @@ var (
 	// ColumnUsername is the column name for the username column
 	ColumnUsername = "username"
 
+	// ColumnIDNumber is the column name for the id_number column
+	ColumnIDNumber = "id_number"
+
 	dialect = goqu.Dialect("sqlite3")
 )
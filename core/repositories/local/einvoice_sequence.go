@@ -0,0 +1,65 @@
+package local
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"neon/core/database/embedded"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+// EInvoiceSequenceRepository implements EInvoiceSequenceRepository for SQLite using goqu
+type EInvoiceSequenceRepository struct {
+	ctx context.Context
+	db  *embedded.SQLite
+}
+
+// NewEInvoiceSequenceRepository creates a new e-invoice sequence repository
+func NewEInvoiceSequenceRepository(ctx context.Context, db *embedded.SQLite) *EInvoiceSequenceRepository {
+	return &EInvoiceSequenceRepository{
+		ctx: ctx,
+		db:  db,
+	}
+}
+
+// NextTx takes the next sequence of a branch, terminal and document type inside the caller's
+// transaction. Sequences start at 1; a sale rolled back gives its sequence back, so the numbers have no gaps.
+func (r *EInvoiceSequenceRepository) NextTx(tx *sql.Tx, branch int, terminal int, documentType string) (int64, error) {
+	insert := dialect.Insert(TableEInvoiceSequences).Rows(goqu.Record{
+		"branch":        branch,
+		"terminal":      terminal,
+		"document_type": documentType,
+		"last_sequence": 1,
+	}).OnConflict(goqu.DoUpdate(
+		"branch, terminal, document_type",
+		goqu.Record{"last_sequence": goqu.L("last_sequence + 1")},
+	))
+
+	sql, args, err := insert.Prepared(true).ToSQL()
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	if _, err := tx.Exec(sql, args...); err != nil {
+		return 0, fmt.Errorf("failed to take e-invoice sequence: %w", err)
+	}
+
+	query := dialect.Select("last_sequence").From(TableEInvoiceSequences).Where(
+		goqu.C("branch").Eq(branch),
+		goqu.C("terminal").Eq(terminal),
+		goqu.C("document_type").Eq(documentType),
+	)
+
+	sql, args, err = query.Prepared(true).ToSQL()
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	var sequence int64
+	if err := tx.QueryRow(sql, args...).Scan(&sequence); err != nil {
+		return 0, fmt.Errorf("failed to get e-invoice sequence: %w", err)
+	}
+
+	return sequence, nil
+}
@@ -0,0 +1,232 @@
+package local
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"neon/core/database/embedded"
+	"neon/core/helpers/enums"
+	"neon/core/models"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+// electronicDocumentColumns are the columns scanned by scanElectronicDocument, in order
+var electronicDocumentColumns = []interface{}{
+	"id", "sale_id", "report_id", "document_type", "clave", "consecutive",
+	"receiver_name", "receiver_id_type", "receiver_id_number", "receiver_email", "reference_clave",
+	"total", "xml", "status", "issued_at", "submitted_at",
+}
+
+// ElectronicDocumentRepository implements ElectronicDocumentRepository for SQLite using goqu
+type ElectronicDocumentRepository struct {
+	ctx context.Context
+	db  *embedded.SQLite
+}
+
+// NewElectronicDocumentRepository creates a new electronic document repository
+func NewElectronicDocumentRepository(ctx context.Context, db *embedded.SQLite) *ElectronicDocumentRepository {
+	return &ElectronicDocumentRepository{
+		ctx: ctx,
+		db:  db,
+	}
+}
+
+// AddTx stores a signed document inside the caller's transaction
+func (r *ElectronicDocumentRepository) AddTx(tx *sql.Tx, document models.ElectronicDocument) (*models.ElectronicDocument, error) {
+	insert := dialect.Insert(TableElectronicDocuments).Rows(document)
+
+	sql, args, err := insert.Prepared(true).ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	result, err := tx.Exec(sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add electronic document: %w", err)
+	}
+
+	generatedID, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get generated ID: %w", err)
+	}
+
+	document.ID = generatedID
+
+	return &document, nil
+}
+
+// GetBySaleID gets the electronic invoice of a sale, not its credit notes
+func (r *ElectronicDocumentRepository) GetBySaleID(saleID int64) (*models.ElectronicDocument, error) {
+	return r.getOne(r.db.GetDB(), ColumnSaleID.Eq(saleID), goqu.C("reference_clave").Eq(""))
+}
+
+// GetBySaleIDTx gets the electronic invoice of a sale, not its credit notes, inside the caller's transaction
+func (r *ElectronicDocumentRepository) GetBySaleIDTx(tx *sql.Tx, saleID int64) (*models.ElectronicDocument, error) {
+	return r.getOne(tx, ColumnSaleID.Eq(saleID), goqu.C("reference_clave").Eq(""))
+}
+
+// GetByClave gets an electronic document by its clave
+func (r *ElectronicDocumentRepository) GetByClave(clave string) (*models.ElectronicDocument, error) {
+	return r.getOne(r.db.GetDB(), goqu.C("clave").Eq(clave))
+}
+
+// getOne gets the first electronic document matching the conditions with either the database or a
+// transaction
+func (r *ElectronicDocumentRepository) getOne(db interface {
+	QueryRow(query string, args ...any) *sql.Row
+}, conditions ...goqu.Expression) (*models.ElectronicDocument, error) {
+	query := dialect.Select(electronicDocumentColumns...).
+		From(TableElectronicDocuments).
+		Where(conditions...).
+		Order(ColumnID.Asc()).
+		Limit(1)
+
+	sql, args, err := query.Prepared(true).ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	document, err := scanElectronicDocument(db.QueryRow(sql, args...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan electronic document: %w", err)
+	}
+
+	return document, nil
+}
+
+// Get gets an electronic document by its ID
+func (r *ElectronicDocumentRepository) Get(id int64) (*models.ElectronicDocument, error) {
+	query := dialect.Select(electronicDocumentColumns...).
+		From(TableElectronicDocuments).
+		Where(ColumnID.Eq(id))
+
+	sql, args, err := query.Prepared(true).ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	document, err := scanElectronicDocument(r.db.GetDB().QueryRow(sql, args...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan electronic document: %w", err)
+	}
+
+	return document, nil
+}
+
+// UpdateStatus records the state of a document's outbox message; submittedAt is kept once set
+func (r *ElectronicDocumentRepository) UpdateStatus(id int64, status enums.OutboxState, submittedAt *string) error {
+	record := goqu.Record{"status": status}
+	if submittedAt != nil {
+		record["submitted_at"] = goqu.COALESCE(goqu.C("submitted_at"), *submittedAt)
+	}
+
+	update := dialect.Update(TableElectronicDocuments).
+		Set(record).
+		Where(ColumnID.Eq(id))
+
+	sql, args, err := update.Prepared(true).ToSQL()
+	if err != nil {
+		return fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	if _, err := r.db.GetDB().Exec(sql, args...); err != nil {
+		return fmt.Errorf("failed to update electronic document: %w", err)
+	}
+
+	return nil
+}
+
+// GetRejected gets the documents whose outbox message was rejected or refused, the latest first
+func (r *ElectronicDocumentRepository) GetRejected() ([]models.RejectedDocument, error) {
+	query := dialect.Select(
+		TableOutboxMessages.Col("id"),
+		TableElectronicDocuments.Col("id"),
+		TableElectronicDocuments.Col("sale_id"),
+		TableElectronicDocuments.Col("report_id"),
+		TableElectronicDocuments.Col("document_type"),
+		TableElectronicDocuments.Col("clave"),
+		TableElectronicDocuments.Col("consecutive"),
+		TableElectronicDocuments.Col("receiver_name"),
+		TableElectronicDocuments.Col("total"),
+		TableElectronicDocuments.Col("issued_at"),
+		TableOutboxMessages.Col("state"),
+		TableOutboxMessages.Col("reason"),
+		TableOutboxMessages.Col("response"),
+		TableOutboxMessages.Col("attempts"),
+		TableOutboxMessages.Col("updated_at"),
+	).From(TableElectronicDocuments).
+		InnerJoin(TableOutboxMessages, goqu.On(
+			TableOutboxMessages.Col("kind").Eq(enums.OutboxEInvoice),
+			TableOutboxMessages.Col("record_id").Eq(TableElectronicDocuments.Col("id")),
+		)).
+		Where(TableOutboxMessages.Col("state").In(enums.OutboxRejected, enums.OutboxError)).
+		Order(TableOutboxMessages.Col("updated_at").Desc(), TableOutboxMessages.Col("id").Desc())
+
+	sql, args, err := query.Prepared(true).ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	rows, err := r.db.GetDB().Query(sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rejected documents: %w", err)
+	}
+	defer rows.Close()
+
+	documents := []models.RejectedDocument{}
+	for rows.Next() {
+		var document models.RejectedDocument
+		if err := rows.Scan(
+			&document.MessageID,
+			&document.DocumentID,
+			&document.SaleID,
+			&document.ReportID,
+			&document.DocumentType,
+			&document.Clave,
+			&document.Consecutive,
+			&document.ReceiverName,
+			&document.Total,
+			&document.IssuedAt,
+			&document.State,
+			&document.Reason,
+			&document.Response,
+			&document.Attempts,
+			&document.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan rejected document: %w", err)
+		}
+		documents = append(documents, document)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate rejected documents: %w", err)
+	}
+
+	return documents, nil
+}
+
+func scanElectronicDocument(row interface{ Scan(dest ...any) error }) (*models.ElectronicDocument, error) {
+	var document models.ElectronicDocument
+	if err := row.Scan(
+		&document.ID,
+		&document.SaleID,
+		&document.ReportID,
+		&document.DocumentType,
+		&document.Clave,
+		&document.Consecutive,
+		&document.ReceiverName,
+		&document.ReceiverIDType,
+		&document.ReceiverIDNumber,
+		&document.ReceiverEmail,
+		&document.ReferenceClave,
+		&document.Total,
+		&document.XML,
+		&document.Status,
+		&document.IssuedAt,
+		&document.SubmittedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &document, nil
+}
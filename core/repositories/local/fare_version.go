@@ -0,0 +1,85 @@
+package local
+
+import (
+	"fmt"
+	"neon/core/constants"
+	"neon/core/database/embedded"
+	"neon/core/helpers"
+	"neon/core/models"
+	"sort"
+
+	c "github.com/ostafen/clover/v2/document"
+	q "github.com/ostafen/clover/v2/query"
+)
+
+// FareVersionRepository implements FareVersionRepository for CloverDB
+type FareVersionRepository struct {
+	collection string
+	db         *embedded.CloverDB
+}
+
+// NewFareVersionRepository creates a new fare version repository with CloverDB
+func NewFareVersionRepository(db *embedded.CloverDB) *FareVersionRepository {
+	return &FareVersionRepository{
+		collection: constants.FareVersionCollection,
+		db:         db,
+	}
+}
+
+// BulkCreate creates multiple fare versions in the database
+func (r *FareVersionRepository) BulkCreate(versions []models.FareVersion) error {
+	if len(versions) == 0 {
+		return nil
+	}
+
+	docs := make([]*c.Document, len(versions))
+
+	for i, version := range versions {
+		doc, err := helpers.MarshalAsCloverDocument(version)
+		if err != nil {
+			return fmt.Errorf("failed to marshal fare version: %w", err)
+		}
+
+		docs[i] = doc
+	}
+
+	if err := r.db.GetDB().Insert(r.collection, docs...); err != nil {
+		return fmt.Errorf("failed to insert fare versions: %w", err)
+	}
+
+	return nil
+}
+
+// GetByRoute gets the fare versions of a route ordered by version
+func (r *FareVersionRepository) GetByRoute(departure string, destination string) ([]models.FareVersion, error) {
+	query := q.NewQuery(r.collection).Where(
+		q.Field("departure").Eq(departure).And(q.Field("destination").Eq(destination)),
+	)
+
+	docs, err := r.db.GetDB().FindAll(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find fare versions: %w", err)
+	}
+
+	versions := make([]models.FareVersion, len(docs))
+	for i, doc := range docs {
+		var version models.FareVersion
+		if err := doc.Unmarshal(&version); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal fare version: %w", err)
+		}
+		versions[i] = version
+	}
+
+	sort.Slice(versions, func(i, j int) bool { return versions[i].Version < versions[j].Version })
+
+	return versions, nil
+}
+
+// Clear clears all fare versions from the database
+func (r *FareVersionRepository) Clear() error {
+	if err := r.db.GetDB().Delete(q.NewQuery(r.collection)); err != nil {
+		return fmt.Errorf("failed to delete fare versions: %w", err)
+	}
+
+	return nil
+}
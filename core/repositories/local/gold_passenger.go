@@ -0,0 +1,79 @@
+// Package local
+package local
+
+import (
+	"fmt"
+	"neon/core/constants"
+	"neon/core/database/embedded"
+	"neon/core/helpers"
+	"neon/core/models"
+
+	c "github.com/ostafen/clover/v2/document"
+	q "github.com/ostafen/clover/v2/query"
+)
+
+// GoldPassengerRepository implements GoldPassengerRepository for CloverDB
+type GoldPassengerRepository struct {
+	collection string
+	db         *embedded.CloverDB
+}
+
+// NewGoldPassengerRepository creates a new gold passenger repository with CloverDB
+func NewGoldPassengerRepository(db *embedded.CloverDB) *GoldPassengerRepository {
+	return &GoldPassengerRepository{
+		collection: constants.GoldPassengerCollection,
+		db:         db,
+	}
+}
+
+// BulkCreate creates multiple gold passengers in the database
+func (r *GoldPassengerRepository) BulkCreate(passengers []models.GoldPassenger) error {
+	if len(passengers) == 0 {
+		return nil
+	}
+
+	docs := make([]*c.Document, len(passengers))
+
+	for i, passenger := range passengers {
+		doc, err := helpers.MarshalAsCloverDocument(passenger)
+		if err != nil {
+			return fmt.Errorf("failed to marshal gold passenger: %w", err)
+		}
+
+		docs[i] = doc
+	}
+
+	if err := r.db.GetDB().Insert(r.collection, docs...); err != nil {
+		return fmt.Errorf("failed to insert gold passengers: %w", err)
+	}
+
+	return nil
+}
+
+// FindByIDNumber finds a gold passenger by its normalized identification number
+func (r *GoldPassengerRepository) FindByIDNumber(idNumber string) (*models.GoldPassenger, error) {
+	doc, err := r.db.GetDB().FindFirst(q.NewQuery(r.collection).Where(q.Field(ColumnIDNumber).Eq(idNumber)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find gold passenger: %w", err)
+	}
+	if doc == nil {
+		return nil, nil
+	}
+
+	var passenger models.GoldPassenger
+	err = doc.Unmarshal(&passenger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal gold passenger: %w", err)
+	}
+
+	return &passenger, nil
+}
+
+// Clear clears all gold passengers from the database
+func (r *GoldPassengerRepository) Clear() error {
+	if err := r.db.GetDB().Delete(q.NewQuery(r.collection)); err != nil {
+		return fmt.Errorf("failed to delete gold passengers: %w", err)
+	}
+
+	return nil
+}
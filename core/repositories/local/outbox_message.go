@@ -0,0 +1,256 @@
+package local
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"neon/core/database/embedded"
+	"neon/core/helpers/enums"
+	"neon/core/models"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+// outboxMessageColumns are the columns scanned by scanOutboxMessage, in order
+var outboxMessageColumns = []interface{}{
+	"id", "kind", "record_id", "reference", "state", "attempts", "next_attempt_at",
+	"last_error", "response", "reason", "created_at", "updated_at",
+}
+
+// OutboxMessageRepository implements OutboxMessageRepository for SQLite using goqu
+type OutboxMessageRepository struct {
+	ctx context.Context
+	db  *embedded.SQLite
+}
+
+// NewOutboxMessageRepository creates a new outbox message repository
+func NewOutboxMessageRepository(ctx context.Context, db *embedded.SQLite) *OutboxMessageRepository {
+	return &OutboxMessageRepository{
+		ctx: ctx,
+		db:  db,
+	}
+}
+
+// AddTx queues a message inside the caller's transaction, next to the document it delivers
+func (r *OutboxMessageRepository) AddTx(tx *sql.Tx, message models.OutboxMessage) (*models.OutboxMessage, error) {
+	insert := dialect.Insert(TableOutboxMessages).Rows(message)
+
+	sql, args, err := insert.Prepared(true).ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	result, err := tx.Exec(sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add outbox message: %w", err)
+	}
+
+	generatedID, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get generated ID: %w", err)
+	}
+
+	message.ID = generatedID
+
+	return &message, nil
+}
+
+// Get gets a message by its ID
+func (r *OutboxMessageRepository) Get(id int64) (*models.OutboxMessage, error) {
+	query := dialect.Select(outboxMessageColumns...).
+		From(TableOutboxMessages).
+		Where(ColumnID.Eq(id))
+
+	sql, args, err := query.Prepared(true).ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	message, err := scanOutboxMessage(r.db.GetDB().QueryRow(sql, args...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan outbox message: %w", err)
+	}
+
+	return message, nil
+}
+
+// GetDue gets up to limit pending or sent messages whose next attempt is at or before now, the most
+// overdue first
+func (r *OutboxMessageRepository) GetDue(now string, limit uint) ([]models.OutboxMessage, error) {
+	query := dialect.Select(outboxMessageColumns...).
+		From(TableOutboxMessages).
+		Where(
+			goqu.C("state").In(enums.OutboxPending, enums.OutboxSent),
+			goqu.C("next_attempt_at").Lte(now),
+		).
+		Order(goqu.C("next_attempt_at").Asc(), ColumnID.Asc()).
+		Limit(limit)
+
+	sql, args, err := query.Prepared(true).ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	rows, err := r.db.GetDB().Query(sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query outbox messages: %w", err)
+	}
+	defer rows.Close()
+
+	messages := []models.OutboxMessage{}
+	for rows.Next() {
+		message, err := scanOutboxMessage(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan outbox message: %w", err)
+		}
+		messages = append(messages, *message)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate outbox messages: %w", err)
+	}
+
+	return messages, nil
+}
+
+// Claim takes a pending or sent message for one attempt until the claim expires, so a message is never
+// attempted twice at once. It returns the message as stored, or nil when it is claimed by another attempt
+// or was settled in the meantime. Update releases the claim; an expired claim, e.g. of an attempt cut off
+// by a crash, can be taken again.
+func (r *OutboxMessageRepository) Claim(id int64, now string, until string) (*models.OutboxMessage, error) {
+	update := dialect.Update(TableOutboxMessages).
+		Set(goqu.Record{"claimed_until": until}).
+		Where(
+			ColumnID.Eq(id),
+			goqu.C("state").In(enums.OutboxPending, enums.OutboxSent),
+			goqu.C("claimed_until").Lte(now),
+		)
+
+	sql, args, err := update.Prepared(true).ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	result, err := r.db.GetDB().Exec(sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim outbox message: %w", err)
+	}
+
+	claimed, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get claimed outbox message: %w", err)
+	}
+	if claimed == 0 {
+		return nil, nil
+	}
+
+	return r.Get(id)
+}
+
+// CancelTx withdraws the pending message of a kind for a record inside the caller's transaction, e.g. the
+// capture of a card payment voided before it was captured. It reports whether the message was cancelled;
+// one already sent, settled or claimed by an attempt isn't.
+func (r *OutboxMessageRepository) CancelTx(tx *sql.Tx, kind enums.OutboxKind, recordID int64, now string) (bool, error) {
+	update := dialect.Update(TableOutboxMessages).
+		Set(goqu.Record{"state": enums.OutboxCancelled, "updated_at": now}).
+		Where(
+			goqu.C("kind").Eq(kind),
+			goqu.C("record_id").Eq(recordID),
+			goqu.C("state").Eq(enums.OutboxPending),
+			goqu.C("claimed_until").Lte(now),
+		)
+
+	sql, args, err := update.Prepared(true).ToSQL()
+	if err != nil {
+		return false, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	result, err := tx.Exec(sql, args...)
+	if err != nil {
+		return false, fmt.Errorf("failed to cancel outbox message: %w", err)
+	}
+
+	cancelled, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get cancelled outbox message: %w", err)
+	}
+
+	return cancelled > 0, nil
+}
+
+// Update saves a message's delivery state and releases its claim
+func (r *OutboxMessageRepository) Update(message models.OutboxMessage) error {
+	update := dialect.Update(TableOutboxMessages).
+		Set(goqu.Record{
+			"state":           message.State,
+			"attempts":        message.Attempts,
+			"next_attempt_at": message.NextAttemptAt,
+			"last_error":      message.LastError,
+			"response":        message.Response,
+			"reason":          message.Reason,
+			"claimed_until":   "",
+			"updated_at":      message.UpdatedAt,
+		}).
+		Where(ColumnID.Eq(message.ID))
+
+	sql, args, err := update.Prepared(true).ToSQL()
+	if err != nil {
+		return fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	if _, err := r.db.GetDB().Exec(sql, args...); err != nil {
+		return fmt.Errorf("failed to update outbox message: %w", err)
+	}
+
+	return nil
+}
+
+// RescheduleWaiting makes the pending and sent messages waiting out a backoff after a failed or unanswered
+// attempt due at now, and returns how many were waiting
+func (r *OutboxMessageRepository) RescheduleWaiting(now string) (int64, error) {
+	update := dialect.Update(TableOutboxMessages).
+		Set(goqu.Record{"next_attempt_at": now}).
+		Where(
+			goqu.C("state").In(enums.OutboxPending, enums.OutboxSent),
+			goqu.C("attempts").Gt(0),
+			goqu.C("next_attempt_at").Gt(now),
+		)
+
+	sql, args, err := update.Prepared(true).ToSQL()
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	result, err := r.db.GetDB().Exec(sql, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to reschedule outbox messages: %w", err)
+	}
+
+	rescheduled, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rescheduled outbox messages: %w", err)
+	}
+
+	return rescheduled, nil
+}
+
+func scanOutboxMessage(row interface{ Scan(dest ...any) error }) (*models.OutboxMessage, error) {
+	var message models.OutboxMessage
+	if err := row.Scan(
+		&message.ID,
+		&message.Kind,
+		&message.RecordID,
+		&message.Reference,
+		&message.State,
+		&message.Attempts,
+		&message.NextAttemptAt,
+		&message.LastError,
+		&message.Response,
+		&message.Reason,
+		&message.CreatedAt,
+		&message.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
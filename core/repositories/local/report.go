@@ -2,11 +2,15 @@ package local
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"neon/core/database/embedded"
+	"neon/core/helpers"
+	"neon/core/helpers/enums"
 	"neon/core/models"
 
 	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
 )
 
 // ReportRepository implements ReportRepository
@@ -49,6 +53,30 @@ func (r *ReportRepository) Add(report models.Report) (*models.Report, error) {
 	return &report, nil
 }
 
+// AddTx adds a report inside the caller's transaction and returns it with the generated ID
+func (r *ReportRepository) AddTx(tx *sql.Tx, report models.Report) (*models.Report, error) {
+	query := dialect.Insert(TableReports).Rows(report)
+
+	sql, args, err := query.Prepared(true).ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	result, err := tx.Exec(sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add report: %w", err)
+	}
+
+	generatedID, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get generated ID: %w", err)
+	}
+
+	report.ID = generatedID
+
+	return &report, nil
+}
+
 // Update updates a report in the database
 func (r *ReportRepository) Update(report models.Report) error {
 	if report.ID == 0 {
@@ -70,9 +98,60 @@ func (r *ReportRepository) Update(report models.Report) error {
 	return nil
 }
 
+// UpdateTx updates a report inside the caller's transaction
+func (r *ReportRepository) UpdateTx(tx *sql.Tx, report models.Report) error {
+	if report.ID == 0 {
+		return fmt.Errorf("report id is required")
+	}
+
+	query := dialect.Update(TableReports).Set(report).Where(ColumnID.Eq(report.ID))
+
+	sql, args, err := query.Prepared(true).ToSQL()
+	if err != nil {
+		return fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	if _, err := tx.Exec(sql, args...); err != nil {
+		return fmt.Errorf("failed to update report: %w", err)
+	}
+
+	return nil
+}
+
+// DropTx moves the tickets and cash of a drop from the period open since the last drop to the dropped ones
+// and records the drop as the report's latest partial close, inside the caller's transaction. A non-empty
+// pendingRecount holds the report until an admin recounts the drop.
+func (r *ReportRepository) DropTx(tx *sql.Tx, drop models.ReportDrop, pendingRecount enums.CashCountStage) error {
+	record := goqu.Record{
+		"partial_tickets":       goqu.L("partial_tickets + ?", drop.Tickets),
+		"partial_cash":          goqu.L("partial_cash + ?", drop.Cash),
+		"partial_cash_received": goqu.L("partial_cash_received + ?", drop.CashReceived),
+		"final_tickets":         goqu.L("final_tickets - ?", drop.Tickets),
+		"final_cash":            goqu.L("final_cash - ?", drop.Cash),
+		"partial_closed_at":     drop.DroppedAt,
+		"partial_closed_by":     drop.DroppedBy,
+	}
+	if pendingRecount != "" {
+		record["pending_recount"] = pendingRecount
+	}
+
+	query := dialect.Update(TableReports).Set(record).Where(ColumnID.Eq(drop.ReportID))
+
+	sql, args, err := query.Prepared(true).ToSQL()
+	if err != nil {
+		return fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	if _, err := tx.Exec(sql, args...); err != nil {
+		return fmt.Errorf("failed to record report drop: %w", err)
+	}
+
+	return nil
+}
+
 // GetByID gets a report by id
 func (r *ReportRepository) GetByID(reportID int64) (*models.Report, error) {
-	query := dialect.Select(goqu.C("*")).From(TableReports).Where(ColumnID.Eq(reportID))
+	query := dialect.Select(reportColumns...).From(TableReports).Where(ColumnID.Eq(reportID))
 
 	sql, args, err := query.Prepared(true).ToSQL()
 	if err != nil {
@@ -81,31 +160,8 @@ func (r *ReportRepository) GetByID(reportID int64) (*models.Report, error) {
 
 	row := r.db.GetDB().QueryRow(sql, args...)
 
-	var report models.Report
-	if err := row.Scan(
-		&report.ID,
-		&report.Username,
-		&report.Timetable,
-		&report.PartialTickets,
-		&report.PartialCash,
-		&report.PartialCashReceived,
-		&report.FinalTickets,
-		&report.FinalCash,
-		&report.FinalCashReceived,
-		&report.Status,
-		&report.TotalGold,
-		&report.TotalGoldCash,
-		&report.TotalNull,
-		&report.TotalNullCash,
-		&report.TotalRegular,
-		&report.TotalRegularCash,
-		&report.PartialClosedAt,
-		&report.ClosedAt,
-		&report.CreatedAt,
-		&report.PartialClosedBy,
-		&report.ClosedBy,
-		&report.RemoteSynced,
-	); err != nil {
+	report, err := scanReport(row)
+	if err != nil {
 		return nil, fmt.Errorf("failed to scan report: %w", err)
 	}
 
@@ -113,12 +169,12 @@ func (r *ReportRepository) GetByID(reportID int64) (*models.Report, error) {
 		return nil, fmt.Errorf("failed to get report: %w", err)
 	}
 
-	return &report, nil
+	return report, nil
 }
 
 // GetOpenOrPendingReport gets an open or pending report
 func (r *ReportRepository) GetOpenOrPendingReport() (*models.Report, error) {
-	query := dialect.Select(goqu.C("*")).From(TableReports).Where(
+	query := dialect.Select(reportColumns...).From(TableReports).Where(
 		ColumnStatus.Eq(true),
 	).Limit(1)
 
@@ -129,40 +185,36 @@ func (r *ReportRepository) GetOpenOrPendingReport() (*models.Report, error) {
 
 	row := r.db.GetDB().QueryRow(sql, args...)
 
-	var report models.Report
-	if err := row.Scan(
-		&report.ID,
-		&report.Username,
-		&report.Timetable,
-		&report.PartialTickets,
-		&report.PartialCash,
-		&report.PartialCashReceived,
-		&report.FinalTickets,
-		&report.FinalCash,
-		&report.FinalCashReceived,
-		&report.Status,
-		&report.TotalGold,
-		&report.TotalGoldCash,
-		&report.TotalNull,
-		&report.TotalNullCash,
-		&report.TotalRegular,
-		&report.TotalRegularCash,
-		&report.PartialClosedAt,
-		&report.ClosedAt,
-		&report.CreatedAt,
-		&report.PartialClosedBy,
-		&report.ClosedBy,
-		&report.RemoteSynced,
-	); err != nil {
+	report, err := scanReport(row)
+	if err != nil {
 		return nil, err
 	}
 
-	return &report, nil
+	return report, nil
+}
+
+// GetAwaitingRecount gets the oldest report with a close waiting for an admin recount
+func (r *ReportRepository) GetAwaitingRecount() (*models.Report, error) {
+	query := dialect.Select(reportColumns...).From(TableReports).Where(
+		goqu.C("pending_recount").Neq(""),
+	).Order(ColumnID.Asc()).Limit(1)
+
+	sql, args, err := query.Prepared(true).ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	report, err := scanReport(r.db.GetDB().QueryRow(sql, args...))
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
 }
 
 // GetLatestReportsByUsername gets the latest 2 closed reports for a specific username
 func (r *ReportRepository) GetLatestReportsByUsername(username string) ([]*models.Report, error) {
-	query := dialect.Select(goqu.C("*")).From(TableReports).Where(
+	query := dialect.Select(reportColumns...).From(TableReports).Where(
 		goqu.And(
 			goqu.C("username").Eq(username),
 			goqu.C("status").Eq(false), // Only closed reports
@@ -182,34 +234,44 @@ func (r *ReportRepository) GetLatestReportsByUsername(username string) ([]*model
 
 	var reports []*models.Report
 	for rows.Next() {
-		var report models.Report
-		if err := rows.Scan(
-			&report.ID,
-			&report.Username,
-			&report.Timetable,
-			&report.PartialTickets,
-			&report.PartialCash,
-			&report.PartialCashReceived,
-			&report.FinalTickets,
-			&report.FinalCash,
-			&report.FinalCashReceived,
-			&report.Status,
-			&report.TotalGold,
-			&report.TotalGoldCash,
-			&report.TotalNull,
-			&report.TotalNullCash,
-			&report.TotalRegular,
-			&report.TotalRegularCash,
-			&report.PartialClosedAt,
-			&report.ClosedAt,
-			&report.CreatedAt,
-			&report.PartialClosedBy,
-			&report.ClosedBy,
-			&report.RemoteSynced,
-		); err != nil {
+		report, err := scanReport(rows)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan report: %w", err)
 		}
-		reports = append(reports, &report)
+		reports = append(reports, report)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate reports: %w", err)
+	}
+
+	return reports, nil
+}
+
+// GetByBusinessDate gets every report started on a business day (YYYY-MM-DD), open or closed, oldest first
+func (r *ReportRepository) GetByBusinessDate(date string) ([]*models.Report, error) {
+	query := dialect.Select(reportColumns...).From(TableReports).Where(
+		goqu.L("substr(created_at, 1, 10)").Eq(date),
+	).Order(ColumnID.Asc())
+
+	sql, args, err := query.Prepared(true).ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	rows, err := r.db.GetDB().Query(sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query reports: %w", err)
+	}
+	defer rows.Close()
+
+	var reports []*models.Report
+	for rows.Next() {
+		report, err := scanReport(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan report: %w", err)
+		}
+		reports = append(reports, report)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -221,7 +283,7 @@ func (r *ReportRepository) GetLatestReportsByUsername(username string) ([]*model
 
 // GetPendingRemoteSync returns reports that were closed (partially or fully) but not yet synced to remote MySQL.
 func (r *ReportRepository) GetPendingRemoteSync() ([]*models.Report, error) {
-	query := dialect.Select(goqu.C("*")).From(TableReports).Where(
+	query := dialect.Select(reportColumns...).From(TableReports).Where(
 		goqu.And(
 			goqu.C("remote_synced").Eq(0),
 			goqu.Or(
@@ -244,34 +306,11 @@ func (r *ReportRepository) GetPendingRemoteSync() ([]*models.Report, error) {
 
 	var reports []*models.Report
 	for rows.Next() {
-		var report models.Report
-		if err := rows.Scan(
-			&report.ID,
-			&report.Username,
-			&report.Timetable,
-			&report.PartialTickets,
-			&report.PartialCash,
-			&report.PartialCashReceived,
-			&report.FinalTickets,
-			&report.FinalCash,
-			&report.FinalCashReceived,
-			&report.Status,
-			&report.TotalGold,
-			&report.TotalGoldCash,
-			&report.TotalNull,
-			&report.TotalNullCash,
-			&report.TotalRegular,
-			&report.TotalRegularCash,
-			&report.PartialClosedAt,
-			&report.ClosedAt,
-			&report.CreatedAt,
-			&report.PartialClosedBy,
-			&report.ClosedBy,
-			&report.RemoteSynced,
-		); err != nil {
+		report, err := scanReport(rows)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan report: %w", err)
 		}
-		reports = append(reports, &report)
+		reports = append(reports, report)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -280,3 +319,204 @@ func (r *ReportRepository) GetPendingRemoteSync() ([]*models.Report, error) {
 
 	return reports, nil
 }
+
+// reportVariance is a report's cash variance: the cash received at its drops and total close, net of the
+// opening float, less the cash payments expected in the drawer. It only means something on a closed report.
+var reportVariance = goqu.L(
+	"(partial_cash_received + final_cash_received - opening_float) - "+reportExpectedSQL,
+	enums.PaymentCash,
+)
+
+// reportSettledSQL matches the reports totally closed with no recount pending
+const reportSettledSQL = "closed_at IS NOT NULL AND pending_recount = ''"
+
+// reportExpectedSQL is the cash payments of a report expected in its drawer
+const reportExpectedSQL = "COALESCE((SELECT partial_amount + final_amount FROM report_payment_totals " +
+	"WHERE report_id = reports.id AND method = ?), 0)"
+
+// reportSortColumns are the orders a report search can be sorted by
+var reportSortColumns = map[string]exp.Orderable{
+	"id":         ColumnID,
+	"created_at": goqu.C("created_at"),
+	"closed_at":  goqu.C("closed_at"),
+	"username":   goqu.C("username"),
+	"tickets":    goqu.L("partial_tickets + final_tickets"),
+	"cash":       goqu.L("partial_cash + final_cash"),
+	"variance":   reportVariance,
+}
+
+// Search gets a page of the reports matching a search, sorted as asked, with the totals of all the reports
+// matching it. settledOnly leaves the reports not settled yet (open, or closed awaiting a recount) out of
+// the totals but their count. The search's page and page size must be set; an unknown sort returns
+// helpers.ErrInvalidRequest.
+func (r *ReportRepository) Search(search models.ReportSearch, settledOnly bool) ([]*models.Report, models.ReportSearchTotals, error) {
+	var totals models.ReportSearchTotals
+
+	totaled, closed := "1", "closed_at IS NOT NULL"
+	if settledOnly {
+		totaled, closed = reportSettledSQL, reportSettledSQL
+	}
+
+	order := goqu.C("created_at").Desc()
+	if search.SortBy != "" {
+		column, ok := reportSortColumns[search.SortBy]
+		if !ok {
+			return nil, totals, helpers.ErrInvalidRequest
+		}
+		order = column.Asc()
+		if search.Descending {
+			order = column.Desc()
+		}
+	}
+
+	where := reportSearchWhere(search)
+
+	query := dialect.Select(
+		goqu.COUNT("*"),
+		goqu.L("COALESCE(SUM(CASE WHEN "+totaled+" THEN partial_tickets + final_tickets END), 0)"),
+		goqu.L("COALESCE(SUM(CASE WHEN "+totaled+" THEN partial_cash + final_cash END), 0)"),
+		goqu.L("COALESCE(SUM(CASE WHEN "+totaled+" THEN total_sales END), 0)"),
+		goqu.L("COALESCE(SUM(CASE WHEN "+totaled+" THEN total_gold END), 0)"),
+		goqu.L("COALESCE(SUM(CASE WHEN "+totaled+" THEN total_null END), 0)"),
+		goqu.L("COALESCE(SUM(CASE WHEN "+totaled+" THEN total_null_cash END), 0)"),
+		goqu.L("COALESCE(SUM(CASE WHEN "+closed+" THEN "+reportExpectedSQL+" END), 0)", enums.PaymentCash),
+		goqu.L("COALESCE(SUM(CASE WHEN "+closed+" THEN partial_cash_received + final_cash_received - opening_float END), 0)"),
+		goqu.L("COALESCE(SUM(CASE WHEN "+closed+" THEN ? END), 0)", reportVariance),
+	).From(TableReports).Where(where...)
+
+	sql, args, err := query.Prepared(true).ToSQL()
+	if err != nil {
+		return nil, totals, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	if err := r.db.GetDB().QueryRow(sql, args...).Scan(
+		&totals.Reports,
+		&totals.Tickets,
+		&totals.Cash,
+		&totals.Sales,
+		&totals.Gold,
+		&totals.Null,
+		&totals.NullCash,
+		&totals.Expected,
+		&totals.Received,
+		&totals.Difference,
+	); err != nil {
+		return nil, totals, fmt.Errorf("failed to total reports: %w", err)
+	}
+
+	page := dialect.Select(reportColumns...).From(TableReports).Where(where...).
+		Order(order, ColumnID.Desc()).
+		Limit(uint(search.PageSize)).
+		Offset(uint((search.Page - 1) * search.PageSize))
+
+	sql, args, err = page.Prepared(true).ToSQL()
+	if err != nil {
+		return nil, totals, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	rows, err := r.db.GetDB().Query(sql, args...)
+	if err != nil {
+		return nil, totals, fmt.Errorf("failed to query reports: %w", err)
+	}
+	defer rows.Close()
+
+	reports := []*models.Report{}
+	for rows.Next() {
+		report, err := scanReport(rows)
+		if err != nil {
+			return nil, totals, fmt.Errorf("failed to scan report: %w", err)
+		}
+		reports = append(reports, report)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, totals, fmt.Errorf("failed to iterate reports: %w", err)
+	}
+
+	return reports, totals, nil
+}
+
+// reportSearchWhere turns a report search's filters into conditions
+func reportSearchWhere(search models.ReportSearch) []goqu.Expression {
+	where := []goqu.Expression{}
+	if search.From != "" {
+		where = append(where, goqu.L("substr(created_at, 1, 10)").Gte(search.From))
+	}
+	if search.To != "" {
+		where = append(where, goqu.L("substr(created_at, 1, 10)").Lte(search.To))
+	}
+	if search.Username != "" {
+		where = append(where, goqu.Or(
+			goqu.C("username").Eq(search.Username),
+			goqu.L("EXISTS (SELECT 1 FROM report_handovers WHERE report_id = reports.id AND to_username = ?)", search.Username),
+		))
+	}
+	if search.Timetable != "" {
+		where = append(where, goqu.C("timetable").Eq(search.Timetable))
+	}
+	if search.Status != nil {
+		where = append(where, ColumnStatus.Eq(*search.Status))
+	}
+	if search.RemoteSynced != nil {
+		where = append(where, goqu.C("remote_synced").Eq(*search.RemoteSynced))
+	}
+	switch search.Variance {
+	case enums.VarianceShort:
+		where = append(where, goqu.C("closed_at").IsNotNull(), goqu.L("? < 0", reportVariance))
+	case enums.VarianceOver:
+		where = append(where, goqu.C("closed_at").IsNotNull(), goqu.L("? > 0", reportVariance))
+	case enums.VarianceEven:
+		where = append(where, goqu.C("closed_at").IsNotNull(), goqu.L("? = 0", reportVariance))
+	}
+	return where
+}
+
+// reportColumns lists the report columns in the order scanReport reads them
+var reportColumns = []any{
+	"id", "username", "timetable",
+	"partial_tickets", "partial_cash", "partial_cash_received",
+	"final_tickets", "final_cash", "final_cash_received",
+	"status",
+	"total_gold", "total_gold_cash", "total_null", "total_null_cash", "total_regular", "total_regular_cash",
+	"partial_closed_at", "closed_at", "created_at", "partial_closed_by", "closed_by",
+	"remote_synced", "advance_tickets", "advance_cash", "total_sales",
+	"opening_float", "float_confirmed_by", "pending_recount",
+}
+
+// scanReport reads a report selected with reportColumns
+func scanReport(row interface{ Scan(dest ...any) error }) (*models.Report, error) {
+	var report models.Report
+	if err := row.Scan(
+		&report.ID,
+		&report.Username,
+		&report.Timetable,
+		&report.PartialTickets,
+		&report.PartialCash,
+		&report.PartialCashReceived,
+		&report.FinalTickets,
+		&report.FinalCash,
+		&report.FinalCashReceived,
+		&report.Status,
+		&report.TotalGold,
+		&report.TotalGoldCash,
+		&report.TotalNull,
+		&report.TotalNullCash,
+		&report.TotalRegular,
+		&report.TotalRegularCash,
+		&report.PartialClosedAt,
+		&report.ClosedAt,
+		&report.CreatedAt,
+		&report.PartialClosedBy,
+		&report.ClosedBy,
+		&report.RemoteSynced,
+		&report.AdvanceTickets,
+		&report.AdvanceCash,
+		&report.TotalSales,
+		&report.OpeningFloat,
+		&report.FloatConfirmedBy,
+		&report.PendingRecount,
+	); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
@@ -0,0 +1,112 @@
+package local
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"neon/core/database/embedded"
+	"neon/core/helpers/enums"
+	"neon/core/models"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+// ReportCashCountRepository implements ReportCashCountRepository for SQLite using goqu
+type ReportCashCountRepository struct {
+	ctx context.Context
+	db  *embedded.SQLite
+}
+
+// NewReportCashCountRepository creates a new report cash count repository
+func NewReportCashCountRepository(ctx context.Context, db *embedded.SQLite) *ReportCashCountRepository {
+	return &ReportCashCountRepository{
+		ctx: ctx,
+		db:  db,
+	}
+}
+
+// ReplaceTx stores the count of a report's close inside the caller's transaction, replacing an earlier
+// count of the same close
+func (r *ReportCashCountRepository) ReplaceTx(tx *sql.Tx, reportID int64, stage enums.CashCountStage, counts []models.CashCount) error {
+	remove := dialect.Delete(TableReportCashCounts).Where(
+		ColumnReportID.Eq(reportID),
+		goqu.C("stage").Eq(stage),
+	)
+
+	sql, args, err := remove.Prepared(true).ToSQL()
+	if err != nil {
+		return fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	if _, err := tx.Exec(sql, args...); err != nil {
+		return fmt.Errorf("failed to delete report cash count: %w", err)
+	}
+
+	if len(counts) == 0 {
+		return nil
+	}
+
+	rows := make([]interface{}, 0, len(counts))
+	for _, count := range counts {
+		rows = append(rows, count)
+	}
+	insert := dialect.Insert(TableReportCashCounts).Rows(rows...)
+
+	sql, args, err = insert.Prepared(true).ToSQL()
+	if err != nil {
+		return fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	if _, err := tx.Exec(sql, args...); err != nil {
+		return fmt.Errorf("failed to add report cash count: %w", err)
+	}
+
+	return nil
+}
+
+// GetByReportIDs gets the cash counts of reports, keyed by report ID, by stage and from the largest
+// denomination down
+func (r *ReportCashCountRepository) GetByReportIDs(reportIDs []int64) (map[int64][]models.CashCount, error) {
+	counts := map[int64][]models.CashCount{}
+	if len(reportIDs) == 0 {
+		return counts, nil
+	}
+
+	query := dialect.Select("report_id", "stage", "denomination", "quantity", "amount", "counted_by", "counted_at").
+		From(TableReportCashCounts).
+		Where(goqu.C("report_id").In(reportIDs)).
+		Order(ColumnID.Asc())
+
+	sql, args, err := query.Prepared(true).ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	rows, err := r.db.GetDB().Query(sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query report cash counts: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var count models.CashCount
+		if err := rows.Scan(
+			&count.ReportID,
+			&count.Stage,
+			&count.Denomination,
+			&count.Quantity,
+			&count.Amount,
+			&count.CountedBy,
+			&count.CountedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan report cash count: %w", err)
+		}
+		counts[count.ReportID] = append(counts[count.ReportID], count)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate report cash counts: %w", err)
+	}
+
+	return counts, nil
+}
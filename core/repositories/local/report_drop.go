@@ -0,0 +1,282 @@
+package local
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"neon/core/database/embedded"
+	"neon/core/helpers/enums"
+	"neon/core/models"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+// reportDropColumns are the columns scanned by scanReportDrop, in order
+var reportDropColumns = []interface{}{
+	"id", "report_id", "sequence", "dropped_at", "dropped_by", "cash_received", "tickets", "cash", "drawer",
+}
+
+// ReportDropRepository implements ReportDropRepository for SQLite using goqu
+type ReportDropRepository struct {
+	ctx context.Context
+	db  *embedded.SQLite
+}
+
+// NewReportDropRepository creates a new report drop repository
+func NewReportDropRepository(ctx context.Context, db *embedded.SQLite) *ReportDropRepository {
+	return &ReportDropRepository{
+		ctx: ctx,
+		db:  db,
+	}
+}
+
+// AddTx stores a drop with its count inside the caller's transaction and returns it with the generated ID.
+// Its sequence and the tickets, cash and cash payments accrued since the previous drop are read from the
+// report in the same statement, so no sale can fall between them.
+func (r *ReportDropRepository) AddTx(tx *sql.Tx, drop models.ReportDrop) (*models.ReportDrop, error) {
+	// goqu only takes a subquery built without a dialect into an insert
+	snapshot := goqu.From(TableReports).
+		Select(
+			ColumnID,
+			goqu.L("(SELECT COALESCE(MAX(sequence), 0) + 1 FROM report_drops WHERE report_id = reports.id)"),
+			goqu.V(drop.DroppedAt),
+			goqu.V(drop.DroppedBy),
+			goqu.V(drop.CashReceived),
+			goqu.C("final_tickets"),
+			goqu.C("final_cash"),
+			goqu.L(
+				"COALESCE((SELECT final_amount FROM report_payment_totals WHERE report_id = reports.id AND method = ?), 0)",
+				enums.PaymentCash,
+			),
+		).
+		Where(ColumnID.Eq(drop.ReportID))
+	insert := dialect.Insert(TableReportDrops).
+		Cols("report_id", "sequence", "dropped_at", "dropped_by", "cash_received", "tickets", "cash", "drawer").
+		FromQuery(snapshot)
+
+	sql, args, err := insert.Prepared(true).ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	result, err := tx.Exec(sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add report drop: %w", err)
+	}
+
+	generatedID, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get generated ID: %w", err)
+	}
+
+	query := dialect.Select(reportDropColumns...).From(TableReportDrops).Where(ColumnID.Eq(generatedID))
+
+	sql, args, err = query.Prepared(true).ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	output, err := scanReportDrop(tx.QueryRow(sql, args...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get report drop: %w", err)
+	}
+
+	output.Count = drop.Count
+	if err := r.addCountsTx(tx, output.ID, output.Count); err != nil {
+		return nil, err
+	}
+
+	return output, nil
+}
+
+// GetLatest gets the last drop of a report, without its count
+func (r *ReportDropRepository) GetLatest(reportID int64) (*models.ReportDrop, error) {
+	query := dialect.Select(reportDropColumns...).
+		From(TableReportDrops).
+		Where(ColumnReportID.Eq(reportID)).
+		Order(goqu.C("sequence").Desc()).
+		Limit(1)
+
+	sql, args, err := query.Prepared(true).ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	drop, err := scanReportDrop(r.db.GetDB().QueryRow(sql, args...))
+	if err != nil {
+		return nil, err
+	}
+
+	return drop, nil
+}
+
+// RecountTx replaces the cash received at a drop and its count inside the caller's transaction
+func (r *ReportDropRepository) RecountTx(tx *sql.Tx, id int64, cashReceived int, counts []models.CashCount) error {
+	update := dialect.Update(TableReportDrops).
+		Set(goqu.Record{"cash_received": cashReceived}).
+		Where(ColumnID.Eq(id))
+
+	sql, args, err := update.Prepared(true).ToSQL()
+	if err != nil {
+		return fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	if _, err := tx.Exec(sql, args...); err != nil {
+		return fmt.Errorf("failed to update report drop: %w", err)
+	}
+
+	remove := dialect.Delete(TableReportDropCounts).Where(goqu.C("drop_id").Eq(id))
+
+	sql, args, err = remove.Prepared(true).ToSQL()
+	if err != nil {
+		return fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	if _, err := tx.Exec(sql, args...); err != nil {
+		return fmt.Errorf("failed to delete report drop count: %w", err)
+	}
+
+	return r.addCountsTx(tx, id, counts)
+}
+
+// GetByReportIDs gets the drops of reports with their counts, keyed by report ID, in sequence
+func (r *ReportDropRepository) GetByReportIDs(reportIDs []int64) (map[int64][]models.ReportDrop, error) {
+	drops := map[int64][]models.ReportDrop{}
+	if len(reportIDs) == 0 {
+		return drops, nil
+	}
+
+	counts, err := r.getCounts(reportIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	query := dialect.Select(reportDropColumns...).
+		From(TableReportDrops).
+		Where(ColumnReportID.In(reportIDs)).
+		Order(goqu.C("sequence").Asc())
+
+	sql, args, err := query.Prepared(true).ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	rows, err := r.db.GetDB().Query(sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query report drops: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		drop, err := scanReportDrop(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan report drop: %w", err)
+		}
+		drop.Count = counts[drop.ID]
+		if drop.Count == nil {
+			drop.Count = []models.CashCount{}
+		}
+		drops[drop.ReportID] = append(drops[drop.ReportID], *drop)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate report drops: %w", err)
+	}
+
+	return drops, nil
+}
+
+// addCountsTx stores the bills and coins counted at a drop inside the caller's transaction
+func (r *ReportDropRepository) addCountsTx(tx *sql.Tx, dropID int64, counts []models.CashCount) error {
+	if len(counts) == 0 {
+		return nil
+	}
+
+	rows := make([]interface{}, 0, len(counts))
+	for _, count := range counts {
+		rows = append(rows, goqu.Record{
+			"drop_id":      dropID,
+			"report_id":    count.ReportID,
+			"stage":        count.Stage,
+			"denomination": count.Denomination,
+			"quantity":     count.Quantity,
+			"amount":       count.Amount,
+			"counted_by":   count.CountedBy,
+			"counted_at":   count.CountedAt,
+		})
+	}
+	insert := dialect.Insert(TableReportDropCounts).Rows(rows...)
+
+	sql, args, err := insert.Prepared(true).ToSQL()
+	if err != nil {
+		return fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	if _, err := tx.Exec(sql, args...); err != nil {
+		return fmt.Errorf("failed to add report drop count: %w", err)
+	}
+
+	return nil
+}
+
+// getCounts gets the drop counts of reports, keyed by drop ID, from the largest denomination down
+func (r *ReportDropRepository) getCounts(reportIDs []int64) (map[int64][]models.CashCount, error) {
+	query := dialect.Select("drop_id", "report_id", "stage", "denomination", "quantity", "amount", "counted_by", "counted_at").
+		From(TableReportDropCounts).
+		Where(ColumnReportID.In(reportIDs)).
+		Order(ColumnID.Asc())
+
+	sql, args, err := query.Prepared(true).ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	rows, err := r.db.GetDB().Query(sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query report drop counts: %w", err)
+	}
+	defer rows.Close()
+
+	counts := map[int64][]models.CashCount{}
+	for rows.Next() {
+		var dropID int64
+		var count models.CashCount
+		if err := rows.Scan(
+			&dropID,
+			&count.ReportID,
+			&count.Stage,
+			&count.Denomination,
+			&count.Quantity,
+			&count.Amount,
+			&count.CountedBy,
+			&count.CountedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan report drop count: %w", err)
+		}
+		counts[dropID] = append(counts[dropID], count)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate report drop counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+func scanReportDrop(row interface{ Scan(dest ...any) error }) (*models.ReportDrop, error) {
+	var drop models.ReportDrop
+	if err := row.Scan(
+		&drop.ID,
+		&drop.ReportID,
+		&drop.Sequence,
+		&drop.DroppedAt,
+		&drop.DroppedBy,
+		&drop.CashReceived,
+		&drop.Tickets,
+		&drop.Cash,
+		&drop.Drawer,
+	); err != nil {
+		return nil, err
+	}
+	return &drop, nil
+}
@@ -0,0 +1,79 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"neon/core/database/embedded"
+	"neon/core/helpers/enums"
+	"neon/core/models"
+	"sort"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+// ReportFareTotalRepository implements ReportFareTotalRepository for SQLite using goqu
+type ReportFareTotalRepository struct {
+	ctx context.Context
+	db  *embedded.SQLite
+}
+
+// NewReportFareTotalRepository creates a new report fare total repository
+func NewReportFareTotalRepository(ctx context.Context, db *embedded.SQLite) *ReportFareTotalRepository {
+	return &ReportFareTotalRepository{
+		ctx: ctx,
+		db:  db,
+	}
+}
+
+// GetByReportIDs gets the fare category totals of reports, keyed by report ID and ordered as
+// enums.AllFareCategories
+func (r *ReportFareTotalRepository) GetByReportIDs(reportIDs []int64) (map[int64][]models.ReportFareTotal, error) {
+	totals := map[int64][]models.ReportFareTotal{}
+	if len(reportIDs) == 0 {
+		return totals, nil
+	}
+
+	query := dialect.Select("report_id", "category", "tickets", "cash").
+		From(TableReportFareTotals).
+		Where(goqu.C("report_id").In(reportIDs))
+
+	sql, args, err := query.Prepared(true).ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	rows, err := r.db.GetDB().Query(sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query report fare totals: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var total models.ReportFareTotal
+		if err := rows.Scan(&total.ReportID, &total.Category, &total.Tickets, &total.Cash); err != nil {
+			return nil, fmt.Errorf("failed to scan report fare total: %w", err)
+		}
+		totals[total.ReportID] = append(totals[total.ReportID], total)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate report fare totals: %w", err)
+	}
+
+	for _, reportTotals := range totals {
+		sort.Slice(reportTotals, func(i, j int) bool {
+			return categoryOrder(reportTotals[i].Category) < categoryOrder(reportTotals[j].Category)
+		})
+	}
+
+	return totals, nil
+}
+
+func categoryOrder(category enums.FareCategory) int {
+	for i, c := range enums.AllFareCategories {
+		if c.Value == category {
+			return i
+		}
+	}
+	return len(enums.AllFareCategories)
+}
@@ -0,0 +1,284 @@
+package local
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"neon/core/database/embedded"
+	"neon/core/models"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+// reportHandoverColumns are the columns scanned by scanReportHandover, in order
+var reportHandoverColumns = []interface{}{
+	"id", "report_id", "sequence", "from_username", "to_username", "tickets", "cash_taken", "expected",
+	"cash", "counted_at", "accepted_at",
+}
+
+// ReportHandoverRepository implements ReportHandoverRepository for SQLite using goqu
+type ReportHandoverRepository struct {
+	ctx context.Context
+	db  *embedded.SQLite
+}
+
+// NewReportHandoverRepository creates a new report handover repository
+func NewReportHandoverRepository(ctx context.Context, db *embedded.SQLite) *ReportHandoverRepository {
+	return &ReportHandoverRepository{
+		ctx: ctx,
+		db:  db,
+	}
+}
+
+// AddTx stores a handover with its count inside the caller's transaction and returns it with the generated
+// ID
+func (r *ReportHandoverRepository) AddTx(tx *sql.Tx, handover models.ReportHandover) (*models.ReportHandover, error) {
+	insert := dialect.Insert(TableReportHandovers).Rows(handover)
+
+	sql, args, err := insert.Prepared(true).ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	result, err := tx.Exec(sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add report handover: %w", err)
+	}
+
+	generatedID, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get generated ID: %w", err)
+	}
+
+	handover.ID = generatedID
+
+	if len(handover.Count) == 0 {
+		return &handover, nil
+	}
+
+	rows := make([]interface{}, 0, len(handover.Count))
+	for _, count := range handover.Count {
+		rows = append(rows, goqu.Record{
+			"handover_id":  handover.ID,
+			"report_id":    count.ReportID,
+			"stage":        count.Stage,
+			"denomination": count.Denomination,
+			"quantity":     count.Quantity,
+			"amount":       count.Amount,
+			"counted_by":   count.CountedBy,
+			"counted_at":   count.CountedAt,
+		})
+	}
+	insert = dialect.Insert(TableReportHandoverCounts).Rows(rows...)
+
+	sql, args, err = insert.Prepared(true).ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	if _, err := tx.Exec(sql, args...); err != nil {
+		return nil, fmt.Errorf("failed to add report handover count: %w", err)
+	}
+
+	return &handover, nil
+}
+
+// GetLatest gets the last handover of a report, without its count
+func (r *ReportHandoverRepository) GetLatest(reportID int64) (*models.ReportHandover, error) {
+	query := dialect.Select(reportHandoverColumns...).
+		From(TableReportHandovers).
+		Where(ColumnReportID.Eq(reportID)).
+		Order(goqu.C("sequence").Desc()).
+		Limit(1)
+
+	sql, args, err := query.Prepared(true).ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	handover, err := scanReportHandover(r.db.GetDB().QueryRow(sql, args...))
+	if err != nil {
+		return nil, err
+	}
+
+	return handover, nil
+}
+
+// GetLatestTx gets the last handover of a report, without its count, inside the caller's transaction
+func (r *ReportHandoverRepository) GetLatestTx(tx *sql.Tx, reportID int64) (*models.ReportHandover, error) {
+	query := dialect.Select(reportHandoverColumns...).
+		From(TableReportHandovers).
+		Where(ColumnReportID.Eq(reportID)).
+		Order(goqu.C("sequence").Desc()).
+		Limit(1)
+
+	sql, args, err := query.Prepared(true).ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	return scanReportHandover(tx.QueryRow(sql, args...))
+}
+
+// AcceptTx records the incoming cashier accepting a handover inside the caller's transaction. It reports
+// false when the handover is no longer pending, e.g. accepted or cancelled meanwhile.
+func (r *ReportHandoverRepository) AcceptTx(tx *sql.Tx, id int64, toUsername string, acceptedAt string) (bool, error) {
+	update := dialect.Update(TableReportHandovers).
+		Set(goqu.Record{"to_username": toUsername, "accepted_at": acceptedAt}).
+		Where(ColumnID.Eq(id), goqu.C("accepted_at").IsNull())
+
+	sql, args, err := update.Prepared(true).ToSQL()
+	if err != nil {
+		return false, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	result, err := tx.Exec(sql, args...)
+	if err != nil {
+		return false, fmt.Errorf("failed to accept report handover: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to accept report handover: %w", err)
+	}
+
+	return affected == 1, nil
+}
+
+// DeleteTx deletes a handover and its count inside the caller's transaction
+func (r *ReportHandoverRepository) DeleteTx(tx *sql.Tx, id int64) error {
+	remove := dialect.Delete(TableReportHandoverCounts).Where(goqu.C("handover_id").Eq(id))
+
+	sql, args, err := remove.Prepared(true).ToSQL()
+	if err != nil {
+		return fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	if _, err := tx.Exec(sql, args...); err != nil {
+		return fmt.Errorf("failed to delete report handover count: %w", err)
+	}
+
+	remove = dialect.Delete(TableReportHandovers).Where(ColumnID.Eq(id))
+
+	sql, args, err = remove.Prepared(true).ToSQL()
+	if err != nil {
+		return fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	if _, err := tx.Exec(sql, args...); err != nil {
+		return fmt.Errorf("failed to delete report handover: %w", err)
+	}
+
+	return nil
+}
+
+// GetByReportIDs gets the handovers of reports with their counts, keyed by report ID, in sequence
+func (r *ReportHandoverRepository) GetByReportIDs(reportIDs []int64) (map[int64][]models.ReportHandover, error) {
+	handovers := map[int64][]models.ReportHandover{}
+	if len(reportIDs) == 0 {
+		return handovers, nil
+	}
+
+	counts, err := r.getCounts(reportIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	query := dialect.Select(reportHandoverColumns...).
+		From(TableReportHandovers).
+		Where(ColumnReportID.In(reportIDs)).
+		Order(goqu.C("sequence").Asc())
+
+	sql, args, err := query.Prepared(true).ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	rows, err := r.db.GetDB().Query(sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query report handovers: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		handover, err := scanReportHandover(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan report handover: %w", err)
+		}
+		handover.Count = counts[handover.ID]
+		if handover.Count == nil {
+			handover.Count = []models.CashCount{}
+		}
+		handovers[handover.ReportID] = append(handovers[handover.ReportID], *handover)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate report handovers: %w", err)
+	}
+
+	return handovers, nil
+}
+
+// getCounts gets the handover counts of reports, keyed by handover ID, from the largest denomination down
+func (r *ReportHandoverRepository) getCounts(reportIDs []int64) (map[int64][]models.CashCount, error) {
+	query := dialect.Select("handover_id", "report_id", "stage", "denomination", "quantity", "amount", "counted_by", "counted_at").
+		From(TableReportHandoverCounts).
+		Where(ColumnReportID.In(reportIDs)).
+		Order(ColumnID.Asc())
+
+	sql, args, err := query.Prepared(true).ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	rows, err := r.db.GetDB().Query(sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query report handover counts: %w", err)
+	}
+	defer rows.Close()
+
+	counts := map[int64][]models.CashCount{}
+	for rows.Next() {
+		var handoverID int64
+		var count models.CashCount
+		if err := rows.Scan(
+			&handoverID,
+			&count.ReportID,
+			&count.Stage,
+			&count.Denomination,
+			&count.Quantity,
+			&count.Amount,
+			&count.CountedBy,
+			&count.CountedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan report handover count: %w", err)
+		}
+		counts[handoverID] = append(counts[handoverID], count)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate report handover counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+func scanReportHandover(row interface{ Scan(dest ...any) error }) (*models.ReportHandover, error) {
+	var handover models.ReportHandover
+	if err := row.Scan(
+		&handover.ID,
+		&handover.ReportID,
+		&handover.Sequence,
+		&handover.FromUsername,
+		&handover.ToUsername,
+		&handover.Tickets,
+		&handover.CashTaken,
+		&handover.Expected,
+		&handover.Cash,
+		&handover.CountedAt,
+		&handover.AcceptedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &handover, nil
+}
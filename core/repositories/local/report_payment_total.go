@@ -0,0 +1,108 @@
+package local
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"neon/core/database/embedded"
+	"neon/core/helpers/enums"
+	"neon/core/models"
+	"sort"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+// ReportPaymentTotalRepository implements ReportPaymentTotalRepository for SQLite using goqu
+type ReportPaymentTotalRepository struct {
+	ctx context.Context
+	db  *embedded.SQLite
+}
+
+// NewReportPaymentTotalRepository creates a new report payment total repository
+func NewReportPaymentTotalRepository(ctx context.Context, db *embedded.SQLite) *ReportPaymentTotalRepository {
+	return &ReportPaymentTotalRepository{
+		ctx: ctx,
+		db:  db,
+	}
+}
+
+// GetByReportIDs gets the payment method totals of reports, keyed by report ID and ordered as
+// enums.AllPaymentMethods
+func (r *ReportPaymentTotalRepository) GetByReportIDs(reportIDs []int64) (map[int64][]models.ReportPaymentTotal, error) {
+	totals := map[int64][]models.ReportPaymentTotal{}
+	if len(reportIDs) == 0 {
+		return totals, nil
+	}
+
+	query := dialect.Select("report_id", "method", "payments", "partial_amount", "final_amount").
+		From(TableReportPaymentTotals).
+		Where(goqu.C("report_id").In(reportIDs))
+
+	sql, args, err := query.Prepared(true).ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	rows, err := r.db.GetDB().Query(sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query report payment totals: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var total models.ReportPaymentTotal
+		if err := rows.Scan(
+			&total.ReportID,
+			&total.Method,
+			&total.Payments,
+			&total.PartialAmount,
+			&total.FinalAmount,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan report payment total: %w", err)
+		}
+		totals[total.ReportID] = append(totals[total.ReportID], total)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate report payment totals: %w", err)
+	}
+
+	for _, reportTotals := range totals {
+		sort.Slice(reportTotals, func(i, j int) bool {
+			return paymentMethodOrder(reportTotals[i].Method) < paymentMethodOrder(reportTotals[j].Method)
+		})
+	}
+
+	return totals, nil
+}
+
+// DropTx moves the amounts taken since a report's last drop to the dropped ones inside the caller's
+// transaction
+func (r *ReportPaymentTotalRepository) DropTx(tx *sql.Tx, reportID int64) error {
+	query := dialect.Update(TableReportPaymentTotals).
+		Set(goqu.Record{
+			"partial_amount": goqu.L("partial_amount + final_amount"),
+			"final_amount":   0,
+		}).
+		Where(ColumnReportID.Eq(reportID))
+
+	sql, args, err := query.Prepared(true).ToSQL()
+	if err != nil {
+		return fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	if _, err := tx.Exec(sql, args...); err != nil {
+		return fmt.Errorf("failed to move report payment totals to drop: %w", err)
+	}
+
+	return nil
+}
+
+func paymentMethodOrder(method enums.PaymentMethod) int {
+	for i, m := range enums.AllPaymentMethods {
+		if m.Value == method {
+			return i
+		}
+	}
+	return len(enums.AllPaymentMethods)
+}
@@ -0,0 +1,101 @@
+package local
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"neon/core/database/embedded"
+	"neon/core/models"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+// ReportRecountRepository implements ReportRecountRepository for SQLite using goqu
+type ReportRecountRepository struct {
+	ctx context.Context
+	db  *embedded.SQLite
+}
+
+// NewReportRecountRepository creates a new report recount repository
+func NewReportRecountRepository(ctx context.Context, db *embedded.SQLite) *ReportRecountRepository {
+	return &ReportRecountRepository{
+		ctx: ctx,
+		db:  db,
+	}
+}
+
+// AddTx stores an admin recount inside the caller's transaction and returns it with the generated ID
+func (r *ReportRecountRepository) AddTx(tx *sql.Tx, recount models.ReportRecount) (*models.ReportRecount, error) {
+	insert := dialect.Insert(TableReportRecounts).Rows(recount)
+
+	sql, args, err := insert.Prepared(true).ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	result, err := tx.Exec(sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add report recount: %w", err)
+	}
+
+	generatedID, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get generated ID: %w", err)
+	}
+
+	recount.ID = generatedID
+
+	return &recount, nil
+}
+
+// GetByReportIDs gets the recounts of reports, keyed by report ID, oldest first
+func (r *ReportRecountRepository) GetByReportIDs(reportIDs []int64) (map[int64][]models.ReportRecount, error) {
+	recounts := map[int64][]models.ReportRecount{}
+	if len(reportIDs) == 0 {
+		return recounts, nil
+	}
+
+	query := dialect.Select(
+		"id", "report_id", "stage", "expected", "previous_received", "received", "variance",
+		"reason", "recounted_by", "recounted_at",
+	).
+		From(TableReportRecounts).
+		Where(goqu.C("report_id").In(reportIDs)).
+		Order(ColumnID.Asc())
+
+	sql, args, err := query.Prepared(true).ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	rows, err := r.db.GetDB().Query(sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query report recounts: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var recount models.ReportRecount
+		if err := rows.Scan(
+			&recount.ID,
+			&recount.ReportID,
+			&recount.Stage,
+			&recount.Expected,
+			&recount.PreviousReceived,
+			&recount.Received,
+			&recount.Variance,
+			&recount.Reason,
+			&recount.RecountedBy,
+			&recount.RecountedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan report recount: %w", err)
+		}
+		recounts[recount.ReportID] = append(recounts[recount.ReportID], recount)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate report recounts: %w", err)
+	}
+
+	return recounts, nil
+}
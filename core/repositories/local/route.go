@@ -85,3 +85,25 @@ func (r *RouteRepository) BulkCreate(routes []models.Route) error {
 func (r *RouteRepository) Clear() error {
 	return r.db.GetDB().Delete(q.NewQuery(r.collection))
 }
+
+// GetByName returns the route between departure and destination
+func (r *RouteRepository) GetByName(departure string, destination string) (*models.Route, error) {
+	query := q.NewQuery(r.collection).Where(
+		q.Field("departure").Eq(departure).And(q.Field("destination").Eq(destination)),
+	)
+
+	doc, err := r.db.GetDB().FindFirst(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find route: %w", err)
+	}
+	if doc == nil {
+		return nil, helpers.ErrRowNotFound
+	}
+
+	var route models.Route
+	if err := doc.Unmarshal(&route); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal route: %w", err)
+	}
+
+	return &route, nil
+}
@@ -0,0 +1,98 @@
+package local
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"neon/core/database/embedded"
+	"neon/core/models"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+// SaleRepository implements SaleRepository for SQLite using goqu
+type SaleRepository struct {
+	ctx context.Context
+	db  *embedded.SQLite
+}
+
+// NewSaleRepository creates a new sale repository
+func NewSaleRepository(ctx context.Context, db *embedded.SQLite) *SaleRepository {
+	return &SaleRepository{
+		ctx: ctx,
+		db:  db,
+	}
+}
+
+// AddTx creates a sale inside the caller's transaction and returns it with its generated ID
+func (r *SaleRepository) AddTx(tx *sql.Tx, sale models.Sale) (*models.Sale, error) {
+	insert := dialect.Insert(TableSales).Rows(sale)
+
+	sql, args, err := insert.Prepared(true).ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	result, err := tx.Exec(sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add sale: %w", err)
+	}
+
+	generatedID, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get generated ID: %w", err)
+	}
+
+	sale.ID = generatedID
+
+	return &sale, nil
+}
+
+// VoidTx marks a sale as voided inside the caller's transaction
+func (r *SaleRepository) VoidTx(tx *sql.Tx, saleID int64, voidedBy string, voidedAt string) error {
+	update := dialect.Update(TableSales).Set(goqu.Record{
+		"is_null":   true,
+		"voided_at": voidedAt,
+		"voided_by": voidedBy,
+	}).Where(ColumnID.Eq(saleID))
+
+	sql, args, err := update.Prepared(true).ToSQL()
+	if err != nil {
+		return fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	if _, err := tx.Exec(sql, args...); err != nil {
+		return fmt.Errorf("failed to void sale: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID gets a sale by its ID, without its tickets and payments
+func (r *SaleRepository) GetByID(saleID int64) (*models.Sale, error) {
+	query := dialect.Select(
+		"id", "report_id", "username", "total", "ticket_count", "is_null", "created_at", "voided_at", "voided_by",
+	).From(TableSales).Where(ColumnID.Eq(saleID))
+
+	sql, args, err := query.Prepared(true).ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	var sale models.Sale
+	if err := r.db.GetDB().QueryRow(sql, args...).Scan(
+		&sale.ID,
+		&sale.ReportID,
+		&sale.Username,
+		&sale.Total,
+		&sale.TicketCount,
+		&sale.IsNull,
+		&sale.CreatedAt,
+		&sale.VoidedAt,
+		&sale.VoidedBy,
+	); err != nil {
+		return nil, err
+	}
+
+	return &sale, nil
+}
@@ -0,0 +1,210 @@
+package local
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"neon/core/database/embedded"
+	"neon/core/helpers/enums"
+	"neon/core/models"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+// SalePaymentRepository implements SalePaymentRepository for SQLite using goqu
+type SalePaymentRepository struct {
+	ctx context.Context
+	db  *embedded.SQLite
+}
+
+// NewSalePaymentRepository creates a new sale payment repository
+func NewSalePaymentRepository(ctx context.Context, db *embedded.SQLite) *SalePaymentRepository {
+	return &SalePaymentRepository{
+		ctx: ctx,
+		db:  db,
+	}
+}
+
+// AddTx records a payment of a sale inside the caller's transaction
+func (r *SalePaymentRepository) AddTx(tx *sql.Tx, payment models.SalePayment) (*models.SalePayment, error) {
+	insert := dialect.Insert(TableSalePayments).Rows(payment)
+
+	sql, args, err := insert.Prepared(true).ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	result, err := tx.Exec(sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add sale payment: %w", err)
+	}
+
+	generatedID, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get generated ID: %w", err)
+	}
+
+	payment.ID = generatedID
+
+	return &payment, nil
+}
+
+// GetBySaleID gets the payments of a sale
+func (r *SalePaymentRepository) GetBySaleID(saleID int64) ([]models.SalePayment, error) {
+	sql, args, err := salePaymentsQuery(saleID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.GetDB().Query(sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sale payments: %w", err)
+	}
+	defer rows.Close()
+
+	return scanSalePayments(rows)
+}
+
+// GetBySaleIDTx gets the payments of a sale inside the caller's transaction
+func (r *SalePaymentRepository) GetBySaleIDTx(tx *sql.Tx, saleID int64) ([]models.SalePayment, error) {
+	sql, args, err := salePaymentsQuery(saleID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Query(sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sale payments: %w", err)
+	}
+	defer rows.Close()
+
+	return scanSalePayments(rows)
+}
+
+// Get gets a payment
+func (r *SalePaymentRepository) Get(id int64) (*models.SalePayment, error) {
+	query := dialect.Select(salePaymentColumns...).From(TableSalePayments).Where(ColumnID.Eq(id))
+
+	sql, args, err := query.Prepared(true).ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	payment, err := scanSalePayment(r.db.GetDB().QueryRow(sql, args...))
+	if err != nil {
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+// UpdateCaptureStatus stores where the capture of a card payment on the terminal is
+func (r *SalePaymentRepository) UpdateCaptureStatus(id int64, status enums.OutboxState) error {
+	return r.updateStatus(id, goqu.Record{"capture_status": status})
+}
+
+// UpdateRefundStatus stores where the refund of a card payment on the terminal is
+func (r *SalePaymentRepository) UpdateRefundStatus(id int64, status enums.OutboxState) error {
+	return r.updateStatus(id, goqu.Record{"refund_status": status})
+}
+
+// UpdateCaptureStatusTx stores where the capture of a card payment is inside the caller's transaction
+func (r *SalePaymentRepository) UpdateCaptureStatusTx(tx *sql.Tx, id int64, status enums.OutboxState) error {
+	return r.updateStatusTx(tx, id, goqu.Record{"capture_status": status})
+}
+
+// UpdateRefundStatusTx stores where the refund of a card payment is inside the caller's transaction
+func (r *SalePaymentRepository) UpdateRefundStatusTx(tx *sql.Tx, id int64, status enums.OutboxState) error {
+	return r.updateStatusTx(tx, id, goqu.Record{"refund_status": status})
+}
+
+func (r *SalePaymentRepository) updateStatus(id int64, record goqu.Record) error {
+	update := dialect.Update(TableSalePayments).
+		Set(record).
+		Where(ColumnID.Eq(id))
+
+	sql, args, err := update.Prepared(true).ToSQL()
+	if err != nil {
+		return fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	if _, err := r.db.GetDB().Exec(sql, args...); err != nil {
+		return fmt.Errorf("failed to update sale payment: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SalePaymentRepository) updateStatusTx(tx *sql.Tx, id int64, record goqu.Record) error {
+	update := dialect.Update(TableSalePayments).
+		Set(record).
+		Where(ColumnID.Eq(id))
+
+	sql, args, err := update.Prepared(true).ToSQL()
+	if err != nil {
+		return fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	if _, err := tx.Exec(sql, args...); err != nil {
+		return fmt.Errorf("failed to update sale payment: %w", err)
+	}
+
+	return nil
+}
+
+// salePaymentColumns are the columns scanned by scanSalePayments, in order
+var salePaymentColumns = []interface{}{
+	"id", "sale_id", "method", "amount", "tendered", "change_given", "reference", "approval_code",
+	"capture_status", "refund_status", "created_at",
+}
+
+func salePaymentsQuery(saleID int64) (string, []interface{}, error) {
+	query := dialect.Select(salePaymentColumns...).
+		From(TableSalePayments).
+		Where(ColumnSaleID.Eq(saleID)).
+		Order(ColumnID.Asc())
+
+	sql, args, err := query.Prepared(true).ToSQL()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	return sql, args, nil
+}
+
+func scanSalePayments(rows *sql.Rows) ([]models.SalePayment, error) {
+	payments := []models.SalePayment{}
+	for rows.Next() {
+		payment, err := scanSalePayment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan sale payment: %w", err)
+		}
+		payments = append(payments, *payment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate sale payments: %w", err)
+	}
+
+	return payments, nil
+}
+
+func scanSalePayment(row interface{ Scan(dest ...any) error }) (*models.SalePayment, error) {
+	var payment models.SalePayment
+	if err := row.Scan(
+		&payment.ID,
+		&payment.SaleID,
+		&payment.Method,
+		&payment.Amount,
+		&payment.Tendered,
+		&payment.Change,
+		&payment.Reference,
+		&payment.ApprovalCode,
+		&payment.CaptureStatus,
+		&payment.RefundStatus,
+		&payment.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
@@ -0,0 +1,154 @@
+package local
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"neon/core/database/embedded"
+	"neon/core/helpers"
+	"neon/core/models"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+// SeatInventoryRepository implements SeatInventoryRepository for SQLite using goqu
+type SeatInventoryRepository struct {
+	ctx context.Context
+	db  *embedded.SQLite
+}
+
+// NewSeatInventoryRepository creates a new seat inventory repository
+func NewSeatInventoryRepository(ctx context.Context, db *embedded.SQLite) *SeatInventoryRepository {
+	return &SeatInventoryRepository{
+		ctx: ctx,
+		db:  db,
+	}
+}
+
+// ReserveTx takes seats from a departure inside the caller's transaction. The departure's row is created
+// on its first sale and its capacity refreshed from the route. Returns helpers.ErrDepartureFull when the
+// departure doesn't have enough seats left; a capacity of 0 has no limit.
+func (r *SeatInventoryRepository) ReserveTx(tx *sql.Tx, inventory models.SeatInventory, seats int) error {
+	insert := dialect.Insert(TableSeatInventory).Rows(goqu.Record{
+		"departure":   inventory.Departure,
+		"destination": inventory.Destination,
+		"travel_date": inventory.TravelDate,
+		"time":        inventory.Time,
+		"capacity":    inventory.Capacity,
+		"sold":        0,
+	}).OnConflict(goqu.DoUpdate(
+		"departure, destination, travel_date, time",
+		goqu.Record{"capacity": inventory.Capacity},
+	))
+
+	sql, args, err := insert.Prepared(true).ToSQL()
+	if err != nil {
+		return fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	if _, err := tx.Exec(sql, args...); err != nil {
+		return fmt.Errorf("failed to upsert seat inventory: %w", err)
+	}
+
+	update := dialect.Update(TableSeatInventory).
+		Set(goqu.Record{"sold": goqu.L("sold + ?", seats)}).
+		Where(
+			inventoryKey(inventory),
+			goqu.Or(
+				goqu.C("capacity").Eq(0),
+				goqu.L("sold + ? <= capacity", seats),
+			),
+		)
+
+	sql, args, err = update.Prepared(true).ToSQL()
+	if err != nil {
+		return fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	result, err := tx.Exec(sql, args...)
+	if err != nil {
+		return fmt.Errorf("failed to reserve seats: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return helpers.ErrDepartureFull
+	}
+
+	return nil
+}
+
+// ReleaseTx gives seats back to a departure inside the caller's transaction
+func (r *SeatInventoryRepository) ReleaseTx(tx *sql.Tx, inventory models.SeatInventory, seats int) error {
+	update := dialect.Update(TableSeatInventory).
+		Set(goqu.Record{"sold": goqu.L("MAX(sold - ?, 0)", seats)}).
+		Where(inventoryKey(inventory))
+
+	sql, args, err := update.Prepared(true).ToSQL()
+	if err != nil {
+		return fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	if _, err := tx.Exec(sql, args...); err != nil {
+		return fmt.Errorf("failed to release seats: %w", err)
+	}
+
+	return nil
+}
+
+// GetByTravelDate gets the seat inventory of a route's departures on a travel date
+func (r *SeatInventoryRepository) GetByTravelDate(departure string, destination string, travelDate string) ([]models.SeatInventory, error) {
+	query := dialect.Select(
+		"id", "departure", "destination", "travel_date", "time", "capacity", "sold",
+	).From(TableSeatInventory).Where(
+		goqu.C("departure").Eq(departure),
+		goqu.C("destination").Eq(destination),
+		goqu.C("travel_date").Eq(travelDate),
+	).Order(goqu.C("time").Asc())
+
+	sql, args, err := query.Prepared(true).ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	rows, err := r.db.GetDB().Query(sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query seat inventory: %w", err)
+	}
+	defer rows.Close()
+
+	var inventories []models.SeatInventory
+	for rows.Next() {
+		var inventory models.SeatInventory
+		if err := rows.Scan(
+			&inventory.ID,
+			&inventory.Departure,
+			&inventory.Destination,
+			&inventory.TravelDate,
+			&inventory.Time,
+			&inventory.Capacity,
+			&inventory.Sold,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan seat inventory: %w", err)
+		}
+		inventories = append(inventories, inventory)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate seat inventory: %w", err)
+	}
+
+	return inventories, nil
+}
+
+func inventoryKey(inventory models.SeatInventory) goqu.Expression {
+	return goqu.And(
+		goqu.C("departure").Eq(inventory.Departure),
+		goqu.C("destination").Eq(inventory.Destination),
+		goqu.C("travel_date").Eq(inventory.TravelDate),
+		goqu.C("time").Eq(inventory.Time),
+	)
+}
@@ -0,0 +1,136 @@
+package local
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"neon/core/database/embedded"
+	"neon/core/helpers"
+	"neon/core/models"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+// SeatReservationRepository implements SeatReservationRepository for SQLite using goqu
+type SeatReservationRepository struct {
+	ctx context.Context
+	db  *embedded.SQLite
+}
+
+// NewSeatReservationRepository creates a new seat reservation repository
+func NewSeatReservationRepository(ctx context.Context, db *embedded.SQLite) *SeatReservationRepository {
+	return &SeatReservationRepository{
+		ctx: ctx,
+		db:  db,
+	}
+}
+
+// AddTx books a numbered seat inside the caller's transaction. Returns helpers.ErrSeatTaken when the
+// seat is already booked on the departure.
+func (r *SeatReservationRepository) AddTx(tx *sql.Tx, reservation models.SeatReservation) (*models.SeatReservation, error) {
+	exists := dialect.Select(ColumnID).From(TableSeatReservations).Where(
+		goqu.C("departure").Eq(reservation.Departure),
+		goqu.C("destination").Eq(reservation.Destination),
+		goqu.C("travel_date").Eq(reservation.TravelDate),
+		goqu.C("time").Eq(reservation.Time),
+		goqu.C("seat_number").Eq(reservation.SeatNumber),
+	).Limit(1)
+
+	existsSQL, args, err := exists.Prepared(true).ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	var id int64
+	err = tx.QueryRow(existsSQL, args...).Scan(&id)
+	if err == nil {
+		return nil, helpers.ErrSeatTaken
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to check seat reservation: %w", err)
+	}
+
+	insert := dialect.Insert(TableSeatReservations).Rows(reservation)
+
+	sql, args, err := insert.Prepared(true).ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	result, err := tx.Exec(sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add seat reservation: %w", err)
+	}
+
+	generatedID, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get generated ID: %w", err)
+	}
+
+	reservation.ID = generatedID
+
+	return &reservation, nil
+}
+
+// DeleteByTicketIDTx releases the seat of a ticket inside the caller's transaction
+func (r *SeatReservationRepository) DeleteByTicketIDTx(tx *sql.Tx, ticketID int64) error {
+	query := dialect.Delete(TableSeatReservations).Where(ColumnTicketID.Eq(ticketID))
+
+	sql, args, err := query.Prepared(true).ToSQL()
+	if err != nil {
+		return fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	if _, err := tx.Exec(sql, args...); err != nil {
+		return fmt.Errorf("failed to delete seat reservation: %w", err)
+	}
+
+	return nil
+}
+
+// GetByDeparture gets the seats booked on a departure of a route on a travel date
+func (r *SeatReservationRepository) GetByDeparture(departure string, destination string, travelDate string, departureTime string) ([]models.SeatReservation, error) {
+	query := dialect.Select(
+		"id", "ticket_id", "departure", "destination", "travel_date", "time", "seat_number",
+	).From(TableSeatReservations).Where(
+		goqu.C("departure").Eq(departure),
+		goqu.C("destination").Eq(destination),
+		goqu.C("travel_date").Eq(travelDate),
+		goqu.C("time").Eq(departureTime),
+	).Order(goqu.C("seat_number").Asc())
+
+	sql, args, err := query.Prepared(true).ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	rows, err := r.db.GetDB().Query(sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query seat reservations: %w", err)
+	}
+	defer rows.Close()
+
+	reservations := []models.SeatReservation{}
+	for rows.Next() {
+		var reservation models.SeatReservation
+		if err := rows.Scan(
+			&reservation.ID,
+			&reservation.TicketID,
+			&reservation.Departure,
+			&reservation.Destination,
+			&reservation.TravelDate,
+			&reservation.Time,
+			&reservation.SeatNumber,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan seat reservation: %w", err)
+		}
+		reservations = append(reservations, reservation)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate seat reservations: %w", err)
+	}
+
+	return reservations, nil
+}
@@ -2,12 +2,15 @@ package local
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"neon/core/database/embedded"
+	"neon/core/helpers"
 	"neon/core/models"
 	"time"
 
 	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
 )
 
 // TicketRepository implements TicketRepository for SQLite using goqu
@@ -35,26 +38,39 @@ func (r *TicketRepository) BulkCreate(tickets []models.Ticket) ([]models.Ticket,
 		return nil, fmt.Errorf("failed to begin transaction: %w", err)
 	}
 
+	updatedTickets, err := r.BulkCreateTx(tx, tickets)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return updatedTickets, nil
+}
+
+// BulkCreateTx inserts a bulk of tickets inside the caller's transaction and returns them with
+// generated IDs. The caller owns the transaction and must commit or roll it back.
+func (r *TicketRepository) BulkCreateTx(tx *sql.Tx, tickets []models.Ticket) ([]models.Ticket, error) {
 	var updatedTickets []models.Ticket
 
 	for i, ticket := range tickets {
 		query := dialect.Insert(TableTickets).Rows(ticket)
 		sql, args, err := query.Prepared(true).ToSQL()
 		if err != nil {
-			tx.Rollback()
 			return nil, fmt.Errorf("failed to prepare query: %w", err)
 		}
 
 		result, err := tx.Exec(sql, args...)
 		if err != nil {
-			tx.Rollback()
 			return nil, fmt.Errorf("failed to add ticket: %w", err)
 		}
 
 		// Get the generated ID
 		generatedID, err := result.LastInsertId()
 		if err != nil {
-			tx.Rollback()
 			return nil, fmt.Errorf("failed to get generated ID: %w", err)
 		}
 
@@ -63,10 +79,6 @@ func (r *TicketRepository) BulkCreate(tickets []models.Ticket) ([]models.Ticket,
 		updatedTickets = append(updatedTickets, tickets[i])
 	}
 
-	if err := tx.Commit(); err != nil {
-		return nil, fmt.Errorf("failed to commit transaction: %w", err)
-	}
-
 	return updatedTickets, nil
 }
 
@@ -104,6 +116,25 @@ func (r *TicketRepository) BulkUpdate(tickets []models.Ticket) error {
 
 // Update updates a ticket in the database
 func (r *TicketRepository) Update(ticket models.Ticket) error {
+	tx, err := r.db.BeginTx(r.ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if err := r.UpdateTx(tx, ticket); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateTx updates a ticket inside the caller's transaction
+func (r *TicketRepository) UpdateTx(tx *sql.Tx, ticket models.Ticket) error {
 	if ticket.ID == 0 {
 		return fmt.Errorf("ticket id is required")
 	}
@@ -122,19 +153,27 @@ func (r *TicketRepository) Update(ticket models.Ticket) error {
 		return fmt.Errorf("failed to prepare query: %w", err)
 	}
 
-	tx, err := r.db.BeginTx(r.ctx, nil)
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+	if _, err := tx.Exec(sql, args...); err != nil {
+		return fmt.Errorf("failed to update ticket: %w", err)
 	}
 
-	_, err = tx.Exec(sql, args...)
+	return nil
+}
+
+// AssignDropTx attributes the tickets of a report sold since its last drop to a new drop inside the
+// caller's transaction
+func (r *TicketRepository) AssignDropTx(tx *sql.Tx, reportID int64, sequence int) error {
+	query := dialect.Update(TableTickets).
+		Set(goqu.Record{"drop_sequence": sequence}).
+		Where(ColumnReportID.Eq(reportID), goqu.C("drop_sequence").Eq(0))
+
+	sql, args, err := query.Prepared(true).ToSQL()
 	if err != nil {
-		tx.Rollback()
-		return fmt.Errorf("failed to update ticket: %w", err)
+		return fmt.Errorf("failed to prepare query: %w", err)
 	}
 
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+	if _, err := tx.Exec(sql, args...); err != nil {
+		return fmt.Errorf("failed to assign tickets to drop: %w", err)
 	}
 
 	return nil
@@ -176,7 +215,7 @@ func (r *TicketRepository) BulkDelete(tickets []models.Ticket) error {
 func (r *TicketRepository) GetByReportID(
 	reportID int64,
 ) ([]models.Ticket, error) {
-	query := dialect.Select().
+	query := dialect.Select(ticketColumns...).
 		From(TableTickets).
 		Where(
 			goqu.I("report_id").Eq(reportID),
@@ -196,26 +235,11 @@ func (r *TicketRepository) GetByReportID(
 
 	var tickets []models.Ticket
 	for rows.Next() {
-		var ticket models.Ticket
-		err = rows.Scan(
-			&ticket.ID,
-			&ticket.Departure,
-			&ticket.Destination,
-			&ticket.Username,
-			&ticket.Stop,
-			&ticket.Time,
-			&ticket.Fare,
-			&ticket.IsGold,
-			&ticket.IsNull,
-			&ticket.IDNumber,
-			&ticket.ReportID,
-			&ticket.CreatedAt,
-			&ticket.UpdatedAt,
-		)
+		ticket, err := scanTicket(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan ticket: %w", err)
 		}
-		tickets = append(tickets, ticket)
+		tickets = append(tickets, *ticket)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -227,7 +251,7 @@ func (r *TicketRepository) GetByReportID(
 
 // GetByID gets a ticket by id
 func (r *TicketRepository) GetByID(id int64) (*models.Ticket, error) {
-	query := dialect.Select(goqu.C("*")).From(TableTickets).Where(ColumnID.Eq(id)).Limit(1)
+	query := dialect.Select(ticketColumns...).From(TableTickets).Where(ColumnID.Eq(id)).Limit(1)
 
 	sql, args, err := query.Prepared(true).ToSQL()
 	if err != nil {
@@ -236,8 +260,372 @@ func (r *TicketRepository) GetByID(id int64) (*models.Ticket, error) {
 
 	row := r.db.GetDB().QueryRow(sql, args...)
 
+	ticket, err := scanTicket(row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan ticket: %w", err)
+	}
+
+	if err := row.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get ticket: %w", err)
+	}
+
+	return ticket, nil
+}
+
+// GetByDeparture gets the tickets sold for a departure of a route on a travel date
+func (r *TicketRepository) GetByDeparture(departure string, destination string, travelDate string, departureTime string) ([]models.Ticket, error) {
+	query := dialect.Select(ticketColumns...).
+		From(TableTickets).
+		Where(
+			goqu.C("departure").Eq(departure),
+			goqu.C("destination").Eq(destination),
+			goqu.C("travel_date").Eq(travelDate),
+			goqu.C("time").Eq(departureTime),
+		).
+		Order(ColumnID.Asc())
+
+	sql, args, err := query.Prepared(true).ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	rows, err := r.db.GetDB().Query(sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tickets: %w", err)
+	}
+	defer rows.Close()
+
+	var tickets []models.Ticket
+	for rows.Next() {
+		ticket, err := scanTicket(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan ticket: %w", err)
+		}
+		tickets = append(tickets, *ticket)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get tickets: %w", err)
+	}
+
+	return tickets, nil
+}
+
+// GetBySaleID gets the tickets of a sale
+func (r *TicketRepository) GetBySaleID(saleID int64) ([]models.Ticket, error) {
+	query := dialect.Select(ticketColumns...).
+		From(TableTickets).
+		Where(ColumnSaleID.Eq(saleID)).
+		Order(ColumnID.Asc())
+
+	sql, args, err := query.Prepared(true).ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	rows, err := r.db.GetDB().Query(sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tickets: %w", err)
+	}
+	defer rows.Close()
+
+	tickets := []models.Ticket{}
+	for rows.Next() {
+		ticket, err := scanTicket(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan ticket: %w", err)
+		}
+		tickets = append(tickets, *ticket)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get tickets: %w", err)
+	}
+
+	return tickets, nil
+}
+
+// GetGoldByIDNumber gets the gold tickets, not voided, of an identification number on a travel date
+func (r *TicketRepository) GetGoldByIDNumber(idNumber string, travelDate string) ([]models.Ticket, error) {
+	query := dialect.Select(ticketColumns...).
+		From(TableTickets).
+		Where(
+			goqu.C("id_number").Eq(idNumber),
+			goqu.C("travel_date").Eq(travelDate),
+			goqu.C("is_gold").Eq(true),
+			goqu.C("is_null").Eq(false),
+		).
+		Order(ColumnID.Asc())
+
+	sql, args, err := query.Prepared(true).ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	rows, err := r.db.GetDB().Query(sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gold tickets: %w", err)
+	}
+	defer rows.Close()
+
+	var tickets []models.Ticket
+	for rows.Next() {
+		ticket, err := scanTicket(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan ticket: %w", err)
+		}
+		tickets = append(tickets, *ticket)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get gold tickets: %w", err)
+	}
+
+	return tickets, nil
+}
+
+// GetAdvanceSales counts the tickets that are not voided per departure travelling after a date
+func (r *TicketRepository) GetAdvanceSales(after string) ([]models.AdvanceDeparture, error) {
+	query := dialect.Select(
+		goqu.C("departure"),
+		goqu.C("destination"),
+		goqu.C("travel_date"),
+		goqu.C("time"),
+		goqu.COUNT("*"),
+		goqu.SUM(goqu.L("CASE WHEN is_gold = 1 THEN 1 ELSE 0 END")),
+		goqu.SUM(goqu.C("fare")),
+	).
+		From(TableTickets).
+		Where(
+			goqu.C("travel_date").Gt(after),
+			goqu.C("is_null").Eq(false),
+		).
+		GroupBy(goqu.C("departure"), goqu.C("destination"), goqu.C("travel_date"), goqu.C("time")).
+		Order(goqu.C("travel_date").Asc(), goqu.C("time").Asc(), goqu.C("departure").Asc(), goqu.C("destination").Asc())
+
+	sql, args, err := query.Prepared(true).ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	rows, err := r.db.GetDB().Query(sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query advance sales: %w", err)
+	}
+	defer rows.Close()
+
+	var departures []models.AdvanceDeparture
+	for rows.Next() {
+		var departure models.AdvanceDeparture
+		if err := rows.Scan(
+			&departure.Departure,
+			&departure.Destination,
+			&departure.TravelDate,
+			&departure.Time,
+			&departure.Tickets,
+			&departure.Gold,
+			&departure.Cash,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan advance sales: %w", err)
+		}
+		departures = append(departures, departure)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate advance sales: %w", err)
+	}
+
+	return departures, nil
+}
+
+// GetTotalsByReportIDs counts the tickets of reports and adds up their fares per route, stop, departure
+// time, seller and kind
+func (r *TicketRepository) GetTotalsByReportIDs(reportIDs []int64) ([]models.TicketTotal, error) {
+	if len(reportIDs) == 0 {
+		return []models.TicketTotal{}, nil
+	}
+
+	groups := []any{
+		goqu.C("departure"), goqu.C("destination"), goqu.C("stop"), goqu.C("time"),
+		goqu.C("username"), goqu.C("is_gold"), goqu.C("is_null"),
+	}
+	query := dialect.Select(append(groups, goqu.COUNT("*"), goqu.SUM(goqu.C("fare")))...).
+		From(TableTickets).
+		Where(ColumnReportID.In(reportIDs)).
+		GroupBy(groups...)
+
+	sql, args, err := query.Prepared(true).ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	rows, err := r.db.GetDB().Query(sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query ticket totals: %w", err)
+	}
+	defer rows.Close()
+
+	totals := []models.TicketTotal{}
+	for rows.Next() {
+		var total models.TicketTotal
+		if err := rows.Scan(
+			&total.Departure,
+			&total.Destination,
+			&total.Stop,
+			&total.Time,
+			&total.Username,
+			&total.IsGold,
+			&total.IsNull,
+			&total.Tickets,
+			&total.Cash,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan ticket totals: %w", err)
+		}
+		totals = append(totals, total)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate ticket totals: %w", err)
+	}
+
+	return totals, nil
+}
+
+// ticketTravelDate is the day a ticket travels on. Tickets sold before travel dates have none and
+// travelled on the local day they were sold.
+var ticketTravelDate = goqu.L("COALESCE(NULLIF(travel_date, ''), date(created_at, 'localtime'))")
+
+// ticketSortColumns are the orders a ticket search can be sorted by
+var ticketSortColumns = map[string]exp.Orderable{
+	"id":          ColumnID,
+	"created_at":  goqu.L("julianday(created_at)"),
+	"travel_date": ticketTravelDate,
+	"time":        goqu.C("time"),
+	"stop":        goqu.C("stop"),
+	"fare":        goqu.C("fare"),
+}
+
+// Search gets a page of the tickets matching a search, sorted as asked, with the totals of all the tickets
+// matching it. soldFrom and soldTo bound when the tickets were sold as RFC3339 instants, soldTo excluded,
+// and are ignored when empty; the search's sale dates are not read. The search's page and page size must
+// be set; an unknown sort returns helpers.ErrInvalidRequest.
+func (r *TicketRepository) Search(search models.TicketSearch, soldFrom string, soldTo string) ([]models.Ticket, models.TicketSearchTotals, error) {
+	var totals models.TicketSearchTotals
+
+	order := ColumnID.Desc()
+	if search.SortBy != "" {
+		column, ok := ticketSortColumns[search.SortBy]
+		if !ok {
+			return nil, totals, helpers.ErrInvalidRequest
+		}
+		order = column.Asc()
+		if search.Descending {
+			order = column.Desc()
+		}
+	}
+
+	where := []goqu.Expression{}
+	if soldFrom != "" {
+		where = append(where, goqu.L("julianday(created_at) >= julianday(?)", soldFrom))
+	}
+	if soldTo != "" {
+		where = append(where, goqu.L("julianday(created_at) < julianday(?)", soldTo))
+	}
+	if search.TravelFrom != "" {
+		where = append(where, ticketTravelDate.Gte(search.TravelFrom))
+	}
+	if search.TravelTo != "" {
+		where = append(where, ticketTravelDate.Lte(search.TravelTo))
+	}
+	for _, filter := range []struct{ column, value string }{
+		{"departure", search.Departure},
+		{"destination", search.Destination},
+		{"stop", search.Stop},
+		{"time", search.Time},
+		{"username", search.Username},
+		{"id_number", search.IDNumber},
+	} {
+		if filter.value != "" {
+			where = append(where, goqu.C(filter.column).Eq(filter.value))
+		}
+	}
+	if search.IsGold != nil {
+		where = append(where, goqu.C("is_gold").Eq(*search.IsGold))
+	}
+	if search.IsNull != nil {
+		where = append(where, goqu.C("is_null").Eq(*search.IsNull))
+	}
+	if search.ReportID != 0 {
+		where = append(where, ColumnReportID.Eq(search.ReportID))
+	}
+
+	query := dialect.Select(
+		goqu.L("COALESCE(SUM(CASE WHEN is_null = 0 THEN 1 ELSE 0 END), 0)"),
+		goqu.L("COALESCE(SUM(CASE WHEN is_null = 0 THEN fare ELSE 0 END), 0)"),
+		goqu.L("COALESCE(SUM(CASE WHEN is_null = 0 AND is_gold = 1 THEN 1 ELSE 0 END), 0)"),
+		goqu.L("COALESCE(SUM(CASE WHEN is_null = 1 THEN 1 ELSE 0 END), 0)"),
+		goqu.L("COALESCE(SUM(CASE WHEN is_null = 1 THEN fare ELSE 0 END), 0)"),
+	).From(TableTickets).Where(where...)
+
+	sql, args, err := query.Prepared(true).ToSQL()
+	if err != nil {
+		return nil, totals, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	if err := r.db.GetDB().QueryRow(sql, args...).Scan(
+		&totals.Tickets,
+		&totals.Cash,
+		&totals.Gold,
+		&totals.Null,
+		&totals.NullCash,
+	); err != nil {
+		return nil, totals, fmt.Errorf("failed to total tickets: %w", err)
+	}
+
+	page := dialect.Select(ticketColumns...).From(TableTickets).Where(where...).
+		Order(order, ColumnID.Desc()).
+		Limit(uint(search.PageSize)).
+		Offset(uint((search.Page - 1) * search.PageSize))
+
+	sql, args, err = page.Prepared(true).ToSQL()
+	if err != nil {
+		return nil, totals, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	rows, err := r.db.GetDB().Query(sql, args...)
+	if err != nil {
+		return nil, totals, fmt.Errorf("failed to query tickets: %w", err)
+	}
+	defer rows.Close()
+
+	tickets := []models.Ticket{}
+	for rows.Next() {
+		ticket, err := scanTicket(rows)
+		if err != nil {
+			return nil, totals, fmt.Errorf("failed to scan ticket: %w", err)
+		}
+		tickets = append(tickets, *ticket)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, totals, fmt.Errorf("failed to iterate tickets: %w", err)
+	}
+
+	return tickets, totals, nil
+}
+
+// ticketColumns lists the ticket columns in the order scanTicket reads them
+var ticketColumns = []any{
+	"id", "departure", "destination", "username", "stop", "time", "fare",
+	"is_gold", "is_null", "id_number", "report_id", "created_at", "updated_at",
+	"travel_date", "is_advance", "seat_number", "fare_category", "fare_rule",
+	"fare_version", "sale_id", "approval_code", "drop_sequence",
+}
+
+// scanTicket reads a ticket selected with ticketColumns
+func scanTicket(row interface{ Scan(dest ...any) error }) (*models.Ticket, error) {
 	var ticket models.Ticket
-	err = row.Scan(
+	if err := row.Scan(
 		&ticket.ID,
 		&ticket.Departure,
 		&ticket.Destination,
@@ -251,14 +639,17 @@ func (r *TicketRepository) GetByID(id int64) (*models.Ticket, error) {
 		&ticket.ReportID,
 		&ticket.CreatedAt,
 		&ticket.UpdatedAt,
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to scan ticket: %w", err)
+		&ticket.TravelDate,
+		&ticket.IsAdvance,
+		&ticket.SeatNumber,
+		&ticket.FareCategory,
+		&ticket.FareRule,
+		&ticket.FareVersion,
+		&ticket.SaleID,
+		&ticket.ApprovalCode,
+		&ticket.DropSequence,
+	); err != nil {
+		return nil, err
 	}
-
-	if err := row.Err(); err != nil {
-		return nil, fmt.Errorf("failed to get ticket: %w", err)
-	}
-
 	return &ticket, nil
 }
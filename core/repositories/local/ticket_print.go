@@ -0,0 +1,132 @@
+package local
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"neon/core/database/embedded"
+	"neon/core/models"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+// TicketPrintRepository implements TicketPrintRepository for SQLite using goqu
+type TicketPrintRepository struct {
+	ctx context.Context
+	db  *embedded.SQLite
+}
+
+// NewTicketPrintRepository creates a new ticket print repository
+func NewTicketPrintRepository(ctx context.Context, db *embedded.SQLite) *TicketPrintRepository {
+	return &TicketPrintRepository{
+		ctx: ctx,
+		db:  db,
+	}
+}
+
+// AddTx records a reprint or void slip inside the caller's transaction
+func (r *TicketPrintRepository) AddTx(tx *sql.Tx, ticketPrint models.TicketPrint) (*models.TicketPrint, error) {
+	query := dialect.Insert(TableTicketPrints).Rows(ticketPrint)
+
+	sql, args, err := query.Prepared(true).ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	result, err := tx.Exec(sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add ticket print: %w", err)
+	}
+
+	generatedID, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get generated ID: %w", err)
+	}
+
+	ticketPrint.ID = generatedID
+
+	return &ticketPrint, nil
+}
+
+// UpdateStatusTx stores whether a recorded print reached the printer inside the caller's transaction
+func (r *TicketPrintRepository) UpdateStatusTx(tx *sql.Tx, id int64, status string) error {
+	update := dialect.Update(TableTicketPrints).
+		Set(goqu.Record{"status": status}).
+		Where(ColumnID.Eq(id))
+
+	sql, args, err := update.Prepared(true).ToSQL()
+	if err != nil {
+		return fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	if _, err := tx.Exec(sql, args...); err != nil {
+		return fmt.Errorf("failed to update ticket print: %w", err)
+	}
+
+	return nil
+}
+
+// CountByTicketIDTx counts the prints of a kind for a ticket that didn't fail inside the caller's
+// transaction. Pending prints are counted so two copies never share a number
+func (r *TicketPrintRepository) CountByTicketIDTx(tx *sql.Tx, ticketID int64, kind string) (int, error) {
+	query := dialect.Select(goqu.COUNT("*")).From(TableTicketPrints).Where(
+		ColumnTicketID.Eq(ticketID),
+		goqu.C("kind").Eq(kind),
+		goqu.C("status").Neq(models.TicketPrintFailed),
+	)
+
+	sql, args, err := query.Prepared(true).ToSQL()
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	var count int
+	if err := tx.QueryRow(sql, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count ticket prints: %w", err)
+	}
+
+	return count, nil
+}
+
+// CountByReportID counts the reprints and void slips printed for a report
+func (r *TicketPrintRepository) CountByReportID(reportID int64) (models.TicketPrintCounts, error) {
+	query := dialect.Select(
+		goqu.C("kind"),
+		goqu.COUNT("*"),
+	).From(TableTicketPrints).Where(
+		ColumnReportID.Eq(reportID),
+		goqu.C("status").Eq(models.TicketPrintPrinted),
+	).GroupBy(goqu.C("kind"))
+
+	sql, args, err := query.Prepared(true).ToSQL()
+	if err != nil {
+		return models.TicketPrintCounts{}, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	rows, err := r.db.GetDB().Query(sql, args...)
+	if err != nil {
+		return models.TicketPrintCounts{}, fmt.Errorf("failed to count ticket prints: %w", err)
+	}
+	defer rows.Close()
+
+	var counts models.TicketPrintCounts
+	for rows.Next() {
+		var kind string
+		var count int
+		if err := rows.Scan(&kind, &count); err != nil {
+			return models.TicketPrintCounts{}, fmt.Errorf("failed to scan ticket print count: %w", err)
+		}
+		switch kind {
+		case models.TicketPrintReprint:
+			counts.Reprints = count
+		case models.TicketPrintVoidSlip:
+			counts.VoidSlips = count
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return models.TicketPrintCounts{}, fmt.Errorf("failed to iterate ticket print counts: %w", err)
+	}
+
+	return counts, nil
+}
@@ -0,0 +1,119 @@
+package local
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"neon/core/database/embedded"
+	"neon/core/models"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+// TicketVoidRepository implements TicketVoidRepository for SQLite using goqu
+type TicketVoidRepository struct {
+	ctx context.Context
+	db  *embedded.SQLite
+}
+
+// NewTicketVoidRepository creates a new ticket void repository
+func NewTicketVoidRepository(ctx context.Context, db *embedded.SQLite) *TicketVoidRepository {
+	return &TicketVoidRepository{
+		ctx: ctx,
+		db:  db,
+	}
+}
+
+// AddTx adds a void record inside the caller's transaction and returns it with the generated ID
+func (r *TicketVoidRepository) AddTx(tx *sql.Tx, void models.TicketVoid) (*models.TicketVoid, error) {
+	query := dialect.Insert(TableTicketVoids).Rows(void)
+
+	sql, args, err := query.Prepared(true).ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	result, err := tx.Exec(sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add ticket void: %w", err)
+	}
+
+	generatedID, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get generated ID: %w", err)
+	}
+
+	void.ID = generatedID
+
+	return &void, nil
+}
+
+// GetByTicketID gets the void record of a ticket
+func (r *TicketVoidRepository) GetByTicketID(ticketID int64) (*models.TicketVoid, error) {
+	voids, err := r.find(ColumnTicketID.Eq(ticketID))
+	if err != nil {
+		return nil, err
+	}
+	if len(voids) == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	return &voids[0], nil
+}
+
+// GetByReportID gets the void records of a report
+func (r *TicketVoidRepository) GetByReportID(reportID int64) ([]models.TicketVoid, error) {
+	return r.find(ColumnReportID.Eq(reportID))
+}
+
+// GetByDateRange gets the void records created in the half-open range [from, to)
+func (r *TicketVoidRepository) GetByDateRange(from string, to string) ([]models.TicketVoid, error) {
+	return r.find(goqu.And(
+		goqu.C("created_at").Gte(from),
+		goqu.C("created_at").Lt(to),
+	))
+}
+
+func (r *TicketVoidRepository) find(where goqu.Expression) ([]models.TicketVoid, error) {
+	query := dialect.Select(
+		"id", "ticket_id", "report_id", "reason", "note", "fare",
+		"bucket", "voided_by", "approved_by", "created_at",
+	).From(TableTicketVoids).Where(where).Order(ColumnID.Asc())
+
+	sql, args, err := query.Prepared(true).ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	rows, err := r.db.GetDB().Query(sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query ticket voids: %w", err)
+	}
+	defer rows.Close()
+
+	var voids []models.TicketVoid
+	for rows.Next() {
+		var void models.TicketVoid
+		if err := rows.Scan(
+			&void.ID,
+			&void.TicketID,
+			&void.ReportID,
+			&void.Reason,
+			&void.Note,
+			&void.Fare,
+			&void.Bucket,
+			&void.VoidedBy,
+			&void.ApprovedBy,
+			&void.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan ticket void: %w", err)
+		}
+		voids = append(voids, void)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate ticket voids: %w", err)
+	}
+
+	return voids, nil
+}
@@ -0,0 +1,198 @@
+package local
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"neon/core/database/embedded"
+	"neon/core/models"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+// zReportColumns are the columns scanned by scanZReport, in order
+var zReportColumns = []interface{}{
+	"id", "number", "business_date", "version", "fingerprint", "printed_by", "printed_at",
+}
+
+// ZReportRepository implements ZReportRepository for SQLite using goqu
+type ZReportRepository struct {
+	ctx context.Context
+	db  *embedded.SQLite
+}
+
+// NewZReportRepository creates a new Z-report repository
+func NewZReportRepository(ctx context.Context, db *embedded.SQLite) *ZReportRepository {
+	return &ZReportRepository{
+		ctx: ctx,
+		db:  db,
+	}
+}
+
+// GetByDate gets the Z-report of a business day
+func (r *ZReportRepository) GetByDate(date string) (*models.ZReport, error) {
+	return r.getByDate(r.db.GetDB(), date)
+}
+
+// GetByDateTx gets the Z-report of a business day inside the caller's transaction
+func (r *ZReportRepository) GetByDateTx(tx *sql.Tx, date string) (*models.ZReport, error) {
+	return r.getByDate(tx, date)
+}
+
+// NextNumberTx gets the number the next Z-report takes inside the caller's transaction
+func (r *ZReportRepository) NextNumberTx(tx *sql.Tx) (int, error) {
+	query := dialect.Select(goqu.L("COALESCE(MAX(number), 0) + 1")).From(TableZReports)
+
+	sql, args, err := query.Prepared(true).ToSQL()
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	var number int
+	if err := tx.QueryRow(sql, args...).Scan(&number); err != nil {
+		return 0, fmt.Errorf("failed to get next z report number: %w", err)
+	}
+
+	return number, nil
+}
+
+// AddTx stores a Z-report inside the caller's transaction and returns it with the generated ID
+func (r *ZReportRepository) AddTx(tx *sql.Tx, zReport models.ZReport) (*models.ZReport, error) {
+	query := dialect.Insert(TableZReports).Rows(zReport)
+
+	sql, args, err := query.Prepared(true).ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	result, err := tx.Exec(sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add z report: %w", err)
+	}
+
+	generatedID, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get generated ID: %w", err)
+	}
+
+	zReport.ID = generatedID
+
+	return &zReport, nil
+}
+
+// UpdateTx stores a new version of a Z-report inside the caller's transaction. Its number and business date
+// never change.
+func (r *ZReportRepository) UpdateTx(tx *sql.Tx, zReport models.ZReport) error {
+	update := dialect.Update(TableZReports).
+		Set(goqu.Record{
+			"version":     zReport.Version,
+			"fingerprint": zReport.Fingerprint,
+			"printed_by":  zReport.PrintedBy,
+			"printed_at":  zReport.PrintedAt,
+		}).
+		Where(ColumnID.Eq(zReport.ID))
+
+	sql, args, err := update.Prepared(true).ToSQL()
+	if err != nil {
+		return fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	if _, err := tx.Exec(sql, args...); err != nil {
+		return fmt.Errorf("failed to update z report: %w", err)
+	}
+
+	return nil
+}
+
+// CountPrintsTx counts the prints of a Z-report inside the caller's transaction
+func (r *ZReportRepository) CountPrintsTx(tx *sql.Tx, zReportID int64) (int, error) {
+	query := dialect.Select(goqu.COUNT("*")).From(TableZReportPrints).Where(goqu.C("z_report_id").Eq(zReportID))
+
+	sql, args, err := query.Prepared(true).ToSQL()
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	var count int
+	if err := tx.QueryRow(sql, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count z report prints: %w", err)
+	}
+
+	return count, nil
+}
+
+// AddPrintTx records a print of a Z-report inside the caller's transaction
+func (r *ZReportRepository) AddPrintTx(tx *sql.Tx, zReportPrint models.ZReportPrint) (*models.ZReportPrint, error) {
+	query := dialect.Insert(TableZReportPrints).Rows(zReportPrint)
+
+	sql, args, err := query.Prepared(true).ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	result, err := tx.Exec(sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add z report print: %w", err)
+	}
+
+	generatedID, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get generated ID: %w", err)
+	}
+
+	zReportPrint.ID = generatedID
+
+	return &zReportPrint, nil
+}
+
+// DeletePrintTx removes the record of a Z-report print that didn't print inside the caller's transaction,
+// so its copy number is taken by the next print
+func (r *ZReportRepository) DeletePrintTx(tx *sql.Tx, id int64) error {
+	query := dialect.Delete(TableZReportPrints).Where(ColumnID.Eq(id))
+
+	sql, args, err := query.Prepared(true).ToSQL()
+	if err != nil {
+		return fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	if _, err := tx.Exec(sql, args...); err != nil {
+		return fmt.Errorf("failed to delete z report print: %w", err)
+	}
+
+	return nil
+}
+
+// getByDate gets the Z-report of a business day with either the database or a transaction
+func (r *ZReportRepository) getByDate(db interface {
+	QueryRow(query string, args ...any) *sql.Row
+}, date string) (*models.ZReport, error) {
+	query := dialect.Select(zReportColumns...).From(TableZReports).Where(goqu.C("business_date").Eq(date))
+
+	sql, args, err := query.Prepared(true).ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	zReport, err := scanZReport(db.QueryRow(sql, args...))
+	if err != nil {
+		return nil, err
+	}
+
+	return zReport, nil
+}
+
+func scanZReport(row interface{ Scan(dest ...any) error }) (*models.ZReport, error) {
+	var zReport models.ZReport
+	if err := row.Scan(
+		&zReport.ID,
+		&zReport.Number,
+		&zReport.BusinessDate,
+		&zReport.Version,
+		&zReport.Fingerprint,
+		&zReport.PrintedBy,
+		&zReport.PrintedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &zReport, nil
+}
@@ -0,0 +1,75 @@
+package remote
+
+import (
+	"context"
+	"fmt"
+	"neon/core/constants"
+	"neon/core/database/remote"
+	"neon/core/models"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// FareVersionRepository implements FareVersionRepository for remote
+type FareVersionRepository struct {
+	collection *mongo.Collection
+}
+
+// NewFareVersionRepository creates a new remote fare version repository
+func NewFareVersionRepository(db *remote.MongoDB) *FareVersionRepository {
+	return &FareVersionRepository{
+		collection: db.GetCollection(constants.FareVersionCollection),
+	}
+}
+
+// All returns all fare versions from MongoDB
+func (r *FareVersionRepository) All(ctx context.Context) ([]models.FareVersion, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list fare versions: %w", err)
+	}
+
+	defer cursor.Close(ctx)
+
+	var versions []models.FareVersion
+	for cursor.Next(ctx) {
+		var version models.FareVersion
+		if err := cursor.Decode(&version); err != nil {
+			return nil, fmt.Errorf("failed to decode fare version: %w", err)
+		}
+		versions = append(versions, version)
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("cursor error: %w", err)
+	}
+
+	return versions, nil
+}
+
+// Create creates a new fare version in MongoDB, numbered after the route's latest version
+func (r *FareVersionRepository) Create(ctx context.Context, version *models.FareVersion) error {
+	if version == nil {
+		return fmt.Errorf("fare version is nil")
+	}
+
+	var latest models.FareVersion
+	err := r.collection.FindOne(
+		ctx,
+		bson.M{"departure": version.Departure, "destination": version.Destination},
+		options.FindOne().SetSort(bson.D{{Key: "version", Value: -1}}),
+	).Decode(&latest)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return fmt.Errorf("failed to get latest fare version: %w", err)
+	}
+
+	version.ID = bson.NewObjectID()
+	version.Version = latest.Version + 1
+	if _, err := r.collection.InsertOne(ctx, version); err != nil {
+		return fmt.Errorf("failed to create fare version: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+// Package remote
+package remote
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+
+	"neon/core/constants"
+	"neon/core/database/remote"
+	"neon/core/models"
+)
+
+// GoldPassengerRepository implements GoldPassengerRepository for remote MongoDB
+type GoldPassengerRepository struct {
+	collection *mongo.Collection
+}
+
+// NewGoldPassengerRepository creates a new remote gold passenger repository
+func NewGoldPassengerRepository(db *remote.MongoDB) *GoldPassengerRepository {
+	return &GoldPassengerRepository{
+		collection: db.GetCollection(constants.GoldPassengerCollection),
+	}
+}
+
+// All returns all gold passengers from MongoDB
+func (r *GoldPassengerRepository) All(ctx context.Context) ([]models.GoldPassenger, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list gold passengers: %w", err)
+	}
+
+	defer cursor.Close(ctx)
+
+	var passengers []models.GoldPassenger
+	for cursor.Next(ctx) {
+		var passenger models.GoldPassenger
+		if err := cursor.Decode(&passenger); err != nil {
+			return nil, fmt.Errorf("failed to decode gold passenger: %w", err)
+		}
+		passengers = append(passengers, passenger)
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("cursor error: %w", err)
+	}
+
+	return passengers, nil
+}
@@ -37,9 +37,10 @@ INSERT INTO %s (
   local_id, username, timetable, partial_tickets, partial_cash, partial_cash_received,
   final_tickets, final_cash, final_cash_received, status, total_gold, total_gold_cash,
   total_null, total_null_cash, total_regular, total_regular_cash,
-  partial_closed_at, closed_at, created_at, partial_closed_by, closed_by, remote_saved_at
+  partial_closed_at, closed_at, created_at, partial_closed_by, closed_by,
+  opening_float, float_confirmed_by, remote_saved_at
 ) VALUES (
-  ?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?
+  ?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?
 )
 ON DUPLICATE KEY UPDATE
   username=VALUES(username),
@@ -62,6 +63,8 @@ ON DUPLICATE KEY UPDATE
   created_at=VALUES(created_at),
   partial_closed_by=VALUES(partial_closed_by),
   closed_by=VALUES(closed_by),
+  opening_float=VALUES(opening_float),
+  float_confirmed_by=VALUES(float_confirmed_by),
   remote_saved_at=VALUES(remote_saved_at)
 `, tbl)
 
@@ -92,6 +95,8 @@ ON DUPLICATE KEY UPDATE
 		strPtr(report.CreatedAt),
 		strPtr(report.PartialClosedBy),
 		strPtr(report.ClosedBy),
+		report.OpeningFloat,
+		strPtr(report.FloatConfirmedBy),
 		now,
 	}
 
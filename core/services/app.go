@@ -34,11 +34,33 @@ func (a *App) Startup() {
 	syncService := NewSyncService(cloverdb)
 	authService := NewAuthService(cloverdb)
 	userService := NewUserService(cloverdb, syncService)
-	printService := NewPrintService()
-	ticketService := NewTicketService(sqlitedb, printService)
+	printService := NewPrintService(sqlitedb)
+	if emulatorConfig := config.LoadPOSConfig().PrinterEmulator; emulatorConfig.Enabled {
+		if err := printService.startEmulator(emulatorConfig); err != nil {
+			zap.L().Error("Error starting printer emulator", zap.Error(err))
+		}
+	}
+	outboxService := NewOutboxService(sqlitedb)
+	einvoiceService := NewEInvoiceService(sqlitedb)
+	if einvoiceConfig := config.LoadPOSConfig().EInvoice; einvoiceConfig.Enabled {
+		if err := einvoiceService.start(einvoiceConfig); err != nil {
+			zap.L().Error("Error starting e-invoicing", zap.Error(err))
+		}
+		einvoiceService.startOutbox(outboxService)
+	}
+	ticketService := NewTicketService(sqlitedb, cloverdb, printService, authService, einvoiceService)
+	if terminalConfig := config.LoadPOSConfig().PaymentTerminal; terminalConfig.Driver != "" {
+		if err := ticketService.startPaymentTerminal(terminalConfig); err != nil {
+			zap.L().Error("Error starting payment terminal", zap.Error(err))
+		} else {
+			ticketService.startCardOutbox(outboxService)
+		}
+	}
 	routeService := NewRouteService(cloverdb, syncService)
 	counterService := NewCounterService(cloverdb)
-	reportService := NewReportService(sqlitedb)
+	reportService := NewReportService(sqlitedb, authService)
+	boardingService := NewBoardingService(sqlitedb)
+	fareService := NewFareService(cloverdb, syncService)
 
 	// repository := local.NewCountRepository(cloverdb)
 	// repository.Clear()
@@ -57,11 +79,17 @@ func (a *App) Startup() {
 			syncService.startup(ctx)
 			authService.startup(ctx)
 			userService.startup(ctx)
+			printService.startup(ctx)
 			ticketService.startup(ctx)
 			routeService.startup(ctx)
 			reportService.startup(ctx)
+			boardingService.startup(ctx)
+			fareService.startup(ctx)
+			einvoiceService.startup(ctx)
+			outboxService.startup(ctx)
 		},
 		OnShutdown: func(ctx context.Context) {
+			printService.shutdown()
 			shutdown()
 		},
 		Bind: []any{
@@ -73,6 +101,10 @@ func (a *App) Startup() {
 			counterService,
 			reportService,
 			printService,
+			boardingService,
+			fareService,
+			einvoiceService,
+			outboxService,
 		},
 	})
 
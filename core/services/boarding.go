@@ -0,0 +1,255 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"neon/core/config"
+	"neon/core/constants"
+	"neon/core/database/embedded"
+	"neon/core/helpers"
+	"neon/core/models"
+	"neon/core/repositories/local"
+	"neon/core/ticketcode"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// BoardingService is a service for validating tickets on board
+type BoardingService struct {
+	ctx     context.Context
+	localDB *embedded.SQLite
+}
+
+// NewBoardingService creates a new boarding service
+func NewBoardingService(localDB *embedded.SQLite) *BoardingService {
+	return &BoardingService{localDB: localDB}
+}
+
+// startup starts the boarding service
+func (b *BoardingService) startup(ctx context.Context) {
+	b.ctx = ctx
+}
+
+// BoardTicket marks a ticket as boarded on the request's departure. The code can be the ticket ID typed
+// by the inspector or a scanned QR payload or barcode. A ticket boards exactly once; tickets for another
+// route, date or departure time are rejected.
+func (b *BoardingService) BoardTicket(request models.BoardingRequest) (*models.TicketBoarding, error) {
+	ticket, err := findTicketByCode(b.ctx, b.localDB, request.Code, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if ticket.IsNull {
+		return nil, helpers.ErrTicketAlreadyNullified
+	}
+
+	if (request.Departure != "" && ticket.Departure != request.Departure) ||
+		(request.Destination != "" && ticket.Destination != request.Destination) {
+		return nil, helpers.ErrTicketWrongRoute
+	}
+
+	if !isTicketForDeparture(ticket, request.Time) {
+		return nil, helpers.ErrTicketWrongDeparture
+	}
+
+	boarding, boarded, err := boardTicket(b.ctx, b.localDB, ticket.ID, request.Device)
+	if err != nil {
+		return nil, err
+	}
+	if boarded {
+		return nil, helpers.ErrTicketAlreadyBoarded
+	}
+
+	return boarding, nil
+}
+
+// GetManifest returns the tickets sold and the passengers boarded per departure time of a route on a
+// date (YYYY-MM-DD)
+func (b *BoardingService) GetManifest(departure string, destination string, date string) (*models.Manifest, error) {
+	day, err := time.ParseInLocation(constants.DateLayout, date, time.Local)
+	if err != nil {
+		return nil, helpers.ErrInvalidRequest
+	}
+
+	// Tickets without a travel date travel on their sale date. created_at is stored in UTC by the
+	// frontend, so the query is widened by a day on each side and they are matched against the local
+	// sale date below.
+	repository := local.NewTicketBoardingRepository(b.ctx, b.localDB)
+	entries, err := repository.GetManifestEntries(
+		departure,
+		destination,
+		date,
+		day.AddDate(0, 0, -1).Format(constants.DateLayout),
+		day.AddDate(0, 0, 2).Format(constants.DateLayout),
+	)
+	if err != nil {
+		zap.L().Error("failed to get manifest", zap.Error(err))
+		return nil, err
+	}
+
+	manifest := &models.Manifest{
+		Departure:   departure,
+		Destination: destination,
+		Date:        date,
+		Departures:  []models.DepartureManifest{},
+	}
+	byTime := map[string]*models.DepartureManifest{}
+	for _, entry := range entries {
+		if entry.TravelDate == "" {
+			createdAt, err := time.Parse(time.RFC3339, entry.CreatedAt)
+			if err != nil || createdAt.Local().Format(constants.DateLayout) != date {
+				continue
+			}
+		}
+
+		departureManifest, ok := byTime[entry.Time]
+		if !ok {
+			departureManifest = &models.DepartureManifest{Time: entry.Time}
+			byTime[entry.Time] = departureManifest
+		}
+
+		departureManifest.Sold++
+		manifest.Sold++
+		if entry.IsGold {
+			departureManifest.Gold++
+		}
+		if entry.BoardedAt != nil {
+			departureManifest.Boarded++
+			manifest.Boarded++
+		} else {
+			departureManifest.NotBoarded++
+		}
+	}
+
+	for _, departureManifest := range byTime {
+		manifest.Departures = append(manifest.Departures, *departureManifest)
+	}
+	sort.Slice(manifest.Departures, func(i, j int) bool {
+		return manifest.Departures[i].Time < manifest.Departures[j].Time
+	})
+
+	return manifest, nil
+}
+
+// findTicketByCode loads the ticket of a scanned QR payload or barcode short code, verifying its signature.
+// A plain ticket ID is accepted only when allowID is set.
+func findTicketByCode(ctx context.Context, localDB *embedded.SQLite, code string, allowID bool) (*models.Ticket, error) {
+	code = strings.TrimSpace(code)
+
+	var ticketID int64
+	var claims *ticketcode.Claims
+	var key []byte
+	plainID, err := strconv.ParseInt(code, 10, 64)
+	switch {
+	case err == nil:
+		if !allowID {
+			return nil, helpers.ErrInvalidTicketCode
+		}
+		ticketID = plainID
+	case ticketcode.IsPayload(code):
+		if key, err = ticketSigningKey(); err != nil {
+			return nil, err
+		}
+		decoded, err := ticketcode.Decode(code, key)
+		if err != nil {
+			return nil, helpers.ErrInvalidTicketCode
+		}
+		claims = &decoded
+		ticketID = decoded.TicketID
+	default:
+		if key, err = ticketSigningKey(); err != nil {
+			return nil, err
+		}
+		id, err := ticketcode.ParseShortCode(code)
+		if err != nil {
+			return nil, helpers.ErrInvalidTicketCode
+		}
+		ticketID = id
+	}
+
+	repository := local.NewTicketRepository(ctx, localDB)
+	ticket, err := repository.GetByID(ticketID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, helpers.ErrRowNotFound
+		}
+		zap.L().Error("failed to get ticket", zap.Error(err))
+		return nil, err
+	}
+
+	if key == nil {
+		return ticket, nil
+	}
+
+	stored := ticketcode.ClaimsFromTicket(*ticket)
+	if claims != nil {
+		if *claims != stored {
+			return nil, helpers.ErrInvalidTicketCode
+		}
+	} else if err := ticketcode.VerifyShortCode(code, stored, key); err != nil {
+		return nil, helpers.ErrInvalidTicketCode
+	}
+
+	return ticket, nil
+}
+
+// ticketSigningKey returns the installation's ticket signing key
+func ticketSigningKey() ([]byte, error) {
+	key := config.LoadPOSConfig().TicketSigningKey
+	if key == "" {
+		return nil, helpers.ErrTicketSigningKeyMissing
+	}
+	return []byte(key), nil
+}
+
+// isTicketForDeparture reports whether a ticket travels today on departureTime (HH:MM). An empty
+// departureTime only checks the date.
+func isTicketForDeparture(ticket *models.Ticket, departureTime string) bool {
+	if ticketcode.ClaimsFromTicket(*ticket).Date != time.Now().Format("20060102") {
+		return false
+	}
+	return departureTime == "" || ticket.Time == departureTime
+}
+
+// boardTicket records a ticket's boarding. When the ticket already boarded, the existing boarding is
+// returned with boarded set.
+func boardTicket(ctx context.Context, localDB *embedded.SQLite, ticketID int64, device string) (*models.TicketBoarding, bool, error) {
+	tx, err := localDB.BeginTx(ctx, nil)
+	if err != nil {
+		zap.L().Error("failed to begin boarding transaction", zap.Error(err))
+		return nil, false, err
+	}
+	defer tx.Rollback()
+
+	repository := local.NewTicketBoardingRepository(ctx, localDB)
+	boarding, err := repository.GetByTicketIDTx(tx, ticketID)
+	if err == nil {
+		return boarding, true, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		zap.L().Error("failed to get ticket boarding", zap.Error(err))
+		return nil, false, err
+	}
+
+	boarding, err = repository.AddTx(tx, models.TicketBoarding{
+		TicketID:  ticketID,
+		Device:    device,
+		BoardedAt: time.Now().Format(time.RFC3339),
+	})
+	if err != nil {
+		zap.L().Error("failed to record ticket boarding", zap.Error(err))
+		return nil, false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		zap.L().Error("failed to commit ticket boarding", zap.Error(err))
+		return nil, false, err
+	}
+
+	return boarding, false, nil
+}
@@ -0,0 +1,272 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"neon/core/config"
+	"neon/core/constants"
+	"neon/core/database/embedded"
+	"neon/core/helpers"
+	"neon/core/helpers/enums"
+	"neon/core/models"
+	"neon/core/repositories/local"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DailySummary consolidates a business day (YYYY-MM-DD) across all cashiers: every report started that day
+// and its tickets, totalled per route, stop, departure time, cashier, kind and payment method, with the
+// day's Z-report once printed. When the installation closes blind, the reports not settled yet are only
+// counted as open, so their expected cash isn't shown before the drawer is counted.
+func (r *ReportService) DailySummary(date string) (*models.DailySummary, error) {
+	summary, err := dailySummary(r.ctx, r.localDB, date, config.LoadPOSConfig().Cash.BlindClose)
+	if err != nil {
+		return nil, err
+	}
+
+	zReport, err := local.NewZReportRepository(r.ctx, r.localDB).GetByDate(date)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		zap.L().Error("failed to get z report", zap.Error(err))
+		return nil, err
+	}
+	summary.ZReport = zReport
+
+	return summary, nil
+}
+
+// dailySummary totals a business day's reports and tickets, without its Z-report. settledOnly leaves the
+// reports not settled yet out of the figures but the count of open reports.
+func dailySummary(ctx context.Context, localDB *embedded.SQLite, date string, settledOnly bool) (*models.DailySummary, error) {
+	if _, err := time.Parse(constants.DateLayout, date); err != nil {
+		return nil, helpers.ErrInvalidBusinessDate
+	}
+
+	reports, err := local.NewReportRepository(ctx, localDB).GetByBusinessDate(date)
+	if err != nil {
+		zap.L().Error("failed to get reports of the day", zap.Error(err))
+		return nil, err
+	}
+
+	if err := loadReportTotals(ctx, localDB, reports...); err != nil {
+		return nil, err
+	}
+
+	summary := &models.DailySummary{
+		Date:       date,
+		Reports:    len(reports),
+		Routes:     []models.DailyRouteTotal{},
+		Stops:      []models.DailyStopTotal{},
+		Departures: []models.DailyDepartureTotal{},
+		Cashiers:   []models.DailyCashierTotal{},
+		Payments:   []models.DailyPaymentTotal{},
+	}
+
+	cashiers := map[string]*models.DailyCashierTotal{}
+	cashier := func(username string) *models.DailyCashierTotal {
+		if cashiers[username] == nil {
+			cashiers[username] = &models.DailyCashierTotal{Username: username}
+		}
+		return cashiers[username]
+	}
+
+	payments := map[enums.PaymentMethod]*models.DailyPaymentTotal{}
+	for _, method := range enums.AllPaymentMethods {
+		payments[method.Value] = &models.DailyPaymentTotal{Method: method.Value}
+	}
+
+	ids := make([]int64, 0, len(reports))
+	for _, report := range reports {
+		open := report.Status || report.ClosedAt == nil
+		if open {
+			summary.OpenReports++
+		}
+		if settledOnly && !reportSettled(report) {
+			continue
+		}
+		ids = append(ids, report.ID)
+
+		summary.Sales += report.TotalSales
+
+		if !open {
+			expected := report.PartialDrawer + report.FinalDrawer
+			received := report.PartialCashReceived + report.FinalCashReceived - report.OpeningFloat
+			summary.OpeningFloat += report.OpeningFloat
+			summary.Expected += expected
+			summary.Received += received
+			summary.Difference += received - expected
+		}
+
+		for _, total := range report.PaymentTotals {
+			if payment, ok := payments[total.Method]; ok {
+				payment.Payments += total.Payments
+				payment.Amount += total.PartialAmount + total.FinalAmount
+			}
+		}
+
+		shifts := map[string]bool{}
+		for username, variance := range shiftVariances(report) {
+			cashier(username).Variance += variance
+		}
+		for _, segment := range report.Segments {
+			if !shifts[segment.Username] {
+				shifts[segment.Username] = true
+				cashier(segment.Username).Reports++
+			}
+		}
+	}
+
+	ticketTotals, err := local.NewTicketRepository(ctx, localDB).GetTotalsByReportIDs(ids)
+	if err != nil {
+		zap.L().Error("failed to get ticket totals of the day", zap.Error(err))
+		return nil, err
+	}
+
+	routes := map[[2]string]*models.DailyRouteTotal{}
+	stops := map[[3]string]*models.DailyStopTotal{}
+	departures := map[[3]string]*models.DailyDepartureTotal{}
+	for _, total := range ticketTotals {
+		if total.IsNull {
+			summary.Null += total.Tickets
+			summary.NullCash += total.Cash
+			continue
+		}
+
+		summary.Tickets += total.Tickets
+		summary.Cash += total.Cash
+		if total.IsGold {
+			summary.Gold += total.Tickets
+			summary.GoldCash += total.Cash
+		} else {
+			summary.Regular += total.Tickets
+			summary.RegularCash += total.Cash
+		}
+
+		routeKey := [2]string{total.Departure, total.Destination}
+		if routes[routeKey] == nil {
+			routes[routeKey] = &models.DailyRouteTotal{Departure: total.Departure, Destination: total.Destination}
+		}
+		routes[routeKey].Tickets += total.Tickets
+		routes[routeKey].Cash += total.Cash
+
+		stopKey := [3]string{total.Departure, total.Destination, total.Stop}
+		if stops[stopKey] == nil {
+			stops[stopKey] = &models.DailyStopTotal{Departure: total.Departure, Destination: total.Destination, Stop: total.Stop}
+		}
+		stops[stopKey].Tickets += total.Tickets
+		stops[stopKey].Cash += total.Cash
+
+		departureKey := [3]string{total.Departure, total.Destination, total.Time}
+		if departures[departureKey] == nil {
+			departures[departureKey] = &models.DailyDepartureTotal{Departure: total.Departure, Destination: total.Destination, Time: total.Time}
+		}
+		departures[departureKey].Tickets += total.Tickets
+		departures[departureKey].Cash += total.Cash
+
+		seller := cashier(total.Username)
+		seller.Tickets += total.Tickets
+		seller.Cash += total.Cash
+	}
+
+	for _, route := range routes {
+		summary.Routes = append(summary.Routes, *route)
+	}
+	sort.Slice(summary.Routes, func(i, j int) bool {
+		a, b := summary.Routes[i], summary.Routes[j]
+		if a.Departure != b.Departure {
+			return a.Departure < b.Departure
+		}
+		return a.Destination < b.Destination
+	})
+
+	for _, stop := range stops {
+		summary.Stops = append(summary.Stops, *stop)
+	}
+	sort.Slice(summary.Stops, func(i, j int) bool {
+		a, b := summary.Stops[i], summary.Stops[j]
+		if a.Departure != b.Departure {
+			return a.Departure < b.Departure
+		}
+		if a.Destination != b.Destination {
+			return a.Destination < b.Destination
+		}
+		return a.Stop < b.Stop
+	})
+
+	for _, departure := range departures {
+		summary.Departures = append(summary.Departures, *departure)
+	}
+	sort.Slice(summary.Departures, func(i, j int) bool {
+		a, b := summary.Departures[i], summary.Departures[j]
+		if a.Departure != b.Departure {
+			return a.Departure < b.Departure
+		}
+		if a.Destination != b.Destination {
+			return a.Destination < b.Destination
+		}
+		return a.Time < b.Time
+	})
+
+	for _, total := range cashiers {
+		summary.Cashiers = append(summary.Cashiers, *total)
+	}
+	sort.Slice(summary.Cashiers, func(i, j int) bool {
+		return summary.Cashiers[i].Username < summary.Cashiers[j].Username
+	})
+
+	for _, method := range enums.AllPaymentMethods {
+		summary.Payments = append(summary.Payments, *payments[method.Value])
+	}
+
+	return summary, nil
+}
+
+// shiftVariances adds up the cash variance of a report's shifts per cashier. The drawer's variance so far
+// is the drops' variances plus the drawer's difference at a handover count, or the closes' variances at the
+// total close; each shift owns how much it moved. The last shift of an open report is left out.
+func shiftVariances(report *models.Report) map[string]int {
+	variances := map[string]int{}
+	previous := 0
+	for _, segment := range report.Segments {
+		var current int
+		switch {
+		case segment.Handover != nil:
+			current = segment.Handover.Cash - segment.Handover.Expected
+			for _, drop := range report.Drops {
+				if drop.DroppedAt <= segment.Handover.CountedAt {
+					current += drop.CashReceived - drop.Drawer
+				}
+			}
+		case report.FinalVariance != nil:
+			current = *report.FinalVariance
+			if report.PartialVariance != nil {
+				current += *report.PartialVariance
+			}
+		default:
+			continue
+		}
+		variances[segment.Username] += current - previous
+		previous = current
+	}
+	return variances
+}
+
+// dailySummaryFingerprint digests a day's figures, to tell whether they changed since its Z-report was
+// last printed
+func dailySummaryFingerprint(summary *models.DailySummary) (string, error) {
+	figures := *summary
+	figures.ZReport = nil
+
+	bytes, err := json.Marshal(figures)
+	if err != nil {
+		return "", err
+	}
+
+	digest := sha256.Sum256(bytes)
+	return hex.EncodeToString(digest[:]), nil
+}
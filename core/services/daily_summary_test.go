@@ -0,0 +1,107 @@
+package services
+
+import (
+	"neon/core/models"
+	"reflect"
+	"testing"
+)
+
+func TestShiftVariances(t *testing.T) {
+	variance := func(v int) *int { return &v }
+	handover := func(from string, expected int, cash int, countedAt string) models.ReportSegment {
+		return models.ReportSegment{
+			Username: from,
+			Handover: &models.ReportHandover{FromUsername: from, Expected: expected, Cash: cash, CountedAt: countedAt},
+		}
+	}
+	drop := func(droppedAt string, drawer int, received int) models.ReportDrop {
+		return models.ReportDrop{DroppedAt: droppedAt, Drawer: drawer, CashReceived: received}
+	}
+
+	tests := []struct {
+		name   string
+		report models.Report
+		want   map[string]int
+	}{
+		{
+			name:   "open report",
+			report: models.Report{Segments: []models.ReportSegment{{Username: "ana"}}},
+			want:   map[string]int{},
+		},
+		{
+			name: "single shift closed",
+			report: models.Report{
+				Segments:      []models.ReportSegment{{Username: "ana"}},
+				FinalVariance: variance(-100),
+			},
+			want: map[string]int{"ana": -100},
+		},
+		{
+			name: "single shift with drops",
+			report: models.Report{
+				Segments:        []models.ReportSegment{{Username: "ana"}},
+				Drops:           []models.ReportDrop{drop("2026-03-09T10:00:00Z", 10000, 10050)},
+				PartialVariance: variance(50),
+				FinalVariance:   variance(-100),
+			},
+			want: map[string]int{"ana": -50},
+		},
+		{
+			name: "handover then close",
+			report: models.Report{
+				Segments: []models.ReportSegment{
+					handover("ana", 10000, 9900, "2026-03-09T12:00:00Z"),
+					{Username: "beto"},
+				},
+				FinalVariance: variance(-150),
+			},
+			want: map[string]int{"ana": -100, "beto": -50},
+		},
+		{
+			name: "drops before and after the handover",
+			report: models.Report{
+				Segments: []models.ReportSegment{
+					handover("ana", 10000, 9900, "2026-03-09T12:00:00Z"),
+					{Username: "beto"},
+				},
+				Drops: []models.ReportDrop{
+					drop("2026-03-09T10:00:00Z", 5000, 5020),
+					drop("2026-03-09T15:00:00Z", 5000, 5030),
+				},
+				PartialVariance: variance(50),
+				FinalVariance:   variance(-110),
+			},
+			want: map[string]int{"ana": -80, "beto": 20},
+		},
+		{
+			name: "open after a handover",
+			report: models.Report{
+				Segments: []models.ReportSegment{
+					handover("ana", 10000, 9900, "2026-03-09T12:00:00Z"),
+					{Username: "beto"},
+				},
+			},
+			want: map[string]int{"ana": -100},
+		},
+		{
+			name: "cashier back for a later shift",
+			report: models.Report{
+				Segments: []models.ReportSegment{
+					handover("ana", 10000, 9900, "2026-03-09T10:00:00Z"),
+					handover("beto", 20000, 19930, "2026-03-09T14:00:00Z"),
+					{Username: "ana"},
+				},
+				FinalVariance: variance(-90),
+			},
+			want: map[string]int{"ana": -120, "beto": 30},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := shiftVariances(&tt.report); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("shiftVariances() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"neon/core/config"
+	"neon/core/helpers"
+	"neon/core/helpers/enums"
+	"neon/core/models"
+	"neon/core/repositories/local"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// PartialCloseReport records an interim cash drop (retiro): the cash taken out of the drawer while the
+// report stays open. A report takes any number of drops. Each is reconciled against the cash payments taken
+// since the previous one; card and SINPE Móvil payments are not in the drawer.
+func (r *ReportService) PartialCloseReport(
+	reportID int64,
+	cash int,
+	closedByUsername *string,
+) (*models.Report, error) {
+	return r.dropCash(reportID, cash, nil, closedByUsername)
+}
+
+// PartialCloseReportWithCount records a drop with the bills and coins counted. The count is kept for the
+// audit and the cash received is its total.
+func (r *ReportService) PartialCloseReportWithCount(
+	reportID int64,
+	counts []models.CashCount,
+	closedByUsername *string,
+) (*models.Report, error) {
+	if counts == nil {
+		counts = []models.CashCount{}
+	}
+	return r.dropCash(reportID, 0, counts, closedByUsername)
+}
+
+// dropCash records a drop. The tickets, cash and payments of the period open since the last drop move to
+// the report's dropped totals and its tickets are attributed to the drop. When the installation closes
+// blind, a variance above the recount threshold holds the report until an admin recounts the drop.
+func (r *ReportService) dropCash(
+	reportID int64,
+	cash int,
+	counts []models.CashCount,
+	droppedByUsername *string,
+) (*models.Report, error) {
+	now := time.Now().Format(time.RFC3339)
+
+	droppedBy := ""
+	if droppedByUsername != nil {
+		droppedBy = *droppedByUsername
+	}
+
+	if counts != nil {
+		var err error
+		if counts, cash, err = cashCount(reportID, enums.CountPartial, counts, droppedBy, now); err != nil {
+			return nil, err
+		}
+	}
+	if cash < 0 {
+		return nil, helpers.ErrInvalidDrop
+	}
+
+	// The drawer can't be dropped from while it waits for the incoming cashier of a handover
+	if _, err := reportCashier(r.ctx, r.localDB, reportID); err != nil {
+		return nil, err
+	}
+
+	repository := local.NewReportRepository(r.ctx, r.localDB)
+
+	report, err := repository.GetByID(reportID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, helpers.ErrRowNotFound
+		}
+		zap.L().Error("failed to get report", zap.Error(err))
+		return nil, err
+	}
+	if !report.Status {
+		return nil, helpers.ErrInvalidDrop
+	}
+
+	cashConfig := config.LoadPOSConfig().Cash
+	if cashConfig.BlindClose && report.PendingRecount != "" {
+		return nil, helpers.ErrRecountRequired
+	}
+
+	tx, err := r.localDB.BeginTx(r.ctx, nil)
+	if err != nil {
+		zap.L().Error("failed to begin report drop transaction", zap.Error(err))
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	drop, err := local.NewReportDropRepository(r.ctx, r.localDB).AddTx(tx, models.ReportDrop{
+		ReportID:     reportID,
+		DroppedAt:    now,
+		DroppedBy:    droppedBy,
+		CashReceived: cash,
+		Count:        counts,
+	})
+	if err != nil {
+		zap.L().Error("failed to add report drop", zap.Error(err))
+		return nil, err
+	}
+
+	var pendingRecount enums.CashCountStage
+	if cashConfig.BlindClose {
+		if variance := cash - drop.Drawer; variance > cashConfig.RecountThreshold || -variance > cashConfig.RecountThreshold {
+			pendingRecount = enums.CountPartial
+		}
+	}
+
+	if err := repository.DropTx(tx, *drop, pendingRecount); err != nil {
+		zap.L().Error("failed to record report drop", zap.Error(err))
+		return nil, err
+	}
+
+	if err := local.NewReportPaymentTotalRepository(r.ctx, r.localDB).DropTx(tx, reportID); err != nil {
+		zap.L().Error("failed to move report payment totals to drop", zap.Error(err))
+		return nil, err
+	}
+
+	if err := local.NewTicketRepository(r.ctx, r.localDB).AssignDropTx(tx, reportID, drop.Sequence); err != nil {
+		zap.L().Error("failed to assign tickets to drop", zap.Error(err))
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		zap.L().Error("failed to commit report drop", zap.Error(err))
+		return nil, err
+	}
+
+	report, err = r.getReport(reportID)
+	if err != nil {
+		return nil, err
+	}
+
+	r.trySyncAfterClose(report)
+
+	return blindReport(report), nil
+}
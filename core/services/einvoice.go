@@ -0,0 +1,553 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"neon/core/config"
+	"neon/core/database/embedded"
+	"neon/core/einvoice"
+	"neon/core/helpers"
+	"neon/core/helpers/enums"
+	"neon/core/models"
+	"neon/core/repositories/local"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// EInvoiceService issues the Hacienda electronic invoices of the sales and submits them. Each sale gets a
+// Tiquete Electrónico, or a Factura Electrónica for an identified receiver, signed in the sale's
+// transaction and queued in the outbox, which submits it and polls Hacienda for its answer. A void of
+// invoiced tickets gets a Nota de Crédito the same way.
+type EInvoiceService struct {
+	ctx     context.Context
+	localDB *embedded.SQLite
+	cfg     config.EInvoiceConfig
+	// outboxService submits the documents; while it can't reach Hacienda documents are issued without
+	// internet
+	outboxService *OutboxService
+	// signer is nil until the signing key is loaded; sales are refused meanwhile
+	signer    *einvoice.Signer
+	submitter einvoice.Submitter
+	mock      *einvoice.MockRecepcion
+}
+
+// NewEInvoiceService creates a new e-invoice service
+func NewEInvoiceService(localDB *embedded.SQLite) *EInvoiceService {
+	return &EInvoiceService{localDB: localDB}
+}
+
+// startup starts the e-invoice service
+func (e *EInvoiceService) startup(ctx context.Context) {
+	e.ctx = ctx
+}
+
+// start turns e-invoicing on: it checks the emitter and loads the signing key and the recepción API. When
+// it fails, e-invoicing stays on and sales are refused with ErrEInvoiceUnavailable rather than sold without
+// their document.
+func (e *EInvoiceService) start(cfg config.EInvoiceConfig) error {
+	e.cfg = cfg
+
+	if strings.TrimSpace(cfg.Emitter.Name) == "" || cfg.ActivityCode == "" || cfg.CABYS == "" {
+		return fmt.Errorf("e-invoice emitter name, activity code and CABYS are required")
+	}
+	if err := einvoice.ValidateIdentification(cfg.Emitter.IDType, cfg.Emitter.IDNumber); err != nil {
+		return fmt.Errorf("e-invoice emitter: %w", err)
+	}
+
+	path, err := cfg.CertificatePath()
+	if err != nil {
+		return err
+	}
+	signer, err := einvoice.LoadSigner(path, cfg.CertificatePIN)
+	if err != nil {
+		return err
+	}
+
+	switch cfg.API {
+	case config.EInvoiceAPIMock:
+		e.mock = einvoice.NewMockRecepcion()
+		e.submitter = e.mock
+	case config.EInvoiceAPIHacienda:
+		recepcion, err := einvoice.NewRecepcion(cfg.Environment, cfg.Username, cfg.Password)
+		if err != nil {
+			return err
+		}
+		e.submitter = recepcion
+	default:
+		return fmt.Errorf("unknown e-invoice api %q", cfg.API)
+	}
+	e.signer = signer
+
+	zap.L().Info("e-invoicing started",
+		zap.String("api", cfg.API),
+		zap.String("certificate", signer.Certificate().Subject.String()),
+	)
+	return nil
+}
+
+// startOutbox makes outbox submit the documents
+func (e *EInvoiceService) startOutbox(outbox *OutboxService) {
+	e.outboxService = outbox
+	outbox.register(enums.OutboxEInvoice, e)
+}
+
+// enabled reports whether the sales get an electronic invoice
+func (e *EInvoiceService) enabled() bool {
+	return e != nil && e.cfg.Enabled
+}
+
+// checkReceiver validates the receiver of a Factura Electrónica before the sale is charged
+func (e *EInvoiceService) checkReceiver(receiver *models.InvoiceReceiver) error {
+	if receiver == nil {
+		return nil
+	}
+	if !e.enabled() {
+		return helpers.ErrEInvoiceDisabled
+	}
+	if strings.TrimSpace(receiver.Name) == "" {
+		return helpers.ErrInvalidInvoiceReceiver
+	}
+	if err := einvoice.ValidateIdentification(receiver.IDType, strings.TrimSpace(receiver.IDNumber)); err != nil {
+		return helpers.ErrInvalidInvoiceReceiver
+	}
+	return nil
+}
+
+// checkContingency validates the handwritten receipt a sale replaces before the sale is charged
+func (e *EInvoiceService) checkContingency(contingency *models.ContingencyReceipt) error {
+	if contingency == nil {
+		return nil
+	}
+	if !e.enabled() {
+		return helpers.ErrEInvoiceDisabled
+	}
+	if strings.TrimSpace(contingency.Number) == "" {
+		return helpers.ErrInvalidRequest
+	}
+	if _, err := time.Parse(time.RFC3339, contingency.IssuedAt); err != nil {
+		return helpers.ErrInvalidRequest
+	}
+	return nil
+}
+
+// issueTx signs the electronic invoice of a sale inside the sale's transaction and queues it for
+// submission. Sales without an amount to invoice, e.g. only gold tickets, get no document. A sale replacing
+// a contingency receipt is issued in contingency, referencing it.
+func (e *EInvoiceService) issueTx(
+	tx *sql.Tx,
+	sale *models.Sale,
+	receiver *models.InvoiceReceiver,
+	contingency *models.ContingencyReceipt,
+) (*models.ElectronicDocument, error) {
+	if e.signer == nil {
+		return nil, helpers.ErrEInvoiceUnavailable
+	}
+
+	lines := invoiceLines(sale.Tickets)
+	if len(lines) == 0 {
+		return nil, nil
+	}
+
+	issuedAt := time.Now()
+	if createdAt, err := time.Parse(time.RFC3339, sale.CreatedAt); err == nil {
+		issuedAt = createdAt
+	}
+
+	doc := e.document(einvoice.Tiquete, issuedAt, lines)
+	doc.Payments = invoicePayments(sale.Payments)
+	situation := e.situation()
+	if contingency != nil {
+		writtenAt, _ := time.Parse(time.RFC3339, contingency.IssuedAt)
+		situation = einvoice.SituationContingency
+		doc.References = []einvoice.Reference{{
+			Type:     einvoice.Contingencia,
+			Number:   strings.TrimSpace(contingency.Number),
+			IssuedAt: writtenAt,
+			Code:     einvoice.ReferenceContingency,
+			Reason:   "Sustituye comprobante provisional por contingencia",
+		}}
+	}
+
+	document := models.ElectronicDocument{
+		SaleID:   sale.ID,
+		ReportID: sale.ReportID,
+	}
+	if receiver != nil {
+		doc.Type = einvoice.Factura
+		doc.Receiver = &einvoice.Party{
+			Name:     strings.TrimSpace(receiver.Name),
+			IDType:   receiver.IDType,
+			IDNumber: strings.TrimSpace(receiver.IDNumber),
+			Email:    strings.TrimSpace(receiver.Email),
+		}
+	}
+
+	return e.signTx(tx, doc, situation, document)
+}
+
+// creditTx signs a Nota de Crédito taking back voided tickets of a sale inside the void's transaction and
+// queues it for submission after the invoice it references. A note taking back the whole invoice annuls it.
+// Sales without an invoice, or whose invoice was rejected, get no note; nor do tickets without an amount.
+func (e *EInvoiceService) creditTx(tx *sql.Tx, saleID int64, reportID int64, tickets []models.Ticket, reason string) (*models.ElectronicDocument, error) {
+	if e == nil || saleID == 0 {
+		return nil, nil
+	}
+
+	invoice, err := local.NewElectronicDocumentRepository(e.ctx, e.localDB).GetBySaleIDTx(tx, saleID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		zap.L().Error("failed to get electronic document", zap.Error(err))
+		return nil, err
+	}
+	if invoice.Status == enums.OutboxRejected {
+		zap.L().Warn("voided sale's invoice was rejected, no credit note issued", zap.Int64("sale_id", saleID))
+		return nil, nil
+	}
+
+	lines := invoiceLines(tickets)
+	if len(lines) == 0 {
+		return nil, nil
+	}
+	if e.signer == nil {
+		return nil, helpers.ErrEInvoiceUnavailable
+	}
+
+	invoiceIssuedAt, err := einvoice.ParseDate(invoice.IssuedAt)
+	if err != nil {
+		zap.L().Error("failed to parse electronic document date", zap.Error(err))
+		return nil, err
+	}
+
+	doc := e.document(einvoice.NotaCredito, time.Now(), lines)
+	code := einvoice.ReferenceOther
+	if doc.Total() == invoice.Total {
+		code = einvoice.ReferenceVoid
+	}
+	doc.References = []einvoice.Reference{{
+		Type:     einvoice.DocumentType(invoice.DocumentType),
+		Number:   invoice.Clave,
+		IssuedAt: invoiceIssuedAt,
+		Code:     code,
+		Reason:   reason,
+	}}
+	if invoice.ReceiverIDNumber != "" {
+		doc.Receiver = &einvoice.Party{
+			Name:     invoice.ReceiverName,
+			IDType:   invoice.ReceiverIDType,
+			IDNumber: invoice.ReceiverIDNumber,
+			Email:    invoice.ReceiverEmail,
+		}
+	}
+
+	return e.signTx(tx, doc, e.situation(), models.ElectronicDocument{
+		SaleID:         saleID,
+		ReportID:       reportID,
+		ReferenceClave: invoice.Clave,
+	})
+}
+
+// document starts a document of the emitter with its lines
+func (e *EInvoiceService) document(docType einvoice.DocumentType, issuedAt time.Time, lines []einvoice.Line) einvoice.Document {
+	emitter := e.cfg.Emitter
+	doc := einvoice.Document{
+		Type:         docType,
+		IssuedAt:     issuedAt,
+		Provider:     e.cfg.Provider,
+		ActivityCode: e.cfg.ActivityCode,
+		Emitter: einvoice.Party{
+			Name:           emitter.Name,
+			IDType:         emitter.IDType,
+			IDNumber:       emitter.IDNumber,
+			CommercialName: emitter.CommercialName,
+			Province:       emitter.Province,
+			Canton:         emitter.Canton,
+			District:       emitter.District,
+			Address:        emitter.Address,
+			Phone:          emitter.Phone,
+			Email:          emitter.Email,
+		},
+		CABYS:       e.cfg.CABYS,
+		TaxRateCode: e.cfg.TaxRateCode,
+		Lines:       lines,
+	}
+	if doc.Provider == "" {
+		doc.Provider = emitter.IDNumber
+	}
+	return doc
+}
+
+// situation is the situation of a document issued now: without internet while Hacienda can't be reached
+func (e *EInvoiceService) situation() string {
+	if e.outboxService != nil && e.outboxService.isOffline(enums.OutboxEInvoice) {
+		return einvoice.SituationNoInternet
+	}
+	return einvoice.SituationNormal
+}
+
+// signTx numbers, signs and stores a document inside the caller's transaction and queues it for submission.
+// The consecutive number is taken from the branch and terminal's sequence of the document type.
+func (e *EInvoiceService) signTx(tx *sql.Tx, doc einvoice.Document, situation string, document models.ElectronicDocument) (*models.ElectronicDocument, error) {
+	sequence, err := local.NewEInvoiceSequenceRepository(e.ctx, e.localDB).
+		NextTx(tx, e.cfg.Branch, e.cfg.Terminal, string(doc.Type))
+	if err != nil {
+		zap.L().Error("failed to take e-invoice sequence", zap.Error(err))
+		return nil, err
+	}
+
+	if doc.Consecutive, err = einvoice.Consecutive(e.cfg.Branch, e.cfg.Terminal, doc.Type, sequence); err != nil {
+		zap.L().Error("failed to number electronic document", zap.Error(err))
+		return nil, err
+	}
+	if doc.Clave, err = einvoice.NewClave(doc.IssuedAt, doc.Emitter.IDNumber, doc.Consecutive, situation); err != nil {
+		zap.L().Error("failed to generate clave", zap.Error(err))
+		return nil, err
+	}
+
+	signed, err := e.signer.Sign(doc, time.Now())
+	if err != nil {
+		zap.L().Error("failed to sign electronic document", zap.Error(err))
+		return nil, err
+	}
+
+	document.DocumentType = string(doc.Type)
+	document.Clave = doc.Clave
+	document.Consecutive = doc.Consecutive
+	document.Total = doc.Total()
+	document.XML = string(signed)
+	document.Status = enums.OutboxPending
+	document.IssuedAt = einvoice.FormatDate(doc.IssuedAt)
+	if doc.Receiver != nil {
+		document.ReceiverName = doc.Receiver.Name
+		document.ReceiverIDType = doc.Receiver.IDType
+		document.ReceiverIDNumber = doc.Receiver.IDNumber
+		document.ReceiverEmail = doc.Receiver.Email
+	}
+
+	created, err := local.NewElectronicDocumentRepository(e.ctx, e.localDB).AddTx(tx, document)
+	if err != nil {
+		zap.L().Error("failed to store electronic document", zap.Error(err))
+		return nil, err
+	}
+
+	now := outboxTime(time.Now())
+	_, err = local.NewOutboxMessageRepository(e.ctx, e.localDB).AddTx(tx, models.OutboxMessage{
+		Kind:          enums.OutboxEInvoice,
+		RecordID:      created.ID,
+		Reference:     created.Clave,
+		State:         enums.OutboxPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	})
+	if err != nil {
+		zap.L().Error("failed to queue electronic document", zap.Error(err))
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// GetSaleDocument returns the electronic invoice of a sale
+func (e *EInvoiceService) GetSaleDocument(saleID int64) (*models.ElectronicDocument, error) {
+	document, err := local.NewElectronicDocumentRepository(e.ctx, e.localDB).GetBySaleID(saleID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, helpers.ErrRowNotFound
+		}
+		zap.L().Error("failed to get electronic document", zap.Error(err))
+		return nil, err
+	}
+	return document, nil
+}
+
+// GetRejectedDocuments returns the documents Hacienda rejected or refused, the latest first, with its
+// reason, for the admin to correct and reissue
+func (e *EInvoiceService) GetRejectedDocuments() ([]models.RejectedDocument, error) {
+	documents, err := local.NewElectronicDocumentRepository(e.ctx, e.localDB).GetRejected()
+	if err != nil {
+		zap.L().Error("failed to get rejected electronic documents", zap.Error(err))
+		return nil, err
+	}
+	return documents, nil
+}
+
+// errInvoiceNotAccepted marks a credit note waiting for Hacienda to accept the invoice it references
+var errInvoiceNotAccepted = fmt.Errorf("referenced invoice not accepted yet: %w", errOutboxWaiting)
+
+// deliver submits a queued document to the recepción API. A credit note waits until the invoice it
+// references is accepted. A document refused as malformed is settled in error with the API's cause; one
+// already received goes on to be polled.
+func (e *EInvoiceService) deliver(ctx context.Context, message models.OutboxMessage) (*outboxAnswer, error) {
+	if e.submitter == nil {
+		return nil, helpers.ErrEInvoiceUnavailable
+	}
+
+	repository := local.NewElectronicDocumentRepository(ctx, e.localDB)
+	document, err := repository.Get(message.RecordID)
+	if err != nil {
+		return nil, err
+	}
+
+	if document.ReferenceClave != "" {
+		invoice, err := repository.GetByClave(document.ReferenceClave)
+		if err != nil {
+			return nil, err
+		}
+		switch invoice.Status {
+		case enums.OutboxPending, enums.OutboxSent:
+			return nil, errInvoiceNotAccepted
+		case enums.OutboxRejected, enums.OutboxError:
+			return &outboxAnswer{State: enums.OutboxError, Reason: "referenced invoice not accepted"}, nil
+		}
+	}
+
+	err = e.submitter.Submit(ctx, e.submission(*document))
+	var refused *einvoice.SubmissionError
+	switch {
+	case err == nil:
+		return nil, nil
+	case errors.As(err, &refused) && refused.Duplicate():
+		return nil, nil
+	case errors.As(err, &refused) && refused.Permanent():
+		return &outboxAnswer{State: enums.OutboxError, Reason: refused.Cause}, nil
+	default:
+		return nil, err
+	}
+}
+
+// poll asks the recepción API for the answer to a submitted document. Hacienda's response XML is kept with
+// its DetalleMensaje as the reason of a rejection or error; a document the API doesn't know is sent again.
+func (e *EInvoiceService) poll(ctx context.Context, message models.OutboxMessage) (*outboxAnswer, error) {
+	if e.submitter == nil {
+		return nil, helpers.ErrEInvoiceUnavailable
+	}
+
+	status, err := e.submitter.Status(ctx, message.Reference)
+	if err != nil {
+		if errors.Is(err, einvoice.ErrUnknownDocument) {
+			return &outboxAnswer{State: enums.OutboxPending}, nil
+		}
+		return nil, err
+	}
+
+	answer := &outboxAnswer{Response: string(status.ResponseXML)}
+	switch status.State {
+	case einvoice.StateAccepted:
+		answer.State = enums.OutboxAccepted
+	case einvoice.StateRejected:
+		answer.State = enums.OutboxRejected
+		answer.Reason = status.Detail()
+	case einvoice.StateError:
+		answer.State = enums.OutboxError
+		answer.Reason = status.Detail()
+	default:
+		return nil, nil
+	}
+	return answer, nil
+}
+
+// changed keeps the state of a document's outbox message on the document
+func (e *EInvoiceService) changed(message models.OutboxMessage) error {
+	var submittedAt *string
+	if message.State == enums.OutboxSent {
+		submittedAt = &message.UpdatedAt
+	}
+	return local.NewElectronicDocumentRepository(e.ctx, e.localDB).UpdateStatus(message.RecordID, message.State, submittedAt)
+}
+
+// submission is the recepción request of a document
+func (e *EInvoiceService) submission(document models.ElectronicDocument) einvoice.Submission {
+	submission := einvoice.Submission{
+		Clave: document.Clave,
+		Date:  document.IssuedAt,
+		Emitter: einvoice.Identification{
+			Type:   e.cfg.Emitter.IDType,
+			Number: e.cfg.Emitter.IDNumber,
+		},
+		XML: []byte(document.XML),
+	}
+	if document.ReceiverIDNumber != "" {
+		submission.Receiver = &einvoice.Identification{
+			Type:   document.ReceiverIDType,
+			Number: document.ReceiverIDNumber,
+		}
+	}
+	return submission
+}
+
+// ScriptEInvoiceMock sets the final states ("aceptado", "rechazado") of the next documents submitted to the
+// recepción mock
+func (e *EInvoiceService) ScriptEInvoiceMock(states []string) error {
+	if e.mock == nil {
+		return helpers.ErrEInvoiceMockDisabled
+	}
+	for _, state := range states {
+		if state != einvoice.StateAccepted && state != einvoice.StateRejected {
+			return helpers.ErrInvalidRequest
+		}
+	}
+	e.mock.Script(states...)
+	return nil
+}
+
+// GetEInvoiceMockDocuments returns the documents submitted to the recepción mock with their state
+func (e *EInvoiceService) GetEInvoiceMockDocuments() ([]einvoice.MockDocument, error) {
+	if e.mock == nil {
+		return nil, helpers.ErrEInvoiceMockDisabled
+	}
+	return e.mock.Documents(), nil
+}
+
+// invoiceLines groups the paid tickets of a sale into document lines by trip and fare
+func invoiceLines(tickets []models.Ticket) []einvoice.Line {
+	lines := []einvoice.Line{}
+	index := map[string]int{}
+	for _, ticket := range tickets {
+		if ticket.Fare <= 0 {
+			continue
+		}
+
+		description := fmt.Sprintf("Pasaje %s - %s %s", ticket.Departure, ticket.Stop, ticket.Time)
+		key := fmt.Sprintf("%s|%d", description, ticket.Fare)
+		if i, ok := index[key]; ok {
+			lines[i].Quantity++
+			continue
+		}
+
+		index[key] = len(lines)
+		lines = append(lines, einvoice.Line{Description: description, Quantity: 1, UnitPrice: ticket.Fare})
+	}
+	return lines
+}
+
+// invoicePaymentMethods are the means of payment of the payment methods
+var invoicePaymentMethods = map[enums.PaymentMethod]string{
+	enums.PaymentCash:  einvoice.PaymentCash,
+	enums.PaymentCard:  einvoice.PaymentCard,
+	enums.PaymentSinpe: einvoice.PaymentSinpe,
+}
+
+// invoicePayments adds up a sale's payments per means of payment, in the order they were taken
+func invoicePayments(payments []models.SalePayment) []einvoice.Payment {
+	totals := []einvoice.Payment{}
+	index := map[string]int{}
+	for _, payment := range payments {
+		method, ok := invoicePaymentMethods[payment.Method]
+		if !ok || payment.Amount <= 0 {
+			continue
+		}
+		if i, ok := index[method]; ok {
+			totals[i].Amount += payment.Amount
+			continue
+		}
+		index[method] = len(totals)
+		totals = append(totals, einvoice.Payment{Method: method, Amount: payment.Amount})
+	}
+	return totals
+}
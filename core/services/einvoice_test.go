@@ -0,0 +1,212 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"neon/core/config"
+	"neon/core/einvoice"
+	"neon/core/emulator"
+	"neon/core/helpers/enums"
+	"neon/core/models"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testInvoicing turns e-invoicing on for a ticket service, signing with a throwaway key. Documents are
+// queued but never submitted.
+func testInvoicing(t *testing.T, tickets *TicketService) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1234),
+		Subject:      pkix.Name{CommonName: "Neon Test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %v", err)
+	}
+
+	invoicing := tickets.einvoiceService
+	invoicing.cfg = config.EInvoiceConfig{
+		Enabled: true,
+		Emitter: config.EInvoiceEmitterConfig{
+			Name:     "Transportes Neón",
+			IDType:   einvoice.IDFisica,
+			IDNumber: "112345678",
+		},
+		ActivityCode: "492101",
+		Branch:       1,
+		Terminal:     1,
+		CABYS:        "6421100000000",
+		TaxRateCode:  "10",
+	}
+	invoicing.signer = einvoice.NewSigner(key, certificate)
+	invoicing.startOutbox(tickets.outboxService)
+}
+
+// creditNotes returns the credit notes referencing an invoice, in the order they were issued
+func creditNotes(t *testing.T, tickets *TicketService, clave string) []models.ElectronicDocument {
+	t.Helper()
+	rows, err := tickets.localDB.GetDB().Query(
+		"SELECT document_type, total, receiver_id_number, xml FROM electronic_documents WHERE reference_clave = ? ORDER BY id",
+		clave,
+	)
+	if err != nil {
+		t.Fatalf("failed to load credit notes: %v", err)
+	}
+	defer rows.Close()
+
+	var notes []models.ElectronicDocument
+	for rows.Next() {
+		var note models.ElectronicDocument
+		if err := rows.Scan(&note.DocumentType, &note.Total, &note.ReceiverIDNumber, &note.XML); err != nil {
+			t.Fatalf("failed to scan credit note: %v", err)
+		}
+		notes = append(notes, note)
+	}
+	return notes
+}
+
+func TestIssueSituation(t *testing.T) {
+	receipt := &models.ContingencyReceipt{Number: "A-0042", IssuedAt: time.Now().Add(-time.Hour).Format(time.RFC3339)}
+
+	tests := []struct {
+		name        string
+		offline     bool
+		contingency *models.ContingencyReceipt
+		want        string
+		// wantReference is the reference block the invoice carries, empty for none
+		wantReference string
+	}{
+		{"normal", false, nil, einvoice.SituationNormal, ""},
+		{"hacienda unreachable", true, nil, einvoice.SituationNoInternet, ""},
+		{"contingency receipt", false, receipt, einvoice.SituationContingency, "<TipoDocIR>08</TipoDocIR><Numero>A-0042</Numero>"},
+		{"contingency receipt while unreachable", true, receipt, einvoice.SituationContingency, "<Codigo>05</Codigo>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tickets, report := testSaleService(t, emulator.Status{})
+			testInvoicing(t, tickets)
+			tickets.outboxService.setOffline(enums.OutboxEInvoice, tt.offline)
+
+			request := testSaleRequest(report.ID, nil)
+			request.Contingency = tt.contingency
+			sale, err := tickets.AddSale(request)
+			if err != nil {
+				t.Fatalf("AddSale() error = %v", err)
+			}
+			if sale.Document == nil {
+				t.Fatalf("AddSale() issued no document")
+			}
+
+			// The situation follows the country code, the date, the emitter and the consecutive number
+			if got := sale.Document.Clave[41:42]; got != tt.want {
+				t.Errorf("clave %s situation = %s, want %s", sale.Document.Clave, got, tt.want)
+			}
+			hasReference := strings.Contains(sale.Document.XML, "<InformacionReferencia>")
+			if hasReference != (tt.wantReference != "") || !strings.Contains(sale.Document.XML, tt.wantReference) {
+				t.Errorf("invoice references = %v, want %q", hasReference, tt.wantReference)
+			}
+		})
+	}
+}
+
+func TestVoidInvoicedSale(t *testing.T) {
+	receiver := &models.InvoiceReceiver{Name: "Empresa S.A.", IDType: einvoice.IDJuridica, IDNumber: "3101123456"}
+
+	tests := []struct {
+		name     string
+		receiver *models.InvoiceReceiver
+		// oneByOne voids the tickets one at a time instead of the whole sale
+		oneByOne   bool
+		wantTotals []int
+		wantCode   string
+	}{
+		{"sale voided", nil, false, []int{2300}, einvoice.ReferenceVoid},
+		{"tickets voided one by one", nil, true, []int{1150, 1150}, einvoice.ReferenceOther},
+		{"factura voided", receiver, false, []int{2300}, einvoice.ReferenceVoid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tickets, report := testSaleService(t, emulator.Status{})
+			testInvoicing(t, tickets)
+
+			request := testSaleRequest(report.ID, nil)
+			request.Receiver = tt.receiver
+			sale, err := tickets.AddSale(request)
+			if err != nil {
+				t.Fatalf("AddSale() error = %v", err)
+			}
+			invoice := sale.Document
+
+			if tt.oneByOne {
+				for _, ticket := range sale.Tickets {
+					if _, err := tickets.VoidTicket(models.TicketVoidRequest{
+						TicketID: ticket.ID,
+						ReportID: report.ID,
+						Reason:   enums.VoidOther,
+					}); err != nil {
+						t.Fatalf("VoidTicket() error = %v", err)
+					}
+				}
+			} else if _, err := tickets.VoidSale(models.SaleVoidRequest{
+				SaleID:   sale.ID,
+				ReportID: report.ID,
+				Reason:   enums.VoidOther,
+			}); err != nil {
+				t.Fatalf("VoidSale() error = %v", err)
+			}
+
+			notes := creditNotes(t, tickets, invoice.Clave)
+			if len(notes) != len(tt.wantTotals) {
+				t.Fatalf("credit notes = %d, want %d", len(notes), len(tt.wantTotals))
+			}
+			reference := "<InformacionReferencia><TipoDocIR>" + invoice.DocumentType + "</TipoDocIR>" +
+				"<Numero>" + invoice.Clave + "</Numero><FechaEmisionIR>" + invoice.IssuedAt + "</FechaEmisionIR>" +
+				"<Codigo>" + tt.wantCode + "</Codigo>"
+			for i, note := range notes {
+				if note.DocumentType != string(einvoice.NotaCredito) || note.Total != tt.wantTotals[i] {
+					t.Errorf("credit note %d type, total = %s, %d, want %s, %d",
+						i, note.DocumentType, note.Total, einvoice.NotaCredito, tt.wantTotals[i])
+				}
+				if !strings.Contains(note.XML, reference) {
+					t.Errorf("credit note %d doesn't reference the invoice with %s", i, reference)
+				}
+				if tt.receiver != nil && note.ReceiverIDNumber != tt.receiver.IDNumber {
+					t.Errorf("credit note %d receiver = %q, want %q", i, note.ReceiverIDNumber, tt.receiver.IDNumber)
+				}
+			}
+
+			var queued int
+			if err := tickets.localDB.GetDB().QueryRow(
+				"SELECT COUNT(*) FROM outbox_messages WHERE kind = ?", enums.OutboxEInvoice,
+			).Scan(&queued); err != nil {
+				t.Fatalf("failed to count outbox messages: %v", err)
+			}
+			if queued != 1+len(notes) {
+				t.Errorf("e-invoice messages queued = %d, want %d", queued, 1+len(notes))
+			}
+
+			// The sale's document is still its invoice
+			document, err := tickets.einvoiceService.GetSaleDocument(sale.ID)
+			if err != nil || document.Clave != invoice.Clave {
+				t.Errorf("GetSaleDocument() = %+v, %v, want the invoice", document, err)
+			}
+		})
+	}
+}
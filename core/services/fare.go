@@ -0,0 +1,428 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"neon/core/config"
+	"neon/core/constants"
+	"neon/core/database/embedded"
+	remotedb "neon/core/database/remote"
+	"neon/core/helpers"
+	"neon/core/helpers/enums"
+	"neon/core/models"
+	"neon/core/repositories/local"
+	"neon/core/repositories/remote"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// quoteLayouts are the accepted formats of the moment a fare is quoted for
+var quoteLayouts = []string{time.RFC3339, "2006-01-02 15:04", "2006-01-02T15:04", constants.DateLayout}
+
+// FareService is a service for quoting fares
+type FareService struct {
+	ctx         context.Context
+	cloverDB    *embedded.CloverDB
+	syncService *SyncService
+}
+
+// NewFareService creates a new fare service
+func NewFareService(cloverDB *embedded.CloverDB, syncService *SyncService) *FareService {
+	return &FareService{cloverDB: cloverDB, syncService: syncService}
+}
+
+// startup starts the fare service
+func (f *FareService) startup(ctx context.Context) {
+	f.ctx = ctx
+}
+
+// Quote computes the fare of a stop of a route for a passenger category on a date. The date is the
+// departure's local date and time ("2006-01-02 15:04", RFC 3339 or just the date); empty is now.
+func (f *FareService) Quote(departure string, destination string, stop string, category string, date string) (*models.FareQuote, error) {
+	at := time.Now()
+	if date != "" {
+		parsed, err := parseQuoteDate(date)
+		if err != nil {
+			return nil, helpers.ErrInvalidRequest
+		}
+		at = parsed
+	}
+
+	route, versions, err := f.routeFares(departure, destination)
+	if err != nil {
+		return nil, err
+	}
+
+	version := effectiveFareVersion(versions, at.Format(constants.DateLayout))
+	return quoteFare(route, version, stop, enums.FareCategory(category), at)
+}
+
+// GetFareVersions returns the tariffs of a route, past and scheduled, ordered by version
+func (f *FareService) GetFareVersions(departure string, destination string) ([]models.FareVersion, error) {
+	versions, err := local.NewFareVersionRepository(f.cloverDB).GetByRoute(departure, destination)
+	if err != nil {
+		zap.L().Error("failed to get fare versions", zap.Error(err))
+		return nil, err
+	}
+
+	return versions, nil
+}
+
+// PreviewFareChange returns, for every stop and fare category of the route, the fare that would apply
+// on the request's effective date without the change and the fare the change sets
+func (f *FareService) PreviewFareChange(request models.FareChangeRequest) ([]models.FareChangePreview, error) {
+	route, versions, err := f.routeFares(request.Departure, request.Destination)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateFareChange(route, request); err != nil {
+		return nil, err
+	}
+
+	current := effectiveFareVersion(versions, request.EffectiveFrom)
+	scheduled := &models.FareVersion{Stops: request.Stops}
+
+	previews := []models.FareChangePreview{}
+	for _, stop := range route.Stops {
+		currentFares, currentVersion := stopFares(stop, current)
+		newFares := currentFares
+		if fares, ok := scheduled.FindStop(stop.Name); ok {
+			newFares = fares
+		}
+
+		for _, category := range enums.AllFareCategories {
+			currentFare, hasCurrent := categoryFare(currentFares, category.Value)
+			newFare, hasNew := categoryFare(newFares, category.Value)
+			if !hasCurrent && !hasNew {
+				continue
+			}
+
+			previews = append(previews, models.FareChangePreview{
+				Stop:           stop.Name,
+				Category:       category.Value,
+				CurrentFare:    currentFare,
+				NewFare:        newFare,
+				Difference:     newFare - currentFare,
+				CurrentVersion: currentVersion,
+			})
+		}
+	}
+
+	return previews, nil
+}
+
+// ScheduleFareChange stores a new tariff for a route in MongoDB, taking effect on a future date, and
+// syncs the fare versions. Sales keep the current fares until then.
+func (f *FareService) ScheduleFareChange(request models.FareChangeRequest) (*models.FareVersion, error) {
+	route, _, err := f.routeFares(request.Departure, request.Destination)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateFareChange(route, request); err != nil {
+		return nil, err
+	}
+
+	version := &models.FareVersion{
+		Departure:     request.Departure,
+		Destination:   request.Destination,
+		EffectiveFrom: request.EffectiveFrom,
+		Stops:         request.Stops,
+		Note:          request.Note,
+		CreatedBy:     request.Username,
+		CreatedAt:     time.Now().Format(time.RFC3339),
+	}
+
+	remotedb := remotedb.NewMongoDB(config.GetMongoDBConfig())
+	if err := remotedb.Connect(f.ctx); err != nil {
+		zap.L().Error("failed to connect to remote database", zap.Error(err))
+		return nil, err
+	}
+	defer remotedb.Close()
+	remoteRepo := remote.NewFareVersionRepository(remotedb)
+
+	if err := remoteRepo.Create(f.ctx, version); err != nil {
+		zap.L().Error("failed to create fare version", zap.Error(err))
+		return nil, err
+	}
+
+	if err := f.syncService.SyncFareVersions(); err != nil {
+		zap.L().Error("failed to sync fare versions after schedule", zap.Error(err))
+		return nil, err
+	}
+
+	return version, nil
+}
+
+// routeFares loads a route and its fare versions from the local database
+func (f *FareService) routeFares(departure string, destination string) (*models.Route, []models.FareVersion, error) {
+	route, err := local.NewRouteRepository(f.cloverDB).GetByName(departure, destination)
+	if err != nil {
+		if !errors.Is(err, helpers.ErrRowNotFound) {
+			zap.L().Error("failed to get route", zap.Error(err))
+		}
+		return nil, nil, err
+	}
+
+	versions, err := local.NewFareVersionRepository(f.cloverDB).GetByRoute(departure, destination)
+	if err != nil {
+		zap.L().Error("failed to get fare versions", zap.Error(err))
+		return nil, nil, err
+	}
+
+	return route, versions, nil
+}
+
+// validateFareChange checks a tariff change: it takes effect after today and lists stops of the route
+// with valid categories and no negative fares
+func validateFareChange(route *models.Route, request models.FareChangeRequest) error {
+	effectiveFrom, err := time.ParseInLocation(constants.DateLayout, request.EffectiveFrom, time.Local)
+	if err != nil || effectiveFrom.Format(constants.DateLayout) <= time.Now().Format(constants.DateLayout) {
+		return helpers.ErrInvalidEffectiveDate
+	}
+
+	if len(request.Stops) == 0 {
+		return helpers.ErrInvalidRequest
+	}
+
+	for _, stop := range request.Stops {
+		if _, ok := route.FindStop(stop.Stop); !ok {
+			return helpers.ErrStopNotFound
+		}
+		if stop.Fare < 0 || stop.GoldFare < 0 {
+			return helpers.ErrInvalidRequest
+		}
+		for _, fare := range stop.Fares {
+			if !fare.Category.IsValid() {
+				return helpers.ErrInvalidFareCategory
+			}
+			if fare.Fare < 0 {
+				return helpers.ErrInvalidRequest
+			}
+		}
+	}
+
+	return nil
+}
+
+// quoteFare computes the fare of a stop of a route for a category at a local moment: the stop's fare for
+// the category under the fare version, lowered by the cheapest promotion of the route that applies. A nil
+// version uses the route's own stop fares.
+func quoteFare(route *models.Route, version *models.FareVersion, stopName string, category enums.FareCategory, at time.Time) (*models.FareQuote, error) {
+	if !category.IsValid() {
+		return nil, helpers.ErrInvalidFareCategory
+	}
+
+	stop, ok := route.FindStop(stopName)
+	if !ok {
+		return nil, helpers.ErrStopNotFound
+	}
+
+	fares, fareVersion := stopFares(stop, version)
+	base, ok := categoryFare(fares, category)
+	if !ok {
+		return nil, helpers.ErrFareNotAvailable
+	}
+
+	quote := &models.FareQuote{
+		Departure:   route.Departure,
+		Destination: route.Destination,
+		Stop:        stop.Name,
+		Category:    category,
+		BaseFare:    base,
+		Fare:        base,
+		Rule:        "stop:" + string(category),
+		FareVersion: fareVersion,
+	}
+
+	for _, promotion := range route.Promotions {
+		if !promotionApplies(promotion, stop.Name, category, at) {
+			continue
+		}
+		if fare := promotionFare(promotion, base); fare < quote.Fare {
+			quote.Fare = fare
+			quote.Rule = "promo:" + promotion.Name
+		}
+	}
+
+	return quote, nil
+}
+
+// stopFares returns the fares of a stop under a fare version and the version they come from: the
+// version's when it lists the stop, otherwise the route's own (version 0)
+func stopFares(stop models.Stop, version *models.FareVersion) (models.StopFares, int) {
+	if version != nil {
+		if fares, ok := version.FindStop(stop.Name); ok {
+			return fares, version.Version
+		}
+	}
+	return models.StopFares{Stop: stop.Name, Fare: stop.Fare, GoldFare: stop.GoldFare, Fares: stop.Fares}, 0
+}
+
+// categoryFare returns a stop's fare for a category. Regular and gold use Fare and GoldFare unless the
+// stop lists them in Fares; other categories are only sold when listed.
+func categoryFare(fares models.StopFares, category enums.FareCategory) (int, bool) {
+	for _, fare := range fares.Fares {
+		if fare.Category == category {
+			return fare.Fare, true
+		}
+	}
+
+	switch category {
+	case enums.FareRegular:
+		return fares.Fare, true
+	case enums.FareGold:
+		return fares.GoldFare, true
+	}
+	return 0, false
+}
+
+// effectiveFareVersion returns the fare version in effect on a local date (YYYY-MM-DD): the latest one
+// that took effect on or before it, nil when none has
+func effectiveFareVersion(versions []models.FareVersion, date string) *models.FareVersion {
+	var effective *models.FareVersion
+	for i := range versions {
+		version := &versions[i]
+		if version.EffectiveFrom > date {
+			continue
+		}
+		if effective == nil || version.EffectiveFrom > effective.EffectiveFrom ||
+			(version.EffectiveFrom == effective.EffectiveFrom && version.Version > effective.Version) {
+			effective = version
+		}
+	}
+	return effective
+}
+
+// promotionApplies reports whether a promotion covers a stop and category at a local moment
+func promotionApplies(promotion models.FarePromotion, stop string, category enums.FareCategory, at time.Time) bool {
+	if len(promotion.Categories) > 0 && !containsCategory(promotion.Categories, category) {
+		return false
+	}
+	if len(promotion.Stops) > 0 && !containsString(promotion.Stops, stop) {
+		return false
+	}
+
+	day := at.Format(constants.DateLayout)
+	if promotion.StartDate != "" && day < promotion.StartDate {
+		return false
+	}
+	if promotion.EndDate != "" && day > promotion.EndDate {
+		return false
+	}
+
+	clock := at.Format("15:04")
+	if promotion.StartTime != "" && clock < promotion.StartTime {
+		return false
+	}
+	if promotion.EndTime != "" && clock >= promotion.EndTime {
+		return false
+	}
+
+	return true
+}
+
+// promotionFare applies a promotion to a base fare, never raising it
+func promotionFare(promotion models.FarePromotion, base int) int {
+	fare := base
+	if promotion.DiscountPercent > 0 {
+		fare = base - base*promotion.DiscountPercent/100
+	}
+	if promotion.Fare != nil && *promotion.Fare < fare {
+		fare = *promotion.Fare
+	}
+	if fare < 0 {
+		fare = 0
+	}
+	return fare
+}
+
+// parseQuoteDate parses the local moment a fare is quoted for
+func parseQuoteDate(date string) (time.Time, error) {
+	var err error
+	for _, layout := range quoteLayouts {
+		var at time.Time
+		if layout == time.RFC3339 {
+			at, err = time.Parse(layout, date)
+			at = at.Local()
+		} else {
+			at, err = time.ParseInLocation(layout, date, time.Local)
+		}
+		if err == nil {
+			return at, nil
+		}
+	}
+	return time.Time{}, err
+}
+
+func containsCategory(categories []enums.FareCategory, category enums.FareCategory) bool {
+	for _, c := range categories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyFares prices the tickets of a sale with the route's fare rules and records the category, rule and
+// fare version on each ticket. The tariff is the one in effect on the sale date; promotions apply by the
+// travel date and departure time. Gold tickets are the gold category. Tickets of a
+// route missing from the local database keep the fare sent by the sale screen. Travel dates must already
+// be set.
+func (t *TicketService) applyFares(tickets []models.Ticket) error {
+	routes := local.NewRouteRepository(t.cloverDB)
+	fareVersions := local.NewFareVersionRepository(t.cloverDB)
+	today := time.Now().Format(constants.DateLayout)
+	for i := range tickets {
+		ticket := &tickets[i]
+
+		category := ticket.Category()
+		if !category.IsValid() {
+			return helpers.ErrInvalidFareCategory
+		}
+		ticket.FareCategory = category
+		ticket.IsGold = category == enums.FareGold
+
+		route, err := routes.GetByName(ticket.Departure, ticket.Destination)
+		if err != nil {
+			if errors.Is(err, helpers.ErrRowNotFound) {
+				continue
+			}
+			zap.L().Error("failed to get route", zap.Error(err))
+			return err
+		}
+
+		versions, err := fareVersions.GetByRoute(ticket.Departure, ticket.Destination)
+		if err != nil {
+			zap.L().Error("failed to get fare versions", zap.Error(err))
+			return err
+		}
+
+		at, err := time.ParseInLocation("2006-01-02 15:04", ticket.TravelDate+" "+ticket.Time, time.Local)
+		if err != nil {
+			at = time.Now()
+		}
+
+		quote, err := quoteFare(route, effectiveFareVersion(versions, today), ticket.Stop, category, at)
+		if err != nil {
+			return err
+		}
+		ticket.Fare = quote.Fare
+		ticket.FareRule = quote.Rule
+		ticket.FareVersion = quote.FareVersion
+	}
+
+	return nil
+}
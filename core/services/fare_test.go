@@ -0,0 +1,194 @@
+package services
+
+import (
+	"errors"
+	"neon/core/helpers"
+	"neon/core/helpers/enums"
+	"neon/core/models"
+	"testing"
+	"time"
+)
+
+func TestPromotionApplies(t *testing.T) {
+	morning := time.Date(2026, 3, 9, 6, 30, 0, 0, time.Local)
+
+	tests := []struct {
+		name      string
+		promotion models.FarePromotion
+		stop      string
+		category  enums.FareCategory
+		at        time.Time
+		want      bool
+	}{
+		{"no limits", models.FarePromotion{}, "Cartago", enums.FareRegular, morning, true},
+		{"category listed", models.FarePromotion{Categories: []enums.FareCategory{enums.FareStudent}}, "Cartago", enums.FareStudent, morning, true},
+		{"category not listed", models.FarePromotion{Categories: []enums.FareCategory{enums.FareStudent}}, "Cartago", enums.FareRegular, morning, false},
+		{"stop listed", models.FarePromotion{Stops: []string{"Cartago"}}, "Cartago", enums.FareRegular, morning, true},
+		{"stop not listed", models.FarePromotion{Stops: []string{"Tres Ríos"}}, "Cartago", enums.FareRegular, morning, false},
+		{"first day included", models.FarePromotion{StartDate: "2026-03-09"}, "Cartago", enums.FareRegular, morning, true},
+		{"before the first day", models.FarePromotion{StartDate: "2026-03-10"}, "Cartago", enums.FareRegular, morning, false},
+		{"last day included", models.FarePromotion{EndDate: "2026-03-09"}, "Cartago", enums.FareRegular, morning, true},
+		{"after the last day", models.FarePromotion{EndDate: "2026-03-08"}, "Cartago", enums.FareRegular, morning, false},
+		{"start time included", models.FarePromotion{StartTime: "06:30", EndTime: "08:00"}, "Cartago", enums.FareRegular, morning, true},
+		{"before the start time", models.FarePromotion{StartTime: "07:00", EndTime: "08:00"}, "Cartago", enums.FareRegular, morning, false},
+		{"end time excluded", models.FarePromotion{StartTime: "05:00", EndTime: "06:30"}, "Cartago", enums.FareRegular, morning, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := promotionApplies(tt.promotion, tt.stop, tt.category, tt.at); got != tt.want {
+				t.Errorf("promotionApplies() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuoteFare(t *testing.T) {
+	fixed := 500
+	route := &models.Route{
+		Departure:   "San José",
+		Destination: "Cartago",
+		Stops: []models.Stop{
+			{Name: "Tres Ríos", Fare: 800},
+			{Name: "Cartago", Fare: 1150, GoldFare: 0, Fares: []models.CategoryFare{
+				{Category: enums.FareStudent, Fare: 900},
+			}},
+		},
+		Promotions: []models.FarePromotion{
+			{Name: "madrugada", StartTime: "04:00", EndTime: "06:00", DiscountPercent: 10},
+			{Name: "estudiantes", Categories: []enums.FareCategory{enums.FareStudent}, DiscountPercent: 50},
+			{Name: "tres-rios", Stops: []string{"Tres Ríos"}, Fare: &fixed},
+		},
+	}
+	early := time.Date(2026, 3, 9, 5, 0, 0, 0, time.Local)
+	noon := time.Date(2026, 3, 9, 12, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name     string
+		stop     string
+		category enums.FareCategory
+		at       time.Time
+		wantFare int
+		wantBase int
+		wantRule string
+		wantErr  error
+	}{
+		{"regular fare", "Cartago", enums.FareRegular, noon, 1150, 1150, "stop:regular", nil},
+		{"gold fare", "Cartago", enums.FareGold, noon, 0, 0, "stop:gold", nil},
+		{"listed category", "Cartago", enums.FareStudent, noon, 450, 900, "promo:estudiantes", nil},
+		{"time window promotion", "Cartago", enums.FareRegular, early, 1035, 1150, "promo:madrugada", nil},
+		{"cheapest promotion wins", "Cartago", enums.FareStudent, early, 450, 900, "promo:estudiantes", nil},
+		{"fixed fare promotion", "Tres Ríos", enums.FareRegular, early, 500, 800, "promo:tres-rios", nil},
+		{"category not sold at the stop", "Tres Ríos", enums.FareChild, noon, 0, 0, "", helpers.ErrFareNotAvailable},
+		{"unknown category", "Cartago", enums.FareCategory("pet"), noon, 0, 0, "", helpers.ErrInvalidFareCategory},
+		{"unknown stop", "Paraíso", enums.FareRegular, noon, 0, 0, "", helpers.ErrStopNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := quoteFare(route, nil, tt.stop, tt.category, tt.at)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("quoteFare() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if got.Fare != tt.wantFare || got.BaseFare != tt.wantBase || got.Rule != tt.wantRule {
+				t.Errorf("quoteFare() = %d (base %d, %s), want %d (base %d, %s)",
+					got.Fare, got.BaseFare, got.Rule, tt.wantFare, tt.wantBase, tt.wantRule)
+			}
+			if got.FareVersion != 0 {
+				t.Errorf("quoteFare() version = %d, want the route's own fares", got.FareVersion)
+			}
+		})
+	}
+}
+
+func TestEffectiveFareVersion(t *testing.T) {
+	versions := []models.FareVersion{
+		{Version: 1, EffectiveFrom: "2026-01-01"},
+		{Version: 3, EffectiveFrom: "2026-06-01"},
+		{Version: 2, EffectiveFrom: "2026-03-01"},
+		// Rescheduled on the same day: the higher version replaces the other
+		{Version: 4, EffectiveFrom: "2026-06-01"},
+	}
+
+	tests := []struct {
+		name     string
+		versions []models.FareVersion
+		date     string
+		want     int
+	}{
+		{"no versions", nil, "2026-03-09", 0},
+		{"before the first version", versions, "2025-12-31", 0},
+		{"first day of a version", versions, "2026-01-01", 1},
+		{"between versions", versions, "2026-03-09", 2},
+		{"day before the next version", versions, "2026-05-31", 2},
+		{"same day versions", versions, "2026-06-01", 4},
+		{"after the last version", versions, "2027-01-01", 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := effectiveFareVersion(tt.versions, tt.date)
+			if tt.want == 0 {
+				if got != nil {
+					t.Errorf("effectiveFareVersion() = version %d, want none", got.Version)
+				}
+				return
+			}
+			if got == nil || got.Version != tt.want {
+				t.Errorf("effectiveFareVersion() = %v, want version %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuoteFareVersion(t *testing.T) {
+	route := &models.Route{
+		Departure:   "San José",
+		Destination: "Cartago",
+		Stops: []models.Stop{
+			{Name: "Tres Ríos", Fare: 800},
+			{Name: "Cartago", Fare: 1150},
+		},
+	}
+	version := &models.FareVersion{
+		Version:       2,
+		EffectiveFrom: "2026-03-01",
+		Stops: []models.StopFares{
+			{Stop: "Cartago", Fare: 1200, Fares: []models.CategoryFare{{Category: enums.FareStudent, Fare: 600}}},
+		},
+	}
+	noon := time.Date(2026, 3, 9, 12, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name        string
+		stop        string
+		category    enums.FareCategory
+		wantFare    int
+		wantVersion int
+		wantErr     error
+	}{
+		{"stop in the version", "Cartago", enums.FareRegular, 1200, 2, nil},
+		{"category in the version", "Cartago", enums.FareStudent, 600, 2, nil},
+		{"stop left out of the version", "Tres Ríos", enums.FareRegular, 800, 0, nil},
+		{"category not in the version", "Cartago", enums.FareChild, 0, 0, helpers.ErrFareNotAvailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := quoteFare(route, version, tt.stop, tt.category, noon)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("quoteFare() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if got.Fare != tt.wantFare || got.FareVersion != tt.wantVersion {
+				t.Errorf("quoteFare() = %d (version %d), want %d (version %d)",
+					got.Fare, got.FareVersion, tt.wantFare, tt.wantVersion)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"neon/core/config"
+	"neon/core/constants"
+	"neon/core/helpers"
+	"neon/core/models"
+	"neon/core/repositories/local"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// validateGoldTickets checks the gold tickets of a sale before it is stored: the passenger's ID must be a
+// valid cédula, DIMEX or passport, registered and active in the gold registry when the installation
+// requires it, and within the gold limits per travel date and per departure. The tickets' ID numbers are
+// normalized in place. Travel dates must already be set.
+func (t *TicketService) validateGoldTickets(tickets []models.Ticket) error {
+	cfg := config.LoadPOSConfig().Gold
+	registry := local.NewGoldPassengerRepository(t.cloverDB)
+	repository := local.NewTicketRepository(t.ctx, t.localDB)
+
+	// Gold tickets already counted per ID and travel date, including the ones earlier in this sale
+	perDay := map[string]int{}
+	perDeparture := map[string]int{}
+	loaded := map[string]bool{}
+
+	for i := range tickets {
+		ticket := &tickets[i]
+		if !ticket.IsGold {
+			continue
+		}
+
+		if helpers.NormalizeIDNumber(ticket.IDNumber) == "" {
+			return helpers.ErrGoldIDRequired
+		}
+
+		idNumber, err := t.validateGoldPassenger(registry, cfg, ticket.IDNumber)
+		if err != nil {
+			return err
+		}
+		ticket.IDNumber = idNumber
+
+		dayKey := idNumber + "|" + ticket.TravelDate
+		if !loaded[dayKey] {
+			sold, err := repository.GetGoldByIDNumber(idNumber, ticket.TravelDate)
+			if err != nil {
+				zap.L().Error("failed to get gold tickets", zap.Error(err))
+				return err
+			}
+			for _, goldTicket := range sold {
+				perDay[dayKey]++
+				perDeparture[goldDepartureKey(goldTicket)]++
+			}
+			loaded[dayKey] = true
+		}
+
+		departureKey := goldDepartureKey(*ticket)
+		if cfg.MaxPerDay > 0 && perDay[dayKey] >= cfg.MaxPerDay {
+			return helpers.ErrGoldDailyLimit
+		}
+		if cfg.MaxPerDeparture > 0 && perDeparture[departureKey] >= cfg.MaxPerDeparture {
+			return helpers.ErrGoldDepartureLimit
+		}
+		perDay[dayKey]++
+		perDeparture[departureKey]++
+	}
+
+	return nil
+}
+
+// validateGoldPassenger validates an ID against the gold registry and returns it normalized. IDs missing
+// from the registry are only checked for format unless the installation requires the registry.
+func (t *TicketService) validateGoldPassenger(registry *local.GoldPassengerRepository, cfg config.GoldConfig, id string) (string, error) {
+	idNumber := helpers.NormalizeIDNumber(id)
+
+	passenger, err := registry.FindByIDNumber(idNumber)
+	if err != nil {
+		zap.L().Error("failed to find gold passenger", zap.Error(err))
+		return "", err
+	}
+
+	if passenger == nil {
+		if cfg.RequireRegistry {
+			return "", helpers.ErrGoldPassengerNotRegistered
+		}
+		return helpers.ValidateIDNumber(helpers.DetectIDType(idNumber), idNumber)
+	}
+
+	if !passenger.Active {
+		return "", helpers.ErrGoldPassengerInactive
+	}
+	if passenger.ExpiresAt != nil && *passenger.ExpiresAt != "" &&
+		*passenger.ExpiresAt < time.Now().Format(constants.DateLayout) {
+		return "", helpers.ErrGoldPassengerInactive
+	}
+
+	return helpers.ValidateIDNumber(passenger.IDType, idNumber)
+}
+
+func goldDepartureKey(ticket models.Ticket) string {
+	return ticket.Departure + "|" + ticket.Destination + "|" + ticket.TravelDate + "|" + ticket.Time
+}
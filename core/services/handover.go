@@ -0,0 +1,295 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"neon/core/database/embedded"
+	"neon/core/helpers"
+	"neon/core/helpers/enums"
+	"neon/core/models"
+	"neon/core/repositories/local"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// HandOverReport records the outgoing cashier's count of the drawer at a shift handover. The report stays
+// open but can't sell or close until the incoming cashier accepts the handover with AcceptHandover.
+func (r *ReportService) HandOverReport(request models.ReportHandoverRequest) (*models.ReportHandover, error) {
+	now := time.Now().Format(time.RFC3339)
+
+	cash := request.Cash
+	var counts []models.CashCount
+	if request.Counts != nil {
+		var err error
+		if counts, cash, err = cashCount(request.ReportID, enums.CountHandover, request.Counts, request.Username, now); err != nil {
+			return nil, err
+		}
+	}
+	if cash < 0 {
+		return nil, helpers.ErrInvalidHandover
+	}
+
+	report, err := local.NewReportRepository(r.ctx, r.localDB).GetByID(request.ReportID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, helpers.ErrRowNotFound
+		}
+		zap.L().Error("failed to get report", zap.Error(err))
+		return nil, err
+	}
+	if !report.Status {
+		return nil, helpers.ErrInvalidHandover
+	}
+
+	if err := loadReportTotals(r.ctx, r.localDB, report); err != nil {
+		return nil, err
+	}
+	if handoverPending(report) {
+		return nil, helpers.ErrHandoverPending
+	}
+	if request.Username != report.Cashier {
+		return nil, helpers.ErrNotReportCashier
+	}
+
+	// The shift's tickets and cash are the report's so far less the earlier shifts'
+	current := report.Segments[len(report.Segments)-1]
+	drawer := report.OpeningFloat + report.PartialDrawer + report.FinalDrawer - report.PartialCashReceived
+
+	handover := models.ReportHandover{
+		ReportID:     report.ID,
+		Sequence:     len(report.Segments),
+		FromUsername: request.Username,
+		Tickets:      current.Tickets,
+		CashTaken:    current.CashTaken,
+		Expected:     drawer,
+		Cash:         cash,
+		CountedAt:    now,
+		Count:        counts,
+	}
+
+	tx, err := r.localDB.BeginTx(r.ctx, nil)
+	if err != nil {
+		zap.L().Error("failed to begin report handover transaction", zap.Error(err))
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	output, err := local.NewReportHandoverRepository(r.ctx, r.localDB).AddTx(tx, handover)
+	if err != nil {
+		zap.L().Error("failed to add report handover", zap.Error(err))
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		zap.L().Error("failed to commit report handover", zap.Error(err))
+		return nil, err
+	}
+
+	if output.Count == nil {
+		output.Count = []models.CashCount{}
+	}
+
+	return output, nil
+}
+
+// AcceptHandover makes the incoming cashier, who logs in with their credentials, take over the drawer of a
+// report handed over with HandOverReport. The sales after it are attributed to them. The outgoing cashier
+// can't accept their own handover.
+func (r *ReportService) AcceptHandover(reportID int64, username string, password string) (*models.Report, error) {
+	if r.authService == nil {
+		return nil, helpers.ErrUserNotFound
+	}
+	if _, err := r.authService.Login(username, password); err != nil {
+		return nil, err
+	}
+
+	tx, err := r.localDB.BeginTx(r.ctx, nil)
+	if err != nil {
+		zap.L().Error("failed to begin report handover transaction", zap.Error(err))
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	repository := local.NewReportHandoverRepository(r.ctx, r.localDB)
+	handover, err := repository.GetLatestTx(tx, reportID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, helpers.ErrNoHandoverPending
+		}
+		zap.L().Error("failed to get report handover", zap.Error(err))
+		return nil, err
+	}
+	if handover.AcceptedAt != nil {
+		return nil, helpers.ErrNoHandoverPending
+	}
+	if handover.FromUsername == username {
+		return nil, helpers.ErrHandoverSameCashier
+	}
+
+	// Only one accept wins: the update skips a handover accepted or cancelled since it was read
+	accepted, err := repository.AcceptTx(tx, handover.ID, username, time.Now().Format(time.RFC3339))
+	if err != nil {
+		zap.L().Error("failed to accept report handover", zap.Error(err))
+		return nil, err
+	}
+	if !accepted {
+		return nil, helpers.ErrNoHandoverPending
+	}
+
+	if err := tx.Commit(); err != nil {
+		zap.L().Error("failed to commit report handover acceptance", zap.Error(err))
+		return nil, err
+	}
+
+	report, err := r.getReport(reportID)
+	if err != nil {
+		return nil, err
+	}
+
+	return blindReport(report), nil
+}
+
+// CancelHandover drops a handover the incoming cashier hasn't accepted, giving the drawer back to the
+// outgoing cashier
+func (r *ReportService) CancelHandover(reportID int64) (*models.Report, error) {
+	repository := local.NewReportHandoverRepository(r.ctx, r.localDB)
+	handover, err := r.getPendingHandover(repository, reportID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := r.localDB.BeginTx(r.ctx, nil)
+	if err != nil {
+		zap.L().Error("failed to begin report handover transaction", zap.Error(err))
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := repository.DeleteTx(tx, handover.ID); err != nil {
+		zap.L().Error("failed to delete report handover", zap.Error(err))
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		zap.L().Error("failed to commit report handover cancellation", zap.Error(err))
+		return nil, err
+	}
+
+	report, err := r.getReport(reportID)
+	if err != nil {
+		return nil, err
+	}
+
+	return blindReport(report), nil
+}
+
+// getPendingHandover gets the handover of a report waiting to be accepted
+func (r *ReportService) getPendingHandover(repository *local.ReportHandoverRepository, reportID int64) (*models.ReportHandover, error) {
+	handover, err := repository.GetLatest(reportID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, helpers.ErrNoHandoverPending
+		}
+		zap.L().Error("failed to get report handover", zap.Error(err))
+		return nil, err
+	}
+	if handover.AcceptedAt != nil {
+		return nil, helpers.ErrNoHandoverPending
+	}
+	return handover, nil
+}
+
+// getReport gets a report with its totals
+func (r *ReportService) getReport(reportID int64) (*models.Report, error) {
+	report, err := local.NewReportRepository(r.ctx, r.localDB).GetByID(reportID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, helpers.ErrRowNotFound
+		}
+		zap.L().Error("failed to get report", zap.Error(err))
+		return nil, err
+	}
+
+	if err := loadReportTotals(r.ctx, r.localDB, report); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// reportCashier returns who sells on a report now, failing with ErrHandoverPending while its drawer waits
+// for the incoming cashier
+func reportCashier(ctx context.Context, localDB *embedded.SQLite, reportID int64) (string, error) {
+	report, err := local.NewReportRepository(ctx, localDB).GetByID(reportID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", helpers.ErrRowNotFound
+		}
+		zap.L().Error("failed to get report", zap.Error(err))
+		return "", err
+	}
+
+	handover, err := local.NewReportHandoverRepository(ctx, localDB).GetLatest(reportID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return report.Username, nil
+		}
+		zap.L().Error("failed to get report handover", zap.Error(err))
+		return "", err
+	}
+	if handover.AcceptedAt == nil {
+		return "", helpers.ErrHandoverPending
+	}
+
+	return handover.ToUsername, nil
+}
+
+// handoverPending reports whether a report with its totals loaded waits for a handover to be accepted
+func handoverPending(report *models.Report) bool {
+	last := report.Segments[len(report.Segments)-1]
+	return last.Handover != nil && last.Handover.AcceptedAt == nil
+}
+
+// reportSegments splits a report into its cashiers' shifts at its handovers. The report's drawer totals
+// must be loaded.
+func reportSegments(report *models.Report, handovers []models.ReportHandover) ([]models.ReportSegment, string) {
+	segments := make([]models.ReportSegment, 0, len(handovers)+1)
+	cashier := report.Username
+	startedAt := report.CreatedAt
+	tickets := 0
+	cash := 0
+	for i := range handovers {
+		handover := handovers[i]
+		segments = append(segments, models.ReportSegment{
+			Sequence:  len(segments) + 1,
+			Username:  cashier,
+			StartedAt: startedAt,
+			EndedAt:   &handover.CountedAt,
+			Tickets:   handover.Tickets,
+			CashTaken: handover.CashTaken,
+			Handover:  &handover,
+		})
+		tickets += handover.Tickets
+		cash += handover.CashTaken
+
+		if handover.AcceptedAt == nil {
+			// The drawer waits for the incoming cashier
+			return segments, cashier
+		}
+		cashier = handover.ToUsername
+		startedAt = handover.AcceptedAt
+	}
+
+	segments = append(segments, models.ReportSegment{
+		Sequence:  len(segments) + 1,
+		Username:  cashier,
+		StartedAt: startedAt,
+		EndedAt:   report.ClosedAt,
+		Tickets:   report.PartialTickets + report.FinalTickets - tickets,
+		CashTaken: report.PartialDrawer + report.FinalDrawer - cash,
+	})
+
+	return segments, cashier
+}
@@ -0,0 +1,365 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"neon/core/database/embedded"
+	"neon/core/helpers"
+	"neon/core/helpers/enums"
+	"neon/core/models"
+	"neon/core/repositories/local"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// outboxInterval is how often the worker looks for due messages
+	outboxInterval = 30 * time.Second
+	// outboxTimeout bounds each delivery or poll
+	outboxTimeout = 30 * time.Second
+	// outboxClaim is how long an attempt holds its message; it outlasts outboxTimeout so a claim only
+	// expires when its attempt was cut off
+	outboxClaim = 2 * outboxTimeout
+	// outboxBatch is the most messages handled per pass
+	outboxBatch = 50
+	// outboxBaseDelay is the wait after the first failed attempt and before the first poll; it doubles with
+	// each further attempt up to outboxMaxDelay
+	outboxBaseDelay = 15 * time.Second
+	outboxMaxDelay  = 30 * time.Minute
+)
+
+// outboxHandler delivers the outbox messages of one kind to their third party
+type outboxHandler interface {
+	// deliver sends a pending message. It returns an answer when the third party settles the message right
+	// away, e.g. refuses it as invalid, and an error when the attempt should be retried.
+	deliver(ctx context.Context, message models.OutboxMessage) (*outboxAnswer, error)
+	// poll asks for the answer to a sent message; a nil answer means it is still being processed
+	poll(ctx context.Context, message models.OutboxMessage) (*outboxAnswer, error)
+	// changed is told the new state of a message, e.g. to keep it on the delivered document
+	changed(message models.OutboxMessage) error
+}
+
+// outboxAnswer is a third party's answer to a message. A pending state sends the message again.
+type outboxAnswer struct {
+	State    enums.OutboxState
+	Response string
+	Reason   string
+}
+
+// OutboxService delivers the documents owed to third parties, e.g. the electronic invoices owed to
+// Hacienda. Documents are queued in outbox_messages in the transaction that creates them; a background
+// worker sends the due messages and polls the sent ones for their answer. Failed attempts are retried with
+// exponential backoff, so the POS keeps selling offline: while a third party can't be reached a single
+// message per kind is tried each pass, and once one gets through every waiting message is drained.
+type OutboxService struct {
+	ctx      context.Context
+	localDB  *embedded.SQLite
+	handlers map[enums.OutboxKind]outboxHandler
+	// mu serializes the passes of the worker and DrainOutbox. Each attempt claims its message instead, so
+	// attempt never waits on a pass.
+	mu sync.Mutex
+	// offline holds the kinds whose last attempt failed, guarded by offlineMu so it can be read while a pass
+	// runs
+	offline   map[enums.OutboxKind]bool
+	offlineMu sync.Mutex
+}
+
+// NewOutboxService creates a new outbox service
+func NewOutboxService(localDB *embedded.SQLite) *OutboxService {
+	return &OutboxService{
+		localDB:  localDB,
+		handlers: map[enums.OutboxKind]outboxHandler{},
+		offline:  map[enums.OutboxKind]bool{},
+	}
+}
+
+// register makes handler deliver the messages of a kind. Messages of kinds without a handler wait.
+func (o *OutboxService) register(kind enums.OutboxKind, handler outboxHandler) {
+	o.handlers[kind] = handler
+}
+
+// startup starts the outbox worker, which runs until ctx is done
+func (o *OutboxService) startup(ctx context.Context) {
+	o.ctx = ctx
+	if len(o.handlers) > 0 {
+		go o.run(ctx)
+	}
+}
+
+// run drains the outbox now and then every outboxInterval
+func (o *OutboxService) run(ctx context.Context) {
+	ticker := time.NewTicker(outboxInterval)
+	defer ticker.Stop()
+
+	for {
+		n, err := o.drain(ctx)
+		if err != nil {
+			zap.L().Warn("outbox pass finished with errors", zap.Int("delivered", n), zap.Error(err))
+		} else if n > 0 {
+			zap.L().Info("outbox pass completed", zap.Int("delivered", n))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// isOffline reports whether the last attempt on a message of a kind failed, i.e. its third party can't be
+// reached
+func (o *OutboxService) isOffline(kind enums.OutboxKind) bool {
+	o.offlineMu.Lock()
+	defer o.offlineMu.Unlock()
+	return o.offline[kind]
+}
+
+// setOffline records whether a kind's third party can be reached and reports whether that changed
+func (o *OutboxService) setOffline(kind enums.OutboxKind, offline bool) bool {
+	o.offlineMu.Lock()
+	defer o.offlineMu.Unlock()
+	changed := o.offline[kind] != offline
+	o.offline[kind] = offline
+	return changed
+}
+
+// DrainOutbox runs a pass of the worker now and returns how many messages were sent or answered
+func (o *OutboxService) DrainOutbox() (int, error) {
+	return o.drain(o.ctx)
+}
+
+// RetryOutboxMessage queues again a message the third party refused with an error, e.g. after fixing the
+// settings it was refused for. Rejected messages can't be retried: the document must be reissued.
+func (o *OutboxService) RetryOutboxMessage(id int64) error {
+	repository := local.NewOutboxMessageRepository(o.ctx, o.localDB)
+	message, err := repository.Get(id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return helpers.ErrRowNotFound
+		}
+		zap.L().Error("failed to get outbox message", zap.Error(err))
+		return err
+	}
+	if message.State != enums.OutboxError {
+		return helpers.ErrOutboxMessageNotRetryable
+	}
+
+	now := time.Now()
+	message.State = enums.OutboxPending
+	message.Attempts = 0
+	message.NextAttemptAt = outboxTime(now)
+	message.LastError = ""
+	message.Reason = ""
+	message.Response = ""
+	message.UpdatedAt = outboxTime(now)
+	if err := repository.Update(*message); err != nil {
+		zap.L().Error("failed to retry outbox message", zap.Error(err))
+		return err
+	}
+
+	o.notify(*message)
+	return nil
+}
+
+// drain handles the due messages and returns how many were sent or answered. After a kind's first
+// success following a failure, its messages waiting out a backoff are made due and handled in the same
+// pass.
+func (o *OutboxService) drain(ctx context.Context) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	repository := local.NewOutboxMessageRepository(ctx, o.localDB)
+	delivered := 0
+	var lastErr error
+	for {
+		messages, err := repository.GetDue(outboxTime(time.Now()), outboxBatch)
+		if err != nil {
+			zap.L().Error("failed to get due outbox messages", zap.Error(err))
+			return delivered, err
+		}
+
+		failed := map[enums.OutboxKind]bool{}
+		reconnected := false
+		for _, message := range messages {
+			handler, ok := o.handlers[message.Kind]
+			if !ok || failed[message.Kind] {
+				continue
+			}
+
+			changed, err := o.handle(ctx, repository, handler, message)
+			if errors.Is(err, errOutboxClaimed) || errors.Is(err, errOutboxWaiting) {
+				continue
+			}
+			if err != nil {
+				if errors.Is(err, errOutboxStore) {
+					return delivered, err
+				}
+				zap.L().Warn("outbox attempt failed",
+					zap.String("kind", string(message.Kind)),
+					zap.String("reference", message.Reference),
+					zap.Error(err),
+				)
+				failed[message.Kind] = true
+				o.setOffline(message.Kind, true)
+				lastErr = err
+				continue
+			}
+
+			if changed {
+				delivered++
+			}
+			if o.setOffline(message.Kind, false) {
+				reconnected = true
+			}
+		}
+
+		if !reconnected {
+			return delivered, lastErr
+		}
+		if _, err := repository.RescheduleWaiting(outboxTime(time.Now())); err != nil {
+			zap.L().Error("failed to reschedule outbox messages", zap.Error(err))
+			return delivered, err
+		}
+	}
+}
+
+// attempt makes an attempt on messages right away, e.g. on the refunds of a void once it is committed,
+// instead of waiting for the worker. It doesn't wait on a running pass: a message the pass is attempting is
+// skipped. Failures are logged and left to the worker's retries.
+func (o *OutboxService) attempt(messages []models.OutboxMessage) {
+	repository := local.NewOutboxMessageRepository(o.ctx, o.localDB)
+	for _, message := range messages {
+		handler, ok := o.handlers[message.Kind]
+		if !ok {
+			continue
+		}
+		_, err := o.handle(o.ctx, repository, handler, message)
+		if err != nil && !errors.Is(err, errOutboxClaimed) && !errors.Is(err, errOutboxWaiting) {
+			zap.L().Warn("outbox attempt failed, left to the worker",
+				zap.String("kind", string(message.Kind)),
+				zap.String("reference", message.Reference),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+var (
+	// errOutboxStore marks a failure to save a message, which stops the pass
+	errOutboxStore = errors.New("failed to store outbox message")
+	// errOutboxClaimed marks a message skipped because another attempt holds it or it was settled since it
+	// was read
+	errOutboxClaimed = errors.New("outbox message already claimed")
+	// errOutboxWaiting marks a message that waits on another one, e.g. a refund on the capture of its charge.
+	// It is retried with backoff like a failure, but doesn't take its kind offline.
+	errOutboxWaiting = errors.New("outbox message waiting")
+)
+
+// handle makes an attempt on a message: it claims it, delivers it when pending or polls it when sent, then
+// saves the outcome. It reports whether the message changed state, and returns the attempt's error once the
+// message is rescheduled. A message claimed by another attempt, or settled since it was read, is left alone.
+func (o *OutboxService) handle(ctx context.Context, repository *local.OutboxMessageRepository, handler outboxHandler, message models.OutboxMessage) (bool, error) {
+	now := time.Now()
+	claimed, err := repository.Claim(message.ID, outboxTime(now), outboxTime(now.Add(outboxClaim)))
+	if err != nil {
+		zap.L().Error("failed to claim outbox message", zap.Error(err))
+		return false, errors.Join(errOutboxStore, err)
+	}
+	if claimed == nil {
+		return false, errOutboxClaimed
+	}
+	message = *claimed
+
+	attemptCtx, cancel := context.WithTimeout(ctx, outboxTimeout)
+	defer cancel()
+
+	var answer *outboxAnswer
+	if message.State == enums.OutboxSent {
+		answer, err = handler.poll(attemptCtx, message)
+	} else {
+		answer, err = handler.deliver(attemptCtx, message)
+	}
+
+	now = time.Now()
+	previous := message.State
+	message.UpdatedAt = outboxTime(now)
+
+	switch {
+	case err != nil:
+		message.Attempts++
+		message.NextAttemptAt = outboxTime(now.Add(outboxBackoff(message.Attempts)))
+		message.LastError = err.Error()
+	case answer != nil:
+		message.State = answer.State
+		message.Attempts = 0
+		message.NextAttemptAt = outboxTime(now)
+		message.LastError = ""
+		message.Response = answer.Response
+		message.Reason = answer.Reason
+	case message.State == enums.OutboxSent:
+		// Still being processed
+		message.Attempts++
+		message.NextAttemptAt = outboxTime(now.Add(outboxBackoff(message.Attempts)))
+	default:
+		message.State = enums.OutboxSent
+		message.Attempts = 0
+		message.NextAttemptAt = outboxTime(now.Add(outboxBaseDelay))
+		message.LastError = ""
+	}
+
+	if storeErr := repository.Update(message); storeErr != nil {
+		zap.L().Error("failed to update outbox message", zap.Error(storeErr))
+		return false, errors.Join(errOutboxStore, storeErr)
+	}
+
+	changed := message.State != previous
+	if changed {
+		o.notify(message)
+		if message.State == enums.OutboxRejected || message.State == enums.OutboxError {
+			zap.L().Warn("outbox message refused",
+				zap.String("kind", string(message.Kind)),
+				zap.String("reference", message.Reference),
+				zap.String("state", string(message.State)),
+				zap.String("reason", message.Reason),
+			)
+		}
+	}
+
+	return changed, err
+}
+
+// notify tells a message's handler about its new state
+func (o *OutboxService) notify(message models.OutboxMessage) {
+	handler, ok := o.handlers[message.Kind]
+	if !ok {
+		return
+	}
+	if err := handler.changed(message); err != nil {
+		zap.L().Error("failed to record outbox message state",
+			zap.String("kind", string(message.Kind)),
+			zap.String("reference", message.Reference),
+			zap.Error(err),
+		)
+	}
+}
+
+// outboxBackoff is the wait before the next attempt on a message after attempts failed or unanswered ones
+func outboxBackoff(attempts int) time.Duration {
+	delay := outboxBaseDelay
+	for i := 1; i < attempts && delay < outboxMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > outboxMaxDelay {
+		return outboxMaxDelay
+	}
+	return delay
+}
+
+// outboxTime formats the outbox times, in UTC so they compare as text
+func outboxTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,344 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"neon/core/config"
+	"neon/core/helpers"
+	"neon/core/helpers/enums"
+	"neon/core/models"
+	"neon/core/payment"
+	"neon/core/repositories/local"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// PaymentProvider charges the card payments of a sale on a card terminal. A charge is authorized before
+// the sale is stored, captured once it is stored and printed, and voided when the sale can't be completed
+// or is voided before the capture; refunds give back the fare of voided tickets.
+type PaymentProvider interface {
+	Authorize(ctx context.Context, charge payment.Charge) (*payment.Authorization, error)
+	Capture(ctx context.Context, authorization payment.Authorization) error
+	Void(ctx context.Context, authorization payment.Authorization) error
+	Refund(ctx context.Context, authorization payment.Authorization, amount int) error
+}
+
+// startPaymentTerminal charges card payments on the configured terminal: the TCP/JSON driver or the
+// built-in simulator
+func (t *TicketService) startPaymentTerminal(cfg config.PaymentTerminalConfig) error {
+	switch cfg.Driver {
+	case config.PaymentTerminalTCP:
+		if cfg.Address == "" {
+			return fmt.Errorf("payment terminal address is not set")
+		}
+		t.paymentProvider = payment.NewTerminal(cfg.Address, time.Duration(cfg.TimeoutSeconds)*time.Second)
+	case config.PaymentTerminalSimulator:
+		script, err := paymentOutcomes(cfg.Script)
+		if err != nil {
+			return err
+		}
+		t.paymentSimulator = payment.NewSimulator(script...)
+		t.paymentProvider = t.paymentSimulator
+	default:
+		return fmt.Errorf("unknown payment terminal driver %q", cfg.Driver)
+	}
+
+	zap.L().Info("payment terminal started", zap.String("driver", cfg.Driver), zap.String("address", cfg.Address))
+	return nil
+}
+
+// ScriptPaymentSimulator sets the outcomes ("approve", "decline", "timeout") of the simulator's next
+// authorizations
+func (t *TicketService) ScriptPaymentSimulator(outcomes []string) error {
+	if t.paymentSimulator == nil {
+		return helpers.ErrPaymentSimulatorDisabled
+	}
+
+	script, err := paymentOutcomes(outcomes)
+	if err != nil {
+		return err
+	}
+	t.paymentSimulator.Script(script...)
+	return nil
+}
+
+// GetPaymentSimulatorTransactions returns the charges taken on the simulator with their state
+func (t *TicketService) GetPaymentSimulatorTransactions() ([]payment.SimulatedTransaction, error) {
+	if t.paymentSimulator == nil {
+		return nil, helpers.ErrPaymentSimulatorDisabled
+	}
+	return t.paymentSimulator.Transactions(), nil
+}
+
+// authorizeCardPayments charges the card payments of a sale on the terminal before the sale is stored,
+// filling in their transaction ID as reference and their approval code. When one is not approved, the
+// charges already authorized are voided. Without a terminal nothing is charged.
+func (t *TicketService) authorizeCardPayments(payments []models.SalePayment, reference string) ([]payment.Authorization, error) {
+	if t.paymentProvider == nil {
+		return nil, nil
+	}
+
+	var authorizations []payment.Authorization
+	for i := range payments {
+		if payments[i].Method != enums.PaymentCard {
+			continue
+		}
+
+		authorization, err := t.paymentProvider.Authorize(t.ctx, payment.Charge{
+			Reference: reference,
+			Amount:    payments[i].Amount,
+		})
+		if err != nil {
+			t.voidAuthorizations(authorizations)
+			zap.L().Warn("card payment not authorized", zap.String("reference", reference), zap.Error(err))
+			return nil, paymentError(err)
+		}
+
+		payments[i].Reference = authorization.TransactionID
+		payments[i].ApprovalCode = authorization.ApprovalCode
+		authorizations = append(authorizations, *authorization)
+	}
+
+	return authorizations, nil
+}
+
+// voidAuthorizations cancels the card charges of a sale that was not stored. Failures are logged; the
+// charge then has to be voided on the terminal by hand.
+func (t *TicketService) voidAuthorizations(authorizations []payment.Authorization) {
+	for _, authorization := range authorizations {
+		if err := t.paymentProvider.Void(t.ctx, authorization); err != nil {
+			zap.L().Error("failed to void card payment, void it on the terminal",
+				zap.String("transaction_id", authorization.TransactionID),
+				zap.String("approval_code", authorization.ApprovalCode),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+// captureHold is how long the captures of a sale being printed wait for the print. A sale that fails to
+// print is voided and its captures cancelled; should the POS stop before the print finishes, the worker
+// captures the sale once the hold is over.
+const captureHold = 10 * time.Minute
+
+// startCardOutbox hands the captures of the card payments authorized on the terminal, and the refunds and
+// voids of those charged there, to the outbox, which retries those the terminal couldn't settle
+func (t *TicketService) startCardOutbox(outbox *OutboxService) {
+	t.outboxService = outbox
+	outbox.register(enums.OutboxCardCapture, t)
+	outbox.register(enums.OutboxCardRefund, t)
+	outbox.register(enums.OutboxCardVoid, t)
+}
+
+// onTerminal reports whether a card payment, or its refund, is settled on the terminal that charged it.
+// Card payments keyed in without a terminal are refunded by hand.
+func (t *TicketService) onTerminal(payment models.SalePayment) bool {
+	return t.paymentProvider != nil && payment.Method == enums.PaymentCard && payment.ApprovalCode != ""
+}
+
+// queueCardPaymentTx queues the capture, refund or void of a card payment on the terminal inside the
+// transaction storing it, for the worker to settle from due on. The terminal is only called once the
+// transaction is committed (see settleCardPayments), so a rollback never leaves a card charged or refunded
+// without its record, and a charge is never lost nor settled twice.
+func (t *TicketService) queueCardPaymentTx(tx *sql.Tx, kind enums.OutboxKind, payment models.SalePayment, due time.Time) (*models.OutboxMessage, error) {
+	now := outboxTime(time.Now())
+	message, err := local.NewOutboxMessageRepository(t.ctx, t.localDB).AddTx(tx, models.OutboxMessage{
+		Kind:          kind,
+		RecordID:      payment.ID,
+		Reference:     payment.Reference,
+		State:         enums.OutboxPending,
+		NextAttemptAt: outboxTime(due),
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	})
+	if err != nil {
+		zap.L().Error("failed to queue card payment", zap.String("kind", string(kind)), zap.Error(err))
+		return nil, err
+	}
+	return message, nil
+}
+
+// releaseChargeTx lets go of the card charge a refund gives back the rest of when the charge isn't
+// captured yet: inside the void's transaction it cancels the queued capture, and the refunds of the charge
+// queued before, so the whole authorization is voided instead (see deliver). An uncaptured charge is never
+// refunded. It reports whether the charge was released; one captured, being captured or only partly given
+// back is refunded once captured.
+func (t *TicketService) releaseChargeTx(tx *sql.Tx, payments []models.SalePayment, refund models.SalePayment) (bool, error) {
+	charge := cardCharge(payments, refund.Reference)
+	if charge == nil {
+		return false, nil
+	}
+
+	left := refund.Amount
+	for _, payment := range payments {
+		if payment.Method == enums.PaymentCard && payment.Reference == refund.Reference {
+			left += payment.Amount
+		}
+	}
+	if left != 0 {
+		return false, nil
+	}
+
+	now := outboxTime(time.Now())
+	outbox := local.NewOutboxMessageRepository(t.ctx, t.localDB)
+	cancelled, err := outbox.CancelTx(tx, enums.OutboxCardCapture, charge.ID, now)
+	if err != nil || !cancelled {
+		return false, err
+	}
+
+	repository := local.NewSalePaymentRepository(t.ctx, t.localDB)
+	if err := repository.UpdateCaptureStatusTx(tx, charge.ID, enums.OutboxCancelled); err != nil {
+		return false, err
+	}
+
+	for _, payment := range payments {
+		if payment.Method != enums.PaymentCard || payment.Reference != refund.Reference || payment.Amount >= 0 {
+			continue
+		}
+		// A refund already being attempted finds its charge uncaptured and is refused (see deliver)
+		cancelled, err := outbox.CancelTx(tx, enums.OutboxCardRefund, payment.ID, now)
+		if err != nil {
+			return false, err
+		}
+		if !cancelled {
+			continue
+		}
+		if err := repository.UpdateRefundStatusTx(tx, payment.ID, enums.OutboxCancelled); err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// cardCharge returns the card payment of a sale with a terminal transaction ID, nil when there is none
+func cardCharge(payments []models.SalePayment, reference string) *models.SalePayment {
+	for i := range payments {
+		if payments[i].Method == enums.PaymentCard && payments[i].Amount > 0 && payments[i].Reference == reference {
+			return &payments[i]
+		}
+	}
+	return nil
+}
+
+// errCaptureNotSettled marks a refund waiting for the capture of the charge it refunds
+var errCaptureNotSettled = fmt.Errorf("card payment not captured yet: %w", errOutboxWaiting)
+
+// settleCardPayments captures, refunds or voids on the terminal right away the card payments queued by a
+// committed sale or void. Those the terminal can't settle stay pending and are retried by the outbox.
+func (t *TicketService) settleCardPayments(messages []models.OutboxMessage) {
+	if len(messages) == 0 || t.outboxService == nil {
+		return
+	}
+	t.outboxService.attempt(messages)
+}
+
+// deliver captures, refunds or voids a queued card payment on the terminal that charged it. A refund
+// waits for the capture of its charge, and is refused when the capture was. A declined capture, refund or
+// void is refused for an admin to retry; a terminal that can't be reached is retried.
+func (t *TicketService) deliver(ctx context.Context, message models.OutboxMessage) (*outboxAnswer, error) {
+	repository := local.NewSalePaymentRepository(ctx, t.localDB)
+	cardPayment, err := repository.Get(message.RecordID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get card payment: %w", err)
+	}
+
+	authorization := payment.Authorization{
+		TransactionID: cardPayment.Reference,
+		ApprovalCode:  cardPayment.ApprovalCode,
+		Amount:        cardPayment.Amount,
+	}
+	var charge *models.SalePayment
+	if message.Kind != enums.OutboxCardCapture {
+		payments, err := repository.GetBySaleID(cardPayment.SaleID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get sale payments: %w", err)
+		}
+		charge = cardCharge(payments, cardPayment.Reference)
+	}
+
+	switch message.Kind {
+	case enums.OutboxCardRefund:
+		if charge != nil {
+			switch charge.CaptureStatus {
+			case enums.OutboxPending, enums.OutboxSent:
+				return nil, errCaptureNotSettled
+			case enums.OutboxRejected, enums.OutboxError, enums.OutboxCancelled:
+				return &outboxAnswer{State: enums.OutboxError, Reason: "card payment not captured"}, nil
+			}
+		}
+		err = t.paymentProvider.Refund(ctx, authorization, -cardPayment.Amount)
+	case enums.OutboxCardVoid:
+		// The whole authorization is voided, whatever part of it the voided ticket was
+		if charge != nil {
+			authorization.Amount = charge.Amount
+		}
+		err = t.paymentProvider.Void(ctx, authorization)
+	default:
+		err = t.paymentProvider.Capture(ctx, authorization)
+	}
+	if errors.Is(err, payment.ErrDeclined) {
+		return &outboxAnswer{State: enums.OutboxError, Reason: err.Error()}, nil
+	}
+	if err != nil {
+		zap.L().Error("failed to settle card payment",
+			zap.String("kind", string(message.Kind)),
+			zap.String("transaction_id", cardPayment.Reference),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	return &outboxAnswer{State: enums.OutboxAccepted}, nil
+}
+
+// poll is never called on card payments: deliver settles one or leaves it pending, never waiting in sent
+func (t *TicketService) poll(ctx context.Context, message models.OutboxMessage) (*outboxAnswer, error) {
+	return nil, nil
+}
+
+// changed keeps the state of a card payment's outbox message on the payment
+func (t *TicketService) changed(message models.OutboxMessage) error {
+	repository := local.NewSalePaymentRepository(t.ctx, t.localDB)
+	if message.Kind == enums.OutboxCardRefund || message.Kind == enums.OutboxCardVoid {
+		return repository.UpdateRefundStatus(message.RecordID, message.State)
+	}
+	return repository.UpdateCaptureStatus(message.RecordID, message.State)
+}
+
+// approvalCodes joins the approval codes of a sale's card payments
+func approvalCodes(authorizations []payment.Authorization) string {
+	codes := make([]string, 0, len(authorizations))
+	for _, authorization := range authorizations {
+		codes = append(codes, authorization.ApprovalCode)
+	}
+	return strings.Join(codes, ",")
+}
+
+// paymentOutcomes parses the simulator outcomes
+func paymentOutcomes(values []string) ([]payment.Outcome, error) {
+	outcomes := make([]payment.Outcome, 0, len(values))
+	for _, value := range values {
+		outcome := payment.Outcome(value)
+		if !outcome.IsValid() {
+			return nil, helpers.ErrInvalidRequest
+		}
+		outcomes = append(outcomes, outcome)
+	}
+	return outcomes, nil
+}
+
+// paymentError maps the terminal's errors to the errors shown to the cashier
+func paymentError(err error) error {
+	switch {
+	case errors.Is(err, payment.ErrDeclined):
+		return helpers.ErrPaymentDeclined
+	case errors.Is(err, payment.ErrTimeout):
+		return helpers.ErrPaymentTimeout
+	}
+	return err
+}
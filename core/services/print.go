@@ -1,18 +1,27 @@
 package services
 
 import (
+	"context"
+	"database/sql"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/DevLumuz/go-escpos"
+	"go.uber.org/zap"
 
+	"neon/core/config"
+	"neon/core/database/embedded"
+	"neon/core/emulator"
 	"neon/core/helpers"
-	"neon/core/helpers/enums"
 	"neon/core/models"
+	"neon/core/receipt"
+	"neon/core/repositories/local"
+	"neon/core/ticketcode"
 )
 
 // escposSafe normalizes text for ESC/POS printers that use limited code pages (e.g. CP437):
@@ -58,36 +67,72 @@ func (c *deadlineConn) Write(p []byte) (int, error) {
 }
 
 // PrintService handles thermal receipt printing over Ethernet.
-type PrintService struct{}
+type PrintService struct {
+	ctx      context.Context
+	localDB  *embedded.SQLite
+	emulator *emulator.Printer
+}
 
 // NewPrintService creates a new print service.
-func NewPrintService() *PrintService {
-	return &PrintService{}
+func NewPrintService(localDB *embedded.SQLite) *PrintService {
+	return &PrintService{localDB: localDB}
 }
 
-func (p *PrintService) openPrinter(printerName string) (escpos.Printer, string, error) {
-	address, err := helpers.ResolvePrinterAddress(printerName)
-	if err != nil {
-		return escpos.Printer{}, "", err
+// startup starts the print service
+func (p *PrintService) startup(ctx context.Context) {
+	p.ctx = ctx
+}
+
+// startEmulator routes every print to a built-in ESC/POS emulator instead of the Ethernet printer.
+func (p *PrintService) startEmulator(cfg config.PrinterEmulatorConfig) error {
+	printer := emulator.New(emulator.Status{})
+	if err := printer.Start(cfg.Address); err != nil {
+		return err
+	}
+	p.emulator = printer
+	zap.L().Info("printer emulator started", zap.String("address", printer.Addr()))
+	return nil
+}
+
+// shutdown stops the printer emulator if it is running
+func (p *PrintService) shutdown() {
+	if p.emulator == nil {
+		return
+	}
+	if err := p.emulator.Close(); err != nil {
+		zap.L().Debug("Error closing printer emulator", zap.Error(err))
+	}
+}
+
+func (p *PrintService) openPrinter(printerName string) (escpos.Printer, io.Writer, string, error) {
+	var address string
+	if p.emulator != nil {
+		address = p.emulator.Addr()
+	} else {
+		resolved, err := helpers.ResolvePrinterAddress(printerName)
+		if err != nil {
+			return escpos.Printer{}, nil, "", err
+		}
+		address = resolved
 	}
 
 	conn, err := net.DialTimeout("tcp", address, printerDialTimeout)
 	if err != nil {
-		return escpos.Printer{}, "", fmt.Errorf("connect to printer %q: %w", address, err)
+		return escpos.Printer{}, nil, "", fmt.Errorf("connect to printer %q: %w", address, err)
 	}
 
-	printer := escpos.NewPrinter(&deadlineConn{
+	raw := &deadlineConn{
 		Conn:         conn,
 		readTimeout:  printerReadTimeout,
 		writeTimeout: printerWriteTimeout,
-	})
+	}
 
-	return printer, address, nil
+	return escpos.NewPrinter(raw), raw, address, nil
 }
 
 // printerSession opens a printer, validates its status, runs fn, and closes it.
-func (p *PrintService) printerSession(printerName string, fn func(printer escpos.Printer) error) error {
-	printer, address, err := p.openPrinter(printerName)
+func (p *PrintService) printerSession(printerName string, fn func(target *escposTarget) error) error {
+	printer, raw, address, err := p.openPrinter(printerName)
 	if err != nil {
 		return err
 	}
@@ -97,7 +142,7 @@ func (p *PrintService) printerSession(printerName string, fn func(printer escpos
 		return err
 	}
 
-	return fn(printer)
+	return fn(&escposTarget{printer: printer, raw: raw, address: address})
 }
 
 func (p *PrintService) ensurePrinterReady(printer escpos.Printer, address string) error {
@@ -158,13 +203,17 @@ func (p *PrintService) resolvePrinterAddress(printerName string) (string, error)
 
 // EnsurePrinterReady validates that the configured printer can print before creating tickets.
 func (p *PrintService) EnsurePrinterReady(printerName string) error {
-	return p.printerSession(printerName, func(printer escpos.Printer) error {
+	return p.printerSession(printerName, func(target *escposTarget) error {
 		return nil
 	})
 }
 
 // GetInstalledPrinters returns the configured Ethernet printer endpoint.
 func (p *PrintService) GetInstalledPrinters() ([]string, error) {
+	if p.emulator != nil {
+		return []string{p.emulator.Addr()}, nil
+	}
+
 	address, err := p.resolvePrinterAddress("")
 	if err != nil {
 		return nil, err
@@ -185,220 +234,581 @@ func (p *PrintService) GetPrinterStatus(printerName string) (string, error) {
 	return "ready", nil
 }
 
-func (p *PrintService) printTicketReceipt(printer escpos.Printer, ticket models.Ticket) error {
-	if err := printer.Initialize(); err != nil {
-		return err
+// loadTemplate loads an installed receipt template, logging why when it can't be used.
+func loadTemplate(name string) (*receipt.Template, error) {
+	tpl, err := receipt.Load(name)
+	if err != nil {
+		zap.L().Error("failed to load receipt template", zap.String("template", name), zap.Error(err))
+		return nil, err
 	}
+	return tpl, nil
+}
 
-	printer.SetCharacterSize(1, 2)
+// printTicketReceipt prints a ticket receipt. A copyNumber above zero prints the "COPIA" banner and the
+// reprint counter so copies can't pass as originals. The clave and consecutive number of the sale's
+// electronic invoice are printed when document is set.
+func (p *PrintService) printTicketReceipt(
+	target receipt.Target,
+	tpl *receipt.Template,
+	ticket models.Ticket,
+	copyNumber int,
+	document *models.ElectronicDocument,
+) error {
+	cfg := config.LoadPOSConfig()
+	data := receipt.NewTicketData(cfg.Company, ticket, copyNumber)
+	data.Document = document
+	setTicketCode(&data, cfg)
+	return tpl.Render(data, target)
+}
 
-	if ticket.IsGold {
-		printer.SelectPrintMode(escpos.Underline)
-		printer.Justify(escpos.CenterJustify)
-		printer.Println("TIQUETE DE ORO")
+// saleDocument returns the electronic invoice of a stored sale, nil when it has none
+func (p *PrintService) saleDocument(saleID int64) *models.ElectronicDocument {
+	if saleID == 0 || p.localDB == nil {
+		return nil
 	}
 
-	printer.LF()
+	document, err := local.NewElectronicDocumentRepository(p.ctx, p.localDB).GetBySaleID(saleID)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			zap.L().Error("failed to get sale electronic document", zap.Error(err))
+		}
+		return nil
+	}
+	return document
+}
 
-	printer.Justify(escpos.CenterJustify)
-	printer.SetCharacterSize(1, 2)
-	printer.Println("TRANSPORTES")
-	printer.Println("EL PUMA PARDO S.A")
-	printer.Println("TEL: 2765-1349")
+// setTicketCode fills the signed validation code selected by ticket_code. Nothing is printed while the
+// installation has no signing key, since an unsigned code would be worthless to inspectors.
+func setTicketCode(data *receipt.TicketData, cfg *config.POSConfig) {
+	if cfg.TicketSigningKey == "" {
+		return
+	}
 
-	printer.LF()
+	claims := ticketcode.ClaimsFromTicket(data.Ticket)
+	key := []byte(cfg.TicketSigningKey)
+	switch cfg.TicketCode {
+	case config.TicketCodeQR:
+		data.QRCode = ticketcode.Encode(claims, key)
+	case config.TicketCodeBarcode:
+		data.Barcode = ticketcode.ShortCode(claims, key)
+	}
+}
 
-	printer.Justify(escpos.LeftJustify)
+// printVoidSlip prints the "ANULADO" slip with the original ticket data and the void record.
+func (p *PrintService) printVoidSlip(target receipt.Target, tpl *receipt.Template, ticket models.Ticket, void *models.TicketVoid) error {
+	data := receipt.NewTicketData(config.LoadPOSConfig().Company, ticket, 0)
+	data.Void = void
+	return tpl.Render(data, target)
+}
 
-	printer.SelectPrintMode(escpos.Bold)
-	printer.SetCharacterSize(1, 1)
-	printer.Print("Ruta:    ")
+// ReprintTicket prints a copy of a ticket receipt with a "COPIA" banner and records the reprint.
+// The reprint only counts on the report once the copy has printed.
+func (p *PrintService) ReprintTicket(ticketID int64, username string, printerName string) (*models.TicketPrint, error) {
+	tpl, err := loadTemplate(receipt.TicketTemplate)
+	if err != nil {
+		return nil, err
+	}
 
-	printer.SelectPrintMode(escpos.ThinFont)
-	printer.SetCharacterSize(1, 1)
-	printer.Println(escposSafe(ticket.Destination))
+	return p.recordedTicketPrint(ticketID, username, printerName, models.TicketPrintReprint,
+		func(target receipt.Target, ticket models.Ticket, copyNumber int) error {
+			return p.printTicketReceipt(target, tpl, ticket, copyNumber, p.saleDocument(ticket.SaleID))
+		},
+	)
+}
 
-	printer.SelectPrintMode(escpos.Bold)
-	printer.SetCharacterSize(1, 1)
-	printer.Print("Destino: ")
+// PrintVoidSlip prints the "ANULADO" slip of a nullified ticket and records it.
+func (p *PrintService) PrintVoidSlip(ticketID int64, username string, printerName string) (*models.TicketPrint, error) {
+	tpl, err := loadTemplate(receipt.VoidSlipTemplate)
+	if err != nil {
+		return nil, err
+	}
 
-	printer.SelectPrintMode(escpos.ThinFont)
-	printer.SetCharacterSize(1, 1)
-	printer.Println(escposSafe(ticket.Stop))
+	voidRepository := local.NewTicketVoidRepository(p.ctx, p.localDB)
+	void, err := voidRepository.GetByTicketID(ticketID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		zap.L().Error("failed to get ticket void", zap.Error(err))
+		return nil, err
+	}
 
-	printer.SelectPrintMode(escpos.Bold)
-	printer.SetCharacterSize(1, 1)
-	printer.Print("Fecha:   ")
+	return p.recordedTicketPrint(ticketID, username, printerName, models.TicketPrintVoidSlip,
+		func(target receipt.Target, ticket models.Ticket, _ int) error {
+			return p.printVoidSlip(target, tpl, ticket, void)
+		},
+	)
+}
 
-	printer.SelectPrintMode(escpos.ThinFont)
-	printer.SetCharacterSize(1, 1)
-	printer.Println(time.Now().Format("02/01/2006"))
+// recordedTicketPrint records a print of a ticket as pending and commits it, so the copy number is
+// taken, then prints it and marks the record printed or failed in a second transaction. The printer is
+// never driven with the SQLite transaction open.
+func (p *PrintService) recordedTicketPrint(
+	ticketID int64,
+	username string,
+	printerName string,
+	kind string,
+	printFn func(target receipt.Target, ticket models.Ticket, copyNumber int) error,
+) (*models.TicketPrint, error) {
+	if p.localDB == nil {
+		return nil, fmt.Errorf("local database is not available")
+	}
 
-	printer.SelectPrintMode(escpos.Bold)
-	printer.SetCharacterSize(1, 1)
-	printer.Print("Hora:    ")
+	ticketRepository := local.NewTicketRepository(p.ctx, p.localDB)
+	ticket, err := ticketRepository.GetByID(ticketID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, helpers.ErrRowNotFound
+		}
+		zap.L().Error("failed to get ticket", zap.Error(err))
+		return nil, err
+	}
 
-	printer.SelectPrintMode(escpos.ThinFont)
-	printer.SetCharacterSize(1, 1)
-	printer.Println(ticket.Time)
+	if kind == models.TicketPrintVoidSlip && !ticket.IsNull {
+		return nil, helpers.ErrTicketNotNullified
+	}
 
-	printer.SelectPrintMode(escpos.Bold)
-	printer.SetCharacterSize(1, 1)
-	printer.Print("Tarifa:  ")
+	tx, err := p.localDB.BeginTx(p.ctx, nil)
+	if err != nil {
+		zap.L().Error("failed to begin print transaction", zap.Error(err))
+		return nil, err
+	}
 
-	printer.SelectPrintMode(escpos.ThinFont)
-	printer.SetCharacterSize(1, 1)
-	printer.Println(strconv.Itoa(ticket.Fare))
+	repository := local.NewTicketPrintRepository(p.ctx, p.localDB)
+	previous, err := repository.CountByTicketIDTx(tx, ticket.ID, kind)
+	if err != nil {
+		tx.Rollback()
+		zap.L().Error("failed to count ticket prints", zap.Error(err))
+		return nil, err
+	}
 
-	printer.LF()
-	printer.FeedLines(1)
+	record, err := repository.AddTx(tx, models.TicketPrint{
+		TicketID:   ticket.ID,
+		ReportID:   ticket.ReportID,
+		Kind:       kind,
+		CopyNumber: previous + 1,
+		PrintedBy:  username,
+		Status:     models.TicketPrintPending,
+		CreatedAt:  time.Now().Format(time.RFC3339),
+	})
+	if err != nil {
+		tx.Rollback()
+		zap.L().Error("failed to record ticket print", zap.Error(err))
+		return nil, err
+	}
 
-	printer.Justify(escpos.CenterJustify)
-	printer.SetCharacterSize(2, 1)
-	printer.Println("BUEN VIAJE")
+	if err := tx.Commit(); err != nil {
+		zap.L().Error("failed to commit ticket print", zap.Error(err))
+		return nil, err
+	}
 
-	printer.LF()
+	printErr := p.printerSession(printerName, func(target *escposTarget) error {
+		return printFn(target, *ticket, record.CopyNumber)
+	})
+	if printErr != nil {
+		zap.L().Warn("ticket print failed", zap.String("kind", kind), zap.Int64("ticket_id", ticket.ID), zap.Error(printErr))
+		record.Status = models.TicketPrintFailed
+	} else {
+		record.Status = models.TicketPrintPrinted
+	}
 
-	printer.SelectPrintMode(escpos.ThinFont)
-	printer.SetCharacterSize(1, 1)
-	printer.Justify(escpos.RightJustify)
-	printer.Println(fmt.Sprintf("%d", ticket.ID))
+	// The receipt is already out, or not: a record that can't be marked stays pending and out of the
+	// report's counts, which is logged rather than failing a copy that printed
+	if err := p.markTicketPrint(repository, record); err != nil {
+		zap.L().Error("failed to mark ticket print", zap.Int64("print_id", record.ID), zap.String("status", record.Status), zap.Error(err))
+		record.Status = models.TicketPrintPending
+	}
 
-	printer.LF()
-	printer.FeedLines(2)
+	if printErr != nil {
+		return nil, printErr
+	}
+	return record, nil
+}
 
-	return printer.Cut()
+// markTicketPrint stores how a recorded print ended in its own transaction
+func (p *PrintService) markTicketPrint(repository *local.TicketPrintRepository, record *models.TicketPrint) error {
+	tx, err := p.localDB.BeginTx(p.ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := repository.UpdateStatusTx(tx, record.ID, record.Status); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
 }
 
 // PrintTicket prints one ticket receipt (id, departure -> destination, time, fare, type).
 func (p *PrintService) PrintTicket(ticket models.Ticket, printerName string) error {
-	return p.printerSession(printerName, func(printer escpos.Printer) error {
-		return p.printTicketReceipt(printer, ticket)
+	tpl, err := loadTemplate(receipt.TicketTemplate)
+	if err != nil {
+		return err
+	}
+
+	document := p.saleDocument(ticket.SaleID)
+	return p.printerSession(printerName, func(target *escposTarget) error {
+		return p.printTicketReceipt(target, tpl, ticket, 0, document)
 	})
 }
 
+// ensureStillReady checks the printer again before the receipt at index of a batch, after the first, which
+// the session checked. The paper can run out mid-batch, and a printer that stopped drops the receipts sent
+// to it without an error on the connection.
+func (p *PrintService) ensureStillReady(target *escposTarget, index int) error {
+	if index == 0 {
+		return nil
+	}
+	return p.ensurePrinterReady(target.printer, target.address)
+}
+
+// TicketPrintError reports a batch print that did not finish. Printed holds the IDs of the
+// receipts that were fully sent to the printer before the failure, in print order. SaleID is set when
+// the tickets were sold and stored before printing; Voided reports that the sale was then voided, as it
+// is unless the void failed too and the sale must be voided by hand.
+type TicketPrintError struct {
+	SaleID  int64
+	Voided  bool
+	Printed []int64
+	Err     error
+}
+
+func (e *TicketPrintError) Error() string {
+	message := fmt.Sprintf("no tickets printed: %v", e.Err)
+	if len(e.Printed) > 0 {
+		ids := make([]string, len(e.Printed))
+		for i, id := range e.Printed {
+			ids[i] = strconv.FormatInt(id, 10)
+		}
+		message = fmt.Sprintf("printed tickets [%s] before failure: %v", strings.Join(ids, ", "), e.Err)
+	}
+	switch {
+	case e.Voided:
+		return fmt.Sprintf("sale %d voided, %s", e.SaleID, message)
+	case e.SaleID != 0:
+		return fmt.Sprintf("sale %d stored, %s", e.SaleID, message)
+	}
+	return message
+}
+
+func (e *TicketPrintError) Unwrap() error { return e.Err }
+
 // PrintTickets prints multiple tickets in one session (open once, print all, close).
+// On failure it returns a *TicketPrintError listing the receipts already printed.
 func (p *PrintService) PrintTickets(tickets []models.Ticket, printerName string) error {
 	if len(tickets) == 0 {
 		return nil
 	}
 
-	return p.printerSession(printerName, func(printer escpos.Printer) error {
-		for _, ticket := range tickets {
-			if err := p.printTicketReceipt(printer, ticket); err != nil {
-				return err
+	tpl, err := loadTemplate(receipt.TicketTemplate)
+	if err != nil {
+		return &TicketPrintError{Err: err}
+	}
+
+	documents := make([]*models.ElectronicDocument, len(tickets))
+	for i, ticket := range tickets {
+		documents[i] = p.saleDocument(ticket.SaleID)
+	}
+
+	printed := make([]int64, 0, len(tickets))
+	err = p.printerSession(printerName, func(target *escposTarget) error {
+		for i, ticket := range tickets {
+			if err := p.ensureStillReady(target, i); err != nil {
+				return fmt.Errorf("ticket %d: %w", ticket.ID, err)
 			}
+			if err := p.printTicketReceipt(target, tpl, ticket, 0, documents[i]); err != nil {
+				return fmt.Errorf("ticket %d: %w", ticket.ID, err)
+			}
+			printed = append(printed, ticket.ID)
 		}
 		return nil
 	})
+	if err != nil {
+		return &TicketPrintError{Printed: printed, Err: err}
+	}
+
+	return nil
+}
+
+// PrintSale prints the tickets of a sale in one session, followed by the sale summary when the sale has
+// more than one ticket. On failure it returns a *TicketPrintError listing the tickets already printed.
+func (p *PrintService) PrintSale(sale models.Sale, printerName string) error {
+	tpl, err := loadTemplate(receipt.TicketTemplate)
+	if err != nil {
+		return &TicketPrintError{Err: err}
+	}
+
+	var summary *receipt.Template
+	if len(sale.Tickets) > 1 {
+		if summary, err = loadTemplate(receipt.SaleTemplate); err != nil {
+			return &TicketPrintError{Err: err}
+		}
+	}
+
+	printed := make([]int64, 0, len(sale.Tickets))
+	err = p.printerSession(printerName, func(target *escposTarget) error {
+		for i, ticket := range sale.Tickets {
+			if err := p.ensureStillReady(target, i); err != nil {
+				return fmt.Errorf("ticket %d: %w", ticket.ID, err)
+			}
+			if err := p.printTicketReceipt(target, tpl, ticket, 0, sale.Document); err != nil {
+				return fmt.Errorf("ticket %d: %w", ticket.ID, err)
+			}
+			printed = append(printed, ticket.ID)
+		}
+		if summary == nil {
+			return nil
+		}
+		if err := p.ensureStillReady(target, len(sale.Tickets)); err != nil {
+			return fmt.Errorf("sale %d summary: %w", sale.ID, err)
+		}
+		if err := summary.Render(receipt.NewSaleData(config.LoadPOSConfig().Company, sale), target); err != nil {
+			return fmt.Errorf("sale %d summary: %w", sale.ID, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return &TicketPrintError{Printed: printed, Err: err}
+	}
+
+	return nil
+}
+
+// PrintSaleSummary prints the summary receipt of a stored sale, e.g. when the customer asks for it again
+func (p *PrintService) PrintSaleSummary(saleID int64, printerName string) error {
+	if p.localDB == nil {
+		return fmt.Errorf("local database is not available")
+	}
+
+	tpl, err := loadTemplate(receipt.SaleTemplate)
+	if err != nil {
+		return err
+	}
+
+	sale, err := loadSale(p.ctx, p.localDB, saleID)
+	if err != nil {
+		return err
+	}
+
+	return p.printerSession(printerName, func(target *escposTarget) error {
+		return tpl.Render(receipt.NewSaleData(config.LoadPOSConfig().Company, *sale), target)
+	})
 }
 
 // PrintReport prints a report summary receipt.
 func (p *PrintService) PrintReport(report models.Report, printerName string) error {
-	return p.printerSession(printerName, func(printer escpos.Printer) error {
-		if err := printer.Initialize(); err != nil {
-			return err
-		}
+	tpl, err := loadTemplate(receipt.ReportTemplate)
+	if err != nil {
+		return err
+	}
 
-		printer.SelectPrintMode(escpos.ThinFont)
-		printer.SetCharacterSize(1, 1)
-		printer.Justify(escpos.CenterJustify)
-		printer.SetBold(true)
-		printer.Print(fmt.Sprintf("REPORTE %d", report.ID))
-		printer.SetBold(false)
-		printer.LF()
+	data, err := p.reportData(report)
+	if err != nil {
+		return err
+	}
 
-		printer.Justify(escpos.LeftJustify)
-		printer.Println(fmt.Sprintf("Usuario:     %s", report.Username))
+	return p.printerSession(printerName, func(target *escposTarget) error {
+		return tpl.Render(data, target)
+	})
+}
 
-		if report.PartialClosedBy != nil {
-			printer.Println(fmt.Sprintf("Parcial por: %s", *report.PartialClosedBy))
+// reportData gathers the report template data, including the reprint and void slip counts and the
+// fare category totals.
+func (p *PrintService) reportData(report models.Report) (receipt.ReportData, error) {
+	var printCounts models.TicketPrintCounts
+	if p.localDB != nil {
+		counts, err := local.NewTicketPrintRepository(p.ctx, p.localDB).CountByReportID(report.ID)
+		if err != nil {
+			zap.L().Error("failed to count ticket prints", zap.Error(err))
+			return receipt.ReportData{}, err
 		}
+		printCounts = counts
 
-		if report.ClosedBy != nil {
-			printer.Println(fmt.Sprintf("Cerrado por: %s", *report.ClosedBy))
+		if err := loadReportTotals(p.ctx, p.localDB, &report); err != nil {
+			return receipt.ReportData{}, err
 		}
+	}
 
-		printer.Justify(escpos.CenterJustify)
-		printer.Println("--------------------------------")
-		printer.Justify(escpos.LeftJustify)
+	return receipt.NewReportData(config.LoadPOSConfig().Company, report, printCounts), nil
+}
 
-		var timetable string
-		if report.Timetable == enums.Holiday {
-			timetable = "Feriado"
-		} else {
-			timetable = "Regular"
-		}
+// PrintDailySummary prints the Z-report of a business day (YYYY-MM-DD). The first print gives the day the
+// next sequential number and is the only original; every later print is a copy. A print after the day's
+// figures changed raises the Z-report's version. It returns ErrDayReportsOpen while any report of the day
+// isn't totally closed, so no number is taken for figures that can still change.
+//
+// The number and the print are committed before the printer is driven. A print that fails keeps the number,
+// which belongs to the day, and drops its record so the next print takes its copy number.
+func (p *PrintService) PrintDailySummary(date string, username string, printerName string) (*models.ZReportPrint, error) {
+	if p.localDB == nil {
+		return nil, fmt.Errorf("local database is not available")
+	}
 
-		if report.CreatedAt != nil {
-			date, err := time.Parse(time.RFC3339, *report.CreatedAt)
-			if err != nil {
-				return err
-			}
-			printer.Println(fmt.Sprintf("Fecha:   %s", date.Format("02/01/2006 15:04:05")))
-		}
+	tpl, err := loadTemplate(receipt.DailySummaryTemplate)
+	if err != nil {
+		return nil, err
+	}
 
-		if report.PartialClosedAt != nil {
-			date, err := time.Parse(time.RFC3339, *report.PartialClosedAt)
-			if err != nil {
-				return err
-			}
-			printer.Println(fmt.Sprintf("Parcial: %s", date.Format("02/01/2006 15:04:05")))
-		}
+	summary, err := dailySummary(p.ctx, p.localDB, date, false)
+	if err != nil {
+		return nil, err
+	}
+	if summary.OpenReports > 0 {
+		return nil, helpers.ErrDayReportsOpen
+	}
 
-		if report.ClosedAt != nil {
-			date, err := time.Parse(time.RFC3339, *report.ClosedAt)
-			if err != nil {
-				return err
-			}
-			printer.Println(fmt.Sprintf("Cerrado: %s", date.Format("02/01/2006 15:04:05")))
+	fingerprint, err := dailySummaryFingerprint(summary)
+	if err != nil {
+		zap.L().Error("failed to fingerprint daily summary", zap.Error(err))
+		return nil, err
+	}
+
+	now := time.Now().Format(time.RFC3339)
+
+	tx, err := p.localDB.BeginTx(p.ctx, nil)
+	if err != nil {
+		zap.L().Error("failed to begin z report transaction", zap.Error(err))
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	repository := local.NewZReportRepository(p.ctx, p.localDB)
+	zReport, err := repository.GetByDateTx(tx, date)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		number, err := repository.NextNumberTx(tx)
+		if err != nil {
+			zap.L().Error("failed to get next z report number", zap.Error(err))
+			return nil, err
 		}
+		zReport, err = repository.AddTx(tx, models.ZReport{
+			Number:       number,
+			BusinessDate: date,
+			Version:      1,
+			Fingerprint:  fingerprint,
+			PrintedBy:    username,
+			PrintedAt:    now,
+		})
+		if err != nil {
+			zap.L().Error("failed to add z report", zap.Error(err))
+			return nil, err
+		}
+	case err != nil:
+		zap.L().Error("failed to get z report", zap.Error(err))
+		return nil, err
+	case zReport.Fingerprint != fingerprint:
+		zReport.Version++
+		zReport.Fingerprint = fingerprint
+		zReport.PrintedBy = username
+		zReport.PrintedAt = now
+		if err := repository.UpdateTx(tx, *zReport); err != nil {
+			zap.L().Error("failed to update z report", zap.Error(err))
+			return nil, err
+		}
+	}
 
-		printer.Println(fmt.Sprintf("Horario: %s", timetable))
+	previous, err := repository.CountPrintsTx(tx, zReport.ID)
+	if err != nil {
+		zap.L().Error("failed to count z report prints", zap.Error(err))
+		return nil, err
+	}
 
-		printer.Justify(escpos.CenterJustify)
-		printer.Println("--------------------------------")
-		printer.Justify(escpos.LeftJustify)
+	record, err := repository.AddPrintTx(tx, models.ZReportPrint{
+		ZReportID:  zReport.ID,
+		Version:    zReport.Version,
+		CopyNumber: previous,
+		PrintedBy:  username,
+		PrintedAt:  now,
+	})
+	if err != nil {
+		zap.L().Error("failed to record z report print", zap.Error(err))
+		return nil, err
+	}
 
-		printer.Println(fmt.Sprintf("Regulares: %d", report.TotalRegular))
-		printer.Println(fmt.Sprintf("Total:     C %s", strconv.Itoa(report.TotalRegularCash)))
+	if err := tx.Commit(); err != nil {
+		zap.L().Error("failed to commit z report print", zap.Error(err))
+		return nil, err
+	}
 
-		printer.Justify(escpos.CenterJustify)
-		printer.Println("--------------------------------")
-		printer.Justify(escpos.LeftJustify)
-		printer.Println(fmt.Sprintf("Oro:       %d", report.TotalGold))
-		printer.Println(fmt.Sprintf("Total:     C %s", strconv.Itoa(report.TotalGoldCash)))
+	summary.ZReport = zReport
+	data := receipt.NewDailySummaryData(config.LoadPOSConfig().Company, *summary, *zReport, record.CopyNumber)
+	if err := p.printerSession(printerName, func(target *escposTarget) error {
+		return tpl.Render(data, target)
+	}); err != nil {
+		zap.L().Warn("z report print failed", zap.String("date", date), zap.Error(err))
+		if err := p.dropZReportPrint(repository, record.ID); err != nil {
+			zap.L().Error("failed to drop z report print", zap.Int64("print_id", record.ID), zap.Error(err))
+		}
+		return nil, err
+	}
 
-		printer.Justify(escpos.CenterJustify)
-		printer.Println("--------------------------------")
-		printer.Justify(escpos.LeftJustify)
+	return record, nil
+}
 
-		printer.Println(fmt.Sprintf("Anulados:  %d", report.TotalNull))
-		printer.Println(fmt.Sprintf("Total:     C %s", strconv.Itoa(report.TotalNullCash)))
+// dropZReportPrint removes the record of a Z-report print that failed in its own transaction
+func (p *PrintService) dropZReportPrint(repository *local.ZReportRepository, id int64) error {
+	tx, err := p.localDB.BeginTx(p.ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := repository.DeletePrintTx(tx, id); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
 
-		printer.Justify(escpos.CenterJustify)
-		printer.Println("--------------------------------")
-		printer.Println("ENTREGAS")
-		printer.Justify(escpos.LeftJustify)
+// PreviewTemplate renders a receipt template to plain text with sample data, so a layout can be checked
+// before it is deployed. An empty source previews the installed template (or the built-in one).
+func (p *PrintService) PreviewTemplate(name string, source string) (string, error) {
+	var tpl *receipt.Template
+	var err error
+	if strings.TrimSpace(source) == "" {
+		tpl, err = receipt.Load(name)
+	} else {
+		tpl, err = receipt.Parse(name, []byte(source))
+	}
+	if err != nil {
+		return "", err
+	}
+
+	data, err := receipt.SampleData(name, config.LoadPOSConfig().Company)
+	if err != nil {
+		return "", err
+	}
 
-		printer.Println(fmt.Sprintf("Parcial: C %s", strconv.Itoa(report.PartialCash)))
-		printer.Println(fmt.Sprintf("Cierre:  C %s", strconv.Itoa(report.FinalCash)))
-		printer.Println(fmt.Sprintf("Total:   C %s", strconv.Itoa(report.PartialCash+report.FinalCash)))
+	target := receipt.NewTextTarget(tpl.Width)
+	if err := tpl.Render(data, target); err != nil {
+		return "", err
+	}
 
-		printer.Justify(escpos.CenterJustify)
-		printer.Println("--------------------------------")
-		printer.Println("CIERRE")
-		printer.Justify(escpos.LeftJustify)
+	return target.String(), nil
+}
 
-		printer.Println(fmt.Sprintf("Vendidos:   %d", report.PartialTickets+report.FinalTickets))
-		printer.Println(fmt.Sprintf("Total:      C %s", strconv.Itoa(report.PartialCashReceived+report.FinalCashReceived)))
-		printer.Println(fmt.Sprintf("Diferencia: C %s", strconv.Itoa(report.PartialCashReceived+report.FinalCashReceived-report.PartialCash-report.FinalCash)))
+// SetEmulatorStatus scripts the status reported by the printer emulator (cover open, paper end, ...).
+func (p *PrintService) SetEmulatorStatus(status emulator.Status) error {
+	if p.emulator == nil {
+		return helpers.ErrPrinterEmulatorDisabled
+	}
+	p.emulator.SetStatus(status)
+	return nil
+}
 
-		printer.LF()
-		printer.FeedLines(4)
+// GetEmulatorReceipts returns the plain-text transcripts of the receipts printed on the emulator.
+func (p *PrintService) GetEmulatorReceipts() ([]string, error) {
+	if p.emulator == nil {
+		return nil, helpers.ErrPrinterEmulatorDisabled
+	}
+	return p.emulator.Receipts(), nil
+}
 
-		return printer.Cut()
-	})
+// ResetEmulator clears the receipts printed on the emulator.
+func (p *PrintService) ResetEmulator() error {
+	if p.emulator == nil {
+		return helpers.ErrPrinterEmulatorDisabled
+	}
+	p.emulator.Reset()
+	return nil
 }
 
 // Startup is a no-op for PrintService (required by Wails bindings if needed).
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"io"
+
+	"github.com/DevLumuz/go-escpos"
+
+	"neon/core/receipt"
+)
+
+// qrModuleSize is the QR module size in dots; 6 keeps a signed ticket payload readable on 58mm paper
+const qrModuleSize = 6
+
+// escposTarget renders receipt templates on an ESC/POS printer. Barcodes and QR codes are sent as raw
+// GS k / GS ( k commands on the printer connection.
+type escposTarget struct {
+	printer escpos.Printer
+	raw     io.Writer
+	address string
+}
+
+// Initialize resets the printer formatting
+func (t *escposTarget) Initialize() error {
+	return t.printer.Initialize()
+}
+
+// Justify sets the alignment of the following lines
+func (t *escposTarget) Justify(justification receipt.Justification) {
+	switch justification {
+	case receipt.Center:
+		t.printer.Justify(escpos.CenterJustify)
+	case receipt.Right:
+		t.printer.Justify(escpos.RightJustify)
+	default:
+		t.printer.Justify(escpos.LeftJustify)
+	}
+}
+
+// Size sets the character size; thermal receipts only use the single and double sizes
+func (t *escposTarget) Size(width int, height int) {
+	switch {
+	case width >= 2 && height >= 2:
+		t.printer.SetCharacterSize(2, 2)
+	case width >= 2:
+		t.printer.SetCharacterSize(2, 1)
+	case height >= 2:
+		t.printer.SetCharacterSize(1, 2)
+	default:
+		t.printer.SetCharacterSize(1, 1)
+	}
+}
+
+// Style selects the print mode
+func (t *escposTarget) Style(style receipt.Style) {
+	switch style {
+	case receipt.StyleBold:
+		t.printer.SelectPrintMode(escpos.Bold)
+	case receipt.StyleUnderline:
+		t.printer.SelectPrintMode(escpos.Underline)
+	default:
+		t.printer.SelectPrintMode(escpos.ThinFont)
+	}
+}
+
+// Bold toggles emphasized printing
+func (t *escposTarget) Bold(bold bool) {
+	t.printer.SetBold(bold)
+}
+
+// Print writes text without ending the line
+func (t *escposTarget) Print(text string) {
+	t.printer.Print(escposSafe(text))
+}
+
+// Println writes text and ends the line
+func (t *escposTarget) Println(text string) {
+	t.printer.Println(escposSafe(text))
+}
+
+// Feed prints empty lines
+func (t *escposTarget) Feed(lines int) {
+	for i := 0; i < lines; i++ {
+		t.printer.LF()
+	}
+}
+
+// Barcode prints a CODE128 barcode with its human readable text below
+func (t *escposTarget) Barcode(code string) error {
+	data := append([]byte("{B"), code...)
+	if len(data) > 255 {
+		data = data[:255]
+	}
+
+	command := []byte{
+		0x1d, 'h', 80, // height in dots
+		0x1d, 'w', 2, // module width
+		0x1d, 'H', 2, // HRI below the barcode
+		0x1d, 'k', 73, byte(len(data)),
+	}
+	command = append(command, data...)
+	command = append(command, '\n')
+
+	_, err := t.raw.Write(command)
+	return err
+}
+
+// QR prints a model 2 QR code with medium error correction
+func (t *escposTarget) QR(code string) error {
+	size := len(code) + 3
+	command := []byte{
+		0x1d, '(', 'k', 4, 0, 49, 65, 50, 0, // model 2
+		0x1d, '(', 'k', 3, 0, 49, 67, qrModuleSize, // module size
+		0x1d, '(', 'k', 3, 0, 49, 69, 49, // error correction M
+		0x1d, '(', 'k', byte(size), byte(size >> 8), 49, 80, 48, // store data
+	}
+	command = append(command, code...)
+	command = append(command,
+		0x1d, '(', 'k', 3, 0, 49, 81, 48, // print
+		'\n',
+	)
+
+	_, err := t.raw.Write(command)
+	return err
+}
+
+// Cut cuts the paper
+func (t *escposTarget) Cut() error {
+	return t.printer.Cut()
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"errors"
+	"neon/core/constants"
+	"neon/core/emulator"
+	"neon/core/helpers"
+	"neon/core/models"
+	"neon/core/repositories/local"
+	"testing"
+	"time"
+)
+
+func TestReprintTicket(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       emulator.Status
+		wantErr      bool
+		wantStatus   string
+		wantReprints int
+	}{
+		{"printed", emulator.Status{}, false, models.TicketPrintPrinted, 1},
+		{"printer out of paper", emulator.Status{PaperEnd: true}, true, models.TicketPrintFailed, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tickets, report := testSaleService(t, tt.status)
+
+			sale, err := tickets.AddSale(testSaleRequest(report.ID, nil))
+			if err != nil {
+				t.Fatalf("AddSale() error = %v", err)
+			}
+			ticketID := sale.Tickets[0].ID
+
+			record, err := tickets.printService.ReprintTicket(ticketID, "cajero", "")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReprintTicket() error = %v, want error %v", err, tt.wantErr)
+			}
+			if err == nil && (record.Status != tt.wantStatus || record.CopyNumber != 1) {
+				t.Errorf("ReprintTicket() = %+v, want copy 1 %s", record, tt.wantStatus)
+			}
+
+			var status string
+			if err := tickets.localDB.GetDB().QueryRow(
+				"SELECT status FROM ticket_prints WHERE ticket_id = ?", ticketID,
+			).Scan(&status); err != nil {
+				t.Fatalf("failed to load ticket print: %v", err)
+			}
+			if status != tt.wantStatus {
+				t.Errorf("stored print status = %s, want %s", status, tt.wantStatus)
+			}
+
+			counts, err := local.NewTicketPrintRepository(tickets.ctx, tickets.localDB).CountByReportID(report.ID)
+			if err != nil {
+				t.Fatalf("failed to count ticket prints: %v", err)
+			}
+			if counts.Reprints != tt.wantReprints {
+				t.Errorf("report reprints = %d, want %d", counts.Reprints, tt.wantReprints)
+			}
+		})
+	}
+}
+
+func TestPrintDailySummary(t *testing.T) {
+	tests := []struct {
+		name string
+		// closed is whether the day's report is totally closed before the print
+		closed  bool
+		status  emulator.Status
+		wantErr bool
+		// wantNumber is the Z-report number the day holds after the print, 0 for none
+		wantNumber int
+		wantPrints int
+	}{
+		{"printed", true, emulator.Status{}, false, 1, 1},
+		{"report still open", false, emulator.Status{}, true, 0, 0},
+		{"printer out of paper", true, emulator.Status{PaperEnd: true}, true, 1, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tickets, report := testSaleService(t, tt.status)
+			date := time.Now().Format(constants.DateLayout)
+
+			if tt.closed {
+				if _, err := tickets.localDB.GetDB().Exec(
+					"UPDATE reports SET status = 0, closed_at = ? WHERE id = ?", time.Now().Format(time.RFC3339), report.ID,
+				); err != nil {
+					t.Fatalf("failed to close report: %v", err)
+				}
+			}
+
+			record, err := tickets.printService.PrintDailySummary(date, "admin", "")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PrintDailySummary() error = %v, want error %v", err, tt.wantErr)
+			}
+			if errors.Is(err, helpers.ErrDayReportsOpen) == tt.closed {
+				t.Errorf("PrintDailySummary() error = %v, want %v only while the report is open", err, helpers.ErrDayReportsOpen)
+			}
+			if err == nil && record.CopyNumber != 0 {
+				t.Errorf("PrintDailySummary() copy = %d, want the original", record.CopyNumber)
+			}
+
+			var number, prints int
+			if err := tickets.localDB.GetDB().QueryRow(
+				"SELECT COALESCE(MAX(number), 0), (SELECT COUNT(*) FROM z_report_prints) FROM z_reports WHERE business_date = ?", date,
+			).Scan(&number, &prints); err != nil {
+				t.Fatalf("failed to load z report: %v", err)
+			}
+			if number != tt.wantNumber || prints != tt.wantPrints {
+				t.Errorf("z report number, prints = %d, %d, want %d, %d", number, prints, tt.wantNumber, tt.wantPrints)
+			}
+		})
+	}
+}
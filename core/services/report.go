@@ -12,6 +12,7 @@ import (
 	"neon/core/models"
 	"neon/core/repositories/local"
 	"neon/core/repositories/remote"
+	"strings"
 	"time"
 
 	"go.uber.org/zap"
@@ -19,13 +20,14 @@ import (
 
 // ReportService is a service for reports
 type ReportService struct {
-	ctx     context.Context
-	localDB *embedded.SQLite
+	ctx         context.Context
+	localDB     *embedded.SQLite
+	authService *AuthService
 }
 
 // NewReportService creates a new report service
-func NewReportService(localDB *embedded.SQLite) *ReportService {
-	return &ReportService{localDB: localDB}
+func NewReportService(localDB *embedded.SQLite, authService *AuthService) *ReportService {
+	return &ReportService{localDB: localDB, authService: authService}
 }
 
 // startup starts the report service
@@ -133,29 +135,125 @@ func (r *ReportService) SyncPendingReportsToRemote(ctx context.Context) (int, er
 	return synced, nil
 }
 
-// StartReport starts a new report
+// StartReport starts a new report without an opening float
 func (r *ReportService) StartReport(username string, timetable string) (*models.Report, error) {
-	repository := local.NewReportRepository(r.ctx, r.localDB)
+	return r.StartReportWithFloat(models.ReportStartRequest{Username: username, Timetable: timetable})
+}
 
+// StartReportWithFloat starts a new report with the change the cashier starts with, given as an amount or
+// as the bills and coins counted (stored like the closes' counts). The float is expected back in the
+// drawer at the total close. When the installation requires it, an admin confirms a float above zero.
+func (r *ReportService) StartReportWithFloat(request models.ReportStartRequest) (*models.Report, error) {
 	now := time.Now().Format(time.RFC3339)
+
+	cashConfig := config.LoadPOSConfig().Cash
+	if cashConfig.BlindClose {
+		if err := r.checkNoRecountPending(); err != nil {
+			return nil, err
+		}
+	}
+
+	float := request.OpeningFloat
+	var counts []models.CashCount
+	if request.OpeningCount != nil {
+		var err error
+		if counts, float, err = cashCount(0, enums.CountOpening, request.OpeningCount, request.Username, now); err != nil {
+			return nil, err
+		}
+	}
+	if float < 0 {
+		return nil, helpers.ErrInvalidOpeningFloat
+	}
+
 	report := models.Report{
-		Username:  username,
-		Timetable: enums.Timetable(timetable),
-		Status:    true,
-		CreatedAt: &now,
+		Username:     request.Username,
+		Timetable:    enums.Timetable(request.Timetable),
+		Status:       true,
+		CreatedAt:    &now,
+		OpeningFloat: float,
+	}
+
+	if float > 0 && cashConfig.FloatApproval {
+		approver, err := r.approve(request.ApproverUsername, request.ApproverPassword, helpers.ErrFloatApprovalRequired, helpers.ErrFloatApproverNotAdmin)
+		if err != nil {
+			return nil, err
+		}
+		report.FloatConfirmedBy = &approver.Username
+	}
+
+	tx, err := r.localDB.BeginTx(r.ctx, nil)
+	if err != nil {
+		zap.L().Error("failed to begin report start transaction", zap.Error(err))
+		return nil, err
 	}
+	defer tx.Rollback()
 
-	output, err := repository.Add(report)
+	output, err := local.NewReportRepository(r.ctx, r.localDB).AddTx(tx, report)
 	if err != nil {
 		zap.L().Error("failed to add report", zap.Error(err))
 		return nil, err
 	}
 
+	for i := range counts {
+		counts[i].ReportID = output.ID
+	}
+	if err := local.NewReportCashCountRepository(r.ctx, r.localDB).ReplaceTx(tx, output.ID, enums.CountOpening, counts); err != nil {
+		zap.L().Error("failed to store opening float count", zap.Error(err))
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		zap.L().Error("failed to commit report start", zap.Error(err))
+		return nil, err
+	}
+
+	if err := loadReportTotals(r.ctx, r.localDB, output); err != nil {
+		return nil, err
+	}
+
 	return output, nil
 }
 
-// CheckIfThereIsAnOpenOrPendingReport checks if a report is open or pending (cash not verified)
+// approve checks the credentials of the admin approving an operation, returning required when they are
+// missing and notAdmin when the user isn't an admin
+func (r *ReportService) approve(username string, password string, required error, notAdmin error) (*models.User, error) {
+	if r.authService == nil || username == "" || password == "" {
+		return nil, required
+	}
+
+	approver, err := r.authService.Login(username, password)
+	if err != nil {
+		return nil, err
+	}
+
+	if enums.Role(approver.Role) != enums.Admin {
+		return nil, notAdmin
+	}
+
+	return approver, nil
+}
+
+// checkNoRecountPending fails with ErrRecountRequired while a report waits for an admin recount
+func (r *ReportService) checkNoRecountPending() error {
+	_, err := local.NewReportRepository(r.ctx, r.localDB).GetAwaitingRecount()
+	if err == nil {
+		return helpers.ErrRecountRequired
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		zap.L().Error("failed to get report awaiting recount", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// CheckIfThereIsAnOpenOrPendingReport checks if a report is open or pending (cash not verified). When the
+// installation closes blind the report carries the expected cash, so the cashier gets GetCashierReport
+// instead.
 func (r *ReportService) CheckIfThereIsAnOpenOrPendingReport() (*models.Report, error) {
+	if config.LoadPOSConfig().Cash.BlindClose {
+		return nil, helpers.ErrBlindClose
+	}
+
 	repository := local.NewReportRepository(r.ctx, r.localDB)
 
 	report, err := repository.GetOpenOrPendingReport()
@@ -171,47 +269,178 @@ func (r *ReportService) CheckIfThereIsAnOpenOrPendingReport() (*models.Report, e
 		return nil, nil
 	}
 
+	if err := loadReportTotals(r.ctx, r.localDB, report); err != nil {
+		return nil, err
+	}
+
 	return report, nil
 }
 
-// PartialCloseReport closes a report partially (records cash counted, ticket count, and who closed)
-func (r *ReportService) PartialCloseReport(
-	reportID int64,
-	cash int,
-	closedByUsername *string,
-) (*models.Report, error) {
+// GetCashierReport gets the open report, or else the report waiting for an admin recount, without the cash
+// expected at its closes, for the cashier to count the drawer blind
+func (r *ReportService) GetCashierReport() (*models.CashierReport, error) {
 	repository := local.NewReportRepository(r.ctx, r.localDB)
 
-	report, err := repository.GetByID(reportID)
+	report, err := repository.GetOpenOrPendingReport()
+	if errors.Is(err, sql.ErrNoRows) {
+		report, err = repository.GetAwaitingRecount()
+	}
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, helpers.ErrRowNotFound
 		}
-		zap.L().Error("failed to get report", zap.Error(err))
+		zap.L().Error("failed to get cashier report", zap.Error(err))
 		return nil, err
 	}
 
-	now := time.Now().Format(time.RFC3339)
-	report.PartialClosedAt = &now
-	report.PartialCashReceived = cash
-	report.PartialClosedBy = closedByUsername
-
-	if err := repository.Update(*report); err != nil {
-		zap.L().Error("failed to update report", zap.Error(err))
+	if err := loadReportTotals(r.ctx, r.localDB, report); err != nil {
 		return nil, err
 	}
 
-	r.trySyncAfterClose(report)
+	return cashierReport(report), nil
+}
 
-	return report, nil
+// cashierReport leaves out of a report the cash and tickets expected at its closes
+func cashierReport(report *models.Report) *models.CashierReport {
+	return &models.CashierReport{
+		ID:                  report.ID,
+		Username:            report.Username,
+		Timetable:           report.Timetable,
+		Status:              report.Status,
+		CreatedAt:           report.CreatedAt,
+		OpeningFloat:        report.OpeningFloat,
+		OpeningCount:        report.OpeningCount,
+		PartialClosedAt:     report.PartialClosedAt,
+		PartialClosedBy:     report.PartialClosedBy,
+		PartialCashReceived: report.PartialCashReceived,
+		PartialCount:        report.PartialCount,
+		PartialVariance:     report.PartialVariance,
+		ClosedAt:            report.ClosedAt,
+		ClosedBy:            report.ClosedBy,
+		FinalCashReceived:   report.FinalCashReceived,
+		FinalCount:          report.FinalCount,
+		FinalVariance:       report.FinalVariance,
+		PendingRecount:      report.PendingRecount,
+	}
+}
+
+// reportSettled reports whether a report is totally closed with no recount pending, so its expected cash
+// can be shown
+func reportSettled(report *models.Report) bool {
+	return !report.Status && report.ClosedAt != nil && report.PendingRecount == ""
 }
 
-// TotalCloseReport closes a report totally (records final cash counted and who closed)
+// blindReport is a report as a cashier may see it: when the installation closes blind and the report isn't
+// settled yet, only what its CashierReport carries, plus who sells on it and its drops' deliveries
+func blindReport(report *models.Report) *models.Report {
+	if report == nil || reportSettled(report) || !config.LoadPOSConfig().Cash.BlindClose {
+		return report
+	}
+
+	cashier := cashierReport(report)
+	drops := make([]models.ReportDrop, 0, len(report.Drops))
+	for _, drop := range report.Drops {
+		drops = append(drops, models.ReportDrop{
+			ID:           drop.ID,
+			ReportID:     drop.ReportID,
+			Sequence:     drop.Sequence,
+			DroppedAt:    drop.DroppedAt,
+			DroppedBy:    drop.DroppedBy,
+			CashReceived: drop.CashReceived,
+			Count:        drop.Count,
+		})
+	}
+
+	return &models.Report{
+		ID:                  cashier.ID,
+		Username:            cashier.Username,
+		Timetable:           cashier.Timetable,
+		Status:              cashier.Status,
+		CreatedAt:           cashier.CreatedAt,
+		OpeningFloat:        cashier.OpeningFloat,
+		OpeningCount:        cashier.OpeningCount,
+		PartialClosedAt:     cashier.PartialClosedAt,
+		PartialClosedBy:     cashier.PartialClosedBy,
+		PartialCashReceived: cashier.PartialCashReceived,
+		PartialCount:        cashier.PartialCount,
+		PartialVariance:     cashier.PartialVariance,
+		ClosedAt:            cashier.ClosedAt,
+		ClosedBy:            cashier.ClosedBy,
+		FinalCashReceived:   cashier.FinalCashReceived,
+		FinalCount:          cashier.FinalCount,
+		FinalVariance:       cashier.FinalVariance,
+		PendingRecount:      cashier.PendingRecount,
+		FloatConfirmedBy:    report.FloatConfirmedBy,
+		RemoteSynced:        report.RemoteSynced,
+		Cashier:             report.Cashier,
+		FareTotals:          []models.ReportFareTotal{},
+		PaymentTotals:       []models.ReportPaymentTotal{},
+		Recounts:            []models.ReportRecount{},
+		Segments:            []models.ReportSegment{},
+		Drops:               drops,
+	}
+}
+
+// blindReports replaces each report with blindReport's
+func blindReports(reports []*models.Report) {
+	for i, report := range reports {
+		reports[i] = blindReport(report)
+	}
+}
+
+// TotalCloseReport closes a report totally (records final cash counted and who closed). As at the drops,
+// only cash counts toward the drawer (FinalDrawer).
 func (r *ReportService) TotalCloseReport(
 	reportID int64,
 	cash int,
 	closedByUsername *string,
 ) (*models.Report, error) {
+	return r.closeReport(reportID, cash, nil, closedByUsername)
+}
+
+// TotalCloseReportWithCount closes a report totally with the bills and coins counted in the drawer, as
+// PartialCloseReportWithCount does
+func (r *ReportService) TotalCloseReportWithCount(
+	reportID int64,
+	counts []models.CashCount,
+	closedByUsername *string,
+) (*models.Report, error) {
+	if counts == nil {
+		counts = []models.CashCount{}
+	}
+	return r.closeReport(reportID, 0, counts, closedByUsername)
+}
+
+// closeReport records the total close. With counts (non-nil) the cash received is the total of the bills
+// and coins counted, stored with the close; otherwise it is cash, and an earlier count of the close is
+// dropped. When the installation closes blind the close is counted once, and a variance above the recount
+// threshold holds the report until an admin recounts it.
+func (r *ReportService) closeReport(
+	reportID int64,
+	cash int,
+	counts []models.CashCount,
+	closedByUsername *string,
+) (*models.Report, error) {
+	stage := enums.CountFinal
+	now := time.Now().Format(time.RFC3339)
+
+	countedBy := ""
+	if closedByUsername != nil {
+		countedBy = *closedByUsername
+	}
+
+	if counts != nil {
+		var err error
+		if counts, cash, err = cashCount(reportID, stage, counts, countedBy, now); err != nil {
+			return nil, err
+		}
+	}
+
+	// The drawer can't be closed while it waits for the incoming cashier of a handover
+	if _, err := reportCashier(r.ctx, r.localDB, reportID); err != nil {
+		return nil, err
+	}
+
 	repository := local.NewReportRepository(r.ctx, r.localDB)
 
 	report, err := repository.GetByID(reportID)
@@ -223,22 +452,267 @@ func (r *ReportService) TotalCloseReport(
 		return nil, err
 	}
 
-	now := time.Now().Format(time.RFC3339)
+	cashConfig := config.LoadPOSConfig().Cash
+	if cashConfig.BlindClose {
+		if report.PendingRecount != "" || closeRecorded(report, stage) {
+			return nil, helpers.ErrRecountRequired
+		}
+		if err := loadReportTotals(r.ctx, r.localDB, report); err != nil {
+			return nil, err
+		}
+	}
+
 	report.ClosedAt = &now
 	report.Status = false
 	report.FinalCashReceived = cash
 	report.ClosedBy = closedByUsername
 
-	if err := repository.Update(*report); err != nil {
+	if cashConfig.BlindClose {
+		if variance := cash - closeExpected(report, stage); variance > cashConfig.RecountThreshold || -variance > cashConfig.RecountThreshold {
+			report.PendingRecount = stage
+		}
+	}
+
+	tx, err := r.localDB.BeginTx(r.ctx, nil)
+	if err != nil {
+		zap.L().Error("failed to begin report close transaction", zap.Error(err))
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := repository.UpdateTx(tx, *report); err != nil {
 		zap.L().Error("failed to update report", zap.Error(err))
 		return nil, err
 	}
 
+	if err := local.NewReportCashCountRepository(r.ctx, r.localDB).ReplaceTx(tx, reportID, stage, counts); err != nil {
+		zap.L().Error("failed to store report cash count", zap.Error(err))
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		zap.L().Error("failed to commit report close", zap.Error(err))
+		return nil, err
+	}
+
 	r.trySyncAfterClose(report)
 
+	if err := loadReportTotals(r.ctx, r.localDB, report); err != nil {
+		return nil, err
+	}
+
+	return blindReport(report), nil
+}
+
+// RecountReport records an admin's recount of the drawer at a recorded close, which replaces the cash
+// received at that close and releases the report when the close was waiting for it. The partial stage
+// recounts the report's latest drop. Every recount is kept with its reason.
+func (r *ReportService) RecountReport(request models.ReportRecountRequest) (*models.Report, error) {
+	reason := strings.TrimSpace(request.Reason)
+	if reason == "" {
+		return nil, helpers.ErrRecountReasonRequired
+	}
+	if request.Stage != enums.CountPartial && request.Stage != enums.CountFinal {
+		return nil, helpers.ErrInvalidRecount
+	}
+
+	approver, err := r.approve(request.ApproverUsername, request.ApproverPassword, helpers.ErrRecountApprovalRequired, helpers.ErrRecountApproverNotAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Format(time.RFC3339)
+
+	cash := request.Cash
+	var counts []models.CashCount
+	if request.Counts != nil {
+		if counts, cash, err = cashCount(request.ReportID, request.Stage, request.Counts, approver.Username, now); err != nil {
+			return nil, err
+		}
+	}
+	if cash < 0 {
+		return nil, helpers.ErrInvalidRecount
+	}
+
+	repository := local.NewReportRepository(r.ctx, r.localDB)
+
+	report, err := repository.GetByID(request.ReportID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, helpers.ErrRowNotFound
+		}
+		zap.L().Error("failed to get report", zap.Error(err))
+		return nil, err
+	}
+	if !closeRecorded(report, request.Stage) {
+		return nil, helpers.ErrInvalidRecount
+	}
+
+	if err := loadReportTotals(r.ctx, r.localDB, report); err != nil {
+		return nil, err
+	}
+
+	// The partial stage is the latest drop, whose cash received is part of the report's
+	expected := closeExpected(report, request.Stage)
+	var drop *models.ReportDrop
+	if request.Stage == enums.CountPartial {
+		if drop, err = local.NewReportDropRepository(r.ctx, r.localDB).GetLatest(report.ID); err != nil {
+			zap.L().Error("failed to get report drop", zap.Error(err))
+			return nil, err
+		}
+		expected = drop.Drawer
+	}
+
+	recount := models.ReportRecount{
+		ReportID:    report.ID,
+		Stage:       request.Stage,
+		Expected:    expected,
+		Received:    cash,
+		Variance:    cash - expected,
+		Reason:      reason,
+		RecountedBy: approver.Username,
+		RecountedAt: now,
+	}
+	if drop != nil {
+		recount.PreviousReceived = drop.CashReceived
+		report.PartialCashReceived += cash - drop.CashReceived
+	} else {
+		recount.PreviousReceived = report.FinalCashReceived
+		report.FinalCashReceived = cash
+	}
+	if report.PendingRecount == request.Stage {
+		report.PendingRecount = ""
+	}
+
+	tx, err := r.localDB.BeginTx(r.ctx, nil)
+	if err != nil {
+		zap.L().Error("failed to begin report recount transaction", zap.Error(err))
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := repository.UpdateTx(tx, *report); err != nil {
+		zap.L().Error("failed to update report", zap.Error(err))
+		return nil, err
+	}
+
+	if drop != nil {
+		if err := local.NewReportDropRepository(r.ctx, r.localDB).RecountTx(tx, drop.ID, cash, counts); err != nil {
+			zap.L().Error("failed to store report drop recount", zap.Error(err))
+			return nil, err
+		}
+	} else if err := local.NewReportCashCountRepository(r.ctx, r.localDB).ReplaceTx(tx, report.ID, request.Stage, counts); err != nil {
+		zap.L().Error("failed to store report cash count", zap.Error(err))
+		return nil, err
+	}
+
+	if _, err := local.NewReportRecountRepository(r.ctx, r.localDB).AddTx(tx, recount); err != nil {
+		zap.L().Error("failed to store report recount", zap.Error(err))
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		zap.L().Error("failed to commit report recount", zap.Error(err))
+		return nil, err
+	}
+
+	// A closed report was already synced with the cash received before the recount
+	if report.ClosedAt != nil {
+		r.trySyncAfterClose(report)
+	}
+
+	if err := loadReportTotals(r.ctx, r.localDB, report); err != nil {
+		return nil, err
+	}
+
 	return report, nil
 }
 
+// closeRecorded reports whether a report has a drop, for the partial stage, or its total close recorded
+func closeRecorded(report *models.Report, stage enums.CashCountStage) bool {
+	if stage == enums.CountPartial {
+		return report.PartialClosedAt != nil
+	}
+	return report.ClosedAt != nil
+}
+
+// closeExpected is the cash expected in the drawer at a report's drops or total close: the cash taken,
+// plus the opening float handed back at the total close. The report's totals must be loaded.
+func closeExpected(report *models.Report, stage enums.CashCountStage) int {
+	if stage == enums.CountPartial {
+		return report.PartialDrawer
+	}
+	return report.FinalDrawer + report.OpeningFloat
+}
+
+// cashCount checks the bills and coins counted at a close and returns them from the largest denomination
+// down, without the empty ones, with their amounts and total
+func cashCount(reportID int64, stage enums.CashCountStage, counts []models.CashCount, countedBy string, countedAt string) ([]models.CashCount, int, error) {
+	quantities := map[enums.Denomination]int{}
+	for _, count := range counts {
+		if !count.Denomination.IsValid() || count.Quantity < 0 {
+			return nil, 0, helpers.ErrInvalidCashCount
+		}
+		if _, ok := quantities[count.Denomination]; ok {
+			return nil, 0, helpers.ErrInvalidCashCount
+		}
+		quantities[count.Denomination] = count.Quantity
+	}
+
+	rows := []models.CashCount{}
+	total := 0
+	for _, denomination := range enums.AllDenominations {
+		quantity := quantities[denomination.Value]
+		if quantity == 0 {
+			continue
+		}
+
+		amount := quantity * int(denomination.Value)
+		total += amount
+		rows = append(rows, models.CashCount{
+			ReportID:     reportID,
+			Stage:        stage,
+			Denomination: denomination.Value,
+			Quantity:     quantity,
+			Amount:       amount,
+			CountedBy:    countedBy,
+			CountedAt:    countedAt,
+		})
+	}
+
+	return rows, total, nil
+}
+
+// dropCounts adds up the bills and coins counted at a report's drops per denomination, from the largest
+// denomination down
+func dropCounts(drops []models.ReportDrop) []models.CashCount {
+	totals := map[enums.Denomination]models.CashCount{}
+	for _, drop := range drops {
+		for _, count := range drop.Count {
+			total := totals[count.Denomination]
+			total.Quantity += count.Quantity
+			total.Amount += count.Amount
+			total.CountedBy = count.CountedBy
+			total.CountedAt = count.CountedAt
+			totals[count.Denomination] = total
+		}
+	}
+
+	counts := []models.CashCount{}
+	for _, denomination := range enums.AllDenominations {
+		total, ok := totals[denomination.Value]
+		if !ok {
+			continue
+		}
+		total.ReportID = drops[0].ReportID
+		total.Stage = enums.CountPartial
+		total.Denomination = denomination.Value
+		counts = append(counts, total)
+	}
+
+	return counts
+}
+
 // GetLatestReportsByUsername gets the latest 5 closed reports for a specific user
 func (r *ReportService) GetLatestReportsByUsername(username string) ([]*models.Report, error) {
 	repository := local.NewReportRepository(r.ctx, r.localDB)
@@ -249,5 +723,121 @@ func (r *ReportService) GetLatestReportsByUsername(username string) ([]*models.R
 		return nil, err
 	}
 
+	if err := loadReportTotals(r.ctx, r.localDB, reports...); err != nil {
+		return nil, err
+	}
+
+	blindReports(reports)
+
 	return reports, nil
 }
+
+// loadReportTotals fills in the fare category and payment method totals of reports, the cash expected in
+// their drawer, their drops, the bills and coins counted at their start, drops and total close, the closes'
+// variances and recounts, and their cashiers' shifts
+func loadReportTotals(ctx context.Context, localDB *embedded.SQLite, reports ...*models.Report) error {
+	ids := make([]int64, 0, len(reports))
+	for _, report := range reports {
+		if report != nil {
+			ids = append(ids, report.ID)
+		}
+	}
+
+	totals, err := local.NewReportFareTotalRepository(ctx, localDB).GetByReportIDs(ids)
+	if err != nil {
+		zap.L().Error("failed to get report fare totals", zap.Error(err))
+		return err
+	}
+
+	paymentTotals, err := local.NewReportPaymentTotalRepository(ctx, localDB).GetByReportIDs(ids)
+	if err != nil {
+		zap.L().Error("failed to get report payment totals", zap.Error(err))
+		return err
+	}
+
+	cashCounts, err := local.NewReportCashCountRepository(ctx, localDB).GetByReportIDs(ids)
+	if err != nil {
+		zap.L().Error("failed to get report cash counts", zap.Error(err))
+		return err
+	}
+
+	recounts, err := local.NewReportRecountRepository(ctx, localDB).GetByReportIDs(ids)
+	if err != nil {
+		zap.L().Error("failed to get report recounts", zap.Error(err))
+		return err
+	}
+
+	handovers, err := local.NewReportHandoverRepository(ctx, localDB).GetByReportIDs(ids)
+	if err != nil {
+		zap.L().Error("failed to get report handovers", zap.Error(err))
+		return err
+	}
+
+	drops, err := local.NewReportDropRepository(ctx, localDB).GetByReportIDs(ids)
+	if err != nil {
+		zap.L().Error("failed to get report drops", zap.Error(err))
+		return err
+	}
+
+	for _, report := range reports {
+		if report == nil {
+			continue
+		}
+		report.FareTotals = totals[report.ID]
+		if report.FareTotals == nil {
+			report.FareTotals = []models.ReportFareTotal{}
+		}
+
+		report.PaymentTotals = paymentTotals[report.ID]
+		if report.PaymentTotals == nil {
+			report.PaymentTotals = []models.ReportPaymentTotal{}
+		}
+
+		// Only cash goes into the drawer; card and SINPE Móvil payments are reconciled with the bank
+		report.PartialDrawer = 0
+		report.FinalDrawer = 0
+		for _, total := range report.PaymentTotals {
+			if total.Method == enums.PaymentCash {
+				report.PartialDrawer = total.PartialAmount
+				report.FinalDrawer = total.FinalAmount
+			}
+		}
+
+		report.Drops = drops[report.ID]
+		if report.Drops == nil {
+			report.Drops = []models.ReportDrop{}
+		}
+
+		report.OpeningCount = []models.CashCount{}
+		report.PartialCount = dropCounts(report.Drops)
+		report.FinalCount = []models.CashCount{}
+		for _, count := range cashCounts[report.ID] {
+			switch count.Stage {
+			case enums.CountOpening:
+				report.OpeningCount = append(report.OpeningCount, count)
+			case enums.CountFinal:
+				report.FinalCount = append(report.FinalCount, count)
+			}
+		}
+
+		report.PartialVariance = nil
+		report.FinalVariance = nil
+		if closeRecorded(report, enums.CountPartial) {
+			variance := report.PartialCashReceived - closeExpected(report, enums.CountPartial)
+			report.PartialVariance = &variance
+		}
+		if closeRecorded(report, enums.CountFinal) {
+			variance := report.FinalCashReceived - closeExpected(report, enums.CountFinal)
+			report.FinalVariance = &variance
+		}
+
+		report.Recounts = recounts[report.ID]
+		if report.Recounts == nil {
+			report.Recounts = []models.ReportRecount{}
+		}
+
+		report.Segments, report.Cashier = reportSegments(report, handovers[report.ID])
+	}
+
+	return nil
+}
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"errors"
+	"neon/core/constants"
+	"neon/core/emulator"
+	"neon/core/helpers"
+	"neon/core/helpers/enums"
+	"neon/core/models"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestCashCount(t *testing.T) {
+	const countedAt = "2026-03-09T18:00:00Z"
+	row := func(denomination enums.Denomination, quantity int) models.CashCount {
+		return models.CashCount{
+			ReportID:     7,
+			Stage:        enums.CountFinal,
+			Denomination: denomination,
+			Quantity:     quantity,
+			Amount:       quantity * int(denomination),
+			CountedBy:    "ana",
+			CountedAt:    countedAt,
+		}
+	}
+
+	tests := []struct {
+		name      string
+		counts    []models.CashCount
+		want      []models.CashCount
+		wantTotal int
+		wantErr   error
+	}{
+		{
+			name:      "nothing counted",
+			counts:    nil,
+			want:      []models.CashCount{},
+			wantTotal: 0,
+		},
+		{
+			name: "largest denomination first",
+			counts: []models.CashCount{
+				{Denomination: enums.Coin100, Quantity: 3},
+				{Denomination: enums.Bill10000, Quantity: 2},
+				{Denomination: enums.Coin5, Quantity: 1},
+			},
+			want:      []models.CashCount{row(enums.Bill10000, 2), row(enums.Coin100, 3), row(enums.Coin5, 1)},
+			wantTotal: 20305,
+		},
+		{
+			name: "empty denominations left out",
+			counts: []models.CashCount{
+				{Denomination: enums.Bill1000, Quantity: 0},
+				{Denomination: enums.Coin500, Quantity: 4},
+			},
+			want:      []models.CashCount{row(enums.Coin500, 4)},
+			wantTotal: 2000,
+		},
+		{
+			name:    "unknown denomination",
+			counts:  []models.CashCount{{Denomination: enums.Denomination(200), Quantity: 1}},
+			wantErr: helpers.ErrInvalidCashCount,
+		},
+		{
+			name:    "negative quantity",
+			counts:  []models.CashCount{{Denomination: enums.Bill1000, Quantity: -1}},
+			wantErr: helpers.ErrInvalidCashCount,
+		},
+		{
+			name: "denomination counted twice",
+			counts: []models.CashCount{
+				{Denomination: enums.Bill1000, Quantity: 1},
+				{Denomination: enums.Bill1000, Quantity: 2},
+			},
+			wantErr: helpers.ErrInvalidCashCount,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, total, err := cashCount(7, enums.CountFinal, tt.counts, "ana", countedAt)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("cashCount() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("cashCount() = %+v, want %+v", got, tt.want)
+			}
+			if total != tt.wantTotal {
+				t.Errorf("cashCount() total = %d, want %d", total, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestBlindReports(t *testing.T) {
+	tests := []struct {
+		name  string
+		blind bool
+		// closed is whether the report is totally closed before the reads, recount the close left
+		// awaiting an admin recount
+		closed     bool
+		recount    enums.CashCountStage
+		wantHidden bool
+	}{
+		{"open report", true, false, "", true},
+		{"awaiting a recount", true, true, enums.CountFinal, true},
+		{"settled", true, true, "", false},
+		{"open report without blind close", false, false, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tickets, report := testSaleService(t, emulator.Status{})
+			if _, err := tickets.AddSale(testSaleRequest(report.ID, nil)); err != nil {
+				t.Fatalf("AddSale() error = %v", err)
+			}
+			if tt.closed {
+				if _, err := tickets.localDB.GetDB().Exec(
+					"UPDATE reports SET status = 0, closed_at = ?, pending_recount = ? WHERE id = ?",
+					time.Now().Format(time.RFC3339), tt.recount, report.ID,
+				); err != nil {
+					t.Fatalf("failed to close report: %v", err)
+				}
+			}
+			t.Setenv("POS_CASH_BLIND_CLOSE", strconv.FormatBool(tt.blind))
+
+			reports := NewReportService(tickets.localDB, nil)
+			reports.ctx = tickets.ctx
+
+			// The sale's ₡2300 is all the drawer expects, so a hidden figure reads zero
+			want, wantTickets := 2300, 2
+			if tt.wantHidden {
+				want, wantTickets = 0, 0
+			}
+
+			result, err := reports.SearchReports(models.ReportSearch{})
+			if err != nil {
+				t.Fatalf("SearchReports() error = %v", err)
+			}
+			if len(result.Reports) != 1 || result.Totals.Reports != 1 {
+				t.Fatalf("SearchReports() = %d reports, totals of %d, want 1", len(result.Reports), result.Totals.Reports)
+			}
+			if got := result.Reports[0]; got.FinalCash != want || got.FinalDrawer != want || got.FinalTickets != wantTickets {
+				t.Errorf("searched report cash, drawer, tickets = %d, %d, %d, want %d, %d, %d",
+					got.FinalCash, got.FinalDrawer, got.FinalTickets, want, want, wantTickets)
+			}
+			if result.Totals.Cash != want {
+				t.Errorf("search totals cash = %d, want %d", result.Totals.Cash, want)
+			}
+
+			summary, err := reports.DailySummary(time.Now().Format(constants.DateLayout))
+			if err != nil {
+				t.Fatalf("DailySummary() error = %v", err)
+			}
+			if summary.Cash != want || summary.Reports != 1 {
+				t.Errorf("daily summary cash, reports = %d, %d, want %d, 1", summary.Cash, summary.Reports, want)
+			}
+			if (summary.OpenReports == 1) == tt.closed {
+				t.Errorf("daily summary open reports = %d, want closed %v", summary.OpenReports, tt.closed)
+			}
+
+			latest, err := reports.GetLatestReportsByUsername("cajero")
+			if err != nil {
+				t.Fatalf("GetLatestReportsByUsername() error = %v", err)
+			}
+			if tt.closed && (len(latest) != 1 || latest[0].FinalDrawer != want) {
+				t.Errorf("latest reports = %+v, want one with a drawer of %d", latest, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,503 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"neon/core/database/embedded"
+	"neon/core/helpers"
+	"neon/core/helpers/enums"
+	"neon/core/models"
+	"neon/core/repositories/local"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// AddSale sells a group of tickets (several passengers, outbound and return) as one sale with its total
+// and payments. The tickets are validated and priced as in AddTicket and stored in the same transaction
+// as the sale. The payments can mix cash, card and SINPE Móvil and must add up to the total (see
+// preparePayments); a sale without payments is paid in cash. With e-invoicing on, the sale's Tiquete
+// Electrónico, or Factura Electrónica for the request's receiver, is signed and queued with it.
+func (t *TicketService) AddSale(request models.SaleRequest) (*models.Sale, error) {
+	return t.sell(request, "", false)
+}
+
+// AddSaleWithPrint sells a group of tickets as AddSale and prints them, followed by the sale summary when
+// the sale has more than one ticket. The printer is checked before the sale is charged, and a sale that
+// fails to print is voided (see AddTicketWithPrint).
+func (t *TicketService) AddSaleWithPrint(request models.SaleRequest, printerName string) (*models.Sale, error) {
+	if t.printService == nil {
+		return nil, fmt.Errorf("print service is not available")
+	}
+
+	return t.sell(request, printerName, true)
+}
+
+// GetSale returns a sale with its tickets and payments
+func (t *TicketService) GetSale(saleID int64) (*models.Sale, error) {
+	return loadSale(t.ctx, t.localDB, saleID)
+}
+
+// VoidSale voids every ticket of a sale still valid, and the sale, in one transaction. The void needs an
+// admin when the tickets' fares together exceed the approval amount or one was sold before the partial
+// close. An invoiced sale gets a Nota de Crédito for the tickets voided, queued with the void.
+func (t *TicketService) VoidSale(request models.SaleVoidRequest) (*models.SaleVoid, error) {
+	if !request.Reason.IsValid() {
+		return nil, helpers.ErrInvalidVoidReason
+	}
+
+	sale, err := loadSale(t.ctx, t.localDB, request.SaleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if sale.IsNull {
+		return nil, helpers.ErrSaleAlreadyNullified
+	}
+
+	report, err := t.getVoidReport(request.ReportID)
+	if err != nil {
+		return nil, err
+	}
+
+	if sale.ReportID != report.ID {
+		return nil, helpers.ErrTicketNotBelongToReport
+	}
+
+	if !report.Status {
+		return nil, helpers.ErrTicketAlreadyClosed
+	}
+
+	voidedBy, err := t.voidedBy(request.VoidedBy, report.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	voidRequest := models.TicketVoidRequest{
+		ReportID:         request.ReportID,
+		Reason:           request.Reason,
+		Note:             request.Note,
+		VoidedBy:         voidedBy,
+		ApproverUsername: request.ApproverUsername,
+		ApproverPassword: request.ApproverPassword,
+	}
+
+	tickets := make([]models.Ticket, 0, len(sale.Tickets))
+	for i := range sale.Tickets {
+		if sale.Tickets[i].IsNull {
+			continue
+		}
+		if err := checkVoidable(&sale.Tickets[i], report); err != nil {
+			return nil, err
+		}
+		tickets = append(tickets, sale.Tickets[i])
+	}
+
+	approvedBy, err := t.voidApproval(voidRequest, tickets)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.voidSale(sale, report, tickets, voidRequest, approvedBy)
+}
+
+// voidSale voids the tickets given, and the sale, in one transaction, then settles the card payments the
+// void queued. The void must already be checked and approved.
+func (t *TicketService) voidSale(
+	sale *models.Sale,
+	report *models.Report,
+	tickets []models.Ticket,
+	voidRequest models.TicketVoidRequest,
+	approvedBy *string,
+) (*models.SaleVoid, error) {
+	tx, err := t.localDB.BeginTx(t.ctx, nil)
+	if err != nil {
+		zap.L().Error("failed to begin sale void transaction", zap.Error(err))
+		return nil, err
+	}
+
+	voids := make([]models.TicketVoid, 0, len(tickets))
+	var refunds []models.OutboxMessage
+	for _, ticket := range tickets {
+		voidRequest.TicketID = ticket.ID
+		void, queued, err := t.voidTicketTx(tx, ticket, report, voidRequest, approvedBy)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		voids = append(voids, *void)
+		refunds = append(refunds, queued...)
+	}
+
+	reason := fmt.Sprintf("Anulación de la venta %d", sale.ID)
+	if _, err := t.einvoiceService.creditTx(tx, sale.ID, report.ID, tickets, reason); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	voidedAt := time.Now().Format(time.RFC3339)
+	voidedBy := voidRequest.VoidedBy
+
+	if err := local.NewSaleRepository(t.ctx, t.localDB).VoidTx(tx, sale.ID, voidedBy, voidedAt); err != nil {
+		tx.Rollback()
+		zap.L().Error("failed to void sale", zap.Error(err))
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		zap.L().Error("failed to commit sale void", zap.Error(err))
+		return nil, err
+	}
+
+	t.settleCardPayments(refunds)
+
+	for i := range sale.Tickets {
+		sale.Tickets[i].IsNull = true
+	}
+	sale.IsNull = true
+	sale.VoidedAt = &voidedAt
+	sale.VoidedBy = &voidedBy
+
+	return &models.SaleVoid{Sale: *sale, Voids: voids}, nil
+}
+
+// voidUnprintedSale voids a committed sale whose receipts failed to print, through the same void as
+// VoidSale so its seats, report totals and card payments are given back. It returns the *TicketPrintError
+// for the cashier, with the receipts that did print, to be taken back from the customer.
+func (t *TicketService) voidUnprintedSale(sale *models.Sale, err error) error {
+	var printErr *TicketPrintError
+	if !errors.As(err, &printErr) {
+		printErr = &TicketPrintError{Err: err}
+	}
+	printErr.SaleID = sale.ID
+
+	report, voidErr := t.getVoidReport(sale.ReportID)
+	if voidErr == nil {
+		_, voidErr = t.voidSale(sale, report, sale.Tickets, models.TicketVoidRequest{
+			ReportID: sale.ReportID,
+			Reason:   enums.VoidPrintError,
+			VoidedBy: sale.Username,
+		}, nil)
+	}
+	if voidErr != nil {
+		zap.L().Error("sale not printed and not voided, void it by hand",
+			zap.Int64("sale_id", sale.ID),
+			zap.Int64s("printed_ticket_ids", printErr.Printed),
+			zap.Error(errors.Join(err, voidErr)),
+		)
+		return printErr
+	}
+
+	printErr.Voided = true
+	zap.L().Warn("sale not printed, voided",
+		zap.Int64("sale_id", sale.ID),
+		zap.Int64s("printed_ticket_ids", printErr.Printed),
+		zap.Error(err),
+	)
+	return printErr
+}
+
+// sell stores a sale and its tickets and payments in one transaction, printing the receipts after the
+// commit when print is set. Nothing is sent to the terminal or the printer while the transaction is open.
+func (t *TicketService) sell(request models.SaleRequest, printerName string, print bool) (*models.Sale, error) {
+	tickets := request.Tickets
+	if len(tickets) == 0 {
+		return nil, helpers.ErrInvalidRequest
+	}
+
+	reportID := request.ReportID
+	if reportID == 0 {
+		reportID = tickets[0].ReportID
+	}
+
+	// The sale belongs to the report's cashier, who changes at each accepted handover
+	username, err := reportCashier(t.ctx, t.localDB, reportID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range tickets {
+		if tickets[i].ReportID == 0 {
+			tickets[i].ReportID = reportID
+		}
+		tickets[i].Username = username
+		if tickets[i].ReportID != reportID {
+			return nil, helpers.ErrTicketNotBelongToReport
+		}
+	}
+
+	if err := t.prepareSale(tickets); err != nil {
+		return nil, err
+	}
+
+	if err := t.einvoiceService.checkReceiver(request.Receiver); err != nil {
+		return nil, err
+	}
+	if err := t.einvoiceService.checkContingency(request.Contingency); err != nil {
+		return nil, err
+	}
+
+	total := 0
+	for _, ticket := range tickets {
+		total += ticket.Fare
+	}
+
+	payments, err := preparePayments(request.Payments, total, t.paymentProvider != nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// A sale is only charged and stored on a printer that is ready to print it
+	if print {
+		if err := t.printService.EnsurePrinterReady(printerName); err != nil {
+			zap.L().Warn("printer not ready, sale refused", zap.Error(err))
+			return nil, err
+		}
+	}
+
+	// Card payments are charged before anything is stored; from here on every failure voids them
+	authorizations, err := t.authorizeCardPayments(payments, fmt.Sprintf("R%d-%d", reportID, time.Now().Unix()))
+	if err != nil {
+		return nil, err
+	}
+	if len(authorizations) > 0 {
+		code := approvalCodes(authorizations)
+		for i := range tickets {
+			tickets[i].ApprovalCode = code
+		}
+	}
+	now := time.Now().Format(time.RFC3339)
+
+	tx, err := t.localDB.BeginTx(t.ctx, nil)
+	if err != nil {
+		t.voidAuthorizations(authorizations)
+		zap.L().Error("failed to begin sale transaction", zap.Error(err))
+		return nil, err
+	}
+
+	sale, err := local.NewSaleRepository(t.ctx, t.localDB).AddTx(tx, models.Sale{
+		ReportID:    reportID,
+		Username:    username,
+		Total:       total,
+		TicketCount: len(tickets),
+		CreatedAt:   now,
+	})
+	if err != nil {
+		tx.Rollback()
+		t.voidAuthorizations(authorizations)
+		zap.L().Error("failed to add sale", zap.Error(err))
+		return nil, err
+	}
+
+	for i := range tickets {
+		tickets[i].SaleID = sale.ID
+	}
+
+	created, err := t.createTicketsTx(tx, tickets)
+	if err != nil {
+		tx.Rollback()
+		t.voidAuthorizations(authorizations)
+		return nil, err
+	}
+	sale.Tickets = created
+
+	// Card payments authorized on the terminal are captured once the sale is committed and, when it prints,
+	// printed; a sale that fails to print is voided before its captures are due (see captureHold)
+	due := time.Now()
+	if print {
+		due = due.Add(captureHold)
+	}
+	paymentRepository := local.NewSalePaymentRepository(t.ctx, t.localDB)
+	sale.Payments = make([]models.SalePayment, 0, len(payments))
+	var captures []models.OutboxMessage
+	for _, payment := range payments {
+		payment.SaleID = sale.ID
+		payment.CreatedAt = now
+		onTerminal := t.onTerminal(payment)
+		if onTerminal {
+			payment.CaptureStatus = enums.OutboxPending
+		}
+		created, err := paymentRepository.AddTx(tx, payment)
+		if err != nil {
+			tx.Rollback()
+			t.voidAuthorizations(authorizations)
+			zap.L().Error("failed to add sale payment", zap.Error(err))
+			return nil, err
+		}
+		sale.Payments = append(sale.Payments, *created)
+		if !onTerminal {
+			continue
+		}
+		message, err := t.queueCardPaymentTx(tx, enums.OutboxCardCapture, *created, due)
+		if err != nil {
+			tx.Rollback()
+			t.voidAuthorizations(authorizations)
+			return nil, err
+		}
+		captures = append(captures, *message)
+	}
+
+	if t.einvoiceService.enabled() {
+		if sale.Document, err = t.einvoiceService.issueTx(tx, sale, request.Receiver, request.Contingency); err != nil {
+			tx.Rollback()
+			t.voidAuthorizations(authorizations)
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		t.voidAuthorizations(authorizations)
+		zap.L().Error("failed to commit sale",
+			zap.Int64s("ticket_ids", ticketIDs(created)),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	if print {
+		if err := t.printService.PrintSale(*sale, printerName); err != nil {
+			var printErr *TicketPrintError
+			if !errors.As(err, &printErr) || len(printErr.Printed) < len(sale.Tickets) {
+				return nil, t.voidUnprintedSale(sale, err)
+			}
+			// Every ticket printed and only the summary is missing, which PrintSaleSummary prints again
+			zap.L().Warn("sale summary not printed", zap.Int64("sale_id", sale.ID), zap.Error(err))
+		}
+	}
+
+	t.settleCardPayments(captures)
+
+	return sale, nil
+}
+
+// preparePayments checks the payments of a sale against its total and fills in the tendered amount and
+// the change. Cash tendered defaults to the amount paid; card and SINPE Móvil payments need their voucher
+// or transfer reference and are tendered exactly. With a card terminal, card payments get their reference
+// from the terminal.
+func preparePayments(payments []models.SalePayment, total int, cardTerminal bool) ([]models.SalePayment, error) {
+	if len(payments) == 0 {
+		return []models.SalePayment{{Method: enums.PaymentCash, Amount: total, Tendered: total}}, nil
+	}
+
+	prepared := make([]models.SalePayment, 0, len(payments))
+	paid := 0
+	for _, payment := range payments {
+		if !payment.Method.IsValid() {
+			return nil, helpers.ErrInvalidPaymentMethod
+		}
+		if payment.Amount <= 0 {
+			return nil, helpers.ErrInvalidPaymentAmount
+		}
+
+		payment.Reference = strings.TrimSpace(payment.Reference)
+		payment.ApprovalCode = ""
+		if payment.Method.NeedsReference() {
+			if cardTerminal && payment.Method == enums.PaymentCard {
+				payment.Reference = ""
+			} else if payment.Reference == "" {
+				return nil, helpers.ErrPaymentReferenceRequired
+			}
+			payment.Tendered = payment.Amount
+		}
+
+		if payment.Tendered == 0 {
+			payment.Tendered = payment.Amount
+		}
+		if payment.Tendered < payment.Amount {
+			return nil, helpers.ErrInsufficientTendered
+		}
+		payment.Change = payment.Tendered - payment.Amount
+
+		paid += payment.Amount
+		prepared = append(prepared, payment)
+	}
+
+	if paid != total {
+		return nil, helpers.ErrPaymentTotalMismatch
+	}
+
+	return prepared, nil
+}
+
+// refundPayments splits the refund of a voided ticket across the payments of its sale, in the order
+// they were taken, each up to what is left of it after earlier refunds. The refunds are returned as
+// payments with a negative amount and the reference of the payment they refund; anything left over is
+// refunded in cash.
+func refundPayments(payments []models.SalePayment, amount int) []models.SalePayment {
+	type source struct {
+		method    enums.PaymentMethod
+		reference string
+	}
+
+	var sources []models.SalePayment
+	left := map[source]int{}
+	for _, payment := range payments {
+		key := source{payment.Method, payment.Reference}
+		if _, ok := left[key]; !ok && payment.Amount > 0 {
+			sources = append(sources, payment)
+		}
+		left[key] += payment.Amount
+	}
+
+	var refunds []models.SalePayment
+	for _, payment := range sources {
+		if amount == 0 {
+			break
+		}
+		refund := min(left[source{payment.Method, payment.Reference}], amount)
+		if refund <= 0 {
+			continue
+		}
+		refunds = append(refunds, models.SalePayment{
+			Method:       payment.Method,
+			Amount:       -refund,
+			Reference:    payment.Reference,
+			ApprovalCode: payment.ApprovalCode,
+		})
+		amount -= refund
+	}
+	if amount > 0 {
+		refunds = append(refunds, models.SalePayment{Method: enums.PaymentCash, Amount: -amount})
+	}
+
+	return refunds
+}
+
+// loadSale loads a sale with its tickets and payments
+func loadSale(ctx context.Context, localDB *embedded.SQLite, saleID int64) (*models.Sale, error) {
+	sale, err := local.NewSaleRepository(ctx, localDB).GetByID(saleID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, helpers.ErrRowNotFound
+		}
+		zap.L().Error("failed to get sale", zap.Error(err))
+		return nil, err
+	}
+
+	tickets, err := local.NewTicketRepository(ctx, localDB).GetBySaleID(saleID)
+	if err != nil {
+		zap.L().Error("failed to get sale tickets", zap.Error(err))
+		return nil, err
+	}
+	sale.Tickets = tickets
+
+	payments, err := local.NewSalePaymentRepository(ctx, localDB).GetBySaleID(saleID)
+	if err != nil {
+		zap.L().Error("failed to get sale payments", zap.Error(err))
+		return nil, err
+	}
+	sale.Payments = payments
+
+	document, err := local.NewElectronicDocumentRepository(ctx, localDB).GetBySaleID(saleID)
+	if err == nil {
+		sale.Document = document
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		zap.L().Error("failed to get sale electronic document", zap.Error(err))
+		return nil, err
+	}
+
+	return sale, nil
+}
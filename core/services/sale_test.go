@@ -0,0 +1,443 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"neon/core/config"
+	"neon/core/database/embedded"
+	"neon/core/emulator"
+	"neon/core/helpers"
+	"neon/core/helpers/enums"
+	"neon/core/models"
+	"neon/core/payment"
+	"neon/core/repositories/local"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPreparePayments(t *testing.T) {
+	tests := []struct {
+		name         string
+		payments     []models.SalePayment
+		total        int
+		cardTerminal bool
+		want         []models.SalePayment
+		wantErr      error
+	}{
+		{
+			name:  "defaults to exact cash",
+			total: 2300,
+			want:  []models.SalePayment{{Method: enums.PaymentCash, Amount: 2300, Tendered: 2300}},
+		},
+		{
+			name:     "cash with change",
+			payments: []models.SalePayment{{Method: enums.PaymentCash, Amount: 2300, Tendered: 5000}},
+			total:    2300,
+			want:     []models.SalePayment{{Method: enums.PaymentCash, Amount: 2300, Tendered: 5000, Change: 2700}},
+		},
+		{
+			name: "split cash and SINPE Móvil",
+			payments: []models.SalePayment{
+				{Method: enums.PaymentSinpe, Amount: 1000, Reference: " 123456 ", Tendered: 2000},
+				{Method: enums.PaymentCash, Amount: 1300},
+			},
+			total: 2300,
+			want: []models.SalePayment{
+				{Method: enums.PaymentSinpe, Amount: 1000, Reference: "123456", Tendered: 1000},
+				{Method: enums.PaymentCash, Amount: 1300, Tendered: 1300},
+			},
+		},
+		{
+			name:     "card voucher",
+			payments: []models.SalePayment{{Method: enums.PaymentCard, Amount: 2300, Reference: "998877", ApprovalCode: "FORGED"}},
+			total:    2300,
+			want:     []models.SalePayment{{Method: enums.PaymentCard, Amount: 2300, Reference: "998877", Tendered: 2300}},
+		},
+		{
+			name:         "card on the terminal",
+			payments:     []models.SalePayment{{Method: enums.PaymentCard, Amount: 2300, Reference: "998877"}},
+			total:        2300,
+			cardTerminal: true,
+			want:         []models.SalePayment{{Method: enums.PaymentCard, Amount: 2300, Tendered: 2300}},
+		},
+		{
+			name:     "card without voucher",
+			payments: []models.SalePayment{{Method: enums.PaymentCard, Amount: 2300, Reference: " "}},
+			total:    2300,
+			wantErr:  helpers.ErrPaymentReferenceRequired,
+		},
+		{
+			name:     "SINPE Móvil without reference",
+			payments: []models.SalePayment{{Method: enums.PaymentSinpe, Amount: 2300}},
+			total:    2300,
+			wantErr:  helpers.ErrPaymentReferenceRequired,
+		},
+		{
+			name:     "unknown method",
+			payments: []models.SalePayment{{Method: enums.PaymentMethod("check"), Amount: 2300}},
+			total:    2300,
+			wantErr:  helpers.ErrInvalidPaymentMethod,
+		},
+		{
+			name:     "zero amount",
+			payments: []models.SalePayment{{Method: enums.PaymentCash, Amount: 0}},
+			total:    0,
+			wantErr:  helpers.ErrInvalidPaymentAmount,
+		},
+		{
+			name:     "negative amount",
+			payments: []models.SalePayment{{Method: enums.PaymentCash, Amount: -100}},
+			total:    -100,
+			wantErr:  helpers.ErrInvalidPaymentAmount,
+		},
+		{
+			name:     "not enough cash tendered",
+			payments: []models.SalePayment{{Method: enums.PaymentCash, Amount: 2300, Tendered: 2000}},
+			total:    2300,
+			wantErr:  helpers.ErrInsufficientTendered,
+		},
+		{
+			name:     "paid short of the total",
+			payments: []models.SalePayment{{Method: enums.PaymentCash, Amount: 2000}},
+			total:    2300,
+			wantErr:  helpers.ErrPaymentTotalMismatch,
+		},
+		{
+			name:     "paid over the total",
+			payments: []models.SalePayment{{Method: enums.PaymentCash, Amount: 2500}},
+			total:    2300,
+			wantErr:  helpers.ErrPaymentTotalMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := preparePayments(tt.payments, tt.total, tt.cardTerminal)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("preparePayments() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("preparePayments() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRefundPayments(t *testing.T) {
+	split := []models.SalePayment{
+		{Method: enums.PaymentCard, Amount: 1500, Reference: "998877", ApprovalCode: "A1B2C3"},
+		{Method: enums.PaymentCash, Amount: 800},
+	}
+
+	tests := []struct {
+		name     string
+		payments []models.SalePayment
+		amount   int
+		want     []models.SalePayment
+	}{
+		{
+			name:     "cash sale",
+			payments: []models.SalePayment{{Method: enums.PaymentCash, Amount: 2300}},
+			amount:   1150,
+			want:     []models.SalePayment{{Method: enums.PaymentCash, Amount: -1150}},
+		},
+		{
+			name:     "within the first payment",
+			payments: split,
+			amount:   1150,
+			want:     []models.SalePayment{{Method: enums.PaymentCard, Amount: -1150, Reference: "998877", ApprovalCode: "A1B2C3"}},
+		},
+		{
+			name:     "across payments in the order taken",
+			payments: split,
+			amount:   2000,
+			want: []models.SalePayment{
+				{Method: enums.PaymentCard, Amount: -1500, Reference: "998877", ApprovalCode: "A1B2C3"},
+				{Method: enums.PaymentCash, Amount: -500},
+			},
+		},
+		{
+			name: "after an earlier refund",
+			payments: append(split[:2:2],
+				models.SalePayment{Method: enums.PaymentCard, Amount: -1150, Reference: "998877"}),
+			amount: 1150,
+			want: []models.SalePayment{
+				{Method: enums.PaymentCard, Amount: -350, Reference: "998877", ApprovalCode: "A1B2C3"},
+				{Method: enums.PaymentCash, Amount: -800},
+			},
+		},
+		{
+			name:     "card fully refunded",
+			payments: append(split[:2:2], models.SalePayment{Method: enums.PaymentCard, Amount: -1500, Reference: "998877"}),
+			amount:   800,
+			want:     []models.SalePayment{{Method: enums.PaymentCash, Amount: -800}},
+		},
+		{
+			name: "separate cards",
+			payments: []models.SalePayment{
+				{Method: enums.PaymentCard, Amount: 1000, Reference: "111111"},
+				{Method: enums.PaymentCard, Amount: 1300, Reference: "222222"},
+			},
+			amount: 1500,
+			want: []models.SalePayment{
+				{Method: enums.PaymentCard, Amount: -1000, Reference: "111111"},
+				{Method: enums.PaymentCard, Amount: -500, Reference: "222222"},
+			},
+		},
+		{
+			name:     "left over in cash",
+			payments: []models.SalePayment{{Method: enums.PaymentSinpe, Amount: 1000, Reference: "123456"}},
+			amount:   1150,
+			want: []models.SalePayment{
+				{Method: enums.PaymentSinpe, Amount: -1000, Reference: "123456"},
+				{Method: enums.PaymentCash, Amount: -150},
+			},
+		},
+		{
+			name:     "nothing to refund",
+			payments: split,
+			amount:   0,
+			want:     nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := refundPayments(tt.payments, tt.amount); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("refundPayments() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// testSaleService returns a ticket service on fresh databases, printing to an emulated printer with the
+// given status and charging cards on the simulator, and an open report to sell on
+func testSaleService(t *testing.T, status emulator.Status) (*TicketService, *models.Report) {
+	t.Helper()
+	// pos.yaml is read from the user's config dir; an empty one keeps the defaults
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	localDB := embedded.NewSQLite(&config.SQLiteConfig{FilePath: filepath.Join(dir, "neon.db"), MaxOpenConns: 4})
+	if err := localDB.Connect(ctx); err != nil {
+		t.Fatalf("failed to connect SQLite: %v", err)
+	}
+	t.Cleanup(func() { localDB.Close() })
+
+	cloverDB := embedded.NewCloverDB(&config.CloverDBConfig{FilePath: t.TempDir()})
+	if err := cloverDB.Connect(ctx); err != nil {
+		t.Fatalf("failed to connect CloverDB: %v", err)
+	}
+	t.Cleanup(func() { cloverDB.Close() })
+
+	printer := emulator.New(status)
+	if err := printer.Start("127.0.0.1:0"); err != nil {
+		t.Fatalf("failed to start printer: %v", err)
+	}
+	t.Cleanup(func() { printer.Close() })
+
+	printService := NewPrintService(localDB)
+	printService.startup(ctx)
+	printService.emulator = printer
+
+	tickets := NewTicketService(localDB, cloverDB, printService, nil, NewEInvoiceService(localDB))
+	tickets.startup(ctx)
+	tickets.paymentSimulator = payment.NewSimulator()
+	tickets.paymentProvider = tickets.paymentSimulator
+
+	// The outbox worker isn't started: messages are only attempted right after their commit
+	outbox := NewOutboxService(localDB)
+	outbox.ctx = ctx
+	tickets.startCardOutbox(outbox)
+
+	createdAt := time.Now().Format(time.RFC3339)
+	report, err := local.NewReportRepository(ctx, localDB).Add(models.Report{
+		Username:  "cajero",
+		Timetable: enums.Regular,
+		Status:    true,
+		CreatedAt: &createdAt,
+	})
+	if err != nil {
+		t.Fatalf("failed to open report: %v", err)
+	}
+
+	return tickets, report
+}
+
+// testSaleRequest is a sale of two tickets of ₡1150 on a report, paid as given
+func testSaleRequest(reportID int64, payments []models.SalePayment) models.SaleRequest {
+	return models.SaleRequest{
+		ReportID: reportID,
+		Tickets: []models.Ticket{
+			{Departure: "San José", Destination: "Cartago", Time: "08:00", Stop: "Cartago", Fare: 1150},
+			{Departure: "San José", Destination: "Cartago", Time: "08:00", Stop: "Cartago", Fare: 1150},
+		},
+		Payments: payments,
+	}
+}
+
+func TestAddSaleWithPrintFailure(t *testing.T) {
+	card := []models.SalePayment{{Method: enums.PaymentCard, Amount: 2300}}
+
+	tests := []struct {
+		name     string
+		status   emulator.Status
+		payments []models.SalePayment
+		// wantVoided is whether the sale was stored, then voided when it failed to print
+		wantVoided  bool
+		wantPrinted int
+		// wantCard is the state of the card charge on the terminal, empty when none was taken
+		wantCard string
+	}{
+		{"printer out of paper", emulator.Status{PaperEnd: true}, nil, false, 0, ""},
+		{"paper runs out after the first ticket", emulator.Status{PaperEndAfterCuts: 1}, nil, true, 1, ""},
+		{"card never charged", emulator.Status{PaperEnd: true}, card, false, 0, ""},
+		{"card authorization voided", emulator.Status{PaperEndAfterCuts: 1}, card, true, 1, payment.StateVoided},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tickets, report := testSaleService(t, tt.status)
+
+			request := testSaleRequest(report.ID, tt.payments)
+			sale, err := tickets.AddSaleWithPrint(request, "")
+			if err == nil {
+				t.Fatalf("AddSaleWithPrint() = %+v, want an error", sale)
+			}
+
+			var printErr *TicketPrintError
+			if tt.wantVoided {
+				if !errors.As(err, &printErr) || !printErr.Voided || printErr.SaleID == 0 {
+					t.Fatalf("AddSaleWithPrint() error = %v, want the sale voided", err)
+				}
+				if len(printErr.Printed) != tt.wantPrinted {
+					t.Errorf("printed tickets = %v, want %d", printErr.Printed, tt.wantPrinted)
+				}
+			} else if errors.As(err, &printErr) && printErr.SaleID != 0 {
+				t.Fatalf("AddSaleWithPrint() error = %v, want no sale stored", err)
+			}
+
+			got, err := local.NewReportRepository(tickets.ctx, tickets.localDB).GetByID(report.ID)
+			if err != nil {
+				t.Fatalf("failed to get report: %v", err)
+			}
+			if got.FinalTickets != 0 || got.FinalCash != 0 || got.TotalSales != 0 {
+				t.Errorf("report tickets, cash, sales = %d, %d, %d, want none",
+					got.FinalTickets, got.FinalCash, got.TotalSales)
+			}
+			wantNull := 0
+			if tt.wantVoided {
+				wantNull = len(request.Tickets)
+			}
+			if got.TotalNull != wantNull {
+				t.Errorf("report voided tickets = %d, want %d", got.TotalNull, wantNull)
+			}
+
+			totals, err := local.NewReportPaymentTotalRepository(tickets.ctx, tickets.localDB).GetByReportIDs([]int64{report.ID})
+			if err != nil {
+				t.Fatalf("failed to get payment totals: %v", err)
+			}
+			for _, total := range totals[report.ID] {
+				if total.FinalAmount != 0 {
+					t.Errorf("%s payments = %d, want 0", total.Method, total.FinalAmount)
+				}
+			}
+
+			inventories, err := local.NewSeatInventoryRepository(tickets.ctx, tickets.localDB).
+				GetByTravelDate("San José", "Cartago", time.Now().Format("2006-01-02"))
+			if err != nil {
+				t.Fatalf("failed to get seat inventory: %v", err)
+			}
+			for _, inventory := range inventories {
+				if inventory.Sold != 0 {
+					t.Errorf("seats sold at %s = %d, want 0", inventory.Time, inventory.Sold)
+				}
+			}
+
+			transactions := tickets.paymentSimulator.Transactions()
+			switch {
+			case tt.wantCard == "" && len(transactions) > 0:
+				t.Errorf("card charges = %+v, want none", transactions)
+			case tt.wantCard != "" && (len(transactions) != 1 || transactions[0].State != tt.wantCard):
+				t.Errorf("card charges = %+v, want one %s", transactions, tt.wantCard)
+			}
+		})
+	}
+}
+
+func TestVoidCardSale(t *testing.T) {
+	tests := []struct {
+		name string
+		// captured is whether the charge was captured before the void
+		captured bool
+		// oneByOne voids the tickets one at a time instead of the whole sale
+		oneByOne     bool
+		wantState    string
+		wantRefunded int
+	}{
+		{"sale voided before the capture", false, false, payment.StateVoided, 0},
+		{"tickets voided one by one before the capture", false, true, payment.StateVoided, 0},
+		{"sale voided after the capture", true, false, payment.StateRefunded, 2300},
+		{"tickets voided one by one after the capture", true, true, payment.StateRefunded, 2300},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tickets, report := testSaleService(t, emulator.Status{})
+
+			// Without the outbox the capture stays queued
+			outbox := tickets.outboxService
+			if !tt.captured {
+				tickets.outboxService = nil
+			}
+			sale, err := tickets.AddSale(testSaleRequest(report.ID, []models.SalePayment{{Method: enums.PaymentCard, Amount: 2300}}))
+			if err != nil {
+				t.Fatalf("AddSale() error = %v", err)
+			}
+			tickets.outboxService = outbox
+
+			if tt.oneByOne {
+				for _, ticket := range sale.Tickets {
+					if _, err := tickets.VoidTicket(models.TicketVoidRequest{
+						TicketID: ticket.ID,
+						ReportID: report.ID,
+						Reason:   enums.VoidOther,
+					}); err != nil {
+						t.Fatalf("VoidTicket() error = %v", err)
+					}
+				}
+			} else if _, err := tickets.VoidSale(models.SaleVoidRequest{
+				SaleID:   sale.ID,
+				ReportID: report.ID,
+				Reason:   enums.VoidOther,
+			}); err != nil {
+				t.Fatalf("VoidSale() error = %v", err)
+			}
+
+			transactions := tickets.paymentSimulator.Transactions()
+			if len(transactions) != 1 {
+				t.Fatalf("card charges = %+v, want one", transactions)
+			}
+			if got := transactions[0]; got.State != tt.wantState || got.Refunded != tt.wantRefunded {
+				t.Errorf("card charge state, refunded = %s, %d, want %s, %d",
+					got.State, got.Refunded, tt.wantState, tt.wantRefunded)
+			}
+
+			got, err := loadSale(tickets.ctx, tickets.localDB, sale.ID)
+			if err != nil {
+				t.Fatalf("failed to load sale: %v", err)
+			}
+			wantCapture := enums.OutboxAccepted
+			if !tt.captured {
+				wantCapture = enums.OutboxCancelled
+			}
+			if charge := cardCharge(got.Payments, got.Payments[0].Reference); charge == nil || charge.CaptureStatus != wantCapture {
+				t.Errorf("card payment = %+v, want its capture %s", charge, wantCapture)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"errors"
+	"neon/core/config"
+	"neon/core/constants"
+	"neon/core/helpers"
+	"neon/core/models"
+	"neon/core/repositories/local"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// SearchReports searches the report history by start date, cashier, timetable, status, sync state and
+// variance sign, a page at a time, with the totals of every report matching. Invalid dates, an unknown
+// variance sign or sort fail with helpers.ErrInvalidRequest.
+func (r *ReportService) SearchReports(search models.ReportSearch) (*models.ReportSearchResult, error) {
+	if _, _, err := searchDates(search.From, search.To); err != nil {
+		return nil, err
+	}
+	if search.Variance != "" && !search.Variance.IsValid() {
+		return nil, helpers.ErrInvalidRequest
+	}
+	search.Page, search.PageSize = searchPage(search.Page, search.PageSize)
+
+	reports, totals, err := local.NewReportRepository(r.ctx, r.localDB).Search(search, config.LoadPOSConfig().Cash.BlindClose)
+	if err != nil {
+		if errors.Is(err, helpers.ErrInvalidRequest) {
+			return nil, err
+		}
+		zap.L().Error("failed to search reports", zap.Error(err))
+		return nil, err
+	}
+
+	if err := loadReportTotals(r.ctx, r.localDB, reports...); err != nil {
+		return nil, err
+	}
+	blindReports(reports)
+
+	return &models.ReportSearchResult{
+		Reports:  reports,
+		Totals:   totals,
+		Page:     search.Page,
+		PageSize: search.PageSize,
+		Pages:    searchPages(totals.Reports, search.PageSize),
+	}, nil
+}
+
+// SearchTickets searches the tickets by sale and travel date, route, stop, departure time, seller, kind, ID
+// number and report, a page at a time, with the totals of every ticket matching. Invalid dates or an
+// unknown sort fail with helpers.ErrInvalidRequest.
+func (t *TicketService) SearchTickets(search models.TicketSearch) (*models.TicketSearchResult, error) {
+	soldFrom, soldTo, err := searchDates(search.SoldFrom, search.SoldTo)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := searchDates(search.TravelFrom, search.TravelTo); err != nil {
+		return nil, err
+	}
+	search.Page, search.PageSize = searchPage(search.Page, search.PageSize)
+
+	// Tickets are stamped in UTC, so the sale days are searched as the instants they start and end locally
+	var from, to string
+	if soldFrom != nil {
+		from = soldFrom.Format(time.RFC3339)
+	}
+	if soldTo != nil {
+		to = soldTo.AddDate(0, 0, 1).Format(time.RFC3339)
+	}
+
+	tickets, totals, err := local.NewTicketRepository(t.ctx, t.localDB).Search(search, from, to)
+	if err != nil {
+		if errors.Is(err, helpers.ErrInvalidRequest) {
+			return nil, err
+		}
+		zap.L().Error("failed to search tickets", zap.Error(err))
+		return nil, err
+	}
+
+	return &models.TicketSearchResult{
+		Tickets:  tickets,
+		Totals:   totals,
+		Page:     search.Page,
+		PageSize: search.PageSize,
+		Pages:    searchPages(totals.Tickets+totals.Null, search.PageSize),
+	}, nil
+}
+
+// searchDates parses the first and last days (YYYY-MM-DD) of a search as local midnights, nil when not
+// given. A last day before the first is invalid.
+func searchDates(from string, to string) (*time.Time, *time.Time, error) {
+	var fromDate, toDate *time.Time
+	if from != "" {
+		date, err := time.ParseInLocation(constants.DateLayout, from, time.Local)
+		if err != nil {
+			return nil, nil, helpers.ErrInvalidRequest
+		}
+		fromDate = &date
+	}
+	if to != "" {
+		date, err := time.ParseInLocation(constants.DateLayout, to, time.Local)
+		if err != nil {
+			return nil, nil, helpers.ErrInvalidRequest
+		}
+		toDate = &date
+	}
+	if fromDate != nil && toDate != nil && toDate.Before(*fromDate) {
+		return nil, nil, helpers.ErrInvalidRequest
+	}
+	return fromDate, toDate, nil
+}
+
+// searchPage defaults a search's page to the first and its size to constants.DefaultPageSize, and caps the
+// size at constants.MaxPageSize
+func searchPage(page int, pageSize int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = constants.DefaultPageSize
+	}
+	if pageSize > constants.MaxPageSize {
+		pageSize = constants.MaxPageSize
+	}
+	return page, pageSize
+}
+
+// searchPages is the number of pages of a search's results
+func searchPages(total int, pageSize int) int {
+	return (total + pageSize - 1) / pageSize
+}
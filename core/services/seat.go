@@ -0,0 +1,152 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"neon/core/config"
+	"neon/core/constants"
+	"neon/core/helpers"
+	"neon/core/helpers/enums"
+	"neon/core/models"
+	"neon/core/repositories/local"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// GetSeatAvailability returns the capacity, seats sold and seats left for every departure of a route's
+// timetable on a travel date (YYYY-MM-DD)
+func (t *TicketService) GetSeatAvailability(departure string, destination string, travelDate string, timetable enums.Timetable) ([]models.DepartureSeats, error) {
+	if _, err := time.ParseInLocation(constants.DateLayout, travelDate, time.Local); err != nil {
+		return nil, helpers.ErrInvalidRequest
+	}
+
+	route, err := local.NewRouteRepository(t.cloverDB).GetByName(departure, destination)
+	if err != nil {
+		zap.L().Error("failed to get route", zap.Error(err))
+		return nil, err
+	}
+
+	repository := local.NewSeatInventoryRepository(t.ctx, t.localDB)
+	inventories, err := repository.GetByTravelDate(departure, destination, travelDate)
+	if err != nil {
+		zap.L().Error("failed to get seat inventory", zap.Error(err))
+		return nil, err
+	}
+
+	sold := make(map[string]int, len(inventories))
+	for _, inventory := range inventories {
+		sold[inventory.Time] = inventory.Sold
+	}
+
+	departures := route.Departures(timetable)
+	seats := make([]models.DepartureSeats, 0, len(departures))
+	for _, departureTime := range departures {
+		key := departureTime.String()
+		remaining := -1
+		if departureTime.Capacity > 0 {
+			remaining = max(departureTime.Capacity-sold[key], 0)
+		}
+		seats = append(seats, models.DepartureSeats{
+			Time:      key,
+			Capacity:  departureTime.Capacity,
+			Sold:      sold[key],
+			Remaining: remaining,
+		})
+	}
+
+	return seats, nil
+}
+
+// reserveSeatsTx takes the seats of a sale from each departure's inventory inside the sale transaction.
+// Capacities come from the route's timetable for each travel date (see saleTimetable); departures not in
+// the timetable are counted without a limit.
+func (t *TicketService) reserveSeatsTx(tx *sql.Tx, tickets []models.Ticket) error {
+	reportTimetable, err := t.reportTimetable(tickets[0].ReportID)
+	if err != nil {
+		return err
+	}
+
+	cfg := config.LoadPOSConfig()
+	routes := local.NewRouteRepository(t.cloverDB)
+	repository := local.NewSeatInventoryRepository(t.ctx, t.localDB)
+
+	seats := map[models.SeatInventory]int{}
+	var order []models.SeatInventory
+	for _, ticket := range tickets {
+		key := seatInventoryKey(ticket)
+		if _, ok := seats[key]; !ok {
+			order = append(order, key)
+		}
+		seats[key]++
+	}
+
+	for _, key := range order {
+		route, err := routes.GetByName(key.Departure, key.Destination)
+		if err != nil && !errors.Is(err, helpers.ErrRowNotFound) {
+			return err
+		}
+
+		inventory := key
+		if route != nil {
+			timetable := saleTimetable(cfg, key.TravelDate, reportTimetable)
+			if departureTime, ok := route.FindDeparture(timetable, key.Time); ok {
+				inventory.Capacity = departureTime.Capacity
+			}
+		}
+
+		if err := repository.ReserveTx(tx, inventory, seats[key]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// releaseSeatTx gives a voided ticket's seat back to its departure, and frees its numbered seat, inside
+// the void transaction
+func (t *TicketService) releaseSeatTx(tx *sql.Tx, ticket models.Ticket) error {
+	repository := local.NewSeatInventoryRepository(t.ctx, t.localDB)
+	if err := repository.ReleaseTx(tx, seatInventoryKey(ticket), 1); err != nil {
+		return err
+	}
+
+	reservations := local.NewSeatReservationRepository(t.ctx, t.localDB)
+	return reservations.DeleteByTicketIDTx(tx, ticket.ID)
+}
+
+// reportTimetable returns the timetable (regular or holiday) a report sells on
+func (t *TicketService) reportTimetable(reportID int64) (enums.Timetable, error) {
+	report, err := local.NewReportRepository(t.ctx, t.localDB).GetByID(reportID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", helpers.ErrRowNotFound
+		}
+		return "", err
+	}
+	return report.Timetable, nil
+}
+
+// seatInventoryKey identifies the departure a ticket takes a seat on
+func seatInventoryKey(ticket models.Ticket) models.SeatInventory {
+	return models.SeatInventory{
+		Departure:   ticket.Departure,
+		Destination: ticket.Destination,
+		TravelDate:  ticketTravelDate(ticket),
+		Time:        ticket.Time,
+	}
+}
+
+// ticketTravelDate returns the local date (YYYY-MM-DD) a ticket travels on. Tickets without a travel
+// date travel on their sale date.
+func ticketTravelDate(ticket models.Ticket) string {
+	if ticket.TravelDate != "" {
+		return ticket.TravelDate
+	}
+
+	date := time.Now()
+	if createdAt, err := time.Parse(time.RFC3339, ticket.CreatedAt); err == nil {
+		date = createdAt.Local()
+	}
+	return date.Format(constants.DateLayout)
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"neon/core/constants"
+	"neon/core/helpers"
+	"neon/core/models"
+	"neon/core/repositories/local"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// GetSeatMap returns the bus layout of a route and the numbered seats booked on a departure (HH:MM) on a
+// travel date (YYYY-MM-DD), for the frontend to draw the seat map
+func (t *TicketService) GetSeatMap(departure string, destination string, travelDate string, departureTime string) (*models.SeatMap, error) {
+	if _, err := time.ParseInLocation(constants.DateLayout, travelDate, time.Local); err != nil {
+		return nil, helpers.ErrInvalidRequest
+	}
+
+	route, err := local.NewRouteRepository(t.cloverDB).GetByName(departure, destination)
+	if err != nil {
+		zap.L().Error("failed to get route", zap.Error(err))
+		return nil, err
+	}
+
+	repository := local.NewSeatReservationRepository(t.ctx, t.localDB)
+	occupied, err := repository.GetByDeparture(departure, destination, travelDate, departureTime)
+	if err != nil {
+		zap.L().Error("failed to get seat reservations", zap.Error(err))
+		return nil, err
+	}
+
+	return &models.SeatMap{
+		Departure:   departure,
+		Destination: destination,
+		TravelDate:  travelDate,
+		Time:        departureTime,
+		Layout:      route.Layout,
+		Occupied:    occupied,
+	}, nil
+}
+
+// validateSeats checks that every numbered seat of a sale exists in its route's bus layout and is not
+// blocked. Whether it is free is checked when it is booked.
+func (t *TicketService) validateSeats(tickets []models.Ticket) error {
+	routes := local.NewRouteRepository(t.cloverDB)
+	for _, ticket := range tickets {
+		if ticket.SeatNumber == 0 {
+			continue
+		}
+
+		route, err := routes.GetByName(ticket.Departure, ticket.Destination)
+		if err != nil {
+			if errors.Is(err, helpers.ErrRowNotFound) {
+				return helpers.ErrInvalidSeat
+			}
+			return err
+		}
+
+		if !route.Layout.IsSellable(ticket.SeatNumber) {
+			return helpers.ErrInvalidSeat
+		}
+	}
+
+	return nil
+}
+
+// bookSeatsTx books the numbered seats of the tickets of a sale inside the sale transaction, rejecting
+// seats already booked on the departure with ErrSeatTaken
+func (t *TicketService) bookSeatsTx(tx *sql.Tx, tickets []models.Ticket) error {
+	repository := local.NewSeatReservationRepository(t.ctx, t.localDB)
+	for _, ticket := range tickets {
+		if ticket.SeatNumber == 0 {
+			continue
+		}
+
+		key := seatInventoryKey(ticket)
+		if _, err := repository.AddTx(tx, models.SeatReservation{
+			TicketID:    ticket.ID,
+			Departure:   key.Departure,
+			Destination: key.Destination,
+			TravelDate:  key.TravelDate,
+			Time:        key.Time,
+			SeatNumber:  ticket.SeatNumber,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
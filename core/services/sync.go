@@ -8,6 +8,7 @@ import (
 	"neon/core/database/embedded"
 	remotedb "neon/core/database/remote"
 	"neon/core/helpers"
+	"neon/core/models"
 	"neon/core/repositories/local"
 	"neon/core/repositories/remote"
 
@@ -104,3 +105,85 @@ func (s *SyncService) SyncUsers() error {
 
 	return nil
 }
+
+// SyncGoldPassengers syncs the gold passenger registry from the remote repository to the local repository
+func (s *SyncService) SyncGoldPassengers() error {
+	// Check internet connectivity before attempting sync
+	if err := helpers.CheckInternetConnection(); err != nil {
+		return err
+	}
+
+	remotedb := remotedb.NewMongoDB(config.GetMongoDBConfig())
+	if err := remotedb.Connect(s.ctx); err != nil {
+		zap.L().Error("failed to connect to remote database", zap.Error(err))
+		return err
+	}
+	defer remotedb.Close()
+	remoteRepo := remote.NewGoldPassengerRepository(remotedb)
+	localRepo := local.NewGoldPassengerRepository(s.localDB)
+
+	passengers, err := remoteRepo.All(s.ctx)
+	if err != nil {
+		zap.L().Error("failed to get gold passengers from remote repository", zap.Error(err))
+		return fmt.Errorf("failed to get gold passengers from remote repository: %w", err)
+	}
+
+	// The registry is looked up by the normalized ID, whatever format the office typed it in
+	valid := make([]models.GoldPassenger, 0, len(passengers))
+	for _, passenger := range passengers {
+		idNumber, err := helpers.ValidateIDNumber(passenger.IDType, passenger.IDNumber)
+		if err != nil {
+			zap.L().Warn("skipping gold passenger with an invalid ID", zap.String("id_number", passenger.IDNumber), zap.String("id_type", string(passenger.IDType)))
+			continue
+		}
+		passenger.IDNumber = idNumber
+		valid = append(valid, passenger)
+	}
+
+	if err := localRepo.Clear(); err != nil {
+		zap.L().Error("failed to clear local gold passengers before sync", zap.Error(err))
+		return fmt.Errorf("failed to clear local gold passengers before sync: %w", err)
+	}
+
+	if err := localRepo.BulkCreate(valid); err != nil {
+		zap.L().Error("failed to bulk create gold passengers", zap.Error(err))
+		return fmt.Errorf("failed to bulk create gold passengers: %w", err)
+	}
+
+	return nil
+}
+
+// SyncFareVersions syncs the scheduled route tariffs from the remote repository to the local repository
+func (s *SyncService) SyncFareVersions() error {
+	// Check internet connectivity before attempting sync
+	if err := helpers.CheckInternetConnection(); err != nil {
+		return err
+	}
+
+	remotedb := remotedb.NewMongoDB(config.GetMongoDBConfig())
+	if err := remotedb.Connect(s.ctx); err != nil {
+		zap.L().Error("failed to connect to remote database", zap.Error(err))
+		return err
+	}
+	defer remotedb.Close()
+	remoteRepo := remote.NewFareVersionRepository(remotedb)
+	localRepo := local.NewFareVersionRepository(s.localDB)
+
+	versions, err := remoteRepo.All(s.ctx)
+	if err != nil {
+		zap.L().Error("failed to get fare versions from remote repository", zap.Error(err))
+		return fmt.Errorf("failed to get fare versions from remote repository: %w", err)
+	}
+
+	if err := localRepo.Clear(); err != nil {
+		zap.L().Error("failed to clear local fare versions before sync", zap.Error(err))
+		return fmt.Errorf("failed to clear local fare versions before sync: %w", err)
+	}
+
+	if err := localRepo.BulkCreate(versions); err != nil {
+		zap.L().Error("failed to bulk create fare versions", zap.Error(err))
+		return fmt.Errorf("failed to bulk create fare versions: %w", err)
+	}
+
+	return nil
+}
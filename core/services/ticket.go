@@ -4,9 +4,14 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"neon/core/config"
+	"neon/core/constants"
 	"neon/core/database/embedded"
 	"neon/core/helpers"
+	"neon/core/helpers/enums"
 	"neon/core/models"
+	"neon/core/payment"
 	"neon/core/repositories/local"
 	"time"
 
@@ -17,12 +22,34 @@ import (
 type TicketService struct {
 	ctx          context.Context
 	localDB      *embedded.SQLite
+	cloverDB     *embedded.CloverDB
 	printService *PrintService
+	authService  *AuthService
+	// paymentProvider charges card payments on the card terminal, nil when there is none
+	paymentProvider  PaymentProvider
+	paymentSimulator *payment.Simulator
+	einvoiceService  *EInvoiceService
+	// outboxService retries the card captures and refunds the terminal couldn't settle, nil when there is
+	// no terminal
+	outboxService *OutboxService
 }
 
-// NewTicketService creates a new ticket service
-func NewTicketService(localDB *embedded.SQLite, printService *PrintService) *TicketService {
-	return &TicketService{localDB: localDB, printService: printService}
+// NewTicketService creates a new ticket service. Routes, for the seat capacities, are read from cloverDB.
+// The sales get their electronic invoice from einvoiceService when e-invoicing is on.
+func NewTicketService(
+	localDB *embedded.SQLite,
+	cloverDB *embedded.CloverDB,
+	printService *PrintService,
+	authService *AuthService,
+	einvoiceService *EInvoiceService,
+) *TicketService {
+	return &TicketService{
+		localDB:         localDB,
+		cloverDB:        cloverDB,
+		printService:    printService,
+		authService:     authService,
+		einvoiceService: einvoiceService,
+	}
 }
 
 // startup starts the ticket service
@@ -30,49 +57,84 @@ func (t *TicketService) startup(ctx context.Context) {
 	t.ctx = ctx
 }
 
-// AddTicket adds a ticket and returns the tickets with generated IDs
+// AddTicket adds a ticket and returns the tickets with generated IDs. The tickets are stored as one sale
+// (see AddSale). Tickets without a travel date travel today; later dates are advance sales and must be in
+// the route's timetable. Numbered seats are booked with the tickets and can't be sold twice. Fares are set
+// by the route's fare rules (see applyFares) and gold tickets are checked against the gold registry and
+// limits (see validateGoldTickets). The seats are taken from the departures' inventory in the same
+// transaction; the sale fails with ErrDepartureFull when one is full.
 func (t *TicketService) AddTicket(ticket []models.Ticket) ([]models.Ticket, error) {
-	repository := local.NewTicketRepository(t.ctx, t.localDB)
-	output, err := repository.BulkCreate(ticket)
+	if len(ticket) == 0 {
+		return ticket, nil
+	}
+
+	sale, err := t.sell(models.SaleRequest{Tickets: ticket}, "", false)
 	if err != nil {
-		zap.L().Error("failed to add tickets", zap.Error(err))
 		return nil, err
 	}
 
-	return output, nil
+	return sale.Tickets, nil
 }
 
-// AddTicketWithPrint saves tickets and prints them. If printing fails (e.g. no paper, printer disconnected),
-// created tickets are deleted and an error is returned so the sale is not persisted.
+// AddTicketWithPrint saves tickets as a single sale and prints them once the sale is committed. The printer
+// is checked before anything is charged or stored. If it fails while printing (e.g. paper runs out) the
+// sale is voided with the print_error reason, giving its seats, report totals and card payments back, and
+// the returned *TicketPrintError carries its ID and the receipts that did reach paper, to be taken back.
+// Seats are reserved first, so nothing prints for a full departure.
 func (t *TicketService) AddTicketWithPrint(tickets []models.Ticket, printerName string) ([]models.Ticket, error) {
-	// if t.printService == nil {
-	// 	return nil, fmt.Errorf("print service is not available")
-	// }
+	if t.printService == nil {
+		return nil, fmt.Errorf("print service is not available")
+	}
 
-	// if printerName == "" {
-	// 	return nil, fmt.Errorf("printer is not configured")
-	// }
+	if len(tickets) == 0 {
+		return tickets, nil
+	}
+
+	sale, err := t.sell(models.SaleRequest{Tickets: tickets}, printerName, true)
+	if err != nil {
+		return nil, err
+	}
 
-	// if err := t.printService.EnsurePrinterReady(printerName); err != nil {
-	// 	zap.L().Warn("printer is not ready, skipping ticket creation", zap.Error(err))
-	// 	return nil, err
-	// }
+	return sale.Tickets, nil
+}
+
+// prepareSale validates and prices the tickets of a sale before its transaction starts
+func (t *TicketService) prepareSale(tickets []models.Ticket) error {
+	if err := t.prepareTravelDates(tickets); err != nil {
+		zap.L().Error("failed to validate travel dates", zap.Error(err))
+		return err
+	}
+
+	if err := t.applyFares(tickets); err != nil {
+		return err
+	}
+
+	if err := t.validateSeats(tickets); err != nil {
+		return err
+	}
+
+	return t.validateGoldTickets(tickets)
+}
+
+// createTicketsTx takes the tickets' seats from the departures' inventory, stores the tickets and books
+// their numbered seats inside the caller's transaction
+func (t *TicketService) createTicketsTx(tx *sql.Tx, tickets []models.Ticket) ([]models.Ticket, error) {
+	if err := t.reserveSeatsTx(tx, tickets); err != nil {
+		zap.L().Error("failed to reserve seats", zap.Error(err))
+		return nil, err
+	}
 
 	repository := local.NewTicketRepository(t.ctx, t.localDB)
-	created, err := repository.BulkCreate(tickets)
+	created, err := repository.BulkCreateTx(tx, tickets)
 	if err != nil {
 		zap.L().Error("failed to add tickets", zap.Error(err))
 		return nil, err
 	}
 
-	// if err := t.printService.PrintTickets(created, printerName); err != nil {
-	// 	zap.L().Warn("print failed, rolling back tickets", zap.Error(err))
-	// 	if delErr := repository.BulkDelete(created); delErr != nil {
-	// 		zap.L().Error("failed to rollback tickets after print error", zap.Error(delErr))
-	// 		return nil, err
-	// 	}
-	// 	return nil, err
-	// }
+	if err := t.bookSeatsTx(tx, created); err != nil {
+		zap.L().Error("failed to book seats", zap.Error(err))
+		return nil, err
+	}
 
 	return created, nil
 }
@@ -88,67 +150,333 @@ func (t *TicketService) UpdateTickets(tickets []models.Ticket) error {
 	return nil
 }
 
-// NullifyTicket nullifies a ticket
+// NullifyTicket nullifies a ticket on behalf of the report's cashier. It is kept for callers that do
+// not collect a reason; use VoidTicket to record the reason and the approving admin.
 func (t *TicketService) NullifyTicket(ticketID int64, reportID int64) error {
+	_, err := t.VoidTicket(models.TicketVoidRequest{
+		TicketID: ticketID,
+		ReportID: reportID,
+		Reason:   enums.VoidOther,
+	})
+	return err
+}
+
+// VoidTicket voids a ticket and records the reason, who voided it and who approved it. Voids of
+// tickets over the configured amount, or sold before the report's latest drop, need an admin's credentials.
+// A ticket of an invoiced sale gets a Nota de Crédito for its fare, queued with the void.
+func (t *TicketService) VoidTicket(request models.TicketVoidRequest) (*models.TicketVoid, error) {
+	if !request.Reason.IsValid() {
+		return nil, helpers.ErrInvalidVoidReason
+	}
+
 	repository := local.NewTicketRepository(t.ctx, t.localDB)
-	ticket, err := repository.GetByID(ticketID)
+	ticket, err := repository.GetByID(request.TicketID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return helpers.ErrRowNotFound
+			return nil, helpers.ErrRowNotFound
 		}
 		zap.L().Error("failed to get ticket", zap.Error(err))
-		return err
+		return nil, err
+	}
+
+	report, err := t.getVoidReport(request.ReportID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkVoidable(ticket, report); err != nil {
+		return nil, err
 	}
 
+	approvedBy, err := t.voidApproval(request, []models.Ticket{*ticket})
+	if err != nil {
+		return nil, err
+	}
+
+	if request.VoidedBy, err = t.voidedBy(request.VoidedBy, report.ID); err != nil {
+		return nil, err
+	}
+
+	tx, err := t.localDB.BeginTx(t.ctx, nil)
+	if err != nil {
+		zap.L().Error("failed to begin void transaction", zap.Error(err))
+		return nil, err
+	}
+
+	void, refunds, err := t.voidTicketTx(tx, *ticket, report, request, approvedBy)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	reason := fmt.Sprintf("Anulación del tiquete %d", ticket.ID)
+	if _, err := t.einvoiceService.creditTx(tx, ticket.SaleID, report.ID, []models.Ticket{*ticket}, reason); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		zap.L().Error("failed to commit ticket void", zap.Error(err))
+		return nil, err
+	}
+
+	t.settleCardPayments(refunds)
+
+	return void, nil
+}
+
+// getVoidReport loads the report a void is charged to
+func (t *TicketService) getVoidReport(reportID int64) (*models.Report, error) {
 	reportRepository := local.NewReportRepository(t.ctx, t.localDB)
 	report, err := reportRepository.GetByID(reportID)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, helpers.ErrRowNotFound
+		}
 		zap.L().Error("failed to get report", zap.Error(err))
-		return err
+		return nil, err
 	}
 
+	return report, nil
+}
+
+// checkVoidable checks that a ticket belongs to the open report and is not voided yet
+func checkVoidable(ticket *models.Ticket, report *models.Report) error {
 	if ticket.ReportID != report.ID {
 		return helpers.ErrTicketNotBelongToReport
 	}
 
-	// If the report is partiallly closed, we can't nullify a ticket created before the partial closed at
-	if report.PartialClosedAt != nil {
-		createdAt, err := time.Parse(time.RFC3339, ticket.CreatedAt)
-		if err != nil {
-			zap.L().Error("failed to parse created at", zap.Error(err))
-			return err
-		}
+	if !report.Status {
+		return helpers.ErrTicketAlreadyClosed
+	}
+
+	if ticket.IsNull {
+		return helpers.ErrTicketAlreadyNullified
+	}
 
-		partialClosedAt, err := time.Parse(time.RFC3339, *report.PartialClosedAt)
+	return nil
+}
+
+// voidApproval returns the admin approving a void of tickets, nil when none is needed. An admin is needed
+// when the fares voided together exceed the configured amount or a ticket was sold before the report's
+// latest drop.
+func (t *TicketService) voidApproval(request models.TicketVoidRequest, tickets []models.Ticket) (*string, error) {
+	total := 0
+	dropped := false
+	for i := range tickets {
+		total += tickets[i].Fare
+		dropped = dropped || tickets[i].DropSequence > 0
+	}
+
+	approvalAmount := config.LoadPOSConfig().VoidApprovalAmount
+	if !dropped && (approvalAmount <= 0 || total <= approvalAmount) {
+		return nil, nil
+	}
+
+	approver, err := t.approveVoid(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return &approver.Username, nil
+}
+
+// voidedBy returns who voids on a report: the one given, else the report's current cashier, who changes at
+// each accepted handover
+func (t *TicketService) voidedBy(voidedBy string, reportID int64) (string, error) {
+	if voidedBy != "" {
+		return voidedBy, nil
+	}
+	return reportCashier(t.ctx, t.localDB, reportID)
+}
+
+// voidTicketTx nullifies a ticket, frees its seat and records the void inside the caller's transaction. It
+// returns the card refunds it queued, for the caller to give back once the transaction is committed.
+func (t *TicketService) voidTicketTx(
+	tx *sql.Tx,
+	ticket models.Ticket,
+	report *models.Report,
+	request models.TicketVoidRequest,
+	approvedBy *string,
+) (*models.TicketVoid, []models.OutboxMessage, error) {
+	repository := local.NewTicketRepository(t.ctx, t.localDB)
+	if err := repository.UpdateTx(tx, models.Ticket{ID: ticket.ID, IsNull: true, ReportID: report.ID}); err != nil {
+		zap.L().Error("failed to nullify ticket", zap.Error(err))
+		return nil, nil, err
+	}
+
+	if err := t.releaseSeatTx(tx, ticket); err != nil {
+		zap.L().Error("failed to release seat", zap.Error(err))
+		return nil, nil, err
+	}
+
+	var refunds []models.OutboxMessage
+	if ticket.SaleID != 0 {
+		var err error
+		refunds, err = t.refundTicketTx(tx, ticket)
 		if err != nil {
-			zap.L().Error("failed to parse created at", zap.Error(err))
-			return err
+			zap.L().Error("failed to refund ticket", zap.Error(err))
+			return nil, nil, err
 		}
+	}
+
+	voidRepository := local.NewTicketVoidRepository(t.ctx, t.localDB)
+	// Always final, even for a ticket sold before the last drop, since the refund is paid from the drawer
+	// open now (see VoidBucketFinal)
+	void, err := voidRepository.AddTx(tx, models.TicketVoid{
+		TicketID:   ticket.ID,
+		ReportID:   report.ID,
+		Reason:     request.Reason,
+		Note:       request.Note,
+		Fare:       ticket.Fare,
+		Bucket:     models.VoidBucketFinal,
+		VoidedBy:   request.VoidedBy,
+		ApprovedBy: approvedBy,
+		CreatedAt:  time.Now().Format(time.RFC3339),
+	})
+	if err != nil {
+		zap.L().Error("failed to record ticket void", zap.Error(err))
+		return nil, nil, err
+	}
+
+	return void, refunds, nil
+}
+
+// refundTicketTx records the refund of a voided ticket as negative payments of its sale, so the report's
+// totals per payment method give the fare back to the methods it was paid with (see refundPayments).
+// Card payments charged on the terminal are given back there: their refunds are recorded pending and
+// queued, and returned for the caller to settle after the commit. A charge given back whole before its
+// capture is released instead (see releaseChargeTx).
+func (t *TicketService) refundTicketTx(tx *sql.Tx, ticket models.Ticket) ([]models.OutboxMessage, error) {
+	repository := local.NewSalePaymentRepository(t.ctx, t.localDB)
+	payments, err := repository.GetBySaleIDTx(tx, ticket.SaleID)
+	if err != nil {
+		return nil, err
+	}
 
-		if createdAt.After(partialClosedAt) {
-			if ticket.IsNull {
-				return helpers.ErrTicketAlreadyNullified
+	var queued []models.OutboxMessage
+	now := time.Now().Format(time.RFC3339)
+	for _, refund := range refundPayments(payments, ticket.Fare) {
+		refund.SaleID = ticket.SaleID
+		refund.CreatedAt = now
+		onTerminal := t.onTerminal(refund)
+		kind := enums.OutboxCardRefund
+		if onTerminal {
+			refund.RefundStatus = enums.OutboxPending
+			released, err := t.releaseChargeTx(tx, payments, refund)
+			if err != nil {
+				return nil, err
 			}
-			if err := repository.Update(models.Ticket{ID: ticketID, IsNull: true, ReportID: reportID}); err != nil {
-				zap.L().Error("failed to nullify ticket", zap.Error(err))
-				return err
+			if released {
+				kind = enums.OutboxCardVoid
 			}
+		}
+		created, err := repository.AddTx(tx, refund)
+		if err != nil {
+			return nil, err
+		}
+		if !onTerminal {
+			continue
+		}
+		message, err := t.queueCardPaymentTx(tx, kind, *created, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		queued = append(queued, *message)
+	}
+
+	return queued, nil
+}
+
+// GetTicketVoids returns the voids recorded between two dates (inclusive, YYYY-MM-DD)
+func (t *TicketService) GetTicketVoids(from string, to string) ([]models.TicketVoid, error) {
+	fromDate, err := time.ParseInLocation(constants.DateLayout, from, time.Local)
+	if err != nil {
+		return nil, helpers.ErrInvalidRequest
+	}
+	toDate, err := time.ParseInLocation(constants.DateLayout, to, time.Local)
+	if err != nil {
+		return nil, helpers.ErrInvalidRequest
+	}
+
+	repository := local.NewTicketVoidRepository(t.ctx, t.localDB)
+	voids, err := repository.GetByDateRange(
+		fromDate.Format(constants.DateLayout),
+		toDate.AddDate(0, 0, 1).Format(constants.DateLayout),
+	)
+	if err != nil {
+		zap.L().Error("failed to get ticket voids", zap.Error(err))
+		return nil, err
+	}
+
+	return voids, nil
+}
+
+// GetTicketVoidsByReport returns the voids recorded on a report
+func (t *TicketService) GetTicketVoidsByReport(reportID int64) ([]models.TicketVoid, error) {
+	repository := local.NewTicketVoidRepository(t.ctx, t.localDB)
+	voids, err := repository.GetByReportID(reportID)
+	if err != nil {
+		zap.L().Error("failed to get ticket voids", zap.Error(err))
+		return nil, err
+	}
+
+	return voids, nil
+}
+
+// approveVoid checks the approver's credentials and that the approver is an admin
+func (t *TicketService) approveVoid(request models.TicketVoidRequest) (*models.User, error) {
+	if t.authService == nil || request.ApproverUsername == "" || request.ApproverPassword == "" {
+		return nil, helpers.ErrVoidApprovalRequired
+	}
+
+	approver, err := t.authService.Login(request.ApproverUsername, request.ApproverPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	if enums.Role(approver.Role) != enums.Admin {
+		return nil, helpers.ErrVoidApproverNotAdmin
+	}
+
+	return approver, nil
+}
 
-			return nil
+// ValidateTicketPayload verifies a scanned ticket code, either the QR payload or the barcode short code,
+// against the installation's signing key and the ticket stored in SQLite, and reports whether it is a
+// genuine, unused ticket for today (and for departureTime, when given as HH:MM). Counterfeit and reused
+// tickets are reported through the status rather than as errors. Validating records nothing: the ticket
+// is boarded with BoardingService.BoardTicket.
+func (t *TicketService) ValidateTicketPayload(payload string, departureTime string) (*models.TicketValidation, error) {
+	ticket, err := findTicketByCode(t.ctx, t.localDB, payload, false)
+	if err != nil {
+		switch {
+		case errors.Is(err, helpers.ErrInvalidTicketCode):
+			return &models.TicketValidation{Status: enums.ValidationInvalid}, nil
+		case errors.Is(err, helpers.ErrRowNotFound):
+			return &models.TicketValidation{Status: enums.ValidationNotFound}, nil
 		}
-		return helpers.ErrTicketAlreadyClosed
+		return nil, err
 	}
 
 	if ticket.IsNull {
-		return helpers.ErrTicketAlreadyNullified
+		return &models.TicketValidation{Status: enums.ValidationVoided, Ticket: ticket}, nil
 	}
 
-	if err := repository.Update(models.Ticket{ID: ticketID, IsNull: true, ReportID: reportID}); err != nil {
-		zap.L().Error("failed to nullify ticket", zap.Error(err))
-		return err
+	if !isTicketForDeparture(ticket, departureTime) {
+		return &models.TicketValidation{Status: enums.ValidationWrongDeparture, Ticket: ticket}, nil
 	}
 
-	return nil
+	boarding, err := local.NewTicketBoardingRepository(t.ctx, t.localDB).GetByTicketID(ticket.ID)
+	if err == nil {
+		return &models.TicketValidation{Status: enums.ValidationUsed, Ticket: ticket, Boarding: boarding}, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		zap.L().Error("failed to get ticket boarding", zap.Error(err))
+		return nil, err
+	}
+
+	return &models.TicketValidation{Status: enums.ValidationValid, Ticket: ticket}, nil
 }
 
 // DeleteTickets deletes a bulk of tickets
@@ -161,3 +489,12 @@ func (t *TicketService) DeleteTickets(tickets []models.Ticket) error {
 
 	return nil
 }
+
+// ticketIDs returns the IDs of the given tickets in order
+func ticketIDs(tickets []models.Ticket) []int64 {
+	ids := make([]int64, len(tickets))
+	for i, ticket := range tickets {
+		ids[i] = ticket.ID
+	}
+	return ids
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"errors"
+	"neon/core/config"
+	"neon/core/constants"
+	"neon/core/helpers"
+	"neon/core/helpers/enums"
+	"neon/core/models"
+	"neon/core/repositories/local"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// prepareTravelDates sets the travel date of every ticket of a sale, today when none is given, and checks
+// it against the advance sale window and the route's timetable for that date. Tickets for a later date
+// are flagged as advance sales so the report keeps their cash apart.
+func (t *TicketService) prepareTravelDates(tickets []models.Ticket) error {
+	reportTimetable, err := t.reportTimetable(tickets[0].ReportID)
+	if err != nil {
+		return err
+	}
+
+	cfg := config.LoadPOSConfig()
+	today := time.Now().Format(constants.DateLayout)
+	lastDate := time.Now().AddDate(0, 0, cfg.AdvanceSaleDays).Format(constants.DateLayout)
+	routes := local.NewRouteRepository(t.cloverDB)
+
+	for i := range tickets {
+		if tickets[i].TravelDate == "" {
+			tickets[i].TravelDate = today
+		}
+
+		if _, err := time.ParseInLocation(constants.DateLayout, tickets[i].TravelDate, time.Local); err != nil {
+			return helpers.ErrInvalidTravelDate
+		}
+		if tickets[i].TravelDate < today || tickets[i].TravelDate > lastDate {
+			return helpers.ErrInvalidTravelDate
+		}
+		tickets[i].IsAdvance = tickets[i].TravelDate > today
+
+		route, err := routes.GetByName(tickets[i].Departure, tickets[i].Destination)
+		if err != nil {
+			if errors.Is(err, helpers.ErrRowNotFound) {
+				continue
+			}
+			return err
+		}
+
+		timetable := saleTimetable(cfg, tickets[i].TravelDate, reportTimetable)
+		if _, ok := route.FindDeparture(timetable, tickets[i].Time); !ok {
+			return helpers.ErrDepartureNotInTimetable
+		}
+	}
+
+	return nil
+}
+
+// saleTimetable returns the timetable a travel date runs on. Today runs on the open report's timetable,
+// chosen by the cashier; later dates follow the holiday calendar in pos.yaml.
+func saleTimetable(cfg *config.POSConfig, travelDate string, reportTimetable enums.Timetable) enums.Timetable {
+	if travelDate <= time.Now().Format(constants.DateLayout) {
+		return reportTimetable
+	}
+
+	date, err := time.ParseInLocation(constants.DateLayout, travelDate, time.Local)
+	if err == nil && cfg.IsHoliday(date) {
+		return enums.Holiday
+	}
+	return enums.Regular
+}
+
+// GetAdvanceSales returns the tickets sold for every departure after today, grouped by route, travel
+// date and departure time
+func (t *TicketService) GetAdvanceSales() ([]models.AdvanceDeparture, error) {
+	repository := local.NewTicketRepository(t.ctx, t.localDB)
+	departures, err := repository.GetAdvanceSales(time.Now().Format(constants.DateLayout))
+	if err != nil {
+		zap.L().Error("failed to get advance sales", zap.Error(err))
+		return nil, err
+	}
+
+	return departures, nil
+}
+
+// GetDepartureTickets returns the tickets, voided ones included, sold for a departure of a route on a
+// travel date (YYYY-MM-DD)
+func (t *TicketService) GetDepartureTickets(departure string, destination string, travelDate string, departureTime string) ([]models.Ticket, error) {
+	if _, err := time.ParseInLocation(constants.DateLayout, travelDate, time.Local); err != nil {
+		return nil, helpers.ErrInvalidRequest
+	}
+
+	repository := local.NewTicketRepository(t.ctx, t.localDB)
+	tickets, err := repository.GetByDeparture(departure, destination, travelDate, departureTime)
+	if err != nil {
+		zap.L().Error("failed to get departure tickets", zap.Error(err))
+		return nil, err
+	}
+
+	return tickets, nil
+}
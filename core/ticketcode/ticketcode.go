@@ -0,0 +1,196 @@
+// Package ticketcode builds and verifies the HMAC-signed codes printed on tickets
+package ticketcode
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"neon/core/models"
+)
+
+const (
+	// payloadPrefix versions the QR payload format. NT2 added the departure to the signed fields.
+	payloadPrefix = "NT2"
+	// signatureSize is the number of HMAC bytes kept in the QR payload
+	signatureSize = 12
+	// shortSignatureSize is the number of HMAC bytes kept in the barcode short code
+	shortSignatureSize = 4
+	dateLayout         = "20060102"
+)
+
+var (
+	// ErrMalformed is returned when a scanned code can't be decoded
+	ErrMalformed = errors.New("malformed ticket code")
+	// ErrBadSignature is returned when a scanned code was not signed with this installation's key
+	ErrBadSignature = errors.New("ticket code signature mismatch")
+
+	encoding = base64.RawURLEncoding
+)
+
+// Claims are the ticket fields carried by a QR payload. Departure and Route are the stops the ticket
+// travels from and to.
+type Claims struct {
+	TicketID  int64  `json:"ticket_id"`
+	ReportID  int64  `json:"report_id"`
+	Departure string `json:"departure"`
+	Route     string `json:"route"`
+	Stop      string `json:"stop"`
+	Time      string `json:"time"`
+	Fare      int    `json:"fare"`
+	IsGold    bool   `json:"is_gold"`
+	Date      string `json:"date"`
+}
+
+// ClaimsFromTicket builds the claims of a ticket. The date is the ticket's travel date, or its local
+// sale date for tickets sold before advance sales.
+func ClaimsFromTicket(ticket models.Ticket) Claims {
+	date := time.Now()
+	if travelDate, err := time.ParseInLocation("2006-01-02", ticket.TravelDate, time.Local); err == nil {
+		date = travelDate
+	} else if createdAt, err := time.Parse(time.RFC3339, ticket.CreatedAt); err == nil {
+		date = createdAt.Local()
+	}
+
+	return Claims{
+		TicketID:  ticket.ID,
+		ReportID:  ticket.ReportID,
+		Departure: ticket.Departure,
+		Route:     ticket.Destination,
+		Stop:      ticket.Stop,
+		Time:      ticket.Time,
+		Fare:      ticket.Fare,
+		IsGold:    ticket.IsGold,
+		Date:      date.Format(dateLayout),
+	}
+}
+
+// Encode returns the compact signed QR payload: NT2.<base64 fields>.<base64 signature>
+func Encode(claims Claims, key []byte) string {
+	body := encoding.EncodeToString([]byte(claims.canonical()))
+	signature := sign(key, claims.canonical())[:signatureSize]
+	return payloadPrefix + "." + body + "." + encoding.EncodeToString(signature)
+}
+
+// Decode verifies a QR payload with key and returns its claims
+func Decode(payload string, key []byte) (Claims, error) {
+	parts := strings.Split(strings.TrimSpace(payload), ".")
+	if len(parts) != 3 || parts[0] != payloadPrefix {
+		return Claims{}, ErrMalformed
+	}
+
+	body, err := encoding.DecodeString(parts[1])
+	if err != nil {
+		return Claims{}, ErrMalformed
+	}
+	signature, err := encoding.DecodeString(parts[2])
+	if err != nil {
+		return Claims{}, ErrMalformed
+	}
+
+	if !hmac.Equal(signature, sign(key, string(body))[:signatureSize]) {
+		return Claims{}, ErrBadSignature
+	}
+
+	claims, err := parseCanonical(string(body))
+	if err != nil {
+		return Claims{}, err
+	}
+
+	return claims, nil
+}
+
+// ShortCode returns the barcode fallback: <ticket id>-<8 hex signature chars>. It is short enough for
+// a CODE128 barcode on 58mm paper; the other fields are checked against the database when validating.
+func ShortCode(claims Claims, key []byte) string {
+	signature := sign(key, claims.canonical())[:shortSignatureSize]
+	return fmt.Sprintf("%d-%s", claims.TicketID, strings.ToUpper(hex.EncodeToString(signature)))
+}
+
+// ParseShortCode returns the ticket ID of a short code, to be verified with VerifyShortCode
+func ParseShortCode(code string) (int64, error) {
+	id, _, ok := strings.Cut(strings.TrimSpace(code), "-")
+	if !ok {
+		return 0, ErrMalformed
+	}
+	ticketID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return 0, ErrMalformed
+	}
+	return ticketID, nil
+}
+
+// VerifyShortCode checks a short code against the claims of the ticket stored in the database
+func VerifyShortCode(code string, claims Claims, key []byte) error {
+	if !hmac.Equal([]byte(strings.ToUpper(strings.TrimSpace(code))), []byte(ShortCode(claims, key))) {
+		return ErrBadSignature
+	}
+	return nil
+}
+
+// IsPayload reports whether a scanned code is a QR payload rather than a short code
+func IsPayload(code string) bool {
+	return strings.HasPrefix(strings.TrimSpace(code), payloadPrefix+".")
+}
+
+func (c Claims) canonical() string {
+	gold := "0"
+	if c.IsGold {
+		gold = "1"
+	}
+	return strings.Join([]string{
+		strconv.FormatInt(c.TicketID, 10),
+		strconv.FormatInt(c.ReportID, 10),
+		c.Departure,
+		c.Route,
+		c.Stop,
+		c.Time,
+		strconv.Itoa(c.Fare),
+		gold,
+		c.Date,
+	}, "|")
+}
+
+func parseCanonical(body string) (Claims, error) {
+	fields := strings.Split(body, "|")
+	if len(fields) != 9 {
+		return Claims{}, ErrMalformed
+	}
+
+	ticketID, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return Claims{}, ErrMalformed
+	}
+	reportID, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return Claims{}, ErrMalformed
+	}
+	fare, err := strconv.Atoi(fields[6])
+	if err != nil {
+		return Claims{}, ErrMalformed
+	}
+
+	return Claims{
+		TicketID:  ticketID,
+		ReportID:  reportID,
+		Departure: fields[2],
+		Route:     fields[3],
+		Stop:      fields[4],
+		Time:      fields[5],
+		Fare:      fare,
+		IsGold:    fields[7] == "1",
+		Date:      fields[8],
+	}, nil
+}
+
+func sign(key []byte, message string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(message))
+	return mac.Sum(nil)
+}
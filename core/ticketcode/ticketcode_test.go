@@ -0,0 +1,206 @@
+package ticketcode
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"neon/core/models"
+)
+
+var (
+	testKey  = []byte("installation key")
+	otherKey = []byte("another installation")
+)
+
+func testClaims() Claims {
+	return Claims{
+		TicketID:  42,
+		ReportID:  7,
+		Departure: "San José",
+		Route:     "Cartago",
+		Stop:      "Tres Ríos",
+		Time:      "06:30",
+		Fare:      1150,
+		IsGold:    true,
+		Date:      "20260309",
+	}
+}
+
+// tamper re-encodes the fields of a payload, changed by edit, keeping its signature
+func tamper(payload string, edit func(*Claims)) string {
+	parts := strings.Split(payload, ".")
+	body, _ := encoding.DecodeString(parts[1])
+	claims, _ := parseCanonical(string(body))
+	edit(&claims)
+	return parts[0] + "." + encoding.EncodeToString([]byte(claims.canonical())) + "." + parts[2]
+}
+
+func TestDecode(t *testing.T) {
+	claims := testClaims()
+	payload := Encode(claims, testKey)
+	parts := strings.Split(payload, ".")
+
+	tests := []struct {
+		name    string
+		payload string
+		key     []byte
+		want    Claims
+		wantErr error
+	}{
+		{"valid", payload, testKey, claims, nil},
+		{"surrounding whitespace", " " + payload + "\n", testKey, claims, nil},
+		{"other key", payload, otherKey, Claims{}, ErrBadSignature},
+		{"departure changed", tamper(payload, func(c *Claims) { c.Departure = "Paraíso" }), testKey, Claims{}, ErrBadSignature},
+		{"route changed", tamper(payload, func(c *Claims) { c.Route = "Turrialba" }), testKey, Claims{}, ErrBadSignature},
+		{"fare changed", tamper(payload, func(c *Claims) { c.Fare = 1 }), testKey, Claims{}, ErrBadSignature},
+		{"gold changed", tamper(payload, func(c *Claims) { c.IsGold = false }), testKey, Claims{}, ErrBadSignature},
+		{"date changed", tamper(payload, func(c *Claims) { c.Date = "20260310" }), testKey, Claims{}, ErrBadSignature},
+		{"previous version", "NT1." + parts[1] + "." + parts[2], testKey, Claims{}, ErrMalformed},
+		{"missing signature", parts[0] + "." + parts[1], testKey, Claims{}, ErrMalformed},
+		{"body not base64", parts[0] + ".!!." + parts[2], testKey, Claims{}, ErrMalformed},
+		{"signature not base64", parts[0] + "." + parts[1] + ".!!", testKey, Claims{}, ErrMalformed},
+		{"short code", ShortCode(claims, testKey), testKey, Claims{}, ErrMalformed},
+		{"empty", "", testKey, Claims{}, ErrMalformed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Decode(tt.payload, tt.key)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Decode() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Decode() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeMalformedFields(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"too few fields", "42|7|San José|Cartago|Tres Ríos|06:30|1150|1"},
+		{"too many fields", "42|7|San José|Cartago|Tres Ríos|06:30|1150|1|20260309|x"},
+		{"ticket ID not a number", "x|7|San José|Cartago|Tres Ríos|06:30|1150|1|20260309"},
+		{"report ID not a number", "42|x|San José|Cartago|Tres Ríos|06:30|1150|1|20260309"},
+		{"fare not a number", "42|7|San José|Cartago|Tres Ríos|06:30|x|1|20260309"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Signed with the right key, so only the fields can fail
+			payload := payloadPrefix + "." + encoding.EncodeToString([]byte(tt.body)) + "." +
+				encoding.EncodeToString(sign(testKey, tt.body)[:signatureSize])
+			if _, err := Decode(payload, testKey); !errors.Is(err, ErrMalformed) {
+				t.Errorf("Decode() error = %v, want %v", err, ErrMalformed)
+			}
+		})
+	}
+}
+
+func TestVerifyShortCode(t *testing.T) {
+	claims := testClaims()
+	code := ShortCode(claims, testKey)
+
+	moved := claims
+	moved.Departure = "Paraíso"
+
+	tests := []struct {
+		name    string
+		code    string
+		claims  Claims
+		key     []byte
+		wantErr error
+	}{
+		{"valid", code, claims, testKey, nil},
+		{"lowercase and whitespace", " " + strings.ToLower(code) + " ", claims, testKey, nil},
+		{"other key", code, claims, otherKey, ErrBadSignature},
+		{"stored ticket departs elsewhere", code, moved, testKey, ErrBadSignature},
+		{"other ticket's code", ShortCode(Claims{TicketID: 43}, testKey), claims, testKey, ErrBadSignature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := VerifyShortCode(tt.code, tt.claims, tt.key); !errors.Is(err, tt.wantErr) {
+				t.Errorf("VerifyShortCode() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseShortCode(t *testing.T) {
+	tests := []struct {
+		name    string
+		code    string
+		want    int64
+		wantErr error
+	}{
+		{"valid", "42-0A1B2C3D", 42, nil},
+		{"surrounding whitespace", " 42-0A1B2C3D\n", 42, nil},
+		{"missing signature", "42", 0, ErrMalformed},
+		{"ID not a number", "x-0A1B2C3D", 0, ErrMalformed},
+		{"empty", "", 0, ErrMalformed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseShortCode(tt.code)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseShortCode() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseShortCode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsPayload(t *testing.T) {
+	tests := []struct {
+		name string
+		code string
+		want bool
+	}{
+		{"payload", Encode(testClaims(), testKey), true},
+		{"payload with whitespace", " " + Encode(testClaims(), testKey), true},
+		{"short code", ShortCode(testClaims(), testKey), false},
+		{"previous version", "NT1.abc.def", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsPayload(tt.code); got != tt.want {
+				t.Errorf("IsPayload() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClaimsFromTicket(t *testing.T) {
+	tests := []struct {
+		name     string
+		ticket   models.Ticket
+		wantDate string
+	}{
+		{"travel date", models.Ticket{TravelDate: "2026-03-10", CreatedAt: "2026-03-09T12:00:00Z"}, "20260310"},
+		{"sold before advance sales", models.Ticket{CreatedAt: "2026-03-09T12:00:00Z"}, "20260309"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.ticket.ID = 42
+			tt.ticket.Departure = "San José"
+			tt.ticket.Destination = "Cartago"
+			got := ClaimsFromTicket(tt.ticket)
+			if got.Date != tt.wantDate {
+				t.Errorf("ClaimsFromTicket() date = %s, want %s", got.Date, tt.wantDate)
+			}
+			if got.TicketID != 42 || got.Departure != "San José" || got.Route != "Cartago" {
+				t.Errorf("ClaimsFromTicket() = %+v, want the ticket's ID, departure and destination", got)
+			}
+		})
+	}
+}
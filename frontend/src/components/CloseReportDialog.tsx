@@ -7,17 +7,19 @@ import {
     Button,
     TextField,
     Typography,
-    InputAdornment
+    Grid,
+    Box
 } from "@mui/material";
-import { LocalAtm } from "@mui/icons-material";
 import { toast } from "react-toastify";
+import { models } from "../../wailsjs/go/models";
+import { DENOMINATIONS, formatCurrency } from "../util/reportHelpers";
 
 interface CloseReportDialogProps {
     open: boolean;
     closeType: 'partial' | 'total';
     reportLoading: boolean;
     onClose: () => void;
-    onCloseReport: (cashAmount: number, type: 'partial' | 'total') => Promise<void>;
+    onCloseReport: (counts: models.CashCount[], type: 'partial' | 'total') => Promise<void>;
 }
 
 const CloseReportDialog: React.FC<CloseReportDialogProps> = ({
@@ -27,22 +29,29 @@ const CloseReportDialog: React.FC<CloseReportDialogProps> = ({
     onClose,
     onCloseReport
 }) => {
-    const [finalCash, setFinalCash] = useState<string>('');
+    // Quantities counted per denomination; the drawer is counted without the cash expected in it
+    const [quantities, setQuantities] = useState<Record<number, string>>({});
+
+    const quantityOf = (denomination: number) => parseInt(quantities[denomination] || '0', 10);
+    const total = DENOMINATIONS.reduce((sum, denomination) => sum + (quantityOf(denomination) || 0) * denomination, 0);
 
     const handleClose = () => {
-        setFinalCash('');
+        setQuantities({});
         onClose();
     };
 
     const handleSubmit = async () => {
-        const cashAmount = parseFloat(finalCash);
-        if (!finalCash || isNaN(cashAmount) || cashAmount < 0) {
-            toast.error('Por favor ingrese un monto final válido');
+        if (DENOMINATIONS.some((denomination) => isNaN(quantityOf(denomination)) || quantityOf(denomination) < 0)) {
+            toast.error('Por favor ingrese cantidades válidas');
             return;
         }
 
+        const counts = DENOMINATIONS
+            .filter((denomination) => quantityOf(denomination) > 0)
+            .map((denomination) => models.CashCount.createFrom({ denomination, quantity: quantityOf(denomination) }));
+
         try {
-            await onCloseReport(Math.round(cashAmount), closeType);
+            await onCloseReport(counts, closeType);
             handleClose();
         } catch (error) {
             console.error('Error closing report:', error);
@@ -57,38 +66,43 @@ const CloseReportDialog: React.FC<CloseReportDialogProps> = ({
             </DialogTitle>
             <DialogContent>
                 <Typography variant="body2" color="text.secondary" sx={{ mb: 3 }}>
-                    {closeType === 'partial' 
+                    {closeType === 'partial'
                         ? 'El cierre parcial permite continuar vendiendo tiquetes pero registra el estado actual.'
                         : 'El cierre total finaliza completamente el reporte. No se podrán vender más tiquetes.'
                     }
-                </Typography>   
-                <TextField
-                    fullWidth
-                    label="Efectivo final contado"
-                    value={finalCash}
-                    onChange={(e) => setFinalCash(e.target.value)}
-                    type="number"
-                    slotProps={{
-                        input: {
-                            startAdornment: (
-                            <InputAdornment position="start">
-                                <LocalAtm />
-                                </InputAdornment>
-                            )
-                        }
-                    }}
-                    helperText="Ingrese el monto total de efectivo contado al momento del cierre"
-                />
+                </Typography>
+                <Typography variant="subtitle2" color="text.secondary" gutterBottom>
+                    Ingrese la cantidad de billetes y monedas contados en la caja
+                </Typography>
+                <Grid container spacing={2}>
+                    {DENOMINATIONS.map((denomination) => (
+                        <Grid key={denomination} size={{ xs: 6, sm: 4 }}>
+                            <TextField
+                                fullWidth
+                                size="small"
+                                label={formatCurrency(denomination)}
+                                value={quantities[denomination] ?? ''}
+                                onChange={(e) => setQuantities({ ...quantities, [denomination]: e.target.value })}
+                                type="number"
+                                slotProps={{ htmlInput: { min: 0, step: 1 } }}
+                            />
+                        </Grid>
+                    ))}
+                </Grid>
+                <Box sx={{ mt: 3, p: 2, bgcolor: 'action.hover', borderRadius: 1 }}>
+                    <Typography variant="caption" color="text.secondary">Efectivo contado</Typography>
+                    <Typography variant="h6">{formatCurrency(total)}</Typography>
+                </Box>
             </DialogContent>
             <DialogActions>
                 <Button onClick={handleClose}>
                     Cancelar
                 </Button>
-                <Button 
-                    onClick={handleSubmit} 
+                <Button
+                    onClick={handleSubmit}
                     variant="contained"
                     color={closeType === 'partial' ? 'warning' : 'error'}
-                    disabled={reportLoading || !finalCash}
+                    disabled={reportLoading}
                 >
                     {closeType === 'partial' ? 'Cierre Parcial' : 'Cierre Total'}
                 </Button>
@@ -97,4 +111,4 @@ const CloseReportDialog: React.FC<CloseReportDialogProps> = ({
     );
 };
 
-export default CloseReportDialog; 
\ No newline at end of file
+export default CloseReportDialog;
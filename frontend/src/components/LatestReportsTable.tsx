@@ -37,7 +37,8 @@ interface LatestReportsTableProps {
     onPrintReport: (report: models.Report) => void;
 }
 
-function reportStatus(report: models.Report): "open" | "pending" | "closed" {
+function reportStatus(report: models.Report): "open" | "pending" | "recount" | "closed" {
+    if (report.pending_recount) return "recount";
     if (report.closed_at) return "closed";
     if (report.partial_closed_at) return "pending";
     return "open";
@@ -56,7 +57,15 @@ function ReportDetailDialog({
 }) {
     if (!report) return null;
     const status = reportStatus(report);
-    const statusLabel = status === "closed" ? "Cerrado" : status === "pending" ? "Pendiente verificación" : "Abierto";
+    const statusLabel =
+        status === "closed"
+            ? "Cerrado"
+            : status === "recount"
+            ? "Pendiente de recuento"
+            : status === "pending"
+            ? "Pendiente verificación"
+            : "Abierto";
+    const difference = getReportDifference(report);
 
     return (
         <Dialog open={open} onClose={onClose} maxWidth="sm" fullWidth>
@@ -293,13 +302,13 @@ function ReportDetailDialog({
                                     display: "flex",
                                     flexDirection: "column",
                                     justifyContent: "center",
-                                    bgcolor: getReportDifference(report) === 0 ? "success.light" : "error.light",
-                                    color: getReportDifference(report) === 0 ? "success.contrastText" : "error.contrastText",
+                                    bgcolor: difference === null ? "warning.light" : difference === 0 ? "success.light" : "error.light",
+                                    color: difference === null ? "warning.contrastText" : difference === 0 ? "success.contrastText" : "error.contrastText",
                                     borderRadius: 1,
                                 }}
                             >
                                 <Typography variant="body2">Diferencia</Typography>
-                                <Typography variant="h6">{formatCurrency(getReportDifference(report))}</Typography>
+                                <Typography variant="h6">{difference === null ? "Pendiente de recuento" : formatCurrency(difference)}</Typography>
                             </Box>
                         </Grid>
                     </Grid>
@@ -364,11 +373,13 @@ const LatestReportsTable: React.FC<LatestReportsTableProps> = ({
                                 const statusLabel =
                                     status === "closed"
                                         ? "Cerrado"
+                                        : status === "recount"
+                                        ? "Recuento"
                                         : status === "pending"
                                         ? "Pendiente"
                                         : "Abierto";
                                 const statusColor =
-                                    status === "closed" ? "success" : status === "pending" ? "warning" : "default";
+                                    status === "closed" ? "success" : status === "open" ? "default" : "warning";
                                 return (
                                     <TableRow key={pastReport.id} hover>
                                         <TableCell>#{pastReport.id}</TableCell>
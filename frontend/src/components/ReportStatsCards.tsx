@@ -1,65 +1,67 @@
 import React from "react";
 import { Box, Typography, Grid } from "@mui/material";
-import { Receipt, AttachMoney, Warning } from "@mui/icons-material";
+import { AccountBalanceWallet, AttachMoney, CompareArrows, Warning } from "@mui/icons-material";
 import { models } from "../../wailsjs/go/models";
+import { formatCurrency, getReportDeliveriesTotal } from "../util/reportHelpers";
 
 interface ReportStatsCardsProps {
-    report: models.Report;
-    isPendingReport: boolean;
+    report: models.CashierReport;
 }
 
-const ReportStatsCards: React.FC<ReportStatsCardsProps> = ({ report, isPendingReport }) => {
-    const formatCurrency = (amount: number) => {
-        return `₡${amount.toLocaleString()}`;
-    };
+// The cashier's figures: what was handed in and, once a close is counted, its variance. The cash and
+// tickets expected in the drawer aren't shown before it is counted.
+const ReportStatsCards: React.FC<ReportStatsCardsProps> = ({ report }) => {
+    const variance = report.final_variance ?? report.partial_variance;
 
     return (
         <Grid container spacing={2} sx={{ mt: 1 }}>
             <Grid size={{ xs: 6, sm: 3 }}>
                 <Box sx={{ textAlign: 'center', p: 2, backgroundColor: 'info.dark', borderRadius: 2 }}>
-                    <Receipt sx={{ fontSize: 32, color: 'info.contrastText', mb: 1 }} />
+                    <AccountBalanceWallet sx={{ fontSize: 32, color: 'info.contrastText', mb: 1 }} />
                     <Typography variant="h6" color="info.contrastText">
-                        {report.partial_tickets + report.final_tickets}
+                        {formatCurrency(report.opening_float)}
                     </Typography>
                     <Typography variant="body2" color="info.contrastText">
-                        Tiquetes Vendidos
+                        Fondo de Caja
                     </Typography>
                 </Box>
             </Grid>
-            
+
             <Grid size={{ xs: 6, sm: 3 }}>
                 <Box sx={{ textAlign: 'center', p: 2, backgroundColor: 'success.light', borderRadius: 2 }}>
                     <AttachMoney sx={{ fontSize: 32, color: 'success.contrastText', mb: 1 }} />
                     <Typography variant="h6" color="success.contrastText">
-                        {formatCurrency(report.partial_cash + report.final_cash)}
+                        {formatCurrency(getReportDeliveriesTotal(report))}
                     </Typography>
                     <Typography variant="body2" color="success.contrastText">
-                        Total Generado
+                        Efectivo Entregado
                     </Typography>
                 </Box>
             </Grid>
-            
-            <Grid size={{ xs: 6, sm: 3 }}>
-                <Box sx={{ textAlign: 'center', p: 2, backgroundColor: 'error.light', borderRadius: 2 }}>
-                    <Receipt sx={{ fontSize: 32, color: 'primary.contrastText', mb: 1 }} />
-                    <Typography variant="h6" color="primary.contrastText">
-                        {formatCurrency(report.total_null)}
-                    </Typography>
-                    <Typography variant="body2" color="primary.contrastText">
-                        Anulados
-                    </Typography>
-                </Box>
-            </Grid>
-            
-            {isPendingReport && (
+
+            {variance != null && (
+                <Grid size={{ xs: 6, sm: 3 }}>
+                    <Box sx={{ textAlign: 'center', p: 2, backgroundColor: variance === 0 ? 'success.light' : 'error.light', borderRadius: 2 }}>
+                        <CompareArrows sx={{ fontSize: 32, color: 'primary.contrastText', mb: 1 }} />
+                        <Typography variant="h6" color="primary.contrastText">
+                            {formatCurrency(variance)}
+                        </Typography>
+                        <Typography variant="body2" color="primary.contrastText">
+                            {report.final_variance != null ? 'Diferencia al Cierre' : 'Diferencia del Retiro'}
+                        </Typography>
+                    </Box>
+                </Grid>
+            )}
+
+            {report.pending_recount && (
                 <Grid size={{ xs: 6, sm: 3 }}>
                     <Box sx={{ textAlign: 'center', p: 2, backgroundColor: 'warning.light', borderRadius: 2 }}>
                         <Warning sx={{ fontSize: 32, color: 'warning.contrastText', mb: 1 }} />
                         <Typography variant="h6" color="warning.contrastText">
-                            {formatCurrency(report.final_cash)}
+                            Recuento
                         </Typography>
                         <Typography variant="body2" color="warning.contrastText">
-                            Efectivo Pendiente
+                            Pendiente de un administrador
                         </Typography>
                     </Box>
                 </Grid>
@@ -68,4 +70,4 @@ const ReportStatsCards: React.FC<ReportStatsCardsProps> = ({ report, isPendingRe
     );
 };
 
-export default ReportStatsCards; 
\ No newline at end of file
+export default ReportStatsCards;
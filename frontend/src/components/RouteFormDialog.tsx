@@ -47,6 +47,7 @@ const emptyStop = (): models.Stop => ({
 const emptyTime = (): models.Time => ({
     hour: 0,
     minute: 0,
+    capacity: 0,
 });
 
 function routeToFormState(route: models.Route | null): RouteFormState {
@@ -197,7 +198,7 @@ export const RouteFormDialog: React.FC<RouteFormDialogProps> = ({ open, route, a
     const changeTime = (
         which: "timetable" | "holiday_timetable",
         index: number,
-        field: "hour" | "minute",
+        field: "hour" | "minute" | "capacity",
         value: number
     ) => {
         const arr = state[which];
@@ -370,6 +371,7 @@ export const RouteFormDialog: React.FC<RouteFormDialogProps> = ({ open, route, a
                                         <TableRow>
                                             <TableCell>Hora</TableCell>
                                             <TableCell>Minuto</TableCell>
+                                            <TableCell>Asientos</TableCell>
                                             <TableCell>Ver</TableCell>
                                             <TableCell width={50} />
                                         </TableRow>
@@ -395,6 +397,16 @@ export const RouteFormDialog: React.FC<RouteFormDialogProps> = ({ open, route, a
                                                         onChange={(e) => changeTime("timetable", idx, "minute", parseInt(e.target.value, 10) || 0)}
                                                     />
                                                 </TableCell>
+                                                <TableCell>
+                                                    <TextField
+                                                        size="small"
+                                                        type="number"
+                                                        inputProps={{ min: 0 }}
+                                                        helperText={t.capacity ? undefined : "Sin límite"}
+                                                        value={t.capacity ?? 0}
+                                                        onChange={(e) => changeTime("timetable", idx, "capacity", parseInt(e.target.value, 10) || 0)}
+                                                    />
+                                                </TableCell>
                                                 <TableCell>{to24HourFormat(t)}</TableCell>
                                                 <TableCell>
                                                     <IconButton size="small" onClick={() => removeTime("timetable", idx)}>
@@ -423,6 +435,7 @@ export const RouteFormDialog: React.FC<RouteFormDialogProps> = ({ open, route, a
                                         <TableRow>
                                             <TableCell>Hora</TableCell>
                                             <TableCell>Minuto</TableCell>
+                                            <TableCell>Asientos</TableCell>
                                             <TableCell>Ver</TableCell>
                                             <TableCell width={50} />
                                         </TableRow>
@@ -448,6 +461,16 @@ export const RouteFormDialog: React.FC<RouteFormDialogProps> = ({ open, route, a
                                                         onChange={(e) => changeTime("holiday_timetable", idx, "minute", parseInt(e.target.value, 10) || 0)}
                                                     />
                                                 </TableCell>
+                                                <TableCell>
+                                                    <TextField
+                                                        size="small"
+                                                        type="number"
+                                                        inputProps={{ min: 0 }}
+                                                        helperText={t.capacity ? undefined : "Sin límite"}
+                                                        value={t.capacity ?? 0}
+                                                        onChange={(e) => changeTime("holiday_timetable", idx, "capacity", parseInt(e.target.value, 10) || 0)}
+                                                    />
+                                                </TableCell>
                                                 <TableCell>{to24HourFormat(t)}</TableCell>
                                                 <TableCell>
                                                     <IconButton size="small" onClick={() => removeTime("holiday_timetable", idx)}>
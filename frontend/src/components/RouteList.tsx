@@ -18,7 +18,7 @@ interface RouteListProps {
     routes: models.Route[];
     selectedRouteID: String | null;
     onRouteSelect: (id: String) => void;
-    report: models.Report;
+    report: models.CashierReport;
     sx?: SxProps<Theme>;
 }
 
@@ -10,7 +10,7 @@ interface UseTicketPurchaseProps {
     selectedStopID: String | null;
     selectedTime: models.Time;
     user: models.User | null;
-    report: models.Report | null;
+    report: models.CashierReport | null;
     incrementCount: (quantity: number, ticketType: 'regular' | 'gold') => void;
     focusInput: () => void;
 }
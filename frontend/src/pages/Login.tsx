@@ -13,7 +13,7 @@ import {ArrowForward} from "@mui/icons-material";
 import {useAuthState} from "../states/AuthState";
 import {toast} from "react-toastify";
 
-import {SyncRoutes, SyncUsers} from "../../wailsjs/go/services/SyncService";
+import {SyncFareVersions, SyncGoldPassengers, SyncRoutes, SyncUsers} from "../../wailsjs/go/services/SyncService";
 import { loginErrorMessages } from "../util/ErrorMessages";
 
 const Login: React.FC = () => {
@@ -54,6 +54,22 @@ const Login: React.FC = () => {
             console.error("Error al sincronizar las rutas:", error);
             toast.error("Error al sincronizar las rutas");
         });
+
+        SyncFareVersions().catch((error) => {
+            if (error === "NO_INTERNET_CONNECTION") {
+                return;
+            }
+            console.error("Error al sincronizar las tarifas:", error);
+            toast.error("Error al sincronizar las tarifas");
+        });
+
+        SyncGoldPassengers().catch((error) => {
+            if (error === "NO_INTERNET_CONNECTION") {
+                return;
+            }
+            console.error("Error al sincronizar el registro de ciudadanos de oro:", error);
+            toast.error("Error al sincronizar el registro de ciudadanos de oro");
+        });
     }, []);
 
     const handleLogin = async () => {
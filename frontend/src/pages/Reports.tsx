@@ -61,7 +61,7 @@ const Reports: React.FC = () => {
         setCloseDialogOpen(true);
     };
 
-    const handleCloseReport = async (cashAmount: number, type: 'partial' | 'total') => {
+    const handleCloseReport = async (counts: models.CashCount[], type: 'partial' | 'total') => {
         if (!report) {
             toast.error('No hay reporte activo');
             return;
@@ -73,10 +73,10 @@ const Reports: React.FC = () => {
         }
 
         if (type === 'partial') {
-            await partialCloseReport(report.id, cashAmount, closedBy);
+            await partialCloseReport(report.id, counts, closedBy);
             toast.success('Reporte cerrado parcialmente');
         } else {
-            await totalCloseReport(report.id, cashAmount, closedBy);
+            await totalCloseReport(report.id, counts, closedBy);
             toast.success('Reporte cerrado totalmente');
             // After total close, fetch latest reports to show the newly closed report
             await fetchLatestReports();
@@ -152,75 +152,10 @@ const Reports: React.FC = () => {
                                 Reporte Actual - ID #{report.id}
                             </Typography>
                             
-                            <ReportStatsCards 
-                                report={report} 
-                                isPendingReport={isPendingReport} 
-                            />
+                            <ReportStatsCards report={report} />
 
                             <Divider sx={{ my: 3 }} />
 
-                            {/* Desglose detallado */}
-                            <Typography variant="subtitle1" fontWeight={600} color="text.secondary" gutterBottom>
-                                Desglose detallado
-                            </Typography>
-
-                            <Grid container spacing={2} sx={{ mb: 2 }}>
-                                <Grid size={{ xs: 12, sm: 4 }}>
-                                    <Box
-                                        sx={{
-                                            textAlign: "center",
-                                            p: 2,
-                                            minHeight: 80,
-                                            display: "flex",
-                                            flexDirection: "column",
-                                            justifyContent: "center",
-                                            bgcolor: "action.hover",
-                                            borderRadius: 1,
-                                        }}
-                                    >
-                                        <Typography variant="caption" color="text.secondary">Regulares</Typography>
-                                        <Typography variant="h6">{report.total_regular}</Typography>
-                                        <Typography variant="body2">{formatCurrency(report.total_regular_cash)}</Typography>
-                                    </Box>
-                                </Grid>
-                                <Grid size={{ xs: 12, sm: 4 }}>
-                                    <Box
-                                        sx={{
-                                            textAlign: "center",
-                                            p: 2,
-                                            minHeight: 80,
-                                            display: "flex",
-                                            flexDirection: "column",
-                                            justifyContent: "center",
-                                            bgcolor: "action.hover",
-                                            borderRadius: 1,
-                                        }}
-                                    >
-                                        <Typography variant="caption" color="text.secondary">Gold</Typography>
-                                        <Typography variant="h6">{report.total_gold}</Typography>
-                                        <Typography variant="body2">{formatCurrency(report.total_gold_cash)}</Typography>
-                                    </Box>
-                                </Grid>
-                                <Grid size={{ xs: 12, sm: 4 }}>
-                                    <Box
-                                        sx={{
-                                            textAlign: "center",
-                                            p: 2,
-                                            minHeight: 80,
-                                            display: "flex",
-                                            flexDirection: "column",
-                                            justifyContent: "center",
-                                            bgcolor: "action.hover",
-                                            borderRadius: 1,
-                                        }}
-                                    >
-                                        <Typography variant="caption" color="text.secondary">Anulados</Typography>
-                                        <Typography variant="h6">{report.total_null}</Typography>
-                                        <Typography variant="body2">{formatCurrency(report.total_null_cash)}</Typography>
-                                    </Box>
-                                </Grid>
-                            </Grid>
-
                             <Typography variant="caption" color="text.secondary" fontWeight={600} sx={{ display: "block", mb: 1 }}>
                                 Información
                             </Typography>
@@ -238,22 +173,13 @@ const Reports: React.FC = () => {
                                     <Typography variant="body1">{getTimetableLabel(report.timetable)}</Typography>
                                 </Grid>
                                 {report.partial_closed_at && (
-                                    <>
-                                        <Grid size={{ xs: 12, sm: 6 }}>
-                                            <Typography variant="body2" color="text.secondary">Cierre parcial</Typography>
-                                            <Typography variant="body1">{formatDateTime(report.partial_closed_at)}</Typography>
-                                            {report.partial_closed_by && (
-                                                <Typography variant="body2" color="text.secondary">por {report.partial_closed_by}</Typography>
-                                            )}
-                                        </Grid>
-                                        <Grid size={{ xs: 12, sm: 6 }}>
-                                            <Typography variant="body2" color="text.secondary">Tiquetes en cierre parcial</Typography>
-                                            <Typography variant="body1">{report.partial_tickets}</Typography>
-                                            {report.partial_cash != null && report.partial_cash > 0 && (
-                                                <Typography variant="body2" color="text.secondary">{formatCurrency(report.partial_cash)} efectivo contado</Typography>
-                                            )}
-                                        </Grid>
-                                    </>
+                                    <Grid size={{ xs: 12, sm: 6 }}>
+                                        <Typography variant="body2" color="text.secondary">Cierre parcial</Typography>
+                                        <Typography variant="body1">{formatDateTime(report.partial_closed_at)}</Typography>
+                                        {report.partial_closed_by && (
+                                            <Typography variant="body2" color="text.secondary">por {report.partial_closed_by}</Typography>
+                                        )}
+                                    </Grid>
                                 )}
                                 {report.closed_at && (
                                     <Grid size={{ xs: 12, sm: 6 }}>
@@ -1,14 +1,16 @@
 import { create } from 'zustand';
 import {models} from "../../wailsjs/go/models";
-import { CheckIfThereIsAnOpenOrPendingReport, StartReport, PartialCloseReport, TotalCloseReport } from "../../wailsjs/go/services/ReportService";
+import { GetCashierReport, StartReport, PartialCloseReportWithCount, TotalCloseReportWithCount } from "../../wailsjs/go/services/ReportService";
 
+// The report is kept as the cashier sees it: the cash and tickets expected at the closes are left out, so
+// the drawer is counted rather than matched
 interface ReportState {
-    report: models.Report | null;
+    report: models.CashierReport | null;
     reportLoading: boolean;
-    startReport: (username: string, timetable: string) => Promise<models.Report>;
-    checkReportStatus: () => Promise<models.Report | null>;
-    partialCloseReport: (reportID: number, finalCash: number, closedByUsername: string) => Promise<models.Report>;
-    totalCloseReport: (reportID: number, finalCash: number, closedByUsername: string) => Promise<models.Report>;
+    startReport: (username: string, timetable: string) => Promise<models.CashierReport>;
+    checkReportStatus: () => Promise<models.CashierReport | null>;
+    partialCloseReport: (reportID: number, counts: models.CashCount[], closedByUsername: string) => Promise<models.CashierReport>;
+    totalCloseReport: (reportID: number, counts: models.CashCount[], closedByUsername: string) => Promise<void>;
     resetReportState: () => void;
 }
 
@@ -19,7 +21,8 @@ export const useReportState = create<ReportState>((set, get) => ({
     startReport: async (username: string, timetable: string) => {
         set({ reportLoading: true });
         try {
-            const output = await StartReport(username, timetable);
+            await StartReport(username, timetable);
+            const output = await GetCashierReport();
             set({ report: output, reportLoading: false });
             return output;
         } catch (error) {
@@ -32,7 +35,7 @@ export const useReportState = create<ReportState>((set, get) => ({
     checkReportStatus: async () => {
         set({ reportLoading: true });
         try {
-            const output = await CheckIfThereIsAnOpenOrPendingReport();
+            const output = await GetCashierReport();
             set({ report: output, reportLoading: false });
             return output;
         } catch (error) {
@@ -43,10 +46,11 @@ export const useReportState = create<ReportState>((set, get) => ({
         }
     },
 
-    partialCloseReport: async (reportID: number, finalCash: number, closedByUsername: string) => {
+    partialCloseReport: async (reportID: number, counts: models.CashCount[], closedByUsername: string) => {
         set({ reportLoading: true });
         try {
-            const output = await PartialCloseReport(reportID, finalCash, closedByUsername);
+            await PartialCloseReportWithCount(reportID, counts, closedByUsername);
+            const output = await GetCashierReport();
             set({ report: output, reportLoading: false });
             return output;
         } catch (error) {
@@ -56,12 +60,11 @@ export const useReportState = create<ReportState>((set, get) => ({
         }
     },
 
-    totalCloseReport: async (reportID: number, finalCash: number, closedByUsername: string) => {
+    totalCloseReport: async (reportID: number, counts: models.CashCount[], closedByUsername: string) => {
         set({ reportLoading: true });
         try {
-            const output = await TotalCloseReport(reportID, finalCash, closedByUsername);
+            await TotalCloseReportWithCount(reportID, counts, closedByUsername);
             set({ report: null, reportLoading: false });
-            return output;
         } catch (error) {
             console.error("Error total closing report", error);
             set({ report: null, reportLoading: false });
@@ -21,8 +21,16 @@ export const formatDateShort = (dateString?: string) => {
 export const getTimetableLabel = (timetable: models.Report["timetable"]) =>
     timetable === "regular" ? "Regular" : "Feriado";
 
-export const getReportDeliveriesTotal = (report: models.Report) =>
+export const getReportDeliveriesTotal = (report: models.Report | models.CashierReport) =>
     report.partial_cash_received + report.final_cash_received;
 
+// Only cash is delivered from the drawer; card and SINPE Móvil payments are not counted, and the
+// opening float handed back at the close is not a sale. A report awaiting a recount has no difference
+// yet: on a blind close its expected cash isn't shown until the recount.
 export const getReportDifference = (report: models.Report) =>
-    report.partial_cash + report.final_cash - getReportDeliveriesTotal(report);
+    report.pending_recount
+        ? null
+        : report.partial_drawer + report.final_drawer - (getReportDeliveriesTotal(report) - (report.opening_float ?? 0));
+
+// The colón bills and coins counted in the drawer, from the largest bill to the smallest coin
+export const DENOMINATIONS = [20000, 10000, 5000, 2000, 1000, 500, 100, 50, 25, 10, 5];
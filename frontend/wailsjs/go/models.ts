@@ -1,129 +1,1771 @@
+export namespace einvoice {
+	
+	export class MockDocument {
+	    clave: string;
+	    state: string;
+	    submitted_at: string;
+	    size: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new MockDocument(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.clave = source["clave"];
+	        this.state = source["state"];
+	        this.submitted_at = source["submitted_at"];
+	        this.size = source["size"];
+	    }
+	}
+
+}
+
+export namespace emulator {
+	
+	export class Status {
+	    offline: boolean;
+	    cover_open: boolean;
+	    paper_near_end: boolean;
+	    paper_end: boolean;
+	    cutter_error: boolean;
+	    unrecoverable: boolean;
+	    auto_recoverable: boolean;
+	    paper_end_after_cuts: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Status(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.offline = source["offline"];
+	        this.cover_open = source["cover_open"];
+	        this.paper_near_end = source["paper_near_end"];
+	        this.paper_end = source["paper_end"];
+	        this.cutter_error = source["cutter_error"];
+	        this.unrecoverable = source["unrecoverable"];
+	        this.auto_recoverable = source["auto_recoverable"];
+	        this.paper_end_after_cuts = source["paper_end_after_cuts"];
+	    }
+	}
+
+}
+
 export namespace models {
 	
+	export class AdvanceDeparture {
+	    departure: string;
+	    destination: string;
+	    travel_date: string;
+	    time: string;
+	    tickets: number;
+	    gold: number;
+	    cash: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new AdvanceDeparture(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.departure = source["departure"];
+	        this.destination = source["destination"];
+	        this.travel_date = source["travel_date"];
+	        this.time = source["time"];
+	        this.tickets = source["tickets"];
+	        this.gold = source["gold"];
+	        this.cash = source["cash"];
+	    }
+	}
+	export class BoardingRequest {
+	    code: string;
+	    departure: string;
+	    destination: string;
+	    time: string;
+	    device: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new BoardingRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.code = source["code"];
+	        this.departure = source["departure"];
+	        this.destination = source["destination"];
+	        this.time = source["time"];
+	        this.device = source["device"];
+	    }
+	}
+	export class BusLayout {
+	    rows: number;
+	    columns: number;
+	    aisle_after: number;
+	    blocked: number[];
+	
+	    static createFrom(source: any = {}) {
+	        return new BusLayout(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.rows = source["rows"];
+	        this.columns = source["columns"];
+	        this.aisle_after = source["aisle_after"];
+	        this.blocked = source["blocked"];
+	    }
+	}
+	export class CashCount {
+	    report_id: number;
+	    stage: string;
+	    denomination: number;
+	    quantity: number;
+	    amount: number;
+	    counted_by: string;
+	    counted_at: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CashCount(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.report_id = source["report_id"];
+	        this.stage = source["stage"];
+	        this.denomination = source["denomination"];
+	        this.quantity = source["quantity"];
+	        this.amount = source["amount"];
+	        this.counted_by = source["counted_by"];
+	        this.counted_at = source["counted_at"];
+	    }
+	}
+	export class CashierReport {
+	    id: number;
+	    username: string;
+	    timetable: string;
+	    status: boolean;
+	    created_at?: string;
+	    opening_float: number;
+	    opening_count: CashCount[];
+	    partial_closed_at?: string;
+	    partial_closed_by?: string;
+	    partial_cash_received: number;
+	    partial_count: CashCount[];
+	    partial_variance?: number;
+	    closed_at?: string;
+	    closed_by?: string;
+	    final_cash_received: number;
+	    final_count: CashCount[];
+	    final_variance?: number;
+	    pending_recount: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CashierReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.username = source["username"];
+	        this.timetable = source["timetable"];
+	        this.status = source["status"];
+	        this.created_at = source["created_at"];
+	        this.opening_float = source["opening_float"];
+	        this.opening_count = this.convertValues(source["opening_count"], CashCount);
+	        this.partial_closed_at = source["partial_closed_at"];
+	        this.partial_closed_by = source["partial_closed_by"];
+	        this.partial_cash_received = source["partial_cash_received"];
+	        this.partial_count = this.convertValues(source["partial_count"], CashCount);
+	        this.partial_variance = source["partial_variance"];
+	        this.closed_at = source["closed_at"];
+	        this.closed_by = source["closed_by"];
+	        this.final_cash_received = source["final_cash_received"];
+	        this.final_count = this.convertValues(source["final_count"], CashCount);
+	        this.final_variance = source["final_variance"];
+	        this.pending_recount = source["pending_recount"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CategoryFare {
+	    category: string;
+	    fare: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new CategoryFare(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.category = source["category"];
+	        this.fare = source["fare"];
+	    }
+	}
+	export class ContingencyReceipt {
+	    number: string;
+	    issued_at: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ContingencyReceipt(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.number = source["number"];
+	        this.issued_at = source["issued_at"];
+	    }
+	}
 	export class Count {
 	    key: string;
 	    value: number;
 	    last_reset: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new Count(source);
+	        return new Count(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.key = source["key"];
+	        this.value = source["value"];
+	        this.last_reset = source["last_reset"];
+	    }
+	}
+	export class DailyCashierTotal {
+	    username: string;
+	    reports: number;
+	    tickets: number;
+	    cash: number;
+	    variance: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new DailyCashierTotal(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.username = source["username"];
+	        this.reports = source["reports"];
+	        this.tickets = source["tickets"];
+	        this.cash = source["cash"];
+	        this.variance = source["variance"];
+	    }
+	}
+	export class DailyDepartureTotal {
+	    departure: string;
+	    destination: string;
+	    time: string;
+	    tickets: number;
+	    cash: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new DailyDepartureTotal(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.departure = source["departure"];
+	        this.destination = source["destination"];
+	        this.time = source["time"];
+	        this.tickets = source["tickets"];
+	        this.cash = source["cash"];
+	    }
+	}
+	export class DailyPaymentTotal {
+	    method: string;
+	    payments: number;
+	    amount: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new DailyPaymentTotal(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.method = source["method"];
+	        this.payments = source["payments"];
+	        this.amount = source["amount"];
+	    }
+	}
+	export class DailyRouteTotal {
+	    departure: string;
+	    destination: string;
+	    tickets: number;
+	    cash: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new DailyRouteTotal(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.departure = source["departure"];
+	        this.destination = source["destination"];
+	        this.tickets = source["tickets"];
+	        this.cash = source["cash"];
+	    }
+	}
+	export class DailyStopTotal {
+	    departure: string;
+	    destination: string;
+	    stop: string;
+	    tickets: number;
+	    cash: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new DailyStopTotal(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.departure = source["departure"];
+	        this.destination = source["destination"];
+	        this.stop = source["stop"];
+	        this.tickets = source["tickets"];
+	        this.cash = source["cash"];
+	    }
+	}
+	export class ZReport {
+	    id: number;
+	    number: number;
+	    business_date: string;
+	    version: number;
+	    fingerprint: string;
+	    printed_by: string;
+	    printed_at: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ZReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.number = source["number"];
+	        this.business_date = source["business_date"];
+	        this.version = source["version"];
+	        this.fingerprint = source["fingerprint"];
+	        this.printed_by = source["printed_by"];
+	        this.printed_at = source["printed_at"];
+	    }
+	}
+	export class DailySummary {
+	    date: string;
+	    reports: number;
+	    open_reports: number;
+	    sales: number;
+	    tickets: number;
+	    cash: number;
+	    gold: number;
+	    gold_cash: number;
+	    regular: number;
+	    regular_cash: number;
+	    null: number;
+	    null_cash: number;
+	    opening_float: number;
+	    expected: number;
+	    received: number;
+	    difference: number;
+	    routes: DailyRouteTotal[];
+	    stops: DailyStopTotal[];
+	    departures: DailyDepartureTotal[];
+	    cashiers: DailyCashierTotal[];
+	    payments: DailyPaymentTotal[];
+	    z_report?: ZReport;
+	
+	    static createFrom(source: any = {}) {
+	        return new DailySummary(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.date = source["date"];
+	        this.reports = source["reports"];
+	        this.open_reports = source["open_reports"];
+	        this.sales = source["sales"];
+	        this.tickets = source["tickets"];
+	        this.cash = source["cash"];
+	        this.gold = source["gold"];
+	        this.gold_cash = source["gold_cash"];
+	        this.regular = source["regular"];
+	        this.regular_cash = source["regular_cash"];
+	        this.null = source["null"];
+	        this.null_cash = source["null_cash"];
+	        this.opening_float = source["opening_float"];
+	        this.expected = source["expected"];
+	        this.received = source["received"];
+	        this.difference = source["difference"];
+	        this.routes = this.convertValues(source["routes"], DailyRouteTotal);
+	        this.stops = this.convertValues(source["stops"], DailyStopTotal);
+	        this.departures = this.convertValues(source["departures"], DailyDepartureTotal);
+	        this.cashiers = this.convertValues(source["cashiers"], DailyCashierTotal);
+	        this.payments = this.convertValues(source["payments"], DailyPaymentTotal);
+	        this.z_report = this.convertValues(source["z_report"], ZReport);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class DepartureManifest {
+	    time: string;
+	    sold: number;
+	    gold: number;
+	    boarded: number;
+	    not_boarded: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new DepartureManifest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.time = source["time"];
+	        this.sold = source["sold"];
+	        this.gold = source["gold"];
+	        this.boarded = source["boarded"];
+	        this.not_boarded = source["not_boarded"];
+	    }
+	}
+	export class DepartureSeats {
+	    time: string;
+	    capacity: number;
+	    sold: number;
+	    remaining: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new DepartureSeats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.time = source["time"];
+	        this.capacity = source["capacity"];
+	        this.sold = source["sold"];
+	        this.remaining = source["remaining"];
+	    }
+	}
+	export class ElectronicDocument {
+	    id: number;
+	    sale_id: number;
+	    report_id: number;
+	    document_type: string;
+	    clave: string;
+	    consecutive: string;
+	    receiver_name: string;
+	    receiver_id_type: string;
+	    receiver_id_number: string;
+	    receiver_email: string;
+	    reference_clave: string;
+	    total: number;
+	    status: string;
+	    issued_at: string;
+	    submitted_at?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ElectronicDocument(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.sale_id = source["sale_id"];
+	        this.report_id = source["report_id"];
+	        this.document_type = source["document_type"];
+	        this.clave = source["clave"];
+	        this.consecutive = source["consecutive"];
+	        this.receiver_name = source["receiver_name"];
+	        this.receiver_id_type = source["receiver_id_type"];
+	        this.receiver_id_number = source["receiver_id_number"];
+	        this.receiver_email = source["receiver_email"];
+	        this.reference_clave = source["reference_clave"];
+	        this.total = source["total"];
+	        this.status = source["status"];
+	        this.issued_at = source["issued_at"];
+	        this.submitted_at = source["submitted_at"];
+	    }
+	}
+	export class FareChangePreview {
+	    stop: string;
+	    category: string;
+	    current_fare: number;
+	    new_fare: number;
+	    difference: number;
+	    current_version: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new FareChangePreview(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.stop = source["stop"];
+	        this.category = source["category"];
+	        this.current_fare = source["current_fare"];
+	        this.new_fare = source["new_fare"];
+	        this.difference = source["difference"];
+	        this.current_version = source["current_version"];
+	    }
+	}
+	export class StopFares {
+	    stop: string;
+	    fare: number;
+	    gold_fare: number;
+	    fares: CategoryFare[];
+	
+	    static createFrom(source: any = {}) {
+	        return new StopFares(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.stop = source["stop"];
+	        this.fare = source["fare"];
+	        this.gold_fare = source["gold_fare"];
+	        this.fares = this.convertValues(source["fares"], CategoryFare);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class FareChangeRequest {
+	    departure: string;
+	    destination: string;
+	    effective_from: string;
+	    stops: StopFares[];
+	    note: string;
+	    username: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new FareChangeRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.departure = source["departure"];
+	        this.destination = source["destination"];
+	        this.effective_from = source["effective_from"];
+	        this.stops = this.convertValues(source["stops"], StopFares);
+	        this.note = source["note"];
+	        this.username = source["username"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class FarePromotion {
+	    name: string;
+	    categories: string[];
+	    stops: string[];
+	    discount_percent: number;
+	    fare?: number;
+	    start_time: string;
+	    end_time: string;
+	    start_date: string;
+	    end_date: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new FarePromotion(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.categories = source["categories"];
+	        this.stops = source["stops"];
+	        this.discount_percent = source["discount_percent"];
+	        this.fare = source["fare"];
+	        this.start_time = source["start_time"];
+	        this.end_time = source["end_time"];
+	        this.start_date = source["start_date"];
+	        this.end_date = source["end_date"];
+	    }
+	}
+	export class FareQuote {
+	    departure: string;
+	    destination: string;
+	    stop: string;
+	    category: string;
+	    base_fare: number;
+	    fare: number;
+	    rule: string;
+	    fare_version: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new FareQuote(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.departure = source["departure"];
+	        this.destination = source["destination"];
+	        this.stop = source["stop"];
+	        this.category = source["category"];
+	        this.base_fare = source["base_fare"];
+	        this.fare = source["fare"];
+	        this.rule = source["rule"];
+	        this.fare_version = source["fare_version"];
+	    }
+	}
+	export class FareVersion {
+	    id: number[];
+	    departure: string;
+	    destination: string;
+	    version: number;
+	    effective_from: string;
+	    stops: StopFares[];
+	    note: string;
+	    created_by: string;
+	    created_at: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new FareVersion(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.departure = source["departure"];
+	        this.destination = source["destination"];
+	        this.version = source["version"];
+	        this.effective_from = source["effective_from"];
+	        this.stops = this.convertValues(source["stops"], StopFares);
+	        this.note = source["note"];
+	        this.created_by = source["created_by"];
+	        this.created_at = source["created_at"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class InvoiceReceiver {
+	    name: string;
+	    id_type: string;
+	    id_number: string;
+	    email: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new InvoiceReceiver(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.id_type = source["id_type"];
+	        this.id_number = source["id_number"];
+	        this.email = source["email"];
+	    }
+	}
+	export class Manifest {
+	    departure: string;
+	    destination: string;
+	    date: string;
+	    departures: DepartureManifest[];
+	    sold: number;
+	    boarded: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Manifest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.departure = source["departure"];
+	        this.destination = source["destination"];
+	        this.date = source["date"];
+	        this.departures = this.convertValues(source["departures"], DepartureManifest);
+	        this.sold = source["sold"];
+	        this.boarded = source["boarded"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class RejectedDocument {
+	    message_id: number;
+	    document_id: number;
+	    sale_id: number;
+	    report_id: number;
+	    document_type: string;
+	    clave: string;
+	    consecutive: string;
+	    receiver_name: string;
+	    total: number;
+	    issued_at: string;
+	    state: string;
+	    reason: string;
+	    response: string;
+	    attempts: number;
+	    updated_at: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new RejectedDocument(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.message_id = source["message_id"];
+	        this.document_id = source["document_id"];
+	        this.sale_id = source["sale_id"];
+	        this.report_id = source["report_id"];
+	        this.document_type = source["document_type"];
+	        this.clave = source["clave"];
+	        this.consecutive = source["consecutive"];
+	        this.receiver_name = source["receiver_name"];
+	        this.total = source["total"];
+	        this.issued_at = source["issued_at"];
+	        this.state = source["state"];
+	        this.reason = source["reason"];
+	        this.response = source["response"];
+	        this.attempts = source["attempts"];
+	        this.updated_at = source["updated_at"];
+	    }
+	}
+	export class ReportDrop {
+	    id: number;
+	    report_id: number;
+	    sequence: number;
+	    dropped_at: string;
+	    dropped_by: string;
+	    cash_received: number;
+	    tickets: number;
+	    cash: number;
+	    drawer: number;
+	    count: CashCount[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ReportDrop(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.report_id = source["report_id"];
+	        this.sequence = source["sequence"];
+	        this.dropped_at = source["dropped_at"];
+	        this.dropped_by = source["dropped_by"];
+	        this.cash_received = source["cash_received"];
+	        this.tickets = source["tickets"];
+	        this.cash = source["cash"];
+	        this.drawer = source["drawer"];
+	        this.count = this.convertValues(source["count"], CashCount);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ReportHandover {
+	    id: number;
+	    report_id: number;
+	    sequence: number;
+	    from_username: string;
+	    to_username: string;
+	    tickets: number;
+	    cash_taken: number;
+	    expected: number;
+	    cash: number;
+	    counted_at: string;
+	    accepted_at?: string;
+	    count: CashCount[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ReportHandover(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.report_id = source["report_id"];
+	        this.sequence = source["sequence"];
+	        this.from_username = source["from_username"];
+	        this.to_username = source["to_username"];
+	        this.tickets = source["tickets"];
+	        this.cash_taken = source["cash_taken"];
+	        this.expected = source["expected"];
+	        this.cash = source["cash"];
+	        this.counted_at = source["counted_at"];
+	        this.accepted_at = source["accepted_at"];
+	        this.count = this.convertValues(source["count"], CashCount);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ReportSegment {
+	    sequence: number;
+	    username: string;
+	    started_at?: string;
+	    ended_at?: string;
+	    tickets: number;
+	    cash_taken: number;
+	    handover?: ReportHandover;
+	
+	    static createFrom(source: any = {}) {
+	        return new ReportSegment(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.sequence = source["sequence"];
+	        this.username = source["username"];
+	        this.started_at = source["started_at"];
+	        this.ended_at = source["ended_at"];
+	        this.tickets = source["tickets"];
+	        this.cash_taken = source["cash_taken"];
+	        this.handover = this.convertValues(source["handover"], ReportHandover);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ReportRecount {
+	    id: number;
+	    report_id: number;
+	    stage: string;
+	    expected: number;
+	    previous_received: number;
+	    received: number;
+	    variance: number;
+	    reason: string;
+	    recounted_by: string;
+	    recounted_at: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ReportRecount(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.report_id = source["report_id"];
+	        this.stage = source["stage"];
+	        this.expected = source["expected"];
+	        this.previous_received = source["previous_received"];
+	        this.received = source["received"];
+	        this.variance = source["variance"];
+	        this.reason = source["reason"];
+	        this.recounted_by = source["recounted_by"];
+	        this.recounted_at = source["recounted_at"];
+	    }
+	}
+	export class ReportPaymentTotal {
+	    report_id: number;
+	    method: string;
+	    payments: number;
+	    partial_amount: number;
+	    final_amount: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ReportPaymentTotal(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.report_id = source["report_id"];
+	        this.method = source["method"];
+	        this.payments = source["payments"];
+	        this.partial_amount = source["partial_amount"];
+	        this.final_amount = source["final_amount"];
+	    }
+	}
+	export class ReportFareTotal {
+	    report_id: number;
+	    category: string;
+	    tickets: number;
+	    cash: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ReportFareTotal(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.report_id = source["report_id"];
+	        this.category = source["category"];
+	        this.tickets = source["tickets"];
+	        this.cash = source["cash"];
+	    }
+	}
+	export class Report {
+	    id: number;
+	    username: string;
+	    timetable: string;
+	    partial_tickets: number;
+	    partial_cash: number;
+	    partial_cash_received: number;
+	    final_tickets: number;
+	    final_cash: number;
+	    final_cash_received: number;
+	    status: boolean;
+	    total_gold: number;
+	    total_gold_cash: number;
+	    total_null: number;
+	    total_null_cash: number;
+	    total_regular: number;
+	    total_regular_cash: number;
+	    partial_closed_at?: string;
+	    closed_at?: string;
+	    created_at?: string;
+	    partial_closed_by?: string;
+	    closed_by?: string;
+	    remote_synced: boolean;
+	    advance_tickets: number;
+	    advance_cash: number;
+	    total_sales: number;
+	    opening_float: number;
+	    float_confirmed_by?: string;
+	    pending_recount: string;
+	    fare_totals: ReportFareTotal[];
+	    payment_totals: ReportPaymentTotal[];
+	    partial_drawer: number;
+	    final_drawer: number;
+	    opening_count: CashCount[];
+	    partial_count: CashCount[];
+	    final_count: CashCount[];
+	    partial_variance?: number;
+	    final_variance?: number;
+	    recounts: ReportRecount[];
+	    cashier: string;
+	    segments: ReportSegment[];
+	    drops: ReportDrop[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Report(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.username = source["username"];
+	        this.timetable = source["timetable"];
+	        this.partial_tickets = source["partial_tickets"];
+	        this.partial_cash = source["partial_cash"];
+	        this.partial_cash_received = source["partial_cash_received"];
+	        this.final_tickets = source["final_tickets"];
+	        this.final_cash = source["final_cash"];
+	        this.final_cash_received = source["final_cash_received"];
+	        this.status = source["status"];
+	        this.total_gold = source["total_gold"];
+	        this.total_gold_cash = source["total_gold_cash"];
+	        this.total_null = source["total_null"];
+	        this.total_null_cash = source["total_null_cash"];
+	        this.total_regular = source["total_regular"];
+	        this.total_regular_cash = source["total_regular_cash"];
+	        this.partial_closed_at = source["partial_closed_at"];
+	        this.closed_at = source["closed_at"];
+	        this.created_at = source["created_at"];
+	        this.partial_closed_by = source["partial_closed_by"];
+	        this.closed_by = source["closed_by"];
+	        this.remote_synced = source["remote_synced"];
+	        this.advance_tickets = source["advance_tickets"];
+	        this.advance_cash = source["advance_cash"];
+	        this.total_sales = source["total_sales"];
+	        this.opening_float = source["opening_float"];
+	        this.float_confirmed_by = source["float_confirmed_by"];
+	        this.pending_recount = source["pending_recount"];
+	        this.fare_totals = this.convertValues(source["fare_totals"], ReportFareTotal);
+	        this.payment_totals = this.convertValues(source["payment_totals"], ReportPaymentTotal);
+	        this.partial_drawer = source["partial_drawer"];
+	        this.final_drawer = source["final_drawer"];
+	        this.opening_count = this.convertValues(source["opening_count"], CashCount);
+	        this.partial_count = this.convertValues(source["partial_count"], CashCount);
+	        this.final_count = this.convertValues(source["final_count"], CashCount);
+	        this.partial_variance = source["partial_variance"];
+	        this.final_variance = source["final_variance"];
+	        this.recounts = this.convertValues(source["recounts"], ReportRecount);
+	        this.cashier = source["cashier"];
+	        this.segments = this.convertValues(source["segments"], ReportSegment);
+	        this.drops = this.convertValues(source["drops"], ReportDrop);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	
+	export class ReportHandoverRequest {
+	    report_id: number;
+	    username: string;
+	    cash: number;
+	    counts: CashCount[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ReportHandoverRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.report_id = source["report_id"];
+	        this.username = source["username"];
+	        this.cash = source["cash"];
+	        this.counts = this.convertValues(source["counts"], CashCount);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	export class ReportRecountRequest {
+	    report_id: number;
+	    stage: string;
+	    cash: number;
+	    counts: CashCount[];
+	    reason: string;
+	    approver_username: string;
+	    approver_password: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ReportRecountRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.report_id = source["report_id"];
+	        this.stage = source["stage"];
+	        this.cash = source["cash"];
+	        this.counts = this.convertValues(source["counts"], CashCount);
+	        this.reason = source["reason"];
+	        this.approver_username = source["approver_username"];
+	        this.approver_password = source["approver_password"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ReportSearch {
+	    from: string;
+	    to: string;
+	    username: string;
+	    timetable: string;
+	    status?: boolean;
+	    remote_synced?: boolean;
+	    variance: string;
+	    page: number;
+	    page_size: number;
+	    sort_by: string;
+	    descending: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ReportSearch(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.from = source["from"];
+	        this.to = source["to"];
+	        this.username = source["username"];
+	        this.timetable = source["timetable"];
+	        this.status = source["status"];
+	        this.remote_synced = source["remote_synced"];
+	        this.variance = source["variance"];
+	        this.page = source["page"];
+	        this.page_size = source["page_size"];
+	        this.sort_by = source["sort_by"];
+	        this.descending = source["descending"];
+	    }
+	}
+	export class ReportSearchTotals {
+	    reports: number;
+	    tickets: number;
+	    cash: number;
+	    sales: number;
+	    gold: number;
+	    null: number;
+	    null_cash: number;
+	    expected: number;
+	    received: number;
+	    difference: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ReportSearchTotals(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.reports = source["reports"];
+	        this.tickets = source["tickets"];
+	        this.cash = source["cash"];
+	        this.sales = source["sales"];
+	        this.gold = source["gold"];
+	        this.null = source["null"];
+	        this.null_cash = source["null_cash"];
+	        this.expected = source["expected"];
+	        this.received = source["received"];
+	        this.difference = source["difference"];
+	    }
+	}
+	export class ReportSearchResult {
+	    reports: Report[];
+	    totals: ReportSearchTotals;
+	    page: number;
+	    page_size: number;
+	    pages: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ReportSearchResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.reports = this.convertValues(source["reports"], Report);
+	        this.totals = this.convertValues(source["totals"], ReportSearchTotals);
+	        this.page = source["page"];
+	        this.page_size = source["page_size"];
+	        this.pages = source["pages"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	export class ReportStartRequest {
+	    username: string;
+	    timetable: string;
+	    opening_float: number;
+	    opening_count: CashCount[];
+	    approver_username: string;
+	    approver_password: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ReportStartRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.username = source["username"];
+	        this.timetable = source["timetable"];
+	        this.opening_float = source["opening_float"];
+	        this.opening_count = this.convertValues(source["opening_count"], CashCount);
+	        this.approver_username = source["approver_username"];
+	        this.approver_password = source["approver_password"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Time {
+	    hour: number;
+	    minute: number;
+	    capacity: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Time(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.hour = source["hour"];
+	        this.minute = source["minute"];
+	        this.capacity = source["capacity"];
+	    }
+	}
+	export class Stop {
+	    name: string;
+	    code: string;
+	    fare: number;
+	    gold_fare: number;
+	    is_main: boolean;
+	    fares: CategoryFare[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Stop(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.code = source["code"];
+	        this.fare = source["fare"];
+	        this.gold_fare = source["gold_fare"];
+	        this.is_main = source["is_main"];
+	        this.fares = this.convertValues(source["fares"], CategoryFare);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Route {
+	    id: number[];
+	    departure: string;
+	    destination: string;
+	    stops: Stop[];
+	    timetable: Time[];
+	    holiday_timetable: Time[];
+	    layout?: BusLayout;
+	    promotions: FarePromotion[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Route(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.departure = source["departure"];
+	        this.destination = source["destination"];
+	        this.stops = this.convertValues(source["stops"], Stop);
+	        this.timetable = this.convertValues(source["timetable"], Time);
+	        this.holiday_timetable = this.convertValues(source["holiday_timetable"], Time);
+	        this.layout = this.convertValues(source["layout"], BusLayout);
+	        this.promotions = this.convertValues(source["promotions"], FarePromotion);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SalePayment {
+	    id: number;
+	    sale_id: number;
+	    method: string;
+	    amount: number;
+	    tendered: number;
+	    change: number;
+	    reference: string;
+	    approval_code: string;
+	    capture_status: string;
+	    refund_status: string;
+	    created_at: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SalePayment(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.sale_id = source["sale_id"];
+	        this.method = source["method"];
+	        this.amount = source["amount"];
+	        this.tendered = source["tendered"];
+	        this.change = source["change"];
+	        this.reference = source["reference"];
+	        this.approval_code = source["approval_code"];
+	        this.capture_status = source["capture_status"];
+	        this.refund_status = source["refund_status"];
+	        this.created_at = source["created_at"];
+	    }
+	}
+	export class Ticket {
+	    id: number;
+	    departure: string;
+	    destination: string;
+	    username: string;
+	    stop: string;
+	    time: string;
+	    fare: number;
+	    is_gold: boolean;
+	    is_null: boolean;
+	    id_number: string;
+	    report_id: number;
+	    created_at: string;
+	    updated_at: string;
+	    travel_date: string;
+	    is_advance: boolean;
+	    seat_number: number;
+	    fare_category: string;
+	    fare_rule: string;
+	    fare_version: number;
+	    sale_id: number;
+	    approval_code: string;
+	    drop_sequence: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Ticket(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.departure = source["departure"];
+	        this.destination = source["destination"];
+	        this.username = source["username"];
+	        this.stop = source["stop"];
+	        this.time = source["time"];
+	        this.fare = source["fare"];
+	        this.is_gold = source["is_gold"];
+	        this.is_null = source["is_null"];
+	        this.id_number = source["id_number"];
+	        this.report_id = source["report_id"];
+	        this.created_at = source["created_at"];
+	        this.updated_at = source["updated_at"];
+	        this.travel_date = source["travel_date"];
+	        this.is_advance = source["is_advance"];
+	        this.seat_number = source["seat_number"];
+	        this.fare_category = source["fare_category"];
+	        this.fare_rule = source["fare_rule"];
+	        this.fare_version = source["fare_version"];
+	        this.sale_id = source["sale_id"];
+	        this.approval_code = source["approval_code"];
+	        this.drop_sequence = source["drop_sequence"];
+	    }
+	}
+	export class Sale {
+	    id: number;
+	    report_id: number;
+	    username: string;
+	    total: number;
+	    ticket_count: number;
+	    is_null: boolean;
+	    created_at: string;
+	    voided_at?: string;
+	    voided_by?: string;
+	    tickets: Ticket[];
+	    payments: SalePayment[];
+	    document?: ElectronicDocument;
+	
+	    static createFrom(source: any = {}) {
+	        return new Sale(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.report_id = source["report_id"];
+	        this.username = source["username"];
+	        this.total = source["total"];
+	        this.ticket_count = source["ticket_count"];
+	        this.is_null = source["is_null"];
+	        this.created_at = source["created_at"];
+	        this.voided_at = source["voided_at"];
+	        this.voided_by = source["voided_by"];
+	        this.tickets = this.convertValues(source["tickets"], Ticket);
+	        this.payments = this.convertValues(source["payments"], SalePayment);
+	        this.document = this.convertValues(source["document"], ElectronicDocument);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class SaleRequest {
+	    report_id: number;
+	    username: string;
+	    tickets: Ticket[];
+	    payments: SalePayment[];
+	    receiver?: InvoiceReceiver;
+	    contingency?: ContingencyReceipt;
+	
+	    static createFrom(source: any = {}) {
+	        return new SaleRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.report_id = source["report_id"];
+	        this.username = source["username"];
+	        this.tickets = this.convertValues(source["tickets"], Ticket);
+	        this.payments = this.convertValues(source["payments"], SalePayment);
+	        this.receiver = this.convertValues(source["receiver"], InvoiceReceiver);
+	        this.contingency = this.convertValues(source["contingency"], ContingencyReceipt);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TicketVoid {
+	    id: number;
+	    ticket_id: number;
+	    report_id: number;
+	    reason: string;
+	    note: string;
+	    fare: number;
+	    bucket: string;
+	    voided_by: string;
+	    approved_by?: string;
+	    created_at: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TicketVoid(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.key = source["key"];
-	        this.value = source["value"];
-	        this.last_reset = source["last_reset"];
+	        this.id = source["id"];
+	        this.ticket_id = source["ticket_id"];
+	        this.report_id = source["report_id"];
+	        this.reason = source["reason"];
+	        this.note = source["note"];
+	        this.fare = source["fare"];
+	        this.bucket = source["bucket"];
+	        this.voided_by = source["voided_by"];
+	        this.approved_by = source["approved_by"];
+	        this.created_at = source["created_at"];
 	    }
 	}
-	export class Report {
-	    id: number;
-	    username: string;
-	    timetable: string;
-	    partial_tickets: number;
-	    partial_cash: number;
-	    partial_cash_received: number;
-	    final_tickets: number;
-	    final_cash: number;
-	    final_cash_received: number;
-	    status: boolean;
-	    total_gold: number;
-	    total_gold_cash: number;
-	    total_null: number;
-	    total_null_cash: number;
-	    total_regular: number;
-	    total_regular_cash: number;
-	    partial_closed_at?: string;
-	    closed_at?: string;
-	    created_at?: string;
-	    partial_closed_by?: string;
-	    closed_by?: string;
-	    remote_synced: boolean;
+	export class SaleVoid {
+	    sale: Sale;
+	    voids: TicketVoid[];
 	
 	    static createFrom(source: any = {}) {
-	        return new Report(source);
+	        return new SaleVoid(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.id = source["id"];
-	        this.username = source["username"];
-	        this.timetable = source["timetable"];
-	        this.partial_tickets = source["partial_tickets"];
-	        this.partial_cash = source["partial_cash"];
-	        this.partial_cash_received = source["partial_cash_received"];
-	        this.final_tickets = source["final_tickets"];
-	        this.final_cash = source["final_cash"];
-	        this.final_cash_received = source["final_cash_received"];
-	        this.status = source["status"];
-	        this.total_gold = source["total_gold"];
-	        this.total_gold_cash = source["total_gold_cash"];
-	        this.total_null = source["total_null"];
-	        this.total_null_cash = source["total_null_cash"];
-	        this.total_regular = source["total_regular"];
-	        this.total_regular_cash = source["total_regular_cash"];
-	        this.partial_closed_at = source["partial_closed_at"];
-	        this.closed_at = source["closed_at"];
-	        this.created_at = source["created_at"];
-	        this.partial_closed_by = source["partial_closed_by"];
-	        this.closed_by = source["closed_by"];
-	        this.remote_synced = source["remote_synced"];
+	        this.sale = this.convertValues(source["sale"], Sale);
+	        this.voids = this.convertValues(source["voids"], TicketVoid);
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
-	export class Time {
-	    hour: number;
-	    minute: number;
+	export class SaleVoidRequest {
+	    sale_id: number;
+	    report_id: number;
+	    reason: string;
+	    note: string;
+	    voided_by: string;
+	    approver_username: string;
+	    approver_password: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new Time(source);
+	        return new SaleVoidRequest(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.hour = source["hour"];
-	        this.minute = source["minute"];
+	        this.sale_id = source["sale_id"];
+	        this.report_id = source["report_id"];
+	        this.reason = source["reason"];
+	        this.note = source["note"];
+	        this.voided_by = source["voided_by"];
+	        this.approver_username = source["approver_username"];
+	        this.approver_password = source["approver_password"];
 	    }
 	}
-	export class Stop {
-	    name: string;
-	    code: string;
-	    fare: number;
-	    gold_fare: number;
-	    is_main: boolean;
+	export class SeatReservation {
+	    id: number;
+	    ticket_id: number;
+	    departure: string;
+	    destination: string;
+	    travel_date: string;
+	    time: string;
+	    seat_number: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new Stop(source);
+	        return new SeatReservation(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.name = source["name"];
-	        this.code = source["code"];
-	        this.fare = source["fare"];
-	        this.gold_fare = source["gold_fare"];
-	        this.is_main = source["is_main"];
+	        this.id = source["id"];
+	        this.ticket_id = source["ticket_id"];
+	        this.departure = source["departure"];
+	        this.destination = source["destination"];
+	        this.travel_date = source["travel_date"];
+	        this.time = source["time"];
+	        this.seat_number = source["seat_number"];
 	    }
 	}
-	export class Route {
-	    id: number[];
+	export class SeatMap {
 	    departure: string;
 	    destination: string;
-	    stops: Stop[];
-	    timetable: Time[];
-	    holiday_timetable: Time[];
+	    travel_date: string;
+	    time: string;
+	    layout?: BusLayout;
+	    occupied: SeatReservation[];
 	
 	    static createFrom(source: any = {}) {
-	        return new Route(source);
+	        return new SeatMap(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.id = source["id"];
 	        this.departure = source["departure"];
 	        this.destination = source["destination"];
-	        this.stops = this.convertValues(source["stops"], Stop);
-	        this.timetable = this.convertValues(source["timetable"], Time);
-	        this.holiday_timetable = this.convertValues(source["holiday_timetable"], Time);
+	        this.travel_date = source["travel_date"];
+	        this.time = source["time"];
+	        this.layout = this.convertValues(source["layout"], BusLayout);
+	        this.occupied = this.convertValues(source["occupied"], SeatReservation);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -145,40 +1787,213 @@ export namespace models {
 		}
 	}
 	
-	export class Ticket {
+	
+	
+	
+	export class TicketBoarding {
+	    id: number;
+	    ticket_id: number;
+	    device: string;
+	    boarded_at: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TicketBoarding(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.ticket_id = source["ticket_id"];
+	        this.device = source["device"];
+	        this.boarded_at = source["boarded_at"];
+	    }
+	}
+	export class TicketPrint {
 	    id: number;
+	    ticket_id: number;
+	    report_id: number;
+	    kind: string;
+	    copy_number: number;
+	    printed_by: string;
+	    status: string;
+	    created_at: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TicketPrint(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.ticket_id = source["ticket_id"];
+	        this.report_id = source["report_id"];
+	        this.kind = source["kind"];
+	        this.copy_number = source["copy_number"];
+	        this.printed_by = source["printed_by"];
+	        this.status = source["status"];
+	        this.created_at = source["created_at"];
+	    }
+	}
+	export class TicketSearch {
+	    sold_from: string;
+	    sold_to: string;
+	    travel_from: string;
+	    travel_to: string;
 	    departure: string;
 	    destination: string;
-	    username: string;
 	    stop: string;
 	    time: string;
-	    fare: number;
-	    is_gold: boolean;
-	    is_null: boolean;
+	    username: string;
+	    is_gold?: boolean;
+	    is_null?: boolean;
 	    id_number: string;
 	    report_id: number;
-	    created_at: string;
-	    updated_at: string;
+	    page: number;
+	    page_size: number;
+	    sort_by: string;
+	    descending: boolean;
 	
 	    static createFrom(source: any = {}) {
-	        return new Ticket(source);
+	        return new TicketSearch(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.id = source["id"];
+	        this.sold_from = source["sold_from"];
+	        this.sold_to = source["sold_to"];
+	        this.travel_from = source["travel_from"];
+	        this.travel_to = source["travel_to"];
 	        this.departure = source["departure"];
 	        this.destination = source["destination"];
-	        this.username = source["username"];
 	        this.stop = source["stop"];
 	        this.time = source["time"];
-	        this.fare = source["fare"];
+	        this.username = source["username"];
 	        this.is_gold = source["is_gold"];
 	        this.is_null = source["is_null"];
 	        this.id_number = source["id_number"];
 	        this.report_id = source["report_id"];
-	        this.created_at = source["created_at"];
-	        this.updated_at = source["updated_at"];
+	        this.page = source["page"];
+	        this.page_size = source["page_size"];
+	        this.sort_by = source["sort_by"];
+	        this.descending = source["descending"];
+	    }
+	}
+	export class TicketSearchTotals {
+	    tickets: number;
+	    cash: number;
+	    gold: number;
+	    null: number;
+	    null_cash: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TicketSearchTotals(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.tickets = source["tickets"];
+	        this.cash = source["cash"];
+	        this.gold = source["gold"];
+	        this.null = source["null"];
+	        this.null_cash = source["null_cash"];
+	    }
+	}
+	export class TicketSearchResult {
+	    tickets: Ticket[];
+	    totals: TicketSearchTotals;
+	    page: number;
+	    page_size: number;
+	    pages: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TicketSearchResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.tickets = this.convertValues(source["tickets"], Ticket);
+	        this.totals = this.convertValues(source["totals"], TicketSearchTotals);
+	        this.page = source["page"];
+	        this.page_size = source["page_size"];
+	        this.pages = source["pages"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class TicketValidation {
+	    status: string;
+	    ticket?: Ticket;
+	    boarding?: TicketBoarding;
+	
+	    static createFrom(source: any = {}) {
+	        return new TicketValidation(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.status = source["status"];
+	        this.ticket = this.convertValues(source["ticket"], Ticket);
+	        this.boarding = this.convertValues(source["boarding"], TicketBoarding);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class TicketVoidRequest {
+	    ticket_id: number;
+	    report_id: number;
+	    reason: string;
+	    note: string;
+	    voided_by: string;
+	    approver_username: string;
+	    approver_password: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TicketVoidRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.ticket_id = source["ticket_id"];
+	        this.report_id = source["report_id"];
+	        this.reason = source["reason"];
+	        this.note = source["note"];
+	        this.voided_by = source["voided_by"];
+	        this.approver_username = source["approver_username"];
+	        this.approver_password = source["approver_password"];
 	    }
 	}
 	
@@ -204,6 +2019,58 @@ export namespace models {
 	        this.updated_at = source["updated_at"];
 	    }
 	}
+	
+	export class ZReportPrint {
+	    id: number;
+	    z_report_id: number;
+	    version: number;
+	    copy_number: number;
+	    printed_by: string;
+	    printed_at: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ZReportPrint(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.z_report_id = source["z_report_id"];
+	        this.version = source["version"];
+	        this.copy_number = source["copy_number"];
+	        this.printed_by = source["printed_by"];
+	        this.printed_at = source["printed_at"];
+	    }
+	}
+
+}
+
+export namespace payment {
+	
+	export class SimulatedTransaction {
+	    transaction_id: string;
+	    approval_code: string;
+	    amount: number;
+	    card: string;
+	    reference: string;
+	    state: string;
+	    refunded: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SimulatedTransaction(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.transaction_id = source["transaction_id"];
+	        this.approval_code = source["approval_code"];
+	        this.amount = source["amount"];
+	        this.card = source["card"];
+	        this.reference = source["reference"];
+	        this.state = source["state"];
+	        this.refunded = source["refunded"];
+	    }
+	}
 
 }
 
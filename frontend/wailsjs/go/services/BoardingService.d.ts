@@ -0,0 +1,7 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+import {models} from '../models';
+
+export function BoardTicket(arg1:models.BoardingRequest):Promise<models.TicketBoarding>;
+
+export function GetManifest(arg1:string,arg2:string,arg3:string):Promise<models.Manifest>;
@@ -0,0 +1,11 @@
+// @ts-check
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+export function BoardTicket(arg1) {
+  return window['go']['services']['BoardingService']['BoardTicket'](arg1);
+}
+
+export function GetManifest(arg1, arg2, arg3) {
+  return window['go']['services']['BoardingService']['GetManifest'](arg1, arg2, arg3);
+}
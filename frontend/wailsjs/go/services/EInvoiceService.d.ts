@@ -0,0 +1,12 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+import {einvoice} from '../models';
+import {models} from '../models';
+
+export function GetEInvoiceMockDocuments():Promise<Array<einvoice.MockDocument>>;
+
+export function GetRejectedDocuments():Promise<Array<models.RejectedDocument>>;
+
+export function GetSaleDocument(arg1:number):Promise<models.ElectronicDocument>;
+
+export function ScriptEInvoiceMock(arg1:Array<string>):Promise<void>;
@@ -0,0 +1,19 @@
+// @ts-check
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+export function GetEInvoiceMockDocuments() {
+  return window['go']['services']['EInvoiceService']['GetEInvoiceMockDocuments']();
+}
+
+export function GetRejectedDocuments() {
+  return window['go']['services']['EInvoiceService']['GetRejectedDocuments']();
+}
+
+export function GetSaleDocument(arg1) {
+  return window['go']['services']['EInvoiceService']['GetSaleDocument'](arg1);
+}
+
+export function ScriptEInvoiceMock(arg1) {
+  return window['go']['services']['EInvoiceService']['ScriptEInvoiceMock'](arg1);
+}
@@ -0,0 +1,11 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+import {models} from '../models';
+
+export function GetFareVersions(arg1:string,arg2:string):Promise<Array<models.FareVersion>>;
+
+export function PreviewFareChange(arg1:models.FareChangeRequest):Promise<Array<models.FareChangePreview>>;
+
+export function Quote(arg1:string,arg2:string,arg3:string,arg4:string,arg5:string):Promise<models.FareQuote>;
+
+export function ScheduleFareChange(arg1:models.FareChangeRequest):Promise<models.FareVersion>;
@@ -0,0 +1,19 @@
+// @ts-check
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+export function GetFareVersions(arg1, arg2) {
+  return window['go']['services']['FareService']['GetFareVersions'](arg1, arg2);
+}
+
+export function PreviewFareChange(arg1) {
+  return window['go']['services']['FareService']['PreviewFareChange'](arg1);
+}
+
+export function Quote(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['services']['FareService']['Quote'](arg1, arg2, arg3, arg4, arg5);
+}
+
+export function ScheduleFareChange(arg1) {
+  return window['go']['services']['FareService']['ScheduleFareChange'](arg1);
+}
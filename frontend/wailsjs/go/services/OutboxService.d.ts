@@ -0,0 +1,6 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+export function DrainOutbox():Promise<number>;
+
+export function RetryOutboxMessage(arg1:number):Promise<void>;
@@ -0,0 +1,11 @@
+// @ts-check
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+export function DrainOutbox() {
+  return window['go']['services']['OutboxService']['DrainOutbox']();
+}
+
+export function RetryOutboxMessage(arg1) {
+  return window['go']['services']['OutboxService']['RetryOutboxMessage'](arg1);
+}
@@ -1,17 +1,36 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 import {models} from '../models';
+import {emulator} from '../models';
 
 export function EnsurePrinterReady(arg1:string):Promise<void>;
 
+export function GetEmulatorReceipts():Promise<Array<string>>;
+
 export function GetInstalledPrinters():Promise<Array<string>>;
 
 export function GetPrinterStatus(arg1:string):Promise<string>;
 
+export function PreviewTemplate(arg1:string,arg2:string):Promise<string>;
+
+export function PrintDailySummary(arg1:string,arg2:string,arg3:string):Promise<models.ZReportPrint>;
+
 export function PrintReport(arg1:models.Report,arg2:string):Promise<void>;
 
+export function PrintSale(arg1:models.Sale,arg2:string):Promise<void>;
+
+export function PrintSaleSummary(arg1:number,arg2:string):Promise<void>;
+
 export function PrintTicket(arg1:models.Ticket,arg2:string):Promise<void>;
 
 export function PrintTickets(arg1:Array<models.Ticket>,arg2:string):Promise<void>;
 
+export function PrintVoidSlip(arg1:number,arg2:string,arg3:string):Promise<models.TicketPrint>;
+
+export function ReprintTicket(arg1:number,arg2:string,arg3:string):Promise<models.TicketPrint>;
+
+export function ResetEmulator():Promise<void>;
+
+export function SetEmulatorStatus(arg1:emulator.Status):Promise<void>;
+
 export function Startup():Promise<void>;
@@ -6,6 +6,10 @@ export function EnsurePrinterReady(arg1) {
   return window['go']['services']['PrintService']['EnsurePrinterReady'](arg1);
 }
 
+export function GetEmulatorReceipts() {
+  return window['go']['services']['PrintService']['GetEmulatorReceipts']();
+}
+
 export function GetInstalledPrinters() {
   return window['go']['services']['PrintService']['GetInstalledPrinters']();
 }
@@ -14,10 +18,26 @@ export function GetPrinterStatus(arg1) {
   return window['go']['services']['PrintService']['GetPrinterStatus'](arg1);
 }
 
+export function PreviewTemplate(arg1, arg2) {
+  return window['go']['services']['PrintService']['PreviewTemplate'](arg1, arg2);
+}
+
+export function PrintDailySummary(arg1, arg2, arg3) {
+  return window['go']['services']['PrintService']['PrintDailySummary'](arg1, arg2, arg3);
+}
+
 export function PrintReport(arg1, arg2) {
   return window['go']['services']['PrintService']['PrintReport'](arg1, arg2);
 }
 
+export function PrintSale(arg1, arg2) {
+  return window['go']['services']['PrintService']['PrintSale'](arg1, arg2);
+}
+
+export function PrintSaleSummary(arg1, arg2) {
+  return window['go']['services']['PrintService']['PrintSaleSummary'](arg1, arg2);
+}
+
 export function PrintTicket(arg1, arg2) {
   return window['go']['services']['PrintService']['PrintTicket'](arg1, arg2);
 }
@@ -26,6 +46,22 @@ export function PrintTickets(arg1, arg2) {
   return window['go']['services']['PrintService']['PrintTickets'](arg1, arg2);
 }
 
+export function PrintVoidSlip(arg1, arg2, arg3) {
+  return window['go']['services']['PrintService']['PrintVoidSlip'](arg1, arg2, arg3);
+}
+
+export function ReprintTicket(arg1, arg2, arg3) {
+  return window['go']['services']['PrintService']['ReprintTicket'](arg1, arg2, arg3);
+}
+
+export function ResetEmulator() {
+  return window['go']['services']['PrintService']['ResetEmulator']();
+}
+
+export function SetEmulatorStatus(arg1) {
+  return window['go']['services']['PrintService']['SetEmulatorStatus'](arg1);
+}
+
 export function Startup() {
   return window['go']['services']['PrintService']['Startup']();
 }
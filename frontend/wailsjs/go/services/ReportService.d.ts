@@ -3,14 +3,34 @@
 import {models} from '../models';
 import {context} from '../models';
 
+export function AcceptHandover(arg1:number,arg2:string,arg3:string):Promise<models.Report>;
+
+export function CancelHandover(arg1:number):Promise<models.Report>;
+
 export function CheckIfThereIsAnOpenOrPendingReport():Promise<models.Report>;
 
+export function DailySummary(arg1:string):Promise<models.DailySummary>;
+
+export function GetCashierReport():Promise<models.CashierReport>;
+
 export function GetLatestReportsByUsername(arg1:string):Promise<Array<models.Report>>;
 
+export function HandOverReport(arg1:models.ReportHandoverRequest):Promise<models.ReportHandover>;
+
 export function PartialCloseReport(arg1:number,arg2:number,arg3:any):Promise<models.Report>;
 
+export function PartialCloseReportWithCount(arg1:number,arg2:Array<models.CashCount>,arg3:any):Promise<models.Report>;
+
+export function RecountReport(arg1:models.ReportRecountRequest):Promise<models.Report>;
+
+export function SearchReports(arg1:models.ReportSearch):Promise<models.ReportSearchResult>;
+
 export function StartReport(arg1:string,arg2:string):Promise<models.Report>;
 
+export function StartReportWithFloat(arg1:models.ReportStartRequest):Promise<models.Report>;
+
 export function SyncPendingReportsToRemote(arg1:context.Context):Promise<number>;
 
 export function TotalCloseReport(arg1:number,arg2:number,arg3:any):Promise<models.Report>;
+
+export function TotalCloseReportWithCount(arg1:number,arg2:Array<models.CashCount>,arg3:any):Promise<models.Report>;
@@ -2,22 +2,58 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function AcceptHandover(arg1, arg2, arg3) {
+  return window['go']['services']['ReportService']['AcceptHandover'](arg1, arg2, arg3);
+}
+
+export function CancelHandover(arg1) {
+  return window['go']['services']['ReportService']['CancelHandover'](arg1);
+}
+
 export function CheckIfThereIsAnOpenOrPendingReport() {
   return window['go']['services']['ReportService']['CheckIfThereIsAnOpenOrPendingReport']();
 }
 
+export function DailySummary(arg1) {
+  return window['go']['services']['ReportService']['DailySummary'](arg1);
+}
+
+export function GetCashierReport() {
+  return window['go']['services']['ReportService']['GetCashierReport']();
+}
+
 export function GetLatestReportsByUsername(arg1) {
   return window['go']['services']['ReportService']['GetLatestReportsByUsername'](arg1);
 }
 
+export function HandOverReport(arg1) {
+  return window['go']['services']['ReportService']['HandOverReport'](arg1);
+}
+
 export function PartialCloseReport(arg1, arg2, arg3) {
   return window['go']['services']['ReportService']['PartialCloseReport'](arg1, arg2, arg3);
 }
 
+export function PartialCloseReportWithCount(arg1, arg2, arg3) {
+  return window['go']['services']['ReportService']['PartialCloseReportWithCount'](arg1, arg2, arg3);
+}
+
+export function RecountReport(arg1) {
+  return window['go']['services']['ReportService']['RecountReport'](arg1);
+}
+
+export function SearchReports(arg1) {
+  return window['go']['services']['ReportService']['SearchReports'](arg1);
+}
+
 export function StartReport(arg1, arg2) {
   return window['go']['services']['ReportService']['StartReport'](arg1, arg2);
 }
 
+export function StartReportWithFloat(arg1) {
+  return window['go']['services']['ReportService']['StartReportWithFloat'](arg1);
+}
+
 export function SyncPendingReportsToRemote(arg1) {
   return window['go']['services']['ReportService']['SyncPendingReportsToRemote'](arg1);
 }
@@ -25,3 +61,7 @@ export function SyncPendingReportsToRemote(arg1) {
 export function TotalCloseReport(arg1, arg2, arg3) {
   return window['go']['services']['ReportService']['TotalCloseReport'](arg1, arg2, arg3);
 }
+
+export function TotalCloseReportWithCount(arg1, arg2, arg3) {
+  return window['go']['services']['ReportService']['TotalCloseReportWithCount'](arg1, arg2, arg3);
+}
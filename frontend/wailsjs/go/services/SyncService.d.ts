@@ -1,6 +1,10 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function SyncFareVersions():Promise<void>;
+
+export function SyncGoldPassengers():Promise<void>;
+
 export function SyncRoutes():Promise<void>;
 
 export function SyncUsers():Promise<void>;
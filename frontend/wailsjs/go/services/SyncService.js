@@ -2,6 +2,14 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function SyncFareVersions() {
+  return window['go']['services']['SyncService']['SyncFareVersions']();
+}
+
+export function SyncGoldPassengers() {
+  return window['go']['services']['SyncService']['SyncGoldPassengers']();
+}
+
 export function SyncRoutes() {
   return window['go']['services']['SyncService']['SyncRoutes']();
 }
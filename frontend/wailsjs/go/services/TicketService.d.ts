@@ -1,6 +1,12 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 import {models} from '../models';
+import {payment} from '../models';
+import {enums} from '../models';
+
+export function AddSale(arg1:models.SaleRequest):Promise<models.Sale>;
+
+export function AddSaleWithPrint(arg1:models.SaleRequest,arg2:string):Promise<models.Sale>;
 
 export function AddTicket(arg1:Array<models.Ticket>):Promise<Array<models.Ticket>>;
 
@@ -8,6 +14,32 @@ export function AddTicketWithPrint(arg1:Array<models.Ticket>,arg2:string):Promis
 
 export function DeleteTickets(arg1:Array<models.Ticket>):Promise<void>;
 
+export function GetAdvanceSales():Promise<Array<models.AdvanceDeparture>>;
+
+export function GetDepartureTickets(arg1:string,arg2:string,arg3:string,arg4:string):Promise<Array<models.Ticket>>;
+
+export function GetPaymentSimulatorTransactions():Promise<Array<payment.SimulatedTransaction>>;
+
+export function GetSale(arg1:number):Promise<models.Sale>;
+
+export function GetSeatAvailability(arg1:string,arg2:string,arg3:string,arg4:enums.Timetable):Promise<Array<models.DepartureSeats>>;
+
+export function GetSeatMap(arg1:string,arg2:string,arg3:string,arg4:string):Promise<models.SeatMap>;
+
+export function GetTicketVoids(arg1:string,arg2:string):Promise<Array<models.TicketVoid>>;
+
+export function GetTicketVoidsByReport(arg1:number):Promise<Array<models.TicketVoid>>;
+
 export function NullifyTicket(arg1:number,arg2:number):Promise<void>;
 
+export function ScriptPaymentSimulator(arg1:Array<string>):Promise<void>;
+
+export function SearchTickets(arg1:models.TicketSearch):Promise<models.TicketSearchResult>;
+
 export function UpdateTickets(arg1:Array<models.Ticket>):Promise<void>;
+
+export function ValidateTicketPayload(arg1:string,arg2:string):Promise<models.TicketValidation>;
+
+export function VoidSale(arg1:models.SaleVoidRequest):Promise<models.SaleVoid>;
+
+export function VoidTicket(arg1:models.TicketVoidRequest):Promise<models.TicketVoid>;
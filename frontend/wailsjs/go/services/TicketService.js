@@ -2,6 +2,14 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function AddSale(arg1) {
+  return window['go']['services']['TicketService']['AddSale'](arg1);
+}
+
+export function AddSaleWithPrint(arg1, arg2) {
+  return window['go']['services']['TicketService']['AddSaleWithPrint'](arg1, arg2);
+}
+
 export function AddTicket(arg1) {
   return window['go']['services']['TicketService']['AddTicket'](arg1);
 }
@@ -14,10 +22,62 @@ export function DeleteTickets(arg1) {
   return window['go']['services']['TicketService']['DeleteTickets'](arg1);
 }
 
+export function GetAdvanceSales() {
+  return window['go']['services']['TicketService']['GetAdvanceSales']();
+}
+
+export function GetDepartureTickets(arg1, arg2, arg3, arg4) {
+  return window['go']['services']['TicketService']['GetDepartureTickets'](arg1, arg2, arg3, arg4);
+}
+
+export function GetPaymentSimulatorTransactions() {
+  return window['go']['services']['TicketService']['GetPaymentSimulatorTransactions']();
+}
+
+export function GetSale(arg1) {
+  return window['go']['services']['TicketService']['GetSale'](arg1);
+}
+
+export function GetSeatAvailability(arg1, arg2, arg3, arg4) {
+  return window['go']['services']['TicketService']['GetSeatAvailability'](arg1, arg2, arg3, arg4);
+}
+
+export function GetSeatMap(arg1, arg2, arg3, arg4) {
+  return window['go']['services']['TicketService']['GetSeatMap'](arg1, arg2, arg3, arg4);
+}
+
+export function GetTicketVoids(arg1, arg2) {
+  return window['go']['services']['TicketService']['GetTicketVoids'](arg1, arg2);
+}
+
+export function GetTicketVoidsByReport(arg1) {
+  return window['go']['services']['TicketService']['GetTicketVoidsByReport'](arg1);
+}
+
 export function NullifyTicket(arg1, arg2) {
   return window['go']['services']['TicketService']['NullifyTicket'](arg1, arg2);
 }
 
+export function ScriptPaymentSimulator(arg1) {
+  return window['go']['services']['TicketService']['ScriptPaymentSimulator'](arg1);
+}
+
+export function SearchTickets(arg1) {
+  return window['go']['services']['TicketService']['SearchTickets'](arg1);
+}
+
 export function UpdateTickets(arg1) {
   return window['go']['services']['TicketService']['UpdateTickets'](arg1);
 }
+
+export function ValidateTicketPayload(arg1, arg2) {
+  return window['go']['services']['TicketService']['ValidateTicketPayload'](arg1, arg2);
+}
+
+export function VoidSale(arg1) {
+  return window['go']['services']['TicketService']['VoidSale'](arg1);
+}
+
+export function VoidTicket(arg1) {
+  return window['go']['services']['TicketService']['VoidTicket'](arg1);
+}